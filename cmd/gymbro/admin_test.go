@@ -0,0 +1,30 @@
+package main
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestAdminFlow checks that an admin can end the workout of a user, it is saved like one the user ended.
+func TestAdminFlow(t *testing.T) {
+	c := newAPIClient(t)
+	email := "bro@gym.com"
+	user, err := c.repos.users.GetUserByEmail(context.Background(), &email)
+	require.NoError(t, err)
+	require.NoError(t, c.repos.users.SetUserRole(context.Background(), &user.UserId, storage.RoleAdmin))
+
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	c.do(http.MethodPost, "/workouts/records/add", dto.RecordRequest{FkExerciseId: 1, Reps: 5, Weight: 100}, http.StatusOK)
+	c.do(http.MethodDelete, "/admin/users/"+user.UserId+"/session", nil, http.StatusOK)
+	require.Equal(t, resp.CodeNoActiveWorkout, c.do(http.MethodPost, "/workouts/end", nil, http.StatusForbidden).Code)
+
+	exercise := 1
+	max, err := c.repos.users.GetUserMax(context.Background(), &user.UserId, &exercise)
+	require.NoError(t, err)
+	require.Equal(t, 100, max.MaxWeight)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestChallengesFlow checks that a user who never joined a clan has no clan results and does not count in a challenge.
+func TestChallengesFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	require.Equal(t, []interface{}{}, c.do(http.MethodGet, "/challenges", nil, http.StatusOK).Data)
+	require.Equal(t, resp.CodeNotFound, c.do(http.MethodGet, "/challenges/results", nil, http.StatusNotFound).Code)
+
+	// challenges are created by admins
+	now := time.Now().UTC().Truncate(time.Second)
+	challenge := &storage.Challenge{ChallengeID: storage.GenerateUID(), Name: "Show up", Goal: storage.GoalWorkouts, Target: 1, Reward: 50,
+		StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour), CreatedAt: now}
+	require.NoError(t, c.repos.challenges.CreateChallenge(context.Background(), challenge))
+
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	c.do(http.MethodPost, "/workouts/records/add", dto.RecordRequest{FkExerciseId: 1, Reps: 5, Weight: 100}, http.StatusOK)
+	c.do(http.MethodPost, "/workouts/end", nil, http.StatusOK)
+
+	progress := c.do(http.MethodGet, "/challenges/"+challenge.ChallengeID, nil, http.StatusOK).Data.(map[string]interface{})
+	require.Equal(t, []interface{}{}, progress["standings"])
+	require.Nil(t, progress["clan"])
+}
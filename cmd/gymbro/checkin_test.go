@@ -0,0 +1,15 @@
+package main
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestCheckInFlow checks that a user without a subscription is not let into the gym.
+func TestCheckInFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	require.Equal(t, resp.CodeNoSubscription, c.do(http.MethodPost, "/gyms/0/check-in", nil, http.StatusForbidden).Code)
+}
@@ -1,21 +1,47 @@
 package main
 
 import (
+	"GYMBRO/cmd/migrate/migrations"
 	"GYMBRO/internal/config"
 	"GYMBRO/internal/http-server/handlers/factory"
+	"GYMBRO/internal/http-server/handlers/health"
+	"GYMBRO/internal/http-server/handlers/openapi"
 	"GYMBRO/internal/http-server/handlers/users/oauth"
+	mwdeprecation "GYMBRO/internal/http-server/middleware/deprecation"
 	mwlogger "GYMBRO/internal/http-server/middleware/logger"
+	mwmetrics "GYMBRO/internal/http-server/middleware/metrics"
+	mwtracing "GYMBRO/internal/http-server/middleware/tracing"
+	"GYMBRO/internal/lib/achievements"
+	"GYMBRO/internal/lib/events"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/mailer"
+	"GYMBRO/internal/lib/metrics"
 	"GYMBRO/internal/lib/prettylogger"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/lib/webhooks"
 	"GYMBRO/internal/services"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/memory"
 	"GYMBRO/internal/storage/postgresql"
 	"GYMBRO/internal/storage/redis"
+	"GYMBRO/internal/storage/sqlite"
+	"context"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
+const apiPrefix = "/api/v1"
+
 func main() {
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
@@ -23,27 +49,75 @@ func main() {
 	log.Info("Configuration loaded")
 	log.Info("Logger loaded")
 
-	db, err := postgresql.New(cfg.StoragePath)
+	shutdownTracing, err := tracing.New(cfg)
+	if err != nil {
+		log.Error("Error initializing tracing", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+	log.Info("Tracing loaded", slog.String("exporter", cfg.TracingCfg.Exporter))
+
+	repos, closeStorage, err := setupStorage(cfg)
 	if err != nil {
 		log.Error("Error initializing storage", slog.Any("error", err))
 		os.Exit(1)
 	}
-	defer db.Close()
-	log.Info("Storage loaded")
+	defer closeStorage()
+	log.Info("Storage loaded", slog.String("driver", cfg.Driver))
 
-	sessionManager, err := redis.New(cfg.RedisPath, cfg.RedisPassword, 0)
+	if err := oauth.NewOAuth(cfg, log, apiPrefix); err != nil {
+		log.Error("Error initializing OAuth providers", slog.Any("error", err))
+		os.Exit(1)
+	}
+	log.Info("OAuth providers loaded")
+
+	metrics.RegisterActiveSessions(repos.sessions.CountSessions)
+
+	// the SQLite driver has its own migration set
+	latestMigration := migrations.Latest
+	if cfg.Driver == config.DriverSQLite {
+		latestMigration = migrations.LatestSQLite
+	}
+	migrationVersion, err := latestMigration()
 	if err != nil {
-		log.Error("Error initializing session manager", slog.Any("error", err))
+		log.Error("Error reading migrations", slog.Any("error", err))
 		os.Exit(1)
 	}
-	log.Info("Session manager loaded")
 
-	router := setupRouter(cfg, log, db, sessionManager)
+	readiness := &health.Readiness{}
+	router := setupRouter(cfg, log, repos, readiness, migrationVersion)
 
-	sessionSched := services.NewSessionScheduler(sessionManager, db, cfg, log)
-	sessionSched.Start()
+	// the background jobs are stopped once the server is, before the storage is closed
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	sessionSched := services.NewSessionScheduler(repos.sessions, repos.finalizer(), cfg, log)
+	sessionSched.Start(jobsCtx, &jobs)
+	challengeSched := services.NewChallengeScheduler(repos.challenges, cfg, log)
+	challengeSched.Start(jobsCtx, &jobs)
+	seasonSched := services.NewSeasonScheduler(repos.seasons, repos.locker, cfg, log)
+	seasonSched.Start(jobsCtx, &jobs)
+	webhookDispatcher := services.NewWebhookDispatcher(repos.webhooks, repos.locker, cfg, log)
+	webhookDispatcher.Start(jobsCtx, &jobs)
 
-	startServer(cfg, router, log)
+	startServer(cfg, router, readiness, log)
+
+	stopJobs()
+	waitJobs(&jobs, cfg.ShutdownTimeout, log)
+}
+
+// waitJobs waits for the runs of the background jobs in progress, at most for timeout.
+func waitJobs(jobs *sync.WaitGroup, timeout time.Duration, log *slog.Logger) {
+	done := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Info("Background jobs stopped")
+	case <-time.After(timeout):
+		log.Warn("Background jobs did not stop in time", slog.Duration("timeout", timeout))
+	}
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -63,28 +137,191 @@ func setupLogger(env string) *slog.Logger {
 	}
 }
 
-func setupRouter(cfg *config.Config, log *slog.Logger, db *postgresql.Storage, sm *redis.RedisStorage) *chi.Mux {
-	handlerFactory := factory.NewConcreteHandlerFactory(log, db, db, sm, cfg)
+// repositories are the storage implementations chosen by the storage driver.
+type repositories struct {
+	users         storage.UserRepository
+	workouts      storage.WorkoutRepository
+	sessions      storage.SessionRepository
+	sessionEvents storage.SessionSubscriber
+	exercises     storage.ExerciseRepository
+	rateLimits    storage.RateLimitRepository
+	lockouts      storage.LockoutRepository
+	stats         storage.StatsRepository
+	achievements  storage.AchievementRepository
+	follows       storage.FollowRepository
+	events        storage.EventRepository
+	gyms          storage.GymRepository
+	challenges    storage.ChallengeRepository
+	seasons       storage.SeasonRepository
+	moderation    storage.ModerationRepository
+	webhooks      storage.WebhookRepository
+	locker        storage.Locker // keeps the background jobs to one replica
+	migrations    storage.MigrationRepository
+	// db is pinged as "postgres" and cache as "redis" by the readiness check
+	db    storage.Pinger
+	cache storage.Pinger
+}
+
+// finalizer ends workouts for the end handler, the session scheduler and the admin force-end and credits approved records. It evaluates the achievement rules,
+// publishes the events to the followers' feeds and queues them for the webhooks, the dispatcher sends them.
+func (r repositories) finalizer() *finalizer.Finalizer {
+	return finalizer.New(r.sessions, r.workouts, r.users, r.moderation,
+		achievements.New(achievements.Rules, r.users, r.workouts, r.exercises, r.achievements),
+		events.New(r.events),
+		webhooks.New(r.webhooks))
+}
+
+// newMemoryRepositories keeps everything in one in-memory storage.
+func newMemoryRepositories() repositories {
+	mem := memory.New()
+	return repositories{
+		users:         mem,
+		workouts:      mem,
+		sessions:      mem,
+		sessionEvents: mem,
+		exercises:     mem,
+		rateLimits:    mem,
+		lockouts:      mem,
+		stats:         mem,
+		achievements:  mem,
+		follows:       mem,
+		events:        mem,
+		gyms:          mem,
+		challenges:    mem,
+		seasons:       mem,
+		moderation:    mem,
+		webhooks:      mem,
+		locker:        mem,
+		migrations:    mem,
+		db:            mem,
+		cache:         mem,
+	}
+}
+
+// setupStorage connects to the storage selected by the driver. The returned function closes it.
+func setupStorage(cfg *config.Config) (repositories, func(), error) {
+	switch cfg.Driver {
+	case config.DriverMemory:
+		return newMemoryRepositories(), func() {}, nil
+	case config.DriverSQLite:
+		return setupSQLite(cfg)
+	}
+
+	db, err := postgresql.New(cfg.StoragePath)
+	if err != nil {
+		return repositories{}, nil, err
+	}
+	sessionManager, err := redis.New(cfg.RedisPath, cfg.RedisPassword, 0)
+	if err != nil {
+		db.Close()
+		return repositories{}, nil, err
+	}
+	if err := sessionManager.MigrateSessionKeys(context.Background()); err != nil {
+		db.Close()
+		return repositories{}, nil, err
+	}
+	prometheus.MustRegister(postgresql.NewPoolCollector(db))
+
+	return repositories{
+		users:         db,
+		workouts:      db,
+		sessions:      sessionManager,
+		sessionEvents: sessionManager,
+		exercises:     db,
+		rateLimits:    sessionManager,
+		lockouts:      sessionManager,
+		stats:         db,
+		achievements:  db,
+		follows:       db,
+		events:        db,
+		gyms:          db,
+		challenges:    db,
+		seasons:       db,
+		moderation:    db,
+		webhooks:      db,
+		locker:        db,
+		migrations:    db,
+		db:            db,
+		cache:         sessionManager,
+	}, db.Close, nil
+}
+
+// setupSQLite opens the SQLite file and applies its migrations, the binary needs nothing else to run.
+// Rate limits, lockouts and locks are kept in memory, so they are per process.
+func setupSQLite(cfg *config.Config) (repositories, func(), error) {
+	db, err := sqlite.New(cfg.StoragePath)
+	if err != nil {
+		return repositories{}, nil, err
+	}
+	if err := db.Migrate(); err != nil {
+		db.Close()
+		return repositories{}, nil, err
+	}
+	mem := memory.New()
+
+	return repositories{
+		users:         db,
+		workouts:      db,
+		sessions:      db,
+		sessionEvents: db,
+		exercises:     db,
+		rateLimits:    mem,
+		lockouts:      mem,
+		stats:         db,
+		achievements:  db,
+		follows:       db,
+		events:        db,
+		gyms:          db,
+		challenges:    db,
+		seasons:       db,
+		moderation:    db,
+		webhooks:      db,
+		locker:        mem,
+		migrations:    db,
+		db:            db,
+		cache:         db,
+	}, db.Close, nil
+}
+
+func setupRouter(cfg *config.Config, log *slog.Logger, repos repositories, readiness *health.Readiness, migrationVersion uint) *chi.Mux {
+	handlerFactory := factory.NewConcreteHandlerFactory(log, repos.users, repos.workouts, repos.sessions, repos.sessionEvents, repos.exercises, repos.rateLimits, repos.lockouts, repos.stats, repos.achievements, repos.follows, repos.events, repos.gyms, repos.challenges, repos.seasons, repos.moderation, repos.webhooks, repos.finalizer(), mailer.New(cfg.MailerCfg, log), cfg)
 
 	userHandlerFactory := handlerFactory.GetUsersHandlerFactory()
 	middlewareHandlerFactory := handlerFactory.GetMiddlewaresHandlerFactory()
 	workoutHandlerFactory := handlerFactory.GetWorkoutsHandlerFactory()
 	recordHandlerFactory := handlerFactory.GetRecordsHandlerFactory()
+	adminHandlerFactory := handlerFactory.GetAdminsHandlerFactory()
+	statsHandlerFactory := handlerFactory.GetStatsHandlerFactory()
+	socialHandlerFactory := handlerFactory.GetSocialsHandlerFactory()
+	gymHandlerFactory := handlerFactory.GetGymsHandlerFactory()
+	challengeHandlerFactory := handlerFactory.GetChallengesHandlerFactory()
+	seasonHandlerFactory := handlerFactory.GetSeasonsHandlerFactory()
+	webhookHandlerFactory := handlerFactory.GetWebhooksHandlerFactory()
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(mwtracing.New())
 	router.Use(mwlogger.New(log))
+	router.Use(mwmetrics.New())
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
-	oauth.NewOAuth(cfg)
+	router.Get("/metrics", promhttp.Handler().ServeHTTP)
+	router.Get("/openapi.json", openapi.NewHandler())
+	router.Get("/healthz", health.NewLivenessHandler())
+	router.Get("/readyz", health.NewReadinessHandler(log, readiness, repos.db, repos.cache, repos.migrations, migrationVersion))
+
+	api := chi.NewRouter()
 
-	router.Group(func(r chi.Router) {
+	api.Group(func(r chi.Router) {
 		r.Use(middlewareHandlerFactory.CreateJWTAuthHandler())
+		r.Use(middlewareHandlerFactory.CreateRateLimitHandler("workouts"))
 		r.Route("/workouts", func(r chi.Router) {
 			r.Post("/start", workoutHandlerFactory.CreateStartHandler())
+			r.Get("/export", workoutHandlerFactory.CreateExportHandler())
+			r.Get("/active/stream", workoutHandlerFactory.CreateStreamHandler(readiness.Done()))
 			r.Get("/{workoutID}", workoutHandlerFactory.CreateGetWorkoutHandler())
 
 			r.Group(func(r chi.Router) {
@@ -96,13 +333,69 @@ func setupRouter(cfg *config.Config, log *slog.Logger, db *postgresql.Storage, s
 				})
 			})
 		})
+		r.Route("/stats", func(r chi.Router) {
+			r.Get("/volume", statsHandlerFactory.CreateVolumeHandler())
+			r.Get("/exercises/{exerciseID}/progress", statsHandlerFactory.CreateProgressHandler())
+		})
+		r.Get("/feed", socialHandlerFactory.CreateFeedHandler())
+		r.Route("/presence", func(r chi.Router) {
+			r.Get("/clan", socialHandlerFactory.CreateClanPresenceHandler())
+			r.Get("/gym", socialHandlerFactory.CreateGymPresenceHandler())
+			r.Get("/gyms/{gymID}", socialHandlerFactory.CreateGymCountHandler())
+		})
+		r.Route("/gyms", func(r chi.Router) {
+			r.Post("/{gymID}/check-in", gymHandlerFactory.CreateCheckInHandler())
+			r.With(middlewareHandlerFactory.CreateRequireRoleHandler(storage.RoleModerator, storage.RoleAdmin)).
+				Post("/check-ins/verify", gymHandlerFactory.CreateVerifyCheckInHandler())
+		})
+		r.Route("/challenges", func(r chi.Router) {
+			r.Get("/", challengeHandlerFactory.CreateListHandler())
+			r.Get("/results", challengeHandlerFactory.CreateResultsHandler())
+			r.Get("/{challengeID}", challengeHandlerFactory.CreateProgressHandler())
+		})
+		r.Route("/seasons", func(r chi.Router) {
+			r.Get("/", seasonHandlerFactory.CreateListHandler())
+			r.Get("/{seasonID}/standings", seasonHandlerFactory.CreateStandingsHandler())
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Post("/", webhookHandlerFactory.CreateCreateHandler())
+			r.Get("/", webhookHandlerFactory.CreateListHandler())
+			r.Get("/dead-letters", webhookHandlerFactory.CreateDeadLettersHandler())
+			r.Post("/deliveries/{deliveryID}/retry", webhookHandlerFactory.CreateRetryHandler())
+			r.Delete("/{webhookID}", webhookHandlerFactory.CreateDeleteHandler())
+			r.Get("/{webhookID}/deliveries", webhookHandlerFactory.CreateDeliveriesHandler())
+		})
 	})
 
-	router.Route("/users", func(r chi.Router) {
-		r.Post("/register", userHandlerFactory.CreateRegisterHandler())
-		r.Post("/login", userHandlerFactory.CreateLoginHandler())
+	api.Route("/users", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middlewareHandlerFactory.CreateRateLimitHandler("auth"))
+			r.Post("/register", userHandlerFactory.CreateRegisterHandler())
+			r.Post("/login", userHandlerFactory.CreateLoginHandler())
+		})
 		r.Get("/logout", userHandlerFactory.CreateLogoutHandler())
 
+		r.Group(func(r chi.Router) {
+			r.Use(middlewareHandlerFactory.CreateJWTAuthHandler())
+			r.Post("/merge", userHandlerFactory.CreateMergeHandler())
+			r.Route("/me", func(r chi.Router) {
+				r.Get("/", userHandlerFactory.CreateProfileHandler())
+				r.Put("/body-weight", userHandlerFactory.CreateBodyWeightHandler())
+				r.Put("/privacy", userHandlerFactory.CreatePrivacyHandler())
+				r.Get("/followers", socialHandlerFactory.CreateFollowersHandler())
+				r.Post("/followers/{userID}/accept", socialHandlerFactory.CreateAcceptHandler())
+				r.Delete("/followers/{userID}", socialHandlerFactory.CreateRemoveFollowerHandler())
+				r.Get("/following", socialHandlerFactory.CreateFollowingHandler())
+			})
+			r.Post("/{userID}/follow", socialHandlerFactory.CreateFollowHandler())
+			r.Delete("/{userID}/follow", socialHandlerFactory.CreateUnfollowHandler())
+			r.Route("/identities", func(r chi.Router) {
+				r.Get("/", userHandlerFactory.CreateIdentitiesHandler())
+				r.Get("/{provider}/link", userHandlerFactory.CreateOAuthLinkHandler())
+				r.Delete("/{provider}", userHandlerFactory.CreateUnlinkHandler())
+			})
+		})
+
 		r.Route("/oauth", func(r chi.Router) {
 			r.Get("/{provider}/callback", userHandlerFactory.CreateOAuthCallbackHandler())
 			r.Get("/{provider}/logout", userHandlerFactory.CreateLogoutHandler())
@@ -110,10 +403,54 @@ func setupRouter(cfg *config.Config, log *slog.Logger, db *postgresql.Storage, s
 		})
 	})
 
+	api.Route("/admin", func(r chi.Router) {
+		r.Use(middlewareHandlerFactory.CreateJWTAuthHandler())
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewareHandlerFactory.CreateRequireRoleHandler(storage.RoleModerator, storage.RoleAdmin))
+			r.Route("/exercises", func(r chi.Router) {
+				r.Get("/", adminHandlerFactory.CreateListExercisesHandler())
+				r.Post("/", adminHandlerFactory.CreateCreateExerciseHandler())
+				r.Put("/{exerciseID}", adminHandlerFactory.CreateUpdateExerciseHandler())
+				r.Delete("/{exerciseID}", adminHandlerFactory.CreateDeleteExerciseHandler())
+			})
+			r.Route("/muscle-groups", func(r chi.Router) {
+				r.Get("/", adminHandlerFactory.CreateListMuscleGroupsHandler())
+				r.Post("/", adminHandlerFactory.CreateCreateMuscleGroupHandler())
+				r.Put("/{muscleGroupID}", adminHandlerFactory.CreateUpdateMuscleGroupHandler())
+				r.Delete("/{muscleGroupID}", adminHandlerFactory.CreateDeleteMuscleGroupHandler())
+			})
+			r.Post("/challenges", adminHandlerFactory.CreateCreateChallengeHandler())
+			r.Route("/records", func(r chi.Router) {
+				r.Get("/flagged", adminHandlerFactory.CreateListFlaggedRecordsHandler())
+				r.Post("/{recordID}/approve", adminHandlerFactory.CreateApproveRecordHandler())
+				r.Post("/{recordID}/void", adminHandlerFactory.CreateVoidRecordHandler())
+			})
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewareHandlerFactory.CreateRequireRoleHandler(storage.RoleAdmin))
+			r.Route("/users/{userID}", func(r chi.Router) {
+				r.Post("/disable", adminHandlerFactory.CreateDisableUserHandler())
+				r.Post("/enable", adminHandlerFactory.CreateEnableUserHandler())
+				r.Put("/role", adminHandlerFactory.CreateSetRoleHandler())
+				r.Post("/unlock", adminHandlerFactory.CreateUnlockUserHandler())
+				r.Post("/unflag", adminHandlerFactory.CreateUnflagUserHandler())
+				r.Get("/session", adminHandlerFactory.CreateGetSessionHandler())
+				r.Delete("/session", adminHandlerFactory.CreateEndSessionHandler())
+				r.Post("/subscriptions", adminHandlerFactory.CreateCreateSubscriptionHandler())
+			})
+		})
+	})
+
+	router.Mount(apiPrefix, api)
+	// paths from before versioning stay as aliases until clients move to /api/v1
+	router.With(mwdeprecation.New(apiPrefix)).Mount("/", api)
+
 	return router
 }
 
-func startServer(cfg *config.Config, router *chi.Mux, log *slog.Logger) {
+func startServer(cfg *config.Config, router *chi.Mux, readiness *health.Readiness, log *slog.Logger) {
 	srv := http.Server{
 		Addr:         cfg.Address,
 		Handler:      router,
@@ -122,11 +459,33 @@ func startServer(cfg *config.Config, router *chi.Mux, log *slog.Logger) {
 		IdleTimeout:  cfg.IdleTimeout,
 	}
 
-	log.Info("Starting server", slog.String("address", cfg.Address))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	if err := srv.ListenAndServe(); err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("Starting server", slog.String("address", cfg.Address))
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Error("Error starting server", slog.Any("error", err))
+		return
+	case <-ctx.Done():
+	}
+
+	// fail readiness first, so the load balancer stops sending traffic before the listener is gone
+	log.Info("Shutting down server", slog.Duration("delay", cfg.ShutdownDelay))
+	readiness.Drain()
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("Error shutting down server", slog.Any("error", err))
+		return
 	}
 
-	log.Error("Server shutdown", slog.String("address", cfg.Address))
+	log.Info("Server stopped", slog.String("address", cfg.Address))
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/health"
+	"GYMBRO/internal/http-server/handlers/openapi"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRoutesMatchOpenAPI fails when a route is added to or removed from the router without updating the OpenAPI document.
+// Deprecated aliases are not documented, but there has to be exactly one for every /api/v1 route.
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	router := setupRouter(&config.Config{}, logger, newMemoryRepositories(), &health.Readiness{}, 0)
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openapi.Spec, &doc))
+
+	var documented, versioned []string
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			route := strings.ToUpper(method) + " " + normalizePath(path)
+			documented = append(documented, route)
+			if strings.HasPrefix(path, apiPrefix+"/") {
+				versioned = append(versioned, strings.ToUpper(method)+" "+normalizePath(strings.TrimPrefix(path, apiPrefix)))
+			}
+		}
+	}
+
+	var routes, aliases []string
+	err := chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = method + " " + normalizePath(route)
+		if slices.Contains(documented, route) {
+			routes = append(routes, route)
+		} else {
+			aliases = append(aliases, route)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, documented, routes, "routes differ from the OpenAPI document")
+	require.ElementsMatch(t, versioned, aliases, "deprecated aliases differ from /api/v1 routes")
+}
+
+// normalizePath drops the trailing slash chi adds to the root of a subrouter.
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// apiClient calls the real router on the in-memory storage as a registered and logged in user.
+type apiClient struct {
+	t      *testing.T
+	url    string
+	client *http.Client
+	// repos are the storage behind the server, for what the user can't do through the API
+	repos repositories
+}
+
+// newAPIClient starts a server on a fresh in-memory storage, registers a user and logs them in.
+func newAPIClient(t *testing.T) *apiClient {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cfg := &config.Config{
+		JWTCfg:      config.JWTCfg{JWTLifetime: time.Hour, SecretKey: "test_secret_key"},
+		WebhooksCfg: config.WebhooksCfg{MaxPerOwner: 10},
+	}
+	repos := newMemoryRepositories()
+	srv := httptest.NewServer(setupRouter(cfg, logger, repos, &health.Readiness{}, 0))
+	t.Cleanup(srv.Close)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	c := &apiClient{
+		t:     t,
+		url:   srv.URL + apiPrefix,
+		repos: repos,
+		client: &http.Client{
+			Jar: jar,
+			// handlers redirect after login and registration, the tests check their own responses
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	c.do(http.MethodPost, "/users/register", dto.RegisterRequest{Username: "bro", Email: "bro@gym.com", Password: "password"}, http.StatusOK)
+	c.do(http.MethodPost, "/users/login", map[string]string{"email": "bro@gym.com", "password": "password"}, http.StatusOK)
+	return c
+}
+
+// do sends body as JSON and checks the status code of the response.
+func (c *apiClient) do(method, path string, body interface{}, expectedStatus int) *resp.DetailedResponse {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(c.t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	require.NoError(c.t, err)
+	res, err := c.client.Do(req)
+	require.NoError(c.t, err)
+	defer res.Body.Close()
+
+	require.Equal(c.t, expectedStatus, res.StatusCode, "%s %s", method, path)
+	var response resp.DetailedResponse
+	require.NoError(c.t, json.NewDecoder(res.Body).Decode(&response))
+	return &response
+}
+
+// get returns the response as is, for the endpoints that do not answer with the usual envelope.
+func (c *apiClient) get(ctx context.Context, path string) *http.Response {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	require.NoError(c.t, err)
+	res, err := c.client.Do(req)
+	require.NoError(c.t, err)
+	c.t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+// TestWorkoutFlow runs a whole workout through the real router on the in-memory storage:
+// start, add a record, end, then find the workout in the export and get it by ID.
+func TestWorkoutFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	record := c.do(http.MethodPost, "/workouts/records/add", dto.RecordRequest{FkExerciseId: 1, Reps: 5, Weight: 100}, http.StatusOK)
+	require.Equal(t, storage.RecordOK, record.Data.(map[string]interface{})["status"])
+	c.do(http.MethodPost, "/workouts/end", nil, http.StatusOK)
+	require.Equal(t, resp.CodeNoActiveWorkout, c.do(http.MethodPost, "/workouts/end", nil, http.StatusForbidden).Code)
+
+	res := c.get(context.Background(), "/workouts/export?format=json")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var exported []dto.WorkoutResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&exported))
+	require.Len(t, exported, 1)
+	require.Len(t, exported[0].Records, 1)
+
+	workout := c.do(http.MethodGet, "/workouts/"+exported[0].WorkoutID, nil, http.StatusOK)
+	require.Equal(t, resp.StatusOK, workout.Status)
+}
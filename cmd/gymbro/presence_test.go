@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestPresenceFlow checks that the user is counted at their gym while their session is there.
+func TestPresenceFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	require.Equal(t, map[string]interface{}{"gym_id": 0.0, "active": 0.0}, c.do(http.MethodGet, "/presence/gyms/0", nil, http.StatusOK).Data)
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	require.Equal(t, map[string]interface{}{"gym_id": 0.0, "active": 1.0}, c.do(http.MethodGet, "/presence/gyms/0", nil, http.StatusOK).Data)
+	c.do(http.MethodPost, "/workouts/end", nil, http.StatusOK)
+	require.Equal(t, map[string]interface{}{"gym_id": 0.0, "active": 0.0}, c.do(http.MethodGet, "/presence/gyms/0", nil, http.StatusOK).Data)
+}
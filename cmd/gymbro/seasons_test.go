@@ -0,0 +1,16 @@
+package main
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestSeasonsFlow checks the seasons before the first one is started by the scheduler, which does not run here.
+func TestSeasonsFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	require.Equal(t, []interface{}{}, c.do(http.MethodGet, "/seasons", nil, http.StatusOK).Data)
+	require.Equal(t, resp.CodeNotFound, c.do(http.MethodGet, "/seasons/current/standings", nil, http.StatusNotFound).Code)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/storage"
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSessionStreamFlow keeps the session stream open during a workout, it has to show every change of the session.
+func TestSessionStreamFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream := c.get(ctx, "/workouts/active/stream")
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	require.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+	events := bufio.NewReader(stream.Body)
+	nextEvent := func() string {
+		for {
+			line, err := events.ReadString('\n')
+			require.NoError(t, err)
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				return strings.TrimSpace(name)
+			}
+		}
+	}
+	require.Equal(t, "snapshot", nextEvent())
+
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	require.Equal(t, storage.SessionCreated, nextEvent())
+	c.do(http.MethodPost, "/workouts/records/add", dto.RecordRequest{FkExerciseId: 1, Reps: 5, Weight: 100}, http.StatusOK)
+	require.Equal(t, storage.SessionUpdated, nextEvent())
+	c.do(http.MethodPost, "/workouts/end", nil, http.StatusOK)
+	require.Equal(t, storage.SessionDeleted, nextEvent())
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestWebhooksFlow subscribes to the workouts of the user and checks the deliveries queued when a workout with a new max
+// ends. The dispatcher does not run here, the deliveries stay pending.
+func TestWebhooksFlow(t *testing.T) {
+	c := newAPIClient(t)
+
+	webhook := c.do(http.MethodPost, "/webhooks", map[string]interface{}{"url": "https://partner.example.com/hooks", "events": []string{storage.WebhookWorkoutEnded, storage.WebhookPRAchieved}}, http.StatusOK)
+	webhookID := webhook.Data.(map[string]interface{})["webhook_id"].(string)
+	// only moderators and admins subscribe to a gym
+	require.Equal(t, resp.CodeForbidden, c.do(http.MethodPost, "/webhooks", map[string]interface{}{"url": "https://partner.example.com/hooks", "events": []string{storage.WebhookWorkoutEnded}, "gym_id": 0}, http.StatusForbidden).Code)
+
+	c.do(http.MethodPost, "/workouts/start", nil, http.StatusOK)
+	c.do(http.MethodPost, "/workouts/records/add", dto.RecordRequest{FkExerciseId: 1, Reps: 5, Weight: 100}, http.StatusOK)
+	c.do(http.MethodPost, "/workouts/end", nil, http.StatusOK)
+
+	deliveries := c.do(http.MethodGet, "/webhooks/"+webhookID+"/deliveries?status=pending", nil, http.StatusOK).Data.([]interface{})
+	require.Len(t, deliveries, 2)
+	queued := []interface{}{deliveries[0].(map[string]interface{})["event"], deliveries[1].(map[string]interface{})["event"]}
+	require.ElementsMatch(t, []interface{}{storage.WebhookWorkoutEnded, storage.WebhookPRAchieved}, queued)
+}
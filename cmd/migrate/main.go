@@ -6,16 +6,28 @@ import (
 	"fmt"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"os"
 )
 
 func main() {
-	var direction string
+	var direction, driver string
 	flag.StringVar(&direction, "direction", "up", "direction [up|down] default:up")
+	flag.StringVar(&driver, "driver", os.Getenv("STORAGE_DRIVER"), "storage driver [postgres|sqlite] default:STORAGE_DRIVER or postgres")
 	flag.Parse()
-	storagePath := os.Getenv("STORAGE_PATH") + "?sslmode=disable"
-	migrationsPath := "file://cmd/migrate/migrations"
+	var storagePath, migrationsPath string
+	switch driver {
+	case "", "postgres":
+		storagePath = os.Getenv("STORAGE_PATH") + "?sslmode=disable"
+		migrationsPath = "file://cmd/migrate/migrations"
+	case "sqlite":
+		// STORAGE_PATH is the database file here
+		storagePath = "sqlite://" + os.Getenv("STORAGE_PATH")
+		migrationsPath = "file://cmd/migrate/migrations/sqlite"
+	default:
+		panic(fmt.Sprintf("unknown storage driver %q", driver))
+	}
 	m, err := migrate.New(migrationsPath, storagePath)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,13 @@
+ALTER TABLE Users
+DROP COLUMN IF EXISTS is_flagged;
+
+ALTER TABLE Exercises
+DROP COLUMN IF EXISTS weight_limit;
+
+DROP INDEX IF EXISTS records_review_idx;
+
+ALTER TABLE Records
+DROP COLUMN IF EXISTS reason;
+
+ALTER TABLE Records
+DROP COLUMN IF EXISTS status;
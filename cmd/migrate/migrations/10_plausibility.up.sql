@@ -0,0 +1,15 @@
+-- records that fail the plausibility checks are held for review or excluded from points, moderators approve or void them
+ALTER TABLE Records
+ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'held', 'excluded', 'voided'));
+
+ALTER TABLE Records
+ADD COLUMN IF NOT EXISTS reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS records_review_idx ON Records (status) WHERE status IN ('held', 'excluded');
+
+-- 0 is the default limit of the config
+ALTER TABLE Exercises
+ADD COLUMN IF NOT EXISTS weight_limit INT NOT NULL DEFAULT 0;
+
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS is_flagged BOOLEAN NOT NULL DEFAULT false;
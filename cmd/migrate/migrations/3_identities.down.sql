@@ -0,0 +1,9 @@
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS google_id VARCHAR(100) UNIQUE;
+
+UPDATE Users u
+SET google_id = i.subject
+FROM UserIdentities i
+WHERE i.fk_user_id = u.user_id AND i.provider = 'google';
+
+drop table if exists useridentities cascade;
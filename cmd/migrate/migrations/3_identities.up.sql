@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS UserIdentities
+(
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    fk_user_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    email VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject)
+);
+
+-- an account has at most one identity per provider, the index also finds the identities of a user
+CREATE UNIQUE INDEX IF NOT EXISTS useridentities_fk_user_id_provider_key ON UserIdentities (fk_user_id, provider);
+
+INSERT INTO UserIdentities (provider, subject, fk_user_id, email)
+SELECT 'google', google_id, user_id, email
+FROM Users
+WHERE google_id IS NOT NULL AND google_id <> '';
+
+ALTER TABLE Users
+DROP COLUMN IF EXISTS google_id;
@@ -0,0 +1,5 @@
+ALTER TABLE Users
+DROP COLUMN IF EXISTS is_disabled;
+
+ALTER TABLE Users
+DROP COLUMN IF EXISTS role;
@@ -0,0 +1,6 @@
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'moderator', 'admin'));
+
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS is_disabled BOOLEAN NOT NULL DEFAULT false;
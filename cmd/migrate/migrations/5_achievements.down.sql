@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS Achievements;
+
+ALTER TABLE Users
+DROP COLUMN IF EXISTS body_weight;
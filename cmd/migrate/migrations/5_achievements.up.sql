@@ -0,0 +1,10 @@
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS body_weight INT CHECK (body_weight > 0);
+
+CREATE TABLE IF NOT EXISTS Achievements
+(
+    fk_user_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    awarded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (fk_user_id, code)
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS Events;
+
+DROP TABLE IF EXISTS Follows;
+
+ALTER TABLE Users
+DROP COLUMN IF EXISTS is_private;
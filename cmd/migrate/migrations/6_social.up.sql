@@ -0,0 +1,30 @@
+ALTER TABLE Users
+ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS Follows
+(
+    follower_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    followee_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('pending', 'accepted')),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON Follows (followee_id);
+
+CREATE TABLE IF NOT EXISTS Events
+(
+    event_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    workout_id TEXT NOT NULL DEFAULT '',
+    points INT NOT NULL DEFAULT 0,
+    exercise_id INT NOT NULL DEFAULT 0,
+    weight INT NOT NULL DEFAULT 0,
+    reps INT NOT NULL DEFAULT 0,
+    code VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS events_fk_user_id_created_at_idx ON Events (fk_user_id, created_at DESC, event_id DESC);
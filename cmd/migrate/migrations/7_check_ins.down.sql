@@ -0,0 +1,6 @@
+ALTER TABLE Workouts
+DROP COLUMN IF EXISTS fk_gym_id;
+
+DROP TABLE IF EXISTS CheckIns;
+
+DROP INDEX IF EXISTS subscriptions_fk_user_id_fk_gym_id_idx;
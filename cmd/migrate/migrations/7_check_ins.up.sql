@@ -0,0 +1,15 @@
+CREATE INDEX IF NOT EXISTS subscriptions_fk_user_id_fk_gym_id_idx ON Subscriptions (fk_user_id, fk_gym_id);
+
+CREATE TABLE IF NOT EXISTS CheckIns
+(
+    check_in_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+    fk_gym_id INT NOT NULL REFERENCES Gyms(gym_id) ON DELETE CASCADE,
+    fk_subscription_id TEXT NOT NULL REFERENCES Subscriptions(subscription_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS checkins_fk_user_id_created_at_idx ON CheckIns (fk_user_id, created_at DESC);
+
+ALTER TABLE Workouts
+ADD COLUMN IF NOT EXISTS fk_gym_id INT REFERENCES Gyms(gym_id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS workouts_start_time_idx;
+
+DROP TABLE IF EXISTS ChallengeResults;
+
+DROP TABLE IF EXISTS Challenges;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS Challenges
+(
+    challenge_id TEXT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    goal VARCHAR(20) NOT NULL,
+    fk_exercise_id INT REFERENCES Exercises(exercise_id) ON DELETE CASCADE,
+    target INT NOT NULL,
+    reward INT NOT NULL DEFAULT 0,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS challenges_end_time_idx ON Challenges (end_time);
+
+CREATE TABLE IF NOT EXISTS ChallengeResults
+(
+    fk_challenge_id TEXT NOT NULL REFERENCES Challenges(challenge_id) ON DELETE CASCADE,
+    fk_clan_id TEXT NOT NULL REFERENCES Clans(clan_id) ON DELETE CASCADE,
+    progress BIGINT NOT NULL,
+    rank INT NOT NULL,
+    completed BOOLEAN NOT NULL,
+    points INT NOT NULL,
+    PRIMARY KEY (fk_challenge_id, fk_clan_id)
+);
+
+CREATE INDEX IF NOT EXISTS challengeresults_fk_clan_id_idx ON ChallengeResults (fk_clan_id);
+
+-- challenge progress sums the workouts started in a window
+CREATE INDEX IF NOT EXISTS workouts_start_time_idx ON Workouts (start_time);
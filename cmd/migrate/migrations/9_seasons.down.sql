@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS SeasonStandings;
+
+DROP TABLE IF EXISTS Seasons;
+
+ALTER TABLE Clans
+DROP COLUMN IF EXISTS season_points;
+
+ALTER TABLE Users
+DROP COLUMN IF EXISTS season_points;
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FS holds the migration files, so the server knows which schema version it was built for.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SQLiteFS holds the migrations of the SQLite storage in the sqlite directory.
+// The server applies them itself, so a SQLite deployment is a single binary.
+//
+//go:embed sqlite/*.sql
+var SQLiteFS embed.FS
+
+// Latest returns the highest migration version, i.e. the version the database is expected to be at.
+func Latest() (uint, error) {
+	const op = "migrations.Latest"
+	version, err := latest(FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, nil
+}
+
+// LatestSQLite returns the highest migration version of the SQLite storage.
+func LatestSQLite() (uint, error) {
+	const op = "migrations.LatestSQLite"
+	version, err := latest(SQLiteFS, "sqlite")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, nil
+}
+
+func latest(fsys fs.FS, dir string) (uint, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.up.sql"))
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, file := range files {
+		version, err := strconv.ParseUint(strings.SplitN(path.Base(file), "_", 2)[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("bad migration name %s: %w", file, err)
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS Sessions;
+DROP TABLE IF EXISTS Records;
+DROP TABLE IF EXISTS Workouts;
+DROP TABLE IF EXISTS UserExerciseMaxWeights;
+DROP TABLE IF EXISTS UserIdentities;
+DROP TABLE IF EXISTS Users;
+DROP TABLE IF EXISTS ExerciseMuscleGroups;
+DROP TABLE IF EXISTS MuscleGroups;
+DROP TABLE IF EXISTS Exercises;
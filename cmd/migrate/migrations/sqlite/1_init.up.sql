@@ -0,0 +1,95 @@
+-- The same schema as the Postgres migrations end up with, without gyms, clans and subscriptions nothing uses yet.
+-- Times are stored as fixed width UTC text (2006-01-02T15:04:05.000000000Z), so they compare as strings.
+CREATE TABLE IF NOT EXISTS Exercises
+(
+    exercise_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    picture TEXT
+);
+
+CREATE TABLE IF NOT EXISTS MuscleGroups
+(
+    muscle_group_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS ExerciseMuscleGroups
+(
+    exercise_id INTEGER NOT NULL,
+    muscle_group_id INTEGER NOT NULL,
+    PRIMARY KEY (exercise_id, muscle_group_id),
+    FOREIGN KEY (exercise_id) REFERENCES Exercises (exercise_id) ON DELETE CASCADE,
+    FOREIGN KEY (muscle_group_id) REFERENCES MuscleGroups (muscle_group_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Users
+(
+    user_id TEXT PRIMARY KEY,
+    username TEXT NOT NULL UNIQUE,
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL DEFAULT '',
+    points INTEGER NOT NULL DEFAULT 0,
+    date_of_birth TEXT NOT NULL DEFAULT '',
+    fk_clan_id TEXT NOT NULL DEFAULT '0',
+    fk_gym_id INTEGER NOT NULL DEFAULT 0,
+    is_active INTEGER NOT NULL DEFAULT 0,
+    last_active TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL DEFAULT '',
+    role TEXT NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'moderator', 'admin')),
+    is_disabled INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS UserIdentities
+(
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+
+-- an account has at most one identity per provider, the index also finds the identities of a user
+CREATE UNIQUE INDEX IF NOT EXISTS useridentities_fk_user_id_provider_key ON UserIdentities (fk_user_id, provider);
+
+CREATE TABLE IF NOT EXISTS UserExerciseMaxWeights
+(
+    user_id TEXT NOT NULL,
+    exercise_id INTEGER NOT NULL,
+    max_weight INTEGER NOT NULL DEFAULT 0,
+    reps INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, exercise_id),
+    FOREIGN KEY (user_id) REFERENCES Users (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (exercise_id) REFERENCES Exercises (exercise_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Workouts
+(
+    workout_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    points INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS workouts_fk_user_id_start_time_idx ON Workouts (fk_user_id, start_time);
+
+CREATE TABLE IF NOT EXISTS Records
+(
+    record_id TEXT PRIMARY KEY,
+    fk_workout_id TEXT NOT NULL REFERENCES Workouts (workout_id) ON DELETE CASCADE,
+    fk_exercise_id INTEGER NOT NULL REFERENCES Exercises (exercise_id) ON DELETE CASCADE,
+    reps INTEGER NOT NULL,
+    weight INTEGER NOT NULL,
+    points INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS records_fk_workout_id_idx ON Records (fk_workout_id);
+
+-- Active workout sessions, kept in Redis by the postgres driver. data is the JSON encoded session.
+CREATE TABLE IF NOT EXISTS Sessions
+(
+    user_id TEXT PRIMARY KEY,
+    data TEXT NOT NULL
+);
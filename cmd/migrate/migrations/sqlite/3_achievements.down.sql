@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Achievements;
+
+ALTER TABLE Users DROP COLUMN body_weight;
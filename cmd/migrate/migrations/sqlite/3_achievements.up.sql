@@ -0,0 +1,10 @@
+-- 0 means the body weight is unknown
+ALTER TABLE Users ADD COLUMN body_weight INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Achievements
+(
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    awarded_at TEXT NOT NULL,
+    PRIMARY KEY (fk_user_id, code)
+);
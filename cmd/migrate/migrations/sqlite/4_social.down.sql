@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS Events;
+
+DROP TABLE IF EXISTS Follows;
+
+ALTER TABLE Users DROP COLUMN is_private;
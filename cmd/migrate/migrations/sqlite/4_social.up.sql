@@ -0,0 +1,29 @@
+ALTER TABLE Users ADD COLUMN is_private INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Follows
+(
+    follower_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    followee_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'accepted')),
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON Follows (followee_id);
+
+CREATE TABLE IF NOT EXISTS Events
+(
+    event_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    workout_id TEXT NOT NULL DEFAULT '',
+    points INTEGER NOT NULL DEFAULT 0,
+    exercise_id INTEGER NOT NULL DEFAULT 0,
+    weight INTEGER NOT NULL DEFAULT 0,
+    reps INTEGER NOT NULL DEFAULT 0,
+    code TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS events_fk_user_id_created_at_idx ON Events (fk_user_id, created_at, event_id);
@@ -0,0 +1,5 @@
+ALTER TABLE Workouts DROP COLUMN fk_gym_id;
+
+DROP TABLE IF EXISTS CheckIns;
+
+DROP TABLE IF EXISTS Subscriptions;
@@ -0,0 +1,25 @@
+-- There are no gyms in the SQLite storage, gym IDs are not checked.
+CREATE TABLE IF NOT EXISTS Subscriptions
+(
+    subscription_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    fk_gym_id INTEGER NOT NULL,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS subscriptions_fk_user_id_fk_gym_id_idx ON Subscriptions (fk_user_id, fk_gym_id);
+
+CREATE TABLE IF NOT EXISTS CheckIns
+(
+    check_in_id TEXT PRIMARY KEY,
+    fk_user_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    fk_gym_id INTEGER NOT NULL,
+    fk_subscription_id TEXT NOT NULL REFERENCES Subscriptions (subscription_id) ON DELETE CASCADE,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS checkins_fk_user_id_created_at_idx ON CheckIns (fk_user_id, created_at);
+
+ALTER TABLE Workouts ADD COLUMN fk_gym_id INTEGER;
@@ -0,0 +1,41 @@
+-- Clans only keep their points here, users refer to clans without a foreign key.
+CREATE TABLE IF NOT EXISTS Clans
+(
+    clan_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    points INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO Clans (clan_id, name) VALUES ('0', 'Default');
+
+CREATE TABLE IF NOT EXISTS Challenges
+(
+    challenge_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    goal TEXT NOT NULL,
+    fk_exercise_id INTEGER REFERENCES Exercises (exercise_id) ON DELETE CASCADE,
+    target INTEGER NOT NULL,
+    reward INTEGER NOT NULL DEFAULT 0,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    settled_at TEXT,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS challenges_end_time_idx ON Challenges (end_time);
+
+CREATE TABLE IF NOT EXISTS ChallengeResults
+(
+    fk_challenge_id TEXT NOT NULL REFERENCES Challenges (challenge_id) ON DELETE CASCADE,
+    fk_clan_id TEXT NOT NULL,
+    progress INTEGER NOT NULL,
+    rank INTEGER NOT NULL,
+    completed INTEGER NOT NULL,
+    points INTEGER NOT NULL,
+    PRIMARY KEY (fk_challenge_id, fk_clan_id)
+);
+
+CREATE INDEX IF NOT EXISTS challengeresults_fk_clan_id_idx ON ChallengeResults (fk_clan_id);
+
+-- challenge progress sums the workouts started in a window
+CREATE INDEX IF NOT EXISTS workouts_start_time_idx ON Workouts (start_time);
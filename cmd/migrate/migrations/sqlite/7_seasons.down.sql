@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS SeasonStandings;
+
+DROP TABLE IF EXISTS Seasons;
+
+ALTER TABLE Clans DROP COLUMN season_points;
+
+ALTER TABLE Users DROP COLUMN season_points;
@@ -0,0 +1,28 @@
+-- points stay the lifetime points, season_points are reset when a season is rolled over
+ALTER TABLE Users ADD COLUMN season_points INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE Clans ADD COLUMN season_points INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Seasons
+(
+    season_id INTEGER PRIMARY KEY,
+    start_time TEXT NOT NULL,
+    end_time TEXT NOT NULL,
+    ended_at TEXT
+);
+
+-- at most one season is not ended
+CREATE UNIQUE INDEX IF NOT EXISTS seasons_current_idx ON Seasons ((ended_at IS NULL)) WHERE ended_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS SeasonStandings
+(
+    fk_season_id INTEGER NOT NULL REFERENCES Seasons (season_id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    subject_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    points INTEGER NOT NULL,
+    rank INTEGER NOT NULL,
+    PRIMARY KEY (fk_season_id, kind, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS seasonstandings_rank_idx ON SeasonStandings (fk_season_id, kind, rank);
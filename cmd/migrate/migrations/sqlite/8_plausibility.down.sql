@@ -0,0 +1,9 @@
+ALTER TABLE Users DROP COLUMN is_flagged;
+
+ALTER TABLE Exercises DROP COLUMN weight_limit;
+
+DROP INDEX IF EXISTS records_review_idx;
+
+ALTER TABLE Records DROP COLUMN reason;
+
+ALTER TABLE Records DROP COLUMN status;
@@ -0,0 +1,11 @@
+-- records that fail the plausibility checks are held for review or excluded from points, moderators approve or void them
+ALTER TABLE Records ADD COLUMN status TEXT NOT NULL DEFAULT 'ok' CHECK (status IN ('ok', 'held', 'excluded', 'voided'));
+
+ALTER TABLE Records ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS records_review_idx ON Records (status) WHERE status IN ('held', 'excluded');
+
+-- 0 is the default limit of the config
+ALTER TABLE Exercises ADD COLUMN weight_limit INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE Users ADD COLUMN is_flagged INTEGER NOT NULL DEFAULT 0;
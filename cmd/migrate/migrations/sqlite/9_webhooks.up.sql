@@ -0,0 +1,36 @@
+-- kind 'user' sends the events of the user subject_id, 'gym' the events of the members of the gym subject_id.
+-- events are comma separated.
+CREATE TABLE IF NOT EXISTS Webhooks
+(
+    webhook_id TEXT PRIMARY KEY,
+    fk_owner_id TEXT NOT NULL REFERENCES Users (user_id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('user', 'gym')),
+    subject_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_fk_owner_id_idx ON Webhooks (fk_owner_id, created_at);
+
+CREATE INDEX IF NOT EXISTS webhooks_kind_subject_id_idx ON Webhooks (kind, subject_id);
+
+CREATE TABLE IF NOT EXISTS WebhookDeliveries
+(
+    delivery_id TEXT PRIMARY KEY,
+    fk_webhook_id TEXT NOT NULL REFERENCES Webhooks (webhook_id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT NOT NULL,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    delivered_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS webhookdeliveries_due_idx ON WebhookDeliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS webhookdeliveries_fk_webhook_id_idx ON WebhookDeliveries (fk_webhook_id, created_at);
@@ -4,17 +4,40 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Env           string `yaml:"env" env-required:"true"`
-	StoragePath   string `yaml:"storage_path" env-required:"true" env:"STORAGE_PATH"`
-	SessionsCfg   `yaml:"sessions_cfg"`
-	JWTCfg        `yaml:"jwt_cfg"`
-	RedisCfg      `yaml:"redis_cfg"`
-	OAuthCfg      `yaml:"oauth_cfg"`
-	HTTPServerCfg `yaml:"http_server_cfg"`
+	Env             string `yaml:"env" env-required:"true"`
+	StoragePath     string `yaml:"storage_path" env:"STORAGE_PATH"`
+	StorageCfg      `yaml:"storage"`
+	SessionsCfg     `yaml:"sessions_cfg"`
+	SeasonsCfg      `yaml:"seasons_cfg"`
+	PlausibilityCfg `yaml:"plausibility_cfg"`
+	WebhooksCfg     `yaml:"webhooks_cfg"`
+	JWTCfg          `yaml:"jwt_cfg"`
+	RedisCfg        `yaml:"redis_cfg"`
+	OAuthCfg        `yaml:"oauth_cfg"`
+	RateLimitCfg    `yaml:"rate_limit_cfg"`
+	LockoutCfg      `yaml:"lockout_cfg"`
+	MailerCfg       `yaml:"mailer_cfg"`
+	TracingCfg      `yaml:"tracing_cfg"`
+	HTTPServerCfg   `yaml:"http_server_cfg"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+// StorageCfg selects where the data lives. "postgres" keeps it in Postgres (StoragePath) and workout sessions,
+// rate limits and lockouts in Redis. "sqlite" keeps the data and workout sessions in the SQLite file at StoragePath,
+// rate limits and lockouts in memory, so it is a single process deployment. "memory" keeps everything in the process and loses it on restart,
+// it is meant for local development and tests.
+type StorageCfg struct {
+	Driver string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres"`
 }
 
 type SessionsCfg struct {
@@ -22,25 +45,107 @@ type SessionsCfg struct {
 	SchedulerInterval time.Duration `yaml:"scheduler_interval" env-required:"true"`
 }
 
+// SeasonsCfg sets how long a competitive season lasts. The seasonal points are reset when it ends,
+// the rollover is checked every SchedulerInterval.
+type SeasonsCfg struct {
+	Length time.Duration `yaml:"length" env-default:"2160h"`
+}
+
+// PlausibilityCfg configures the checks of logged sets. Sets heavier than the weight limit of the exercise
+// (DefaultWeightLimit kg for exercises without one) are excluded from points, sets whose estimated one rep max
+// is more than MaxJump times the user's best are held until a moderator reviews them.
+type PlausibilityCfg struct {
+	DefaultWeightLimit int     `yaml:"default_weight_limit" env-default:"500"`
+	MaxJump            float64 `yaml:"max_jump" env-default:"1.3"`
+}
+
+// WebhooksCfg configures the delivery of webhooks. Due deliveries are sent every Interval, BatchSize at a time,
+// each attempt waits RequestTimeout for the response. A failed delivery is retried after Backoff, doubled for every further attempt
+// up to MaxBackoff, and is dead after DeliveryAttempts attempts. A user manages at most MaxPerOwner webhooks.
+type WebhooksCfg struct {
+	Interval         time.Duration `yaml:"interval" env-default:"10s"`
+	RequestTimeout   time.Duration `yaml:"request_timeout" env-default:"5s"`
+	BatchSize        int           `yaml:"batch_size" env-default:"50"`
+	DeliveryAttempts int           `yaml:"delivery_attempts" env-default:"8"`
+	Backoff          time.Duration `yaml:"backoff" env-default:"30s"`
+	MaxBackoff       time.Duration `yaml:"max_backoff" env-default:"6h"`
+	MaxPerOwner      int           `yaml:"max_per_owner" env-default:"10"`
+}
+
 type JWTCfg struct {
 	JWTLifetime time.Duration `yaml:"jwt_lifetime" env-required:"true"`
 	SecretKey   string        `yaml:"secret_key" env-required:"true" env:"SECRET_KEY"`
 }
 
 type OAuthCfg struct {
-	GoogleKey    string `yaml:"google_key" env-required:"true" env:"GOOGLE_KEY"`
-	GoogleSecret string `yaml:"google_secret" env-required:"true" env:"GOOGLE_SECRET"`
+	BaseURL   string             `yaml:"base_url" env:"PUBLIC_BASE_URL"`
+	Providers []OAuthProviderCfg `yaml:"providers"`
+}
+
+// OAuthProviderCfg describes one OAuth/OIDC provider. Key and secret are usually kept out of the yaml file
+// and taken from OAUTH_<NAME>_KEY and OAUTH_<NAME>_SECRET environment variables. Providers without credentials are skipped.
+type OAuthProviderCfg struct {
+	Name         string   `yaml:"name"`
+	Type         string   `yaml:"type"`
+	Key          string   `yaml:"key"`
+	Secret       string   `yaml:"secret"`
+	DiscoveryURL string   `yaml:"discovery_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// RateLimitCfg holds limits per route group. Groups missing here are not limited.
+type RateLimitCfg struct {
+	Groups map[string]RateLimitRule `yaml:"groups"`
+}
+
+// RateLimitRule allows Limit requests per Window. KeyBy is "ip" or "user"
+// (requests without an authenticated user are counted by IP anyway).
+type RateLimitRule struct {
+	Limit  int           `yaml:"limit"`
+	Window time.Duration `yaml:"window"`
+	KeyBy  string        `yaml:"key_by"`
+}
+
+// LockoutCfg locks an account for BaseDuration after MaxAttempts failed logins within Window.
+// Every next lock lasts twice as long, up to MaxDuration.
+type LockoutCfg struct {
+	MaxAttempts  int           `yaml:"max_attempts" env-default:"5"`
+	Window       time.Duration `yaml:"window" env-default:"15m"`
+	BaseDuration time.Duration `yaml:"base_duration" env-default:"1m"`
+	MaxDuration  time.Duration `yaml:"max_duration" env-default:"24h"`
+}
+
+// MailerCfg configures SMTP. If SMTPHost is empty emails are only written to the log.
+type MailerCfg struct {
+	SMTPHost string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort int    `yaml:"smtp_port" env:"SMTP_PORT" env-default:"587"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+// TracingCfg configures OpenTelemetry. Exporter is "otlp", "stdout" or "none". The OTLP endpoint can also be set
+// with the standard OTEL_EXPORTER_OTLP_* variables, the stdout exporter writes to FilePath if it is set.
+type TracingCfg struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	FilePath    string  `yaml:"file_path" env:"TRACING_FILE_PATH"`
+	ServiceName string  `yaml:"service_name" env-default:"gymbro"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 }
 
 type RedisCfg struct {
-	RedisPath     string `yaml:"redis_path" env-required:"true" env:"REDIS_PATH"`
-	RedisPassword string `yaml:"redis_password" env-required:"true" env:"REDIS_PASSWORD"`
+	RedisPath     string `yaml:"redis_path" env:"REDIS_PATH"`
+	RedisPassword string `yaml:"redis_password" env:"REDIS_PASSWORD"`
 }
 
 type HTTPServerCfg struct {
 	Address     string        `yaml:"address" env-required:"true"`
 	Timeout     time.Duration `yaml:"timeout" env-required:"true"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-required:"true"`
+	// ShutdownDelay is how long /readyz reports failure before the listener is closed
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env-default:"5s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
 }
 
 func MustLoad() *Config {
@@ -58,5 +163,38 @@ func MustLoad() *Config {
 		log.Fatalf("%s: CONFIG_PATH read error: %v", configPath, err)
 	}
 
+	switch config.Driver {
+	case DriverPostgres:
+		if config.StoragePath == "" || config.RedisPath == "" || config.RedisPassword == "" {
+			log.Fatalf("%s: storage_path, redis_path and redis_password are required by the %s storage driver", configPath, DriverPostgres)
+		}
+	case DriverSQLite:
+		if config.StoragePath == "" {
+			log.Fatalf("%s: storage_path is required by the %s storage driver", configPath, DriverSQLite)
+		}
+	case DriverMemory:
+	default:
+		log.Fatalf("%s: unknown storage driver %q", configPath, config.Driver)
+	}
+
+	for i := range config.Providers {
+		provider := &config.Providers[i]
+		envPrefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(provider.Name, "-", "_"))
+		if provider.Key == "" {
+			provider.Key = os.Getenv(envPrefix + "_KEY")
+		}
+		if provider.Secret == "" {
+			provider.Secret = os.Getenv(envPrefix + "_SECRET")
+		}
+	}
+
 	return &config
 }
+
+// PublicBaseURL returns the URL the service is reachable at from the outside (used for OAuth callbacks).
+func (c *Config) PublicBaseURL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+	return "http://" + c.Address
+}
@@ -0,0 +1,146 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+const (
+	ChallengeUpcoming = "upcoming"
+	ChallengeActive   = "active"
+	// ChallengeSettling is a challenge that ended but is not settled yet, workouts still in progress can count.
+	ChallengeSettling = "settling"
+	ChallengeSettled  = "settled"
+)
+
+// ChallengeStatus tells where the challenge is at the given time.
+func ChallengeStatus(challenge *storage.Challenge, now time.Time) string {
+	switch {
+	case challenge.SettledAt != nil:
+		return ChallengeSettled
+	case now.Before(challenge.StartTime):
+		return ChallengeUpcoming
+	case now.Before(challenge.EndTime):
+		return ChallengeActive
+	default:
+		return ChallengeSettling
+	}
+}
+
+type ChallengeResponse struct {
+	ChallengeId string     `json:"challenge_id"`
+	Name        string     `json:"name"`
+	Goal        string     `json:"goal"`
+	ExerciseId  int        `json:"exercise_id,omitempty"`
+	Target      int        `json:"target"`
+	Reward      int        `json:"reward"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Status      string     `json:"status"`
+	SettledAt   *time.Time `json:"settled_at,omitempty"`
+}
+
+func NewChallengeResponse(challenge *storage.Challenge, now time.Time) ChallengeResponse {
+	return ChallengeResponse{
+		ChallengeId: challenge.ChallengeID,
+		Name:        challenge.Name,
+		Goal:        challenge.Goal,
+		ExerciseId:  challenge.FkExerciseId,
+		Target:      challenge.Target,
+		Reward:      challenge.Reward,
+		StartTime:   challenge.StartTime,
+		EndTime:     challenge.EndTime,
+		Status:      ChallengeStatus(challenge, now),
+		SettledAt:   challenge.SettledAt,
+	}
+}
+
+func NewChallengesResponse(challenges []*storage.Challenge, now time.Time) []ChallengeResponse {
+	res := make([]ChallengeResponse, 0, len(challenges))
+	for _, challenge := range challenges {
+		res = append(res, NewChallengeResponse(challenge, now))
+	}
+	return res
+}
+
+type ChallengeResultResponse struct {
+	ClanId    string `json:"clan_id"`
+	Progress  int    `json:"progress"`
+	Rank      int    `json:"rank"`
+	Completed bool   `json:"completed"`
+	Points    int    `json:"points"`
+}
+
+func NewChallengeResultResponse(result *storage.ChallengeResult) ChallengeResultResponse {
+	return ChallengeResultResponse{
+		ClanId:    result.FkClanId,
+		Progress:  result.Progress,
+		Rank:      result.Rank,
+		Completed: result.Completed,
+		Points:    result.Points,
+	}
+}
+
+// ChallengeProgressResponse is the live standings of a challenge, or the final ones once it is settled.
+// Clan is the entry of the viewer's clan, nil while it has no progress.
+type ChallengeProgressResponse struct {
+	Challenge ChallengeResponse         `json:"challenge"`
+	Clan      *ChallengeResultResponse  `json:"clan"`
+	Standings []ChallengeResultResponse `json:"standings"`
+}
+
+func NewChallengeProgressResponse(challenge *storage.Challenge, results []*storage.ChallengeResult, clanID string, now time.Time) ChallengeProgressResponse {
+	res := ChallengeProgressResponse{
+		Challenge: NewChallengeResponse(challenge, now),
+		Standings: make([]ChallengeResultResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		entry := NewChallengeResultResponse(result)
+		res.Standings = append(res.Standings, entry)
+		if result.FkClanId == clanID {
+			res.Clan = &entry
+		}
+	}
+	return res
+}
+
+// ClanResultResponse is the result of the clan in a settled challenge.
+type ClanResultResponse struct {
+	Challenge ChallengeResponse `json:"challenge"`
+	Progress  int               `json:"progress"`
+	Rank      int               `json:"rank"`
+	Completed bool              `json:"completed"`
+	Points    int               `json:"points"`
+}
+
+// ClanResultsResponse is the challenge history of a clan, the last ended first.
+type ClanResultsResponse struct {
+	ClanId  string               `json:"clan_id"`
+	Name    string               `json:"name"`
+	Points  int                  `json:"points"`
+	Results []ClanResultResponse `json:"results"`
+}
+
+// NewClanResultsResponse joins the results with their challenges, results of challenges missing from the map are left out.
+func NewClanResultsResponse(clan *storage.Clan, results []*storage.ChallengeResult, challenges map[string]*storage.Challenge, now time.Time) ClanResultsResponse {
+	res := ClanResultsResponse{
+		ClanId:  clan.ClanId,
+		Name:    clan.Name,
+		Points:  clan.Points,
+		Results: make([]ClanResultResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		challenge, ok := challenges[result.FkChallengeId]
+		if !ok {
+			continue
+		}
+		res.Results = append(res.Results, ClanResultResponse{
+			Challenge: NewChallengeResponse(challenge, now),
+			Progress:  result.Progress,
+			Rank:      result.Rank,
+			Completed: result.Completed,
+			Points:    result.Points,
+		})
+	}
+	return res
+}
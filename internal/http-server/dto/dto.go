@@ -0,0 +1,16 @@
+// Package dto holds the request and response bodies of the API, so the storage models are never
+// decoded from or encoded to clients directly. JSON names match the ones used before /api/v1.
+package dto
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Decode decodes the JSON request body into v. Unknown fields are rejected, so clients can't
+// expect fields that are assigned by the server (ids, points, roles) to be set.
+func Decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,78 @@
+package dto
+
+import "GYMBRO/internal/storage"
+
+type ExerciseRequest struct {
+	Name           string `json:"name" validate:"required"`
+	Description    string `json:"description"`
+	Picture        string `json:"picture"`
+	MuscleGroupIds []int  `json:"muscle_group_ids"`
+	// WeightLimit is the heaviest plausible weight in kg, 0 uses the default limit
+	WeightLimit int `json:"weight_limit" validate:"gte=0"`
+}
+
+func (r *ExerciseRequest) ToExercise() *storage.Exercise {
+	return &storage.Exercise{
+		Name:           r.Name,
+		Description:    r.Description,
+		Picture:        r.Picture,
+		MuscleGroupIds: r.MuscleGroupIds,
+		WeightLimit:    r.WeightLimit,
+	}
+}
+
+type ExerciseResponse struct {
+	ExerciseId     int    `json:"exercise_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Picture        string `json:"picture"`
+	MuscleGroupIds []int  `json:"muscle_group_ids"`
+	WeightLimit    int    `json:"weight_limit"`
+}
+
+func NewExerciseResponse(exercise *storage.Exercise) ExerciseResponse {
+	return ExerciseResponse{
+		ExerciseId:     exercise.ExerciseId,
+		Name:           exercise.Name,
+		Description:    exercise.Description,
+		Picture:        exercise.Picture,
+		MuscleGroupIds: exercise.MuscleGroupIds,
+		WeightLimit:    exercise.WeightLimit,
+	}
+}
+
+func NewExercisesResponse(exercises []*storage.Exercise) []ExerciseResponse {
+	res := make([]ExerciseResponse, 0, len(exercises))
+	for _, exercise := range exercises {
+		res = append(res, NewExerciseResponse(exercise))
+	}
+	return res
+}
+
+type MuscleGroupRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (r *MuscleGroupRequest) ToMuscleGroup() *storage.MuscleGroup {
+	return &storage.MuscleGroup{Name: r.Name}
+}
+
+type MuscleGroupResponse struct {
+	MuscleGroupId int    `json:"muscle_group_id"`
+	Name          string `json:"name"`
+}
+
+func NewMuscleGroupResponse(group *storage.MuscleGroup) MuscleGroupResponse {
+	return MuscleGroupResponse{
+		MuscleGroupId: group.MuscleGroupId,
+		Name:          group.Name,
+	}
+}
+
+func NewMuscleGroupsResponse(groups []*storage.MuscleGroup) []MuscleGroupResponse {
+	res := make([]MuscleGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		res = append(res, NewMuscleGroupResponse(group))
+	}
+	return res
+}
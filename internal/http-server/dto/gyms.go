@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+type SubscriptionResponse struct {
+	SubscriptionId string    `json:"subscription_id"`
+	UserId         string    `json:"user_id"`
+	GymId          int       `json:"gym_id"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewSubscriptionResponse(subscription *storage.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		SubscriptionId: subscription.SubscriptionId,
+		UserId:         subscription.FkUserId,
+		GymId:          subscription.FkGymId,
+		StartDate:      subscription.StartDate.UTC().Format(time.DateOnly),
+		EndDate:        subscription.EndDate.UTC().Format(time.DateOnly),
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
+
+// CheckInResponse carries the token to show as a QR code, gym staff scan it before it expires.
+type CheckInResponse struct {
+	CheckInId      string    `json:"check_in_id"`
+	GymId          int       `json:"gym_id"`
+	SubscriptionId string    `json:"subscription_id"`
+	ValidUntil     string    `json:"valid_until"`
+	Token          string    `json:"token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewCheckInResponse(checkIn *storage.CheckIn, subscription *storage.Subscription, token string, expiresAt time.Time) CheckInResponse {
+	return CheckInResponse{
+		CheckInId:      checkIn.CheckInID,
+		GymId:          checkIn.FkGymId,
+		SubscriptionId: subscription.SubscriptionId,
+		ValidUntil:     subscription.EndDate.UTC().Format(time.DateOnly),
+		Token:          token,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      checkIn.CreatedAt,
+	}
+}
+
+// CheckInVerificationResponse is what gym staff see after scanning a check-in QR code.
+type CheckInVerificationResponse struct {
+	CheckInId      string `json:"check_in_id"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+	GymId          int    `json:"gym_id"`
+	SubscriptionId string `json:"subscription_id"`
+	ValidUntil     string `json:"valid_until"`
+}
+
+func NewCheckInVerificationResponse(checkInID string, user *storage.User, subscription *storage.Subscription) CheckInVerificationResponse {
+	return CheckInVerificationResponse{
+		CheckInId:      checkInID,
+		UserId:         user.UserId,
+		Username:       user.Username,
+		GymId:          subscription.FkGymId,
+		SubscriptionId: subscription.SubscriptionId,
+		ValidUntil:     subscription.EndDate.UTC().Format(time.DateOnly),
+	}
+}
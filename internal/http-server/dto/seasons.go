@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+const (
+	SeasonActive = "active"
+	SeasonEnded  = "ended"
+)
+
+type SeasonResponse struct {
+	SeasonId  int        `json:"season_id"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Status    string     `json:"status"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+func NewSeasonResponse(season *storage.Season) SeasonResponse {
+	res := SeasonResponse{
+		SeasonId:  season.SeasonID,
+		StartTime: season.StartTime,
+		EndTime:   season.EndTime,
+		Status:    SeasonActive,
+		EndedAt:   season.EndedAt,
+	}
+	if season.EndedAt != nil {
+		res.Status = SeasonEnded
+	}
+	return res
+}
+
+func NewSeasonsResponse(seasons []*storage.Season) []SeasonResponse {
+	res := make([]SeasonResponse, 0, len(seasons))
+	for _, season := range seasons {
+		res = append(res, NewSeasonResponse(season))
+	}
+	return res
+}
+
+// StandingResponse is a user, clan or gym in the standings of a season. SubjectId is the ID of the user, clan or gym.
+type StandingResponse struct {
+	Rank      int    `json:"rank"`
+	SubjectId string `json:"subject_id"`
+	Name      string `json:"name"`
+	Points    int    `json:"points"`
+}
+
+// StandingsResponse is the live standings of the current season or the final ones of an ended season.
+type StandingsResponse struct {
+	Season    SeasonResponse     `json:"season"`
+	Kind      string             `json:"kind"`
+	Standings []StandingResponse `json:"standings"`
+}
+
+func NewStandingsResponse(season *storage.Season, kind string, standings []*storage.Standing) StandingsResponse {
+	res := StandingsResponse{
+		Season:    NewSeasonResponse(season),
+		Kind:      kind,
+		Standings: make([]StandingResponse, 0, len(standings)),
+	}
+	for _, standing := range standings {
+		res.Standings = append(res.Standings, StandingResponse{
+			Rank:      standing.Rank,
+			SubjectId: standing.SubjectID,
+			Name:      standing.Name,
+			Points:    standing.Points,
+		})
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+type FollowResponse struct {
+	FollowerId string    `json:"follower_id"`
+	FolloweeId string    `json:"followee_id"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewFollowResponse(follow *storage.Follow) FollowResponse {
+	return FollowResponse{
+		FollowerId: follow.FollowerID,
+		FolloweeId: follow.FolloweeID,
+		Status:     follow.Status,
+		CreatedAt:  follow.CreatedAt,
+	}
+}
+
+func NewFollowsResponse(follows []*storage.Follow) []FollowResponse {
+	res := make([]FollowResponse, 0, len(follows))
+	for _, follow := range follows {
+		res = append(res, NewFollowResponse(follow))
+	}
+	return res
+}
+
+// EventResponse is a feed entry. Only the fields of the event type are set.
+type EventResponse struct {
+	EventId     string               `json:"event_id"`
+	UserId      string               `json:"user_id"`
+	Type        string               `json:"type"`
+	WorkoutId   string               `json:"workout_id,omitempty"`
+	Points      int                  `json:"points,omitempty"`
+	ExerciseId  int                  `json:"exercise_id,omitempty"`
+	Weight      int                  `json:"weight,omitempty"`
+	Reps        int                  `json:"reps,omitempty"`
+	Achievement *AchievementResponse `json:"achievement,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+func NewEventResponse(event *storage.Event) EventResponse {
+	res := EventResponse{
+		EventId:    event.EventID,
+		UserId:     event.UserID,
+		Type:       event.Type,
+		WorkoutId:  event.WorkoutID,
+		Points:     event.Points,
+		ExerciseId: event.ExerciseID,
+		Weight:     event.Weight,
+		Reps:       event.Reps,
+		CreatedAt:  event.CreatedAt,
+	}
+	if event.Type == storage.EventAchievement {
+		achievement := NewAchievementResponse(&storage.Achievement{UserID: event.UserID, Code: event.Code, AwardedAt: event.CreatedAt})
+		res.Achievement = &achievement
+	}
+	return res
+}
+
+type FeedResponse struct {
+	Events []EventResponse `json:"events"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func NewFeedResponse(events []*storage.Event, nextCursor string) FeedResponse {
+	res := FeedResponse{Events: make([]EventResponse, 0, len(events)), NextCursor: nextCursor}
+	for _, event := range events {
+		res.Events = append(res.Events, NewEventResponse(event))
+	}
+	return res
+}
+
+// PresenceResponse is a user who is training right now.
+type PresenceResponse struct {
+	UserId        string    `json:"user_id"`
+	Username      string    `json:"username"`
+	TrainingSince time.Time `json:"training_since"`
+	LastUpdated   time.Time `json:"last_updated"`
+}
+
+func NewPresenceResponse(user *storage.User, session *storage.WorkoutSession) PresenceResponse {
+	return PresenceResponse{
+		UserId:        user.UserId,
+		Username:      user.Username,
+		TrainingSince: session.StartTime,
+		LastUpdated:   session.LastUpdated,
+	}
+}
+
+type GymPresenceResponse struct {
+	GymId  int `json:"gym_id"`
+	Active int `json:"active"`
+}
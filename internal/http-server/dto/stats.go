@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// MuscleGroupVolumeResponse is the volume of one muscle group in one period. Tonnage is the sum of reps * weight.
+type MuscleGroupVolumeResponse struct {
+	MuscleGroupId int    `json:"muscle_group_id"`
+	Name          string `json:"name"`
+	Sets          int    `json:"sets"`
+	Reps          int    `json:"reps"`
+	Tonnage       int    `json:"tonnage"`
+}
+
+// VolumePeriodResponse is the volume of every trained muscle group in the period starting at Start (YYYY-MM-DD).
+type VolumePeriodResponse struct {
+	Start        string                      `json:"start"`
+	Workouts     int                         `json:"workouts"`
+	MuscleGroups []MuscleGroupVolumeResponse `json:"muscle_groups"`
+}
+
+type VolumeResponse struct {
+	Period  string                 `json:"period"`
+	Periods []VolumePeriodResponse `json:"periods"`
+}
+
+type TopSetResponse struct {
+	Weight int `json:"weight"`
+	Reps   int `json:"reps"`
+}
+
+// ProgressPointResponse sums up the sets of an exercise in one workout or period. Start is the workout start time
+// or the start of the period, WorkoutID is only set per workout. Volume is the sum of reps * weight.
+type ProgressPointResponse struct {
+	Start        time.Time      `json:"start"`
+	WorkoutID    string         `json:"workout_id,omitempty"`
+	Workouts     int            `json:"workouts"`
+	Sets         int            `json:"sets"`
+	EstimatedMax float64        `json:"estimated_1rm"`
+	TopSet       TopSetResponse `json:"top_set"`
+	Volume       int            `json:"volume"`
+}
+
+type ProgressResponse struct {
+	ExerciseId int                     `json:"exercise_id"`
+	Period     string                  `json:"period"`
+	Progress   []ProgressPointResponse `json:"progress"`
+}
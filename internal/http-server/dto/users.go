@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"GYMBRO/internal/lib/achievements"
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+type RegisterRequest struct {
+	Username    string    `json:"username" validate:"required"`
+	Email       string    `json:"email" validate:"required,email"`
+	Password    string    `json:"password" validate:"required"`
+	DateOfBirth time.Time `json:"date_of_birth"`
+}
+
+func (r *RegisterRequest) ToUser() *storage.User {
+	return &storage.User{
+		Username:    r.Username,
+		Email:       r.Email,
+		Password:    r.Password,
+		DateOfBirth: r.DateOfBirth,
+	}
+}
+
+type IdentityResponse struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewIdentityResponse(identity *storage.Identity) IdentityResponse {
+	return IdentityResponse{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+func NewIdentitiesResponse(identities []*storage.Identity) []IdentityResponse {
+	res := make([]IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		res = append(res, NewIdentityResponse(identity))
+	}
+	return res
+}
+
+type AchievementResponse struct {
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	AwardedAt   time.Time `json:"awarded_at"`
+}
+
+// NewAchievementResponse describes the achievement by its rule. Codes without a rule keep only the code.
+func NewAchievementResponse(achievement *storage.Achievement) AchievementResponse {
+	rule, _ := achievements.Find(achievement.Code)
+	return AchievementResponse{
+		Code:        achievement.Code,
+		Name:        rule.Name,
+		Description: rule.Description,
+		AwardedAt:   achievement.AwardedAt,
+	}
+}
+
+type ProfileResponse struct {
+	UserId       string                `json:"user_id"`
+	Username     string                `json:"username"`
+	Email        string                `json:"email"`
+	Role         string                `json:"role"`
+	Points       int                   `json:"points"`
+	SeasonPoints int                   `json:"season_points"`
+	BodyWeight   int                   `json:"body_weight"`
+	IsPrivate    bool                  `json:"is_private"`
+	DateOfBirth  time.Time             `json:"date_of_birth"`
+	CreatedAt    time.Time             `json:"created_at"`
+	Achievements []AchievementResponse `json:"achievements"`
+}
+
+func NewProfileResponse(user *storage.User, achievements []*storage.Achievement) ProfileResponse {
+	res := ProfileResponse{
+		UserId:       user.UserId,
+		Username:     user.Username,
+		Email:        user.Email,
+		Role:         user.Role,
+		Points:       user.Points,
+		SeasonPoints: user.SeasonPoints,
+		BodyWeight:   user.BodyWeight,
+		IsPrivate:    user.IsPrivate,
+		DateOfBirth:  user.DateOfBirth,
+		CreatedAt:    user.CreatedAt,
+		Achievements: make([]AchievementResponse, 0, len(achievements)),
+	}
+	for _, achievement := range achievements {
+		res.Achievements = append(res.Achievements, NewAchievementResponse(achievement))
+	}
+	return res
+}
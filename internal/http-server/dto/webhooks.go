@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"encoding/json"
+	"time"
+)
+
+// WebhookResponse is a webhook, the secret is only shown when it is created.
+type WebhookResponse struct {
+	WebhookId string    `json:"webhook_id"`
+	Kind      string    `json:"kind"`
+	SubjectId string    `json:"subject_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhookResponse(webhook *storage.Webhook, withSecret bool) WebhookResponse {
+	res := WebhookResponse{
+		WebhookId: webhook.WebhookID,
+		Kind:      webhook.Kind,
+		SubjectId: webhook.SubjectID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+	if withSecret {
+		res.Secret = webhook.Secret
+	}
+	return res
+}
+
+func NewWebhooksResponse(webhooks []*storage.Webhook) []WebhookResponse {
+	res := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, NewWebhookResponse(webhook, false))
+	}
+	return res
+}
+
+// DeliveryResponse is a delivery of an event to a webhook, NextAttemptAt is only set while it is pending.
+type DeliveryResponse struct {
+	DeliveryId     string          `json:"delivery_id"`
+	WebhookId      string          `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func NewDeliveryResponse(delivery *storage.WebhookDelivery) DeliveryResponse {
+	res := DeliveryResponse{
+		DeliveryId:     delivery.DeliveryID,
+		WebhookId:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+	if delivery.Status == storage.DeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		res.NextAttemptAt = &nextAttemptAt
+	}
+	return res
+}
+
+func NewDeliveriesResponse(deliveries []*storage.WebhookDelivery) []DeliveryResponse {
+	res := make([]DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, NewDeliveryResponse(delivery))
+	}
+	return res
+}
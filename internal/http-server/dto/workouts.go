@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"GYMBRO/internal/storage"
+	"time"
+)
+
+type RecordRequest struct {
+	FkExerciseId int `json:"fk_exercise_id" validate:"required"`
+	Reps         int `json:"reps" validate:"required,gte=1"`
+	Weight       int `json:"weight" validate:"required,gte=1"`
+}
+
+func (r *RecordRequest) ToRecord() *storage.Record {
+	return &storage.Record{
+		FkExerciseId: r.FkExerciseId,
+		Reps:         r.Reps,
+		Weight:       r.Weight,
+	}
+}
+
+type RecordResponse struct {
+	RecordId     string `json:"record_id"`
+	FkWorkoutId  string `json:"fk_workout_id"`
+	FkExerciseId int    `json:"fk_exercise_id"`
+	Reps         int    `json:"reps"`
+	Weight       int    `json:"weight"`
+	Points       int    `json:"points"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+func NewRecordResponse(record *storage.Record) RecordResponse {
+	status := record.Status
+	if status == "" {
+		status = storage.RecordOK
+	}
+	return RecordResponse{
+		RecordId:     record.RecordId,
+		FkWorkoutId:  record.FkWorkoutId,
+		FkExerciseId: record.FkExerciseId,
+		Reps:         record.Reps,
+		Weight:       record.Weight,
+		Points:       record.Points,
+		Status:       status,
+		Reason:       record.Reason,
+	}
+}
+
+func NewRecordsResponse(records []storage.Record) []RecordResponse {
+	res := make([]RecordResponse, 0, len(records))
+	for i := range records {
+		res = append(res, NewRecordResponse(&records[i]))
+	}
+	return res
+}
+
+// FlaggedRecordResponse is a record waiting for a moderator with the user and the start of the workout it belongs to.
+type FlaggedRecordResponse struct {
+	RecordResponse
+	UserID    string    `json:"user_id"`
+	StartTime time.Time `json:"start_time"`
+}
+
+func NewFlaggedRecordResponse(record *storage.FlaggedRecord) FlaggedRecordResponse {
+	return FlaggedRecordResponse{
+		RecordResponse: NewRecordResponse(&record.Record),
+		UserID:         record.UserID,
+		StartTime:      record.StartTime,
+	}
+}
+
+func NewFlaggedRecordsResponse(records []*storage.FlaggedRecord) []FlaggedRecordResponse {
+	res := make([]FlaggedRecordResponse, 0, len(records))
+	for _, record := range records {
+		res = append(res, NewFlaggedRecordResponse(record))
+	}
+	return res
+}
+
+type WorkoutResponse struct {
+	UserID    string           `json:"user_id"`
+	WorkoutID string           `json:"session_id"`
+	StartTime time.Time        `json:"start_time"`
+	EndTime   time.Time        `json:"end_time"`
+	Records   []RecordResponse `json:"records"`
+	Points    int              `json:"points"`
+	GymID     int              `json:"gym_id,omitempty"`
+}
+
+func NewWorkoutResponse(workout *storage.WorkoutWithRecords) WorkoutResponse {
+	return WorkoutResponse{
+		UserID:    workout.UserID,
+		WorkoutID: workout.WorkoutID,
+		StartTime: workout.StartTime,
+		EndTime:   workout.EndTime,
+		Records:   NewRecordsResponse(workout.Records),
+		Points:    workout.Points,
+		GymID:     workout.GymID,
+	}
+}
+
+type SessionResponse struct {
+	UserID      string           `json:"user_id"`
+	SessionID   string           `json:"session_id"`
+	StartTime   time.Time        `json:"start_time"`
+	LastUpdated time.Time        `json:"last_updated"`
+	Records     []RecordResponse `json:"records"`
+	Points      int              `json:"points"`
+	GymID       int              `json:"gym_id,omitempty"`
+}
+
+func NewSessionResponse(session *storage.WorkoutSession) SessionResponse {
+	return SessionResponse{
+		UserID:      session.UserID,
+		SessionID:   session.SessionID,
+		StartTime:   session.StartTime,
+		LastUpdated: session.LastUpdated,
+		Records:     NewRecordsResponse(session.Records),
+		Points:      session.Points,
+		GymID:       session.GymID,
+	}
+}
@@ -0,0 +1,66 @@
+package challenges
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Request is a challenge for the workouts started from StartTime up to EndTime, ExerciseId is only used by tonnage goals.
+type Request struct {
+	Name       string    `json:"name" validate:"required,max=100"`
+	Goal       string    `json:"goal" validate:"required,oneof=points tonnage workouts"`
+	ExerciseId int       `json:"exercise_id" validate:"required_if=Goal tonnage,gte=0"`
+	Target     int       `json:"target" validate:"required,gt=0"`
+	Reward     int       `json:"reward" validate:"gte=0"`
+	StartTime  time.Time `json:"start_time" validate:"required"`
+	EndTime    time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+}
+
+// NewCreateHandler creates an HTTP handler that creates a clan challenge, it is settled by the scheduler after it ends. (1 challengeRepo call)
+func NewCreateHandler(log *slog.Logger, challengeRepo storage.ChallengeRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.challenges.NewCreate"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		var request Request
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors, times are RFC 3339")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		challenge := &storage.Challenge{
+			ChallengeID: storage.GenerateUID(),
+			Name:        request.Name,
+			Goal:        request.Goal,
+			Target:      request.Target,
+			Reward:      request.Reward,
+			StartTime:   request.StartTime.UTC(),
+			EndTime:     request.EndTime.UTC(),
+			CreatedAt:   time.Now().UTC(),
+		}
+		if request.Goal == storage.GoalTonnage {
+			challenge.FkExerciseId = request.ExerciseId
+		}
+		if err := challengeRepo.CreateChallenge(r.Context(), challenge); err != nil {
+			log.Debug("Failed to CREATE challenge", slog.Any("error", err))
+			return err
+		}
+
+		log.Info("Challenge created", slog.String("challenge_id", challenge.ChallengeID), slog.String("goal", challenge.Goal))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewChallengeResponse(challenge, challenge.CreatedAt)))
+		return nil
+	})
+}
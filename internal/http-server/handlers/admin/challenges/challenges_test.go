@@ -0,0 +1,154 @@
+package challenges_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/challenges"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	tonnage := challenges.Request{Name: "Squat week", Goal: storage.GoalTonnage, ExerciseId: 3, Target: 50000, Reward: 100, StartTime: start, EndTime: end}
+	points := challenges.Request{Name: "Points week", Goal: storage.GoalPoints, ExerciseId: 3, Target: 1000, Reward: 50, StartTime: start, EndTime: end}
+
+	challenge := func(goal string, exerciseID int) interface{} {
+		return mock.MatchedBy(func(c *storage.Challenge) bool {
+			return c.ChallengeID != "" && c.Goal == goal && c.FkExerciseId == exerciseID && c.SettledAt == nil &&
+				c.StartTime.Equal(start) && c.EndTime.Equal(end)
+		})
+	}
+
+	tests := []struct {
+		name               string
+		requestBody        interface{}
+		setupMock          func(challengeRepo *mocks.ChallengeRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:        "Tonnage",
+			requestBody: tonnage,
+			setupMock: func(challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("CreateChallenge", mock.Anything, challenge(storage.GoalTonnage, 3)).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			// the exercise only matters for tonnage goals
+			name:        "PointsIgnoreExercise",
+			requestBody: points,
+			setupMock: func(challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("CreateChallenge", mock.Anything, challenge(storage.GoalPoints, 0)).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "TonnageWithoutExercise",
+			requestBody:        challenges.Request{Name: "Squat week", Goal: storage.GoalTonnage, Target: 50000, StartTime: start, EndTime: end},
+			setupMock:          func(challengeRepo *mocks.ChallengeRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "UnknownGoal",
+			requestBody:        challenges.Request{Name: "Run week", Goal: "distance", Target: 100, StartTime: start, EndTime: end},
+			setupMock:          func(challengeRepo *mocks.ChallengeRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "EndsBeforeStart",
+			requestBody:        challenges.Request{Name: "Points week", Goal: storage.GoalPoints, Target: 1000, StartTime: end, EndTime: start},
+			setupMock:          func(challengeRepo *mocks.ChallengeRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "ZeroTarget",
+			requestBody:        challenges.Request{Name: "Points week", Goal: storage.GoalPoints, StartTime: start, EndTime: end},
+			setupMock:          func(challengeRepo *mocks.ChallengeRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "InvalidTime",
+			requestBody:        map[string]interface{}{"name": "Points week", "goal": "points", "target": 1000, "start_time": "2024-03-01", "end_time": "2024-03-08"},
+			setupMock:          func(challengeRepo *mocks.ChallengeRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "ExerciseNotFound",
+			requestBody: tonnage,
+			setupMock: func(challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("CreateChallenge", mock.Anything, challenge(storage.GoalTonnage, 3)).Return(storage.ErrExerciseNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:        "InternalError",
+			requestBody: points,
+			setupMock: func(challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("CreateChallenge", mock.Anything, challenge(storage.GoalPoints, 0)).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challengeRepo := mocks.NewChallengeRepository(t)
+			tt.setupMock(challengeRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Post("/admin/challenges", challenges.NewCreateHandler(logger, challengeRepo))
+
+			b, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/admin/challenges", bytes.NewReader(b))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			challengeRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package exercises
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// NewListHandler creates an HTTP handler that lists all exercises with their muscle groups. (1 exerciseRepo call)
+func NewListHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.exercises.NewList"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		exercises, err := exerciseRepo.GetExercises(r.Context())
+		if err != nil {
+			log.Error("Failed to GET exercises", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewExercisesResponse(exercises)))
+		return nil
+	})
+}
+
+// NewCreateHandler creates an HTTP handler that creates an exercise and links it to muscle groups. (1 exerciseRepo call)
+func NewCreateHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.exercises.NewCreate"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		var request dto.ExerciseRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors, exercise_id is assigned automatically")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+		exercise := request.ToExercise()
+
+		id, err := exerciseRepo.CreateExercise(r.Context(), exercise)
+		if err != nil {
+			return exerciseError(log, err)
+		}
+		exercise.ExerciseId = *id
+
+		log.Info("Exercise created", slog.Int("exercise_id", *id))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewExerciseResponse(exercise)))
+		return nil
+	})
+}
+
+// NewUpdateHandler creates an HTTP handler that updates an exercise and replaces its muscle groups. (1 exerciseRepo call)
+func NewUpdateHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.exercises.NewUpdate"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		exerciseID, err := strconv.Atoi(chi.URLParam(r, "exerciseID"))
+		if err != nil {
+			log.Debug("Invalid exercise ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid exercise ID", "Exercise ID should be a number")
+		}
+
+		var request dto.ExerciseRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+		exercise := request.ToExercise()
+		exercise.ExerciseId = exerciseID
+
+		if err := exerciseRepo.UpdateExercise(r.Context(), exercise); err != nil {
+			return exerciseError(log, err)
+		}
+
+		log.Info("Exercise updated", slog.Int("exercise_id", exerciseID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewExerciseResponse(exercise)))
+		return nil
+	})
+}
+
+// NewDeleteHandler creates an HTTP handler that deletes an exercise nobody used, exercises with records, maxes
+// or challenges are kept. (1 exerciseRepo call)
+func NewDeleteHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.exercises.NewDelete"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		exerciseID, err := strconv.Atoi(chi.URLParam(r, "exerciseID"))
+		if err != nil {
+			log.Debug("Invalid exercise ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid exercise ID", "Exercise ID should be a number")
+		}
+
+		if err := exerciseRepo.DeleteExercise(r.Context(), &exerciseID); err != nil {
+			return exerciseError(log, err)
+		}
+
+		log.Info("Exercise deleted", slog.Int("exercise_id", exerciseID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// exerciseError keeps storage errors for the mapping in resp, except for unknown muscle groups,
+// which come from the request body and are a bad request here.
+func exerciseError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrExerciseNotFound), errors.Is(err, storage.ErrExerciseExists), errors.Is(err, storage.ErrExerciseInUse):
+		log.Debug("Exercise not saved", slog.Any("error", err))
+		return err
+	case errors.Is(err, storage.ErrMuscleGroupNotFound):
+		log.Debug("Muscle group not found")
+		return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Muscle group not found", "Check muscle_group_ids")
+	default:
+		log.Error("Failed to SAVE exercise", slog.Any("error", err))
+		return resp.Internal(err)
+	}
+}
@@ -0,0 +1,211 @@
+package exercises_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/admin/exercises"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExercisesHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	newID := 7
+	exerciseID := 3
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		requestBody        interface{}
+		setupMock          func(exerciseRepo *mocks.ExerciseRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:   "ListSuccess",
+			method: "GET",
+			url:    "/admin/exercises",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("GetExercises", mock.Anything).Return([]*storage.Exercise{{ExerciseId: 1, Name: "Bench press", MuscleGroupIds: []int{1}}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "CreateSuccess",
+			method: "POST",
+			url:    "/admin/exercises",
+			requestBody: dto.ExerciseRequest{
+				Name:           "Squat",
+				MuscleGroupIds: []int{2, 3},
+			},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("CreateExercise", mock.Anything, mock.MatchedBy(func(e *storage.Exercise) bool {
+					return e.Name == "Squat" && len(e.MuscleGroupIds) == 2
+				})).Return(&newID, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "CreateWithoutName",
+			method:             "POST",
+			url:                "/admin/exercises",
+			requestBody:        dto.ExerciseRequest{MuscleGroupIds: []int{2}},
+			setupMock:          func(exerciseRepo *mocks.ExerciseRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "CreateWithID",
+			method:             "POST",
+			url:                "/admin/exercises",
+			requestBody:        map[string]interface{}{"exercise_id": 5, "name": "Squat"},
+			setupMock:          func(exerciseRepo *mocks.ExerciseRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "CreateDuplicate",
+			method:      "POST",
+			url:         "/admin/exercises",
+			requestBody: dto.ExerciseRequest{Name: "Squat"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("CreateExercise", mock.Anything, mock.Anything).Return(nil, storage.ErrExerciseExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAlreadyExists},
+		},
+		{
+			name:        "CreateUnknownMuscleGroup",
+			method:      "POST",
+			url:         "/admin/exercises",
+			requestBody: dto.ExerciseRequest{Name: "Squat", MuscleGroupIds: []int{99}},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("CreateExercise", mock.Anything, mock.Anything).Return(nil, storage.ErrMuscleGroupNotFound)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "UpdateSuccess",
+			method:      "PUT",
+			url:         "/admin/exercises/3",
+			requestBody: dto.ExerciseRequest{Name: "Front squat"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("UpdateExercise", mock.Anything, mock.MatchedBy(func(e *storage.Exercise) bool {
+					return e.ExerciseId == exerciseID && e.Name == "Front squat"
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "UpdateNotFound",
+			method:      "PUT",
+			url:         "/admin/exercises/3",
+			requestBody: dto.ExerciseRequest{Name: "Front squat"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("UpdateExercise", mock.Anything, mock.Anything).Return(storage.ErrExerciseNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:               "UpdateInvalidID",
+			method:             "PUT",
+			url:                "/admin/exercises/abc",
+			requestBody:        dto.ExerciseRequest{Name: "Front squat"},
+			setupMock:          func(exerciseRepo *mocks.ExerciseRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "DeleteSuccess",
+			method: "DELETE",
+			url:    "/admin/exercises/3",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("DeleteExercise", mock.Anything, &exerciseID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "DeleteInUse",
+			method: "DELETE",
+			url:    "/admin/exercises/3",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("DeleteExercise", mock.Anything, &exerciseID).Return(storage.ErrExerciseInUse)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeExerciseInUse},
+		},
+		{
+			name:   "DeleteInternalError",
+			method: "DELETE",
+			url:    "/admin/exercises/3",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("DeleteExercise", mock.Anything, &exerciseID).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exerciseRepo := mocks.NewExerciseRepository(t)
+			tt.setupMock(exerciseRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Get("/admin/exercises", exercises.NewListHandler(logger, exerciseRepo))
+			r.Post("/admin/exercises", exercises.NewCreateHandler(logger, exerciseRepo))
+			r.Put("/admin/exercises/{exerciseID}", exercises.NewUpdateHandler(logger, exerciseRepo))
+			r.Delete("/admin/exercises/{exerciseID}", exercises.NewDeleteHandler(logger, exerciseRepo))
+
+			var body io.Reader
+			if tt.requestBody != nil {
+				b, _ := json.Marshal(tt.requestBody)
+				body = bytes.NewReader(b)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			exerciseRepo.AssertExpectations(t)
+		})
+	}
+}
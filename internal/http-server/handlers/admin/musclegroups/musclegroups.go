@@ -0,0 +1,131 @@
+package musclegroups
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// NewListHandler creates an HTTP handler that lists all muscle groups. (1 exerciseRepo call)
+func NewListHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.musclegroups.NewList"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		groups, err := exerciseRepo.GetMuscleGroups(r.Context())
+		if err != nil {
+			log.Error("Failed to GET muscle groups", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewMuscleGroupsResponse(groups)))
+		return nil
+	})
+}
+
+// NewCreateHandler creates an HTTP handler that creates a muscle group. (1 exerciseRepo call)
+func NewCreateHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.musclegroups.NewCreate"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		var request dto.MuscleGroupRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+		group := request.ToMuscleGroup()
+
+		id, err := exerciseRepo.CreateMuscleGroup(r.Context(), group)
+		if err != nil {
+			return muscleGroupError(log, err)
+		}
+		group.MuscleGroupId = *id
+
+		log.Info("Muscle group created", slog.Int("muscle_group_id", *id))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewMuscleGroupResponse(group)))
+		return nil
+	})
+}
+
+// NewUpdateHandler creates an HTTP handler that renames a muscle group. (1 exerciseRepo call)
+func NewUpdateHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.musclegroups.NewUpdate"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		groupID, err := strconv.Atoi(chi.URLParam(r, "muscleGroupID"))
+		if err != nil {
+			log.Debug("Invalid muscle group ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid muscle group ID", "Muscle group ID should be a number")
+		}
+
+		var request dto.MuscleGroupRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+		group := request.ToMuscleGroup()
+		group.MuscleGroupId = groupID
+
+		if err := exerciseRepo.UpdateMuscleGroup(r.Context(), group); err != nil {
+			return muscleGroupError(log, err)
+		}
+
+		log.Info("Muscle group updated", slog.Int("muscle_group_id", groupID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewMuscleGroupResponse(group)))
+		return nil
+	})
+}
+
+// NewDeleteHandler creates an HTTP handler that deletes a muscle group. (1 exerciseRepo call)
+func NewDeleteHandler(log *slog.Logger, exerciseRepo storage.ExerciseRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.musclegroups.NewDelete"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		groupID, err := strconv.Atoi(chi.URLParam(r, "muscleGroupID"))
+		if err != nil {
+			log.Debug("Invalid muscle group ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid muscle group ID", "Muscle group ID should be a number")
+		}
+
+		if err := exerciseRepo.DeleteMuscleGroup(r.Context(), &groupID); err != nil {
+			return muscleGroupError(log, err)
+		}
+
+		log.Info("Muscle group deleted", slog.Int("muscle_group_id", groupID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// muscleGroupError logs unexpected errors, the known ones are mapped by resp.
+func muscleGroupError(log *slog.Logger, err error) error {
+	if errors.Is(err, storage.ErrMuscleGroupNotFound) || errors.Is(err, storage.ErrMuscleGroupExists) {
+		log.Debug("Muscle group not saved", slog.Any("error", err))
+		return err
+	}
+	log.Error("Failed to SAVE muscle group", slog.Any("error", err))
+	return resp.Internal(err)
+}
@@ -0,0 +1,177 @@
+package musclegroups_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/admin/musclegroups"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMuscleGroupsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	newID := 4
+	groupID := 2
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		requestBody        interface{}
+		setupMock          func(exerciseRepo *mocks.ExerciseRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:   "ListSuccess",
+			method: "GET",
+			url:    "/admin/muscle-groups",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("GetMuscleGroups", mock.Anything).Return([]*storage.MuscleGroup{{MuscleGroupId: 1, Name: "Chest"}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "CreateSuccess",
+			method:      "POST",
+			url:         "/admin/muscle-groups",
+			requestBody: dto.MuscleGroupRequest{Name: "Legs"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("CreateMuscleGroup", mock.Anything, mock.MatchedBy(func(g *storage.MuscleGroup) bool {
+					return g.Name == "Legs"
+				})).Return(&newID, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "CreateWithoutName",
+			method:             "POST",
+			url:                "/admin/muscle-groups",
+			requestBody:        dto.MuscleGroupRequest{},
+			setupMock:          func(exerciseRepo *mocks.ExerciseRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:        "CreateDuplicate",
+			method:      "POST",
+			url:         "/admin/muscle-groups",
+			requestBody: dto.MuscleGroupRequest{Name: "Legs"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("CreateMuscleGroup", mock.Anything, mock.Anything).Return(nil, storage.ErrMuscleGroupExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAlreadyExists},
+		},
+		{
+			name:        "UpdateSuccess",
+			method:      "PUT",
+			url:         "/admin/muscle-groups/2",
+			requestBody: dto.MuscleGroupRequest{Name: "Back"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("UpdateMuscleGroup", mock.Anything, mock.MatchedBy(func(g *storage.MuscleGroup) bool {
+					return g.MuscleGroupId == groupID && g.Name == "Back"
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "UpdateNotFound",
+			method:      "PUT",
+			url:         "/admin/muscle-groups/2",
+			requestBody: dto.MuscleGroupRequest{Name: "Back"},
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("UpdateMuscleGroup", mock.Anything, mock.Anything).Return(storage.ErrMuscleGroupNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:               "DeleteInvalidID",
+			method:             "DELETE",
+			url:                "/admin/muscle-groups/abc",
+			setupMock:          func(exerciseRepo *mocks.ExerciseRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "DeleteSuccess",
+			method: "DELETE",
+			url:    "/admin/muscle-groups/2",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("DeleteMuscleGroup", mock.Anything, &groupID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "DeleteInternalError",
+			method: "DELETE",
+			url:    "/admin/muscle-groups/2",
+			setupMock: func(exerciseRepo *mocks.ExerciseRepository) {
+				exerciseRepo.On("DeleteMuscleGroup", mock.Anything, &groupID).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exerciseRepo := mocks.NewExerciseRepository(t)
+			tt.setupMock(exerciseRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Get("/admin/muscle-groups", musclegroups.NewListHandler(logger, exerciseRepo))
+			r.Post("/admin/muscle-groups", musclegroups.NewCreateHandler(logger, exerciseRepo))
+			r.Put("/admin/muscle-groups/{muscleGroupID}", musclegroups.NewUpdateHandler(logger, exerciseRepo))
+			r.Delete("/admin/muscle-groups/{muscleGroupID}", musclegroups.NewDeleteHandler(logger, exerciseRepo))
+
+			var body io.Reader
+			if tt.requestBody != nil {
+				b, _ := json.Marshal(tt.requestBody)
+				body = bytes.NewReader(b)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			exerciseRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package records
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// NewFlaggedHandler creates an HTTP handler that lists saved records that are held or excluded, the oldest workout first.
+// The limit query parameter caps the list. (1 moderationRepo call)
+func NewFlaggedHandler(log *slog.Logger, moderationRepo storage.ModerationRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.records.NewFlagged"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		limit := DefaultLimit
+		if param := r.URL.Query().Get("limit"); param != "" {
+			parsed, err := strconv.Atoi(param)
+			if err != nil || parsed < 1 || parsed > MaxLimit {
+				log.Debug("Invalid limit", slog.String("limit", param))
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid limit", "Use a number from 1 to "+strconv.Itoa(MaxLimit))
+			}
+			limit = parsed
+		}
+
+		records, err := moderationRepo.GetFlaggedRecords(r.Context(), limit)
+		if err != nil {
+			log.Error("Failed to GET flagged records", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFlaggedRecordsResponse(records)))
+		return nil
+	})
+}
+
+// NewApproveHandler creates an HTTP handler that approves the held record from the URL, its points are added to the workout
+// and the user. A record that beats the max of the user gets the PR bonus, the feed event and the webhook. (1 approval)
+func NewApproveHandler(log *slog.Logger, finalizer *finalizer.Finalizer) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.records.NewApprove"
+		recordID := chi.URLParam(r, "recordID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("record_id", recordID))
+
+		record, err := finalizer.Approve(r.Context(), log, &recordID)
+		if err != nil {
+			return recordError(log, err)
+		}
+
+		metrics.RecordsReviewed.WithLabelValues("approve").Inc()
+		log.Info("Record approved", slog.String("target_id", record.UserID), slog.Int("points", record.Points))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFlaggedRecordResponse(record)))
+		return nil
+	})
+}
+
+// NewVoidHandler creates an HTTP handler that voids the record from the URL. Points it earned are taken back
+// from the workout and the user and the max of its exercise is rebuilt. (1 moderationRepo call)
+func NewVoidHandler(log *slog.Logger, moderationRepo storage.ModerationRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.records.NewVoid"
+		recordID := chi.URLParam(r, "recordID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("record_id", recordID))
+
+		record, err := moderationRepo.VoidRecord(r.Context(), &recordID)
+		if err != nil {
+			return recordError(log, err)
+		}
+
+		metrics.RecordsReviewed.WithLabelValues("void").Inc()
+		log.Info("Record voided", slog.String("target_id", record.UserID), slog.Int("points", record.Points))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFlaggedRecordResponse(record)))
+		return nil
+	})
+}
+
+// recordError logs unexpected errors, the review errors are mapped by resp.
+func recordError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrRecordNotFound), errors.Is(err, storage.ErrRecordNotHeld), errors.Is(err, storage.ErrRecordVoided):
+		log.Debug("Record not reviewed", slog.Any("error", err))
+		return err
+	default:
+		log.Error("Failed to REVIEW record", slog.Any("error", err))
+		return resp.Internal(err)
+	}
+}
@@ -0,0 +1,259 @@
+package records_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/records"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	achievementsmocks "GYMBRO/internal/lib/achievements/mocks"
+	eventsmocks "GYMBRO/internal/lib/events/mocks"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/webhooks"
+	webhooksmocks "GYMBRO/internal/lib/webhooks/mocks"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecordsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	recordIDValue := "record1"
+	recordID := &recordIDValue
+	held := &storage.FlaggedRecord{
+		Record: storage.Record{
+			RecordId:     "record1",
+			FkWorkoutId:  "workout1",
+			FkExerciseId: 1,
+			Reps:         5,
+			Weight:       200,
+			Points:       100,
+			Status:       storage.RecordHeld,
+			Reason:       "estimated 1RM of 233 kg is 2.0x the best of 117 kg",
+		},
+		UserID:    "user123",
+		StartTime: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	approved := *held
+	approved.Status = storage.RecordOK
+	// the PR bonus of 50 is added to the record that becomes the max
+	approvedPR := approved
+	approvedPR.Points += 50
+	newMax := &storage.Max{UserID: "user123", ExerciseId: 1, MaxWeight: 200, Reps: 5}
+	voided := *held
+	voided.Status = storage.RecordVoided
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		setupMock          func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+		expectedStatus     string
+		expectedPoints     int
+	}{
+		{
+			name:   "ListSuccess",
+			method: "GET",
+			url:    "/admin/records/flagged",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("GetFlaggedRecords", mock.Anything, records.DefaultLimit).Return([]*storage.FlaggedRecord{held}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "ListWithLimit",
+			method: "GET",
+			url:    "/admin/records/flagged?limit=10",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("GetFlaggedRecords", mock.Anything, 10).Return([]*storage.FlaggedRecord{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "ListInvalidLimit",
+			method: "GET",
+			url:    "/admin/records/flagged?limit=1000",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "ListInternalError",
+			method: "GET",
+			url:    "/admin/records/flagged",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("GetFlaggedRecords", mock.Anything, records.DefaultLimit).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:   "ApproveSuccess",
+			method: "POST",
+			url:    "/admin/records/record1/approve",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("ApproveRecord", mock.Anything, recordID, 50).Return(&approved, nil, nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.MatchedBy(func(events []*storage.Event) bool { return len(events) == 0 })).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(messages []*webhooks.Message) bool { return len(messages) == 0 })).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordOK,
+			expectedPoints:     100,
+		},
+		{
+			name:   "ApproveNewMax",
+			method: "POST",
+			url:    "/admin/records/record1/approve",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("ApproveRecord", mock.Anything, recordID, 50).Return(&approvedPR, newMax, nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return([]*storage.Achievement{{Code: "tonnage_10t"}}, nil)
+				emitter.On("Emit", mock.Anything, mock.MatchedBy(func(events []*storage.Event) bool {
+					return len(events) == 2 && events[0].Type == storage.EventPersonalRecord && events[0].WorkoutID == "workout1" &&
+						events[0].ExerciseID == 1 && events[0].Weight == 200 && events[0].Reps == 5 &&
+						events[1].Type == storage.EventAchievement && events[1].Code == "tonnage_10t"
+				})).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(messages []*webhooks.Message) bool {
+					return len(messages) == 1 && messages[0].Event == storage.WebhookPRAchieved && messages[0].UserID == "user123" &&
+						messages[0].Data == webhooks.RecordData{UserID: "user123", WorkoutID: "workout1", ExerciseID: 1, Weight: 200, Reps: 5}
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordOK,
+			expectedPoints:     150,
+		},
+		{
+			name:   "ApproveNotifyError",
+			method: "POST",
+			url:    "/admin/records/record1/approve",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("ApproveRecord", mock.Anything, recordID, 50).Return(&approvedPR, newMax, nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, errors.New("db error"))
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(errors.New("db error"))
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordOK,
+		},
+		{
+			name:   "ApproveNotHeld",
+			method: "POST",
+			url:    "/admin/records/record1/approve",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("ApproveRecord", mock.Anything, recordID, 50).Return(nil, nil, storage.ErrRecordNotHeld)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeRecordReviewed},
+		},
+		{
+			name:   "ApproveNotFound",
+			method: "POST",
+			url:    "/admin/records/record1/approve",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("ApproveRecord", mock.Anything, recordID, 50).Return(nil, nil, storage.ErrRecordNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "VoidSuccess",
+			method: "POST",
+			url:    "/admin/records/record1/void",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("VoidRecord", mock.Anything, recordID).Return(&voided, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordVoided,
+		},
+		{
+			name:   "VoidTwice",
+			method: "POST",
+			url:    "/admin/records/record1/void",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("VoidRecord", mock.Anything, recordID).Return(nil, storage.ErrRecordVoided)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeRecordReviewed},
+		},
+		{
+			name:   "VoidInternalError",
+			method: "POST",
+			url:    "/admin/records/record1/void",
+			setupMock: func(moderationRepo *mocks.ModerationRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				moderationRepo.On("VoidRecord", mock.Anything, recordID).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderationRepo := mocks.NewModerationRepository(t)
+			evaluator := achievementsmocks.NewEvaluator(t)
+			emitter := eventsmocks.NewEmitter(t)
+			notifier := webhooksmocks.NewNotifier(t)
+			tt.setupMock(moderationRepo, evaluator, emitter, notifier)
+			finalizer := finalizer.New(mocks.NewSessionRepository(t), mocks.NewWorkoutRepository(t), mocks.NewUserRepository(t), moderationRepo, evaluator, emitter, notifier)
+
+			r := chi.NewRouter()
+			r.Get("/admin/records/flagged", records.NewFlaggedHandler(logger, moderationRepo))
+			r.Post("/admin/records/{recordID}/approve", records.NewApproveHandler(logger, finalizer))
+			r.Post("/admin/records/{recordID}/void", records.NewVoidHandler(logger, moderationRepo))
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "moderator123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data json.RawMessage `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			if tt.expectedStatus != "" {
+				var record struct {
+					Status string `json:"status"`
+					Points int    `json:"points"`
+				}
+				require.NoError(t, json.Unmarshal(response.Data, &record))
+				require.Equal(t, tt.expectedStatus, record.Status)
+				if tt.expectedPoints != 0 {
+					require.Equal(t, tt.expectedPoints, record.Points)
+				}
+			}
+
+			moderationRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package sessions
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// NewGetHandler creates an HTTP handler that returns the active workout session of any user. (1 sessionRepo call)
+func NewGetHandler(log *slog.Logger, sessionRepo storage.SessionRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.sessions.NewGet"
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("target_id", targetID))
+
+		session, err := sessionRepo.GetSession(r.Context(), &targetID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNoSession) {
+				log.Debug("No active session")
+				return resp.NewError(http.StatusNotFound, resp.CodeNoActiveWorkout, "No active workout at this time", "This user has no active workout")
+			}
+			log.Error("Cant GET session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewSessionResponse(session)))
+		return nil
+	})
+}
+
+// NewEndHandler creates an HTTP handler that force-ends the active workout session of any user.
+// The workout is finalized like the ones users end: new maxes, the saved workout, achievements, feed events and webhooks.
+// (1 sessionRepo call, 1 finalization)
+func NewEndHandler(log *slog.Logger, sessionRepo storage.SessionRepository, finalizer *finalizer.Finalizer) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.sessions.NewEnd"
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("target_id", targetID))
+
+		session, err := sessionRepo.GetSession(r.Context(), &targetID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNoSession) {
+				log.Debug("No active session")
+				return resp.NewError(http.StatusNotFound, resp.CodeNoActiveWorkout, "No active workout at this time", "This user has no active workout")
+			}
+			log.Error("Cant GET session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		if err := finalizer.Finalize(r.Context(), log, session, metrics.SourceAdmin); err != nil {
+			log.Error("Cant FINALIZE workout", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Info("Workout force-ended", slog.String("session_id", session.SessionID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
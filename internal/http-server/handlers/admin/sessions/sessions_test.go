@@ -0,0 +1,150 @@
+package sessions_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/sessions"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	achievementsmocks "GYMBRO/internal/lib/achievements/mocks"
+	eventsmocks "GYMBRO/internal/lib/events/mocks"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/webhooks"
+	webhooksmocks "GYMBRO/internal/lib/webhooks/mocks"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSessionsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	targetIDValue := "user123"
+	targetID := &targetIDValue
+
+	session := &storage.WorkoutSession{
+		SessionID: "session123",
+		UserID:    "user123",
+		StartTime: time.Now().Add(-time.Hour),
+		Records: []storage.Record{
+			{FkExerciseId: 1, Weight: 100, Reps: 10},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		setupMock          func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:   "GetSuccess",
+			method: "GET",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, targetID).Return(session, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "GetNoSession",
+			method: "GET",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, targetID).Return(nil, storage.ErrNoSession)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNoActiveWorkout},
+		},
+		{
+			name:   "EndSuccess",
+			method: "DELETE",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, targetID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, targetID).Return(nil, storage.ErrNoMaxes)
+				userRepo.On("SetUserMax", mock.Anything, targetID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, targetID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, targetID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(messages []*webhooks.Message) bool {
+					return len(messages) == 2 && messages[0].Event == storage.WebhookWorkoutEnded && messages[1].Event == storage.WebhookPRAchieved
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "EndNoSession",
+			method: "DELETE",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, targetID).Return(nil, storage.ErrNoSession)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNoActiveWorkout},
+		},
+		{
+			name:   "EndSaveError",
+			method: "DELETE",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, targetID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, targetID).Return(nil, storage.ErrNoMaxes)
+				userRepo.On("SetUserMax", mock.Anything, targetID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewSessionRepository(t)
+			workoutRepo := mocks.NewWorkoutRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+			evaluator := achievementsmocks.NewEvaluator(t)
+			emitter := eventsmocks.NewEmitter(t)
+			notifier := webhooksmocks.NewNotifier(t)
+			tt.setupMock(sessionRepo, workoutRepo, userRepo, evaluator, emitter, notifier)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Get("/admin/users/{userID}/session", sessions.NewGetHandler(logger, sessionRepo))
+			r.Delete("/admin/users/{userID}/session", sessions.NewEndHandler(logger, sessionRepo, finalizer.New(sessionRepo, workoutRepo, userRepo, mocks.NewModerationRepository(t), evaluator, emitter, notifier)))
+
+			req := httptest.NewRequest(tt.method, "/admin/users/user123/session", nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			sessionRepo.AssertExpectations(t)
+			workoutRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package subscriptions
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Request is a subscription from StartDate to EndDate, both days included.
+type Request struct {
+	GymId     *int   `json:"gym_id" validate:"required,gte=0"`
+	StartDate string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+// NewCreateHandler creates an HTTP handler that adds a subscription to a gym to the account from the URL,
+// e.g. when it is paid at the reception or by the billing system. (1 gymRepo call)
+func NewCreateHandler(log *slog.Logger, gymRepo storage.GymRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.subscriptions.NewCreate"
+		adminID := jwt.GetUserIDFromContext(r.Context())
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", adminID), slog.String("target_id", targetID))
+
+		var request Request
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		// the formats are validated above
+		startDate, _ := time.Parse(time.DateOnly, request.StartDate)
+		endDate, _ := time.Parse(time.DateOnly, request.EndDate)
+		if endDate.Before(startDate) {
+			log.Debug("Subscription ends before it starts")
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Subscription ends before it starts", "end_date should not be before start_date")
+		}
+
+		subscription := &storage.Subscription{
+			SubscriptionId: storage.GenerateUID(),
+			FkUserId:       targetID,
+			FkGymId:        *request.GymId,
+			StartDate:      startDate,
+			EndDate:        endDate,
+			CreatedAt:      time.Now(),
+		}
+		if err := gymRepo.CreateSubscription(r.Context(), subscription); err != nil {
+			log.Debug("Failed to CREATE subscription", slog.Any("error", err))
+			return err
+		}
+
+		log.Info("Subscription created", slog.String("subscription_id", subscription.SubscriptionId), slog.Int("gym_id", subscription.FkGymId))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewSubscriptionResponse(subscription)))
+		return nil
+	})
+}
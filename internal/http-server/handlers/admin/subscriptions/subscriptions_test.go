@@ -0,0 +1,134 @@
+package subscriptions_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/subscriptions"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	gymID := 7
+	subscription := mock.MatchedBy(func(s *storage.Subscription) bool {
+		return s.FkUserId == "user123" && s.FkGymId == 7 && s.SubscriptionId != "" &&
+			s.StartDate.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			s.EndDate.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+	})
+
+	tests := []struct {
+		name               string
+		requestBody        interface{}
+		setupMock          func(gymRepo *mocks.GymRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:        "Success",
+			requestBody: subscriptions.Request{GymId: &gymID, StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("CreateSubscription", mock.Anything, subscription).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "MissingGym",
+			requestBody:        subscriptions.Request{StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			setupMock:          func(gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "InvalidDate",
+			requestBody:        subscriptions.Request{GymId: &gymID, StartDate: "01.01.2024", EndDate: "2024-01-31"},
+			setupMock:          func(gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "EndsBeforeStart",
+			requestBody:        subscriptions.Request{GymId: &gymID, StartDate: "2024-01-31", EndDate: "2024-01-01"},
+			setupMock:          func(gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "UserNotFound",
+			requestBody: subscriptions.Request{GymId: &gymID, StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("CreateSubscription", mock.Anything, subscription).Return(storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:        "GymNotFound",
+			requestBody: subscriptions.Request{GymId: &gymID, StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("CreateSubscription", mock.Anything, subscription).Return(storage.ErrGymNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:        "InternalError",
+			requestBody: subscriptions.Request{GymId: &gymID, StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("CreateSubscription", mock.Anything, subscription).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gymRepo := mocks.NewGymRepository(t)
+			tt.setupMock(gymRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Post("/admin/users/{userID}/subscriptions", subscriptions.NewCreateHandler(logger, gymRepo))
+
+			b, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/user123/subscriptions", bytes.NewReader(b))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			gymRepo.AssertExpectations(t)
+		})
+	}
+}
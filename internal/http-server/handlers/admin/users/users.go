@@ -0,0 +1,133 @@
+package users
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type RoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin"`
+}
+
+// NewSetDisabledHandler creates an HTTP handler that disables or enables the account from the URL.
+// Admins can not disable themselves. (1 userRepo call)
+func NewSetDisabledHandler(log *slog.Logger, userRepo storage.UserRepository, disabled bool) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.users.NewSetDisabled"
+		adminID := jwt.GetUserIDFromContext(r.Context())
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", adminID), slog.String("target_id", targetID))
+
+		if disabled && targetID == adminID {
+			log.Debug("Admin tried to disable themselves")
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "You can not disable your own account", "Ask another admin to do it")
+		}
+
+		if err := userRepo.SetUserDisabled(r.Context(), &targetID, disabled); err != nil {
+			return userError(log, err)
+		}
+
+		log.Info("User disabled status changed", slog.Bool("disabled", disabled))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewSetRoleHandler creates an HTTP handler that changes the role of the account from the URL.
+// Admins can not change their own role, so there is always at least one admin left. (1 userRepo call)
+func NewSetRoleHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.users.NewSetRole"
+		adminID := jwt.GetUserIDFromContext(r.Context())
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", adminID), slog.String("target_id", targetID))
+
+		var request RoleRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		if targetID == adminID {
+			log.Debug("Admin tried to change their own role")
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "You can not change your own role", "Ask another admin to do it")
+		}
+
+		if err := userRepo.SetUserRole(r.Context(), &targetID, request.Role); err != nil {
+			return userError(log, err)
+		}
+
+		log.Info("User role changed", slog.String("role", request.Role))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewUnflagHandler creates an HTTP handler that clears the plausibility flag of the account from the URL,
+// its records keep their status. (1 userRepo call)
+func NewUnflagHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.users.NewUnflag"
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("target_id", targetID))
+
+		if err := userRepo.SetUserFlagged(r.Context(), &targetID, false); err != nil {
+			return userError(log, err)
+		}
+
+		log.Info("User unflagged")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewUnlockHandler creates an HTTP handler that lifts the lock set after failed logins from the account from the URL.
+// It also forgets previous locks, so the next one starts with the base duration again. (1 userRepo call, 1 lockoutRepo call)
+func NewUnlockHandler(log *slog.Logger, userRepo storage.UserRepository, lockoutRepo storage.LockoutRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.admin.users.NewUnlock"
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("target_id", targetID))
+
+		usr, err := userRepo.GetUserByID(r.Context(), &targetID)
+		if err != nil {
+			return userError(log, err)
+		}
+
+		if err := lockoutRepo.Unlock(r.Context(), &usr.Email); err != nil {
+			log.Error("Failed to UNLOCK user", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Info("User unlocked")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// userError logs unexpected errors, ErrUserNotFound is mapped by resp.
+func userError(log *slog.Logger, err error) error {
+	if errors.Is(err, storage.ErrUserNotFound) {
+		log.Debug("User not found")
+		return err
+	}
+	log.Error("Failed to UPDATE user", slog.Any("error", err))
+	return resp.Internal(err)
+}
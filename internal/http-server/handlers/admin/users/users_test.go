@@ -0,0 +1,204 @@
+package users_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/users"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUsersHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	targetIDValue := "user123"
+	targetID := &targetIDValue
+	email := "test@example.com"
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		requestBody        interface{}
+		setupMock          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:   "DisableSuccess",
+			method: "POST",
+			url:    "/admin/users/user123/disable",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserDisabled", mock.Anything, targetID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "DisableSelf",
+			method:             "POST",
+			url:                "/admin/users/admin123/disable",
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "DisableNotFound",
+			method: "POST",
+			url:    "/admin/users/user123/disable",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserDisabled", mock.Anything, targetID, true).Return(storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "EnableSuccess",
+			method: "POST",
+			url:    "/admin/users/user123/enable",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserDisabled", mock.Anything, targetID, false).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "SetRoleSuccess",
+			method:      "PUT",
+			url:         "/admin/users/user123/role",
+			requestBody: users.RoleRequest{Role: storage.RoleModerator},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserRole", mock.Anything, targetID, storage.RoleModerator).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "SetUnknownRole",
+			method:             "PUT",
+			url:                "/admin/users/user123/role",
+			requestBody:        users.RoleRequest{Role: "superuser"},
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "SetOwnRole",
+			method:             "PUT",
+			url:                "/admin/users/admin123/role",
+			requestBody:        users.RoleRequest{Role: storage.RoleUser},
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "SetRoleInternalError",
+			method:      "PUT",
+			url:         "/admin/users/user123/role",
+			requestBody: users.RoleRequest{Role: storage.RoleAdmin},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserRole", mock.Anything, targetID, storage.RoleAdmin).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:   "UnflagSuccess",
+			method: "POST",
+			url:    "/admin/users/user123/unflag",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserFlagged", mock.Anything, targetID, false).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "UnflagNotFound",
+			method: "POST",
+			url:    "/admin/users/user123/unflag",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("SetUserFlagged", mock.Anything, targetID, false).Return(storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "UnlockSuccess",
+			method: "POST",
+			url:    "/admin/users/user123/unlock",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(&storage.User{UserId: "user123", Email: "test@example.com"}, nil)
+				lockoutRepo.On("Unlock", mock.Anything, &email).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "UnlockNotFound",
+			method: "POST",
+			url:    "/admin/users/user123/unlock",
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			lockoutRepo := mocks.NewLockoutRepository(t)
+			tt.setupMock(userRepo, lockoutRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Post("/admin/users/{userID}/disable", users.NewSetDisabledHandler(logger, userRepo, true))
+			r.Post("/admin/users/{userID}/enable", users.NewSetDisabledHandler(logger, userRepo, false))
+			r.Put("/admin/users/{userID}/role", users.NewSetRoleHandler(logger, userRepo))
+			r.Post("/admin/users/{userID}/unflag", users.NewUnflagHandler(logger, userRepo))
+			r.Post("/admin/users/{userID}/unlock", users.NewUnlockHandler(logger, userRepo, lockoutRepo))
+
+			var body io.Reader
+			if tt.requestBody != nil {
+				b, _ := json.Marshal(tt.requestBody)
+				body = bytes.NewReader(b)
+			}
+			req := httptest.NewRequest(tt.method, tt.url, body)
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "admin123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			userRepo.AssertExpectations(t)
+			lockoutRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package challenges
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NewListHandler creates an HTTP handler that lists the challenges not settled yet, the first to end first. (1 challengeRepo call)
+func NewListHandler(log *slog.Logger, challengeRepo storage.ChallengeRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.challenges.NewList"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		challenges, err := challengeRepo.GetChallenges(r.Context(), false)
+		if err != nil {
+			log.Error("Failed to GET challenges", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewChallengesResponse(challenges, time.Now())))
+		return nil
+	})
+}
+
+// NewProgressHandler creates an HTTP handler that returns the standings of a challenge with the entry of the authenticated
+// user's clan. They are computed live until the challenge is settled and read from its results after. (1 userRepo call, 2 challengeRepo calls)
+func NewProgressHandler(log *slog.Logger, userRepo storage.UserRepository, challengeRepo storage.ChallengeRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.challenges.NewProgress"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		challengeID := chi.URLParam(r, "challengeID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("challenge_id", challengeID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+
+		challenge, err := challengeRepo.GetChallenge(r.Context(), &challengeID)
+		if err != nil {
+			log.Debug("Failed to GET challenge", slog.Any("error", err))
+			return err
+		}
+
+		now := time.Now()
+		var results []*storage.ChallengeResult
+		switch {
+		case challenge.SettledAt != nil:
+			results, err = challengeRepo.GetChallengeResults(r.Context(), &challengeID)
+		case now.Before(challenge.StartTime):
+			// nobody can have progress yet
+		default:
+			results, err = challengeRepo.GetChallengeProgress(r.Context(), challenge)
+		}
+		if err != nil {
+			log.Error("Failed to GET challenge progress", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewChallengeProgressResponse(challenge, results, user.FkClanId, now)))
+		return nil
+	})
+}
+
+// NewResultsHandler creates an HTTP handler that returns the clan points of the authenticated user's clan
+// and its results in settled challenges, the last ended first. (1 userRepo call, 3 challengeRepo calls)
+func NewResultsHandler(log *slog.Logger, userRepo storage.UserRepository, challengeRepo storage.ChallengeRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.challenges.NewResults"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+		if user.FkClanId == "" || user.FkClanId == storage.DefaultClanID {
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "You are not in a clan", "Join a clan to take part in challenges")
+		}
+
+		clan, err := challengeRepo.GetClan(r.Context(), &user.FkClanId)
+		if err != nil {
+			log.Debug("Failed to GET clan", slog.String("clan_id", user.FkClanId), slog.Any("error", err))
+			return err
+		}
+
+		results, err := challengeRepo.GetClanResults(r.Context(), &clan.ClanId)
+		if err != nil {
+			log.Error("Failed to GET clan results", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		settled, err := challengeRepo.GetChallenges(r.Context(), true)
+		if err != nil {
+			log.Error("Failed to GET settled challenges", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+		byID := make(map[string]*storage.Challenge, len(settled))
+		for _, challenge := range settled {
+			byID[challenge.ChallengeID] = challenge
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewClanResultsResponse(clan, results, byID, time.Now())))
+		return nil
+	})
+}
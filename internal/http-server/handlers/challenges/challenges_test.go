@@ -0,0 +1,224 @@
+package challenges_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/challenges"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+)
+
+func TestChallengeHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	clanID := "clan1"
+	me := &storage.User{UserId: "user123", FkClanId: clanID}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	settledAt := time.Date(2024, 3, 8, 1, 0, 0, 0, time.UTC)
+	active := &storage.Challenge{ChallengeID: "active", Name: "Points week", Goal: storage.GoalPoints, Target: 100, Reward: 50,
+		StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}
+	upcoming := &storage.Challenge{ChallengeID: "upcoming", Name: "Squat week", Goal: storage.GoalTonnage, FkExerciseId: 3, Target: 5000, Reward: 100,
+		StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)}
+	settled := &storage.Challenge{ChallengeID: "settled", Name: "Workouts week", Goal: storage.GoalWorkouts, Target: 10, Reward: 30,
+		StartTime: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), SettledAt: &settledAt}
+
+	progress := []*storage.ChallengeResult{
+		{FkChallengeId: "active", FkClanId: "clan2", Progress: 120, Rank: 1, Completed: true},
+		{FkChallengeId: "active", FkClanId: clanID, Progress: 80, Rank: 2},
+	}
+	results := []*storage.ChallengeResult{{FkChallengeId: "settled", FkClanId: clanID, Progress: 12, Rank: 1, Completed: true, Points: 30}}
+
+	activeResponse := dto.NewChallengeResponse(active, now)
+	settledResponse := dto.NewChallengeResponse(settled, now)
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedData       interface{}
+	}{
+		{
+			name: "List",
+			path: "/challenges",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("GetChallenges", mock.Anything, false).Return([]*storage.Challenge{active, upcoming}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       []dto.ChallengeResponse{activeResponse, dto.NewChallengeResponse(upcoming, now)},
+		},
+		{
+			name: "EmptyList",
+			path: "/challenges",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				challengeRepo.On("GetChallenges", mock.Anything, false).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       []dto.ChallengeResponse{},
+		},
+		{
+			name: "LiveProgress",
+			path: "/challenges/active",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetChallenge", mock.Anything, mock.Anything).Return(active, nil)
+				challengeRepo.On("GetChallengeProgress", mock.Anything, active).Return(progress, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: dto.ChallengeProgressResponse{
+				Challenge: activeResponse,
+				Clan:      &dto.ChallengeResultResponse{ClanId: clanID, Progress: 80, Rank: 2},
+				Standings: []dto.ChallengeResultResponse{
+					{ClanId: "clan2", Progress: 120, Rank: 1, Completed: true},
+					{ClanId: clanID, Progress: 80, Rank: 2},
+				},
+			},
+		},
+		{
+			name: "UpcomingHasNoProgress",
+			path: "/challenges/upcoming",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetChallenge", mock.Anything, mock.Anything).Return(upcoming, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       dto.ChallengeProgressResponse{Challenge: dto.NewChallengeResponse(upcoming, now), Standings: []dto.ChallengeResultResponse{}},
+		},
+		{
+			name: "SettledReadsResults",
+			path: "/challenges/settled",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetChallenge", mock.Anything, mock.Anything).Return(settled, nil)
+				challengeRepo.On("GetChallengeResults", mock.Anything, mock.Anything).Return(results, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: dto.ChallengeProgressResponse{
+				Challenge: settledResponse,
+				Clan:      &dto.ChallengeResultResponse{ClanId: clanID, Progress: 12, Rank: 1, Completed: true, Points: 30},
+				Standings: []dto.ChallengeResultResponse{{ClanId: clanID, Progress: 12, Rank: 1, Completed: true, Points: 30}},
+			},
+		},
+		{
+			name: "ChallengeNotFound",
+			path: "/challenges/missing",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetChallenge", mock.Anything, mock.Anything).Return(nil, storage.ErrChallengeNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "ProgressError",
+			path: "/challenges/active",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetChallenge", mock.Anything, mock.Anything).Return(active, nil)
+				challengeRepo.On("GetChallengeProgress", mock.Anything, active).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+		{
+			name: "Results",
+			path: "/challenges/results",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetClan", mock.Anything, &clanID).Return(&storage.Clan{ClanId: clanID, Name: "Lifters", Points: 130}, nil)
+				challengeRepo.On("GetClanResults", mock.Anything, &clanID).Return(results, nil)
+				challengeRepo.On("GetChallenges", mock.Anything, true).Return([]*storage.Challenge{settled}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: dto.ClanResultsResponse{ClanId: clanID, Name: "Lifters", Points: 130, Results: []dto.ClanResultResponse{
+				{Challenge: settledResponse, Progress: 12, Rank: 1, Completed: true, Points: 30},
+			}},
+		},
+		{
+			name: "ClanNotFound",
+			path: "/challenges/results",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				challengeRepo.On("GetClan", mock.Anything, &clanID).Return(nil, storage.ErrClanNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "DefaultClanIsNoClan",
+			path: "/challenges/results",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", FkClanId: storage.DefaultClanID}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "UserNotFound",
+			path: "/challenges/results",
+			setupMock: func(userRepo *mocks.UserRepository, challengeRepo *mocks.ChallengeRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			challengeRepo := mocks.NewChallengeRepository(t)
+			tt.setupMock(userRepo, challengeRepo)
+
+			r := chi.NewRouter()
+			r.Get("/challenges", challenges.NewListHandler(logger, challengeRepo))
+			r.Get("/challenges/results", challenges.NewResultsHandler(logger, userRepo, challengeRepo))
+			r.Get("/challenges/{challengeID}", challenges.NewProgressHandler(logger, userRepo, challengeRepo))
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				Code string          `json:"code"`
+				Data json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedData != nil {
+				expected, err := json.Marshal(tt.expectedData)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(response.Data))
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package factory
 
 import (
 	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/mailer"
 	"GYMBRO/internal/storage"
 	"log/slog"
 )
@@ -11,38 +13,103 @@ type AbstractHandlerFactory interface {
 	GetUsersHandlerFactory() UsersHandlerFactory
 	GetWorkoutsHandlerFactory() WorkoutsHandlerFactory
 	GetRecordsHandlerFactory() RecordsHandlerFactory
+	GetAdminsHandlerFactory() AdminsHandlerFactory
+	GetStatsHandlerFactory() StatsHandlerFactory
+	GetSocialsHandlerFactory() SocialsHandlerFactory
+	GetGymsHandlerFactory() GymsHandlerFactory
+	GetChallengesHandlerFactory() ChallengesHandlerFactory
+	GetSeasonsHandlerFactory() SeasonsHandlerFactory
+	GetWebhooksHandlerFactory() WebhooksHandlerFactory
 }
 
 type ConcreteHandlerFactory struct {
-	log         *slog.Logger
-	userRepo    storage.UserRepository
-	workoutRepo storage.WorkoutRepository
-	sessionRepo storage.SessionRepository
-	cfg         *config.Config
+	log             *slog.Logger
+	userRepo        storage.UserRepository
+	workoutRepo     storage.WorkoutRepository
+	sessionRepo     storage.SessionRepository
+	subscriber      storage.SessionSubscriber
+	exerciseRepo    storage.ExerciseRepository
+	rateLimitRepo   storage.RateLimitRepository
+	lockoutRepo     storage.LockoutRepository
+	statsRepo       storage.StatsRepository
+	achievementRepo storage.AchievementRepository
+	followRepo      storage.FollowRepository
+	eventRepo       storage.EventRepository
+	gymRepo         storage.GymRepository
+	challengeRepo   storage.ChallengeRepository
+	seasonRepo      storage.SeasonRepository
+	moderationRepo  storage.ModerationRepository
+	webhookRepo     storage.WebhookRepository
+	finalizer       *finalizer.Finalizer
+	mailer          mailer.Mailer
+	cfg             *config.Config
 }
 
-func NewConcreteHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, workoutRepo storage.WorkoutRepository, sessionRepo storage.SessionRepository, cfg *config.Config) *ConcreteHandlerFactory {
+func NewConcreteHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, workoutRepo storage.WorkoutRepository, sessionRepo storage.SessionRepository, subscriber storage.SessionSubscriber, exerciseRepo storage.ExerciseRepository, rateLimitRepo storage.RateLimitRepository, lockoutRepo storage.LockoutRepository, statsRepo storage.StatsRepository, achievementRepo storage.AchievementRepository, followRepo storage.FollowRepository, eventRepo storage.EventRepository, gymRepo storage.GymRepository, challengeRepo storage.ChallengeRepository, seasonRepo storage.SeasonRepository, moderationRepo storage.ModerationRepository, webhookRepo storage.WebhookRepository, finalizer *finalizer.Finalizer, mailer mailer.Mailer, cfg *config.Config) *ConcreteHandlerFactory {
 	return &ConcreteHandlerFactory{
-		log:         log,
-		userRepo:    userRepo,
-		workoutRepo: workoutRepo,
-		sessionRepo: sessionRepo,
-		cfg:         cfg,
+		log:             log,
+		userRepo:        userRepo,
+		workoutRepo:     workoutRepo,
+		sessionRepo:     sessionRepo,
+		subscriber:      subscriber,
+		exerciseRepo:    exerciseRepo,
+		rateLimitRepo:   rateLimitRepo,
+		lockoutRepo:     lockoutRepo,
+		statsRepo:       statsRepo,
+		achievementRepo: achievementRepo,
+		followRepo:      followRepo,
+		eventRepo:       eventRepo,
+		gymRepo:         gymRepo,
+		challengeRepo:   challengeRepo,
+		seasonRepo:      seasonRepo,
+		moderationRepo:  moderationRepo,
+		webhookRepo:     webhookRepo,
+		finalizer:       finalizer,
+		mailer:          mailer,
+		cfg:             cfg,
 	}
 }
 
 func (f *ConcreteHandlerFactory) GetMiddlewaresHandlerFactory() MiddlewaresHandlerFactory {
-	return NewMiddlewareHandlerFactory(f.log, f.userRepo, f.sessionRepo, f.cfg)
+	return NewMiddlewareHandlerFactory(f.log, f.userRepo, f.sessionRepo, f.rateLimitRepo, f.cfg)
 }
 
 func (f *ConcreteHandlerFactory) GetUsersHandlerFactory() UsersHandlerFactory {
-	return NewUserHandlerFactory(f.log, f.userRepo, f.cfg)
+	return NewUserHandlerFactory(f.log, f.userRepo, f.sessionRepo, f.lockoutRepo, f.achievementRepo, f.mailer, f.cfg)
 }
 
 func (f *ConcreteHandlerFactory) GetWorkoutsHandlerFactory() WorkoutsHandlerFactory {
-	return NewWorkoutHandlerFactory(f.log, f.workoutRepo, f.sessionRepo, f.userRepo)
+	return NewWorkoutHandlerFactory(f.log, f.workoutRepo, f.sessionRepo, f.subscriber, f.userRepo, f.gymRepo, f.finalizer)
 }
 
 func (f *ConcreteHandlerFactory) GetRecordsHandlerFactory() RecordsHandlerFactory {
-	return NewRecordHandlerFactory(f.log, f.sessionRepo, f.userRepo)
+	return NewRecordHandlerFactory(f.log, f.sessionRepo, f.userRepo, f.exerciseRepo, f.cfg)
+}
+
+func (f *ConcreteHandlerFactory) GetAdminsHandlerFactory() AdminsHandlerFactory {
+	return NewAdminHandlerFactory(f.log, f.userRepo, f.sessionRepo, f.exerciseRepo, f.lockoutRepo, f.gymRepo, f.challengeRepo, f.moderationRepo, f.finalizer)
+}
+
+func (f *ConcreteHandlerFactory) GetStatsHandlerFactory() StatsHandlerFactory {
+	return NewStatHandlerFactory(f.log, f.statsRepo)
+}
+
+func (f *ConcreteHandlerFactory) GetSocialsHandlerFactory() SocialsHandlerFactory {
+	return NewSocialHandlerFactory(f.log, f.userRepo, f.followRepo, f.eventRepo, f.sessionRepo)
+}
+
+func (f *ConcreteHandlerFactory) GetGymsHandlerFactory() GymsHandlerFactory {
+	return NewGymHandlerFactory(f.log, f.userRepo, f.gymRepo, f.cfg)
+}
+
+func (f *ConcreteHandlerFactory) GetChallengesHandlerFactory() ChallengesHandlerFactory {
+	return NewChallengeHandlerFactory(f.log, f.userRepo, f.challengeRepo)
+}
+
+func (f *ConcreteHandlerFactory) GetSeasonsHandlerFactory() SeasonsHandlerFactory {
+	return NewSeasonHandlerFactory(f.log, f.seasonRepo)
+}
+
+func (f *ConcreteHandlerFactory) GetWebhooksHandlerFactory() WebhooksHandlerFactory {
+	return NewWebhookHandlerFactory(f.log, f.webhookRepo, f.cfg)
 }
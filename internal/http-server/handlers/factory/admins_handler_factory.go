@@ -0,0 +1,144 @@
+package factory
+
+import (
+	"GYMBRO/internal/http-server/handlers/admin/challenges"
+	"GYMBRO/internal/http-server/handlers/admin/exercises"
+	"GYMBRO/internal/http-server/handlers/admin/musclegroups"
+	"GYMBRO/internal/http-server/handlers/admin/records"
+	"GYMBRO/internal/http-server/handlers/admin/sessions"
+	"GYMBRO/internal/http-server/handlers/admin/subscriptions"
+	"GYMBRO/internal/http-server/handlers/admin/users"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type AdminsHandlerFactory interface {
+	CreateListExercisesHandler() http.HandlerFunc
+	CreateCreateExerciseHandler() http.HandlerFunc
+	CreateUpdateExerciseHandler() http.HandlerFunc
+	CreateDeleteExerciseHandler() http.HandlerFunc
+	CreateListMuscleGroupsHandler() http.HandlerFunc
+	CreateCreateMuscleGroupHandler() http.HandlerFunc
+	CreateUpdateMuscleGroupHandler() http.HandlerFunc
+	CreateDeleteMuscleGroupHandler() http.HandlerFunc
+	CreateDisableUserHandler() http.HandlerFunc
+	CreateEnableUserHandler() http.HandlerFunc
+	CreateSetRoleHandler() http.HandlerFunc
+	CreateUnlockUserHandler() http.HandlerFunc
+	CreateUnflagUserHandler() http.HandlerFunc
+	CreateGetSessionHandler() http.HandlerFunc
+	CreateEndSessionHandler() http.HandlerFunc
+	CreateCreateSubscriptionHandler() http.HandlerFunc
+	CreateCreateChallengeHandler() http.HandlerFunc
+	CreateListFlaggedRecordsHandler() http.HandlerFunc
+	CreateApproveRecordHandler() http.HandlerFunc
+	CreateVoidRecordHandler() http.HandlerFunc
+}
+
+type AdminHandlerFactory struct {
+	log            *slog.Logger
+	userRepo       storage.UserRepository
+	sessionRepo    storage.SessionRepository
+	exerciseRepo   storage.ExerciseRepository
+	lockoutRepo    storage.LockoutRepository
+	gymRepo        storage.GymRepository
+	challengeRepo  storage.ChallengeRepository
+	moderationRepo storage.ModerationRepository
+	finalizer      *finalizer.Finalizer
+}
+
+func NewAdminHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository, exerciseRepo storage.ExerciseRepository, lockoutRepo storage.LockoutRepository, gymRepo storage.GymRepository, challengeRepo storage.ChallengeRepository, moderationRepo storage.ModerationRepository, finalizer *finalizer.Finalizer) *AdminHandlerFactory {
+	return &AdminHandlerFactory{
+		log:            log,
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		exerciseRepo:   exerciseRepo,
+		lockoutRepo:    lockoutRepo,
+		gymRepo:        gymRepo,
+		challengeRepo:  challengeRepo,
+		moderationRepo: moderationRepo,
+		finalizer:      finalizer,
+	}
+}
+
+func (f *AdminHandlerFactory) CreateListExercisesHandler() http.HandlerFunc {
+	return exercises.NewListHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateCreateExerciseHandler() http.HandlerFunc {
+	return exercises.NewCreateHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateUpdateExerciseHandler() http.HandlerFunc {
+	return exercises.NewUpdateHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateDeleteExerciseHandler() http.HandlerFunc {
+	return exercises.NewDeleteHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateListMuscleGroupsHandler() http.HandlerFunc {
+	return musclegroups.NewListHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateCreateMuscleGroupHandler() http.HandlerFunc {
+	return musclegroups.NewCreateHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateUpdateMuscleGroupHandler() http.HandlerFunc {
+	return musclegroups.NewUpdateHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateDeleteMuscleGroupHandler() http.HandlerFunc {
+	return musclegroups.NewDeleteHandler(f.log, f.exerciseRepo)
+}
+
+func (f *AdminHandlerFactory) CreateDisableUserHandler() http.HandlerFunc {
+	return users.NewSetDisabledHandler(f.log, f.userRepo, true)
+}
+
+func (f *AdminHandlerFactory) CreateEnableUserHandler() http.HandlerFunc {
+	return users.NewSetDisabledHandler(f.log, f.userRepo, false)
+}
+
+func (f *AdminHandlerFactory) CreateSetRoleHandler() http.HandlerFunc {
+	return users.NewSetRoleHandler(f.log, f.userRepo)
+}
+
+func (f *AdminHandlerFactory) CreateUnlockUserHandler() http.HandlerFunc {
+	return users.NewUnlockHandler(f.log, f.userRepo, f.lockoutRepo)
+}
+
+func (f *AdminHandlerFactory) CreateUnflagUserHandler() http.HandlerFunc {
+	return users.NewUnflagHandler(f.log, f.userRepo)
+}
+
+func (f *AdminHandlerFactory) CreateGetSessionHandler() http.HandlerFunc {
+	return sessions.NewGetHandler(f.log, f.sessionRepo)
+}
+
+func (f *AdminHandlerFactory) CreateEndSessionHandler() http.HandlerFunc {
+	return sessions.NewEndHandler(f.log, f.sessionRepo, f.finalizer)
+}
+
+func (f *AdminHandlerFactory) CreateCreateSubscriptionHandler() http.HandlerFunc {
+	return subscriptions.NewCreateHandler(f.log, f.gymRepo)
+}
+
+func (f *AdminHandlerFactory) CreateCreateChallengeHandler() http.HandlerFunc {
+	return challenges.NewCreateHandler(f.log, f.challengeRepo)
+}
+
+func (f *AdminHandlerFactory) CreateListFlaggedRecordsHandler() http.HandlerFunc {
+	return records.NewFlaggedHandler(f.log, f.moderationRepo)
+}
+
+func (f *AdminHandlerFactory) CreateApproveRecordHandler() http.HandlerFunc {
+	return records.NewApproveHandler(f.log, f.finalizer)
+}
+
+func (f *AdminHandlerFactory) CreateVoidRecordHandler() http.HandlerFunc {
+	return records.NewVoidHandler(f.log, f.moderationRepo)
+}
@@ -0,0 +1,40 @@
+package factory
+
+import (
+	"GYMBRO/internal/http-server/handlers/challenges"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type ChallengesHandlerFactory interface {
+	CreateListHandler() http.HandlerFunc
+	CreateProgressHandler() http.HandlerFunc
+	CreateResultsHandler() http.HandlerFunc
+}
+
+type ChallengeHandlerFactory struct {
+	log           *slog.Logger
+	userRepo      storage.UserRepository
+	challengeRepo storage.ChallengeRepository
+}
+
+func NewChallengeHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, challengeRepo storage.ChallengeRepository) *ChallengeHandlerFactory {
+	return &ChallengeHandlerFactory{
+		log:           log,
+		userRepo:      userRepo,
+		challengeRepo: challengeRepo,
+	}
+}
+
+func (f *ChallengeHandlerFactory) CreateListHandler() http.HandlerFunc {
+	return challenges.NewListHandler(f.log, f.challengeRepo)
+}
+
+func (f *ChallengeHandlerFactory) CreateProgressHandler() http.HandlerFunc {
+	return challenges.NewProgressHandler(f.log, f.userRepo, f.challengeRepo)
+}
+
+func (f *ChallengeHandlerFactory) CreateResultsHandler() http.HandlerFunc {
+	return challenges.NewResultsHandler(f.log, f.userRepo, f.challengeRepo)
+}
@@ -0,0 +1,38 @@
+package factory
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/handlers/gyms/checkin"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type GymsHandlerFactory interface {
+	CreateCheckInHandler() http.HandlerFunc
+	CreateVerifyCheckInHandler() http.HandlerFunc
+}
+
+type GymHandlerFactory struct {
+	log      *slog.Logger
+	userRepo storage.UserRepository
+	gymRepo  storage.GymRepository
+	cfg      *config.Config
+}
+
+func NewGymHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, gymRepo storage.GymRepository, cfg *config.Config) *GymHandlerFactory {
+	return &GymHandlerFactory{
+		log:      log,
+		userRepo: userRepo,
+		gymRepo:  gymRepo,
+		cfg:      cfg,
+	}
+}
+
+func (f *GymHandlerFactory) CreateCheckInHandler() http.HandlerFunc {
+	return checkin.NewCheckInHandler(f.log, f.gymRepo, f.cfg)
+}
+
+func (f *GymHandlerFactory) CreateVerifyCheckInHandler() http.HandlerFunc {
+	return checkin.NewVerifyHandler(f.log, f.userRepo, f.gymRepo, f.cfg)
+}
@@ -3,6 +3,8 @@ package factory
 import (
 	"GYMBRO/internal/config"
 	mwjwt "GYMBRO/internal/http-server/middleware/jwt"
+	mwratelimit "GYMBRO/internal/http-server/middleware/ratelimit"
+	mwrole "GYMBRO/internal/http-server/middleware/role"
 	mwworkout "GYMBRO/internal/http-server/middleware/workout"
 	"GYMBRO/internal/storage"
 	"log/slog"
@@ -12,21 +14,25 @@ import (
 type MiddlewaresHandlerFactory interface {
 	CreateJWTAuthHandler() func(http.Handler) http.Handler
 	CreateActiveSessionHandler() func(http.Handler) http.Handler
+	CreateRequireRoleHandler(roles ...string) func(http.Handler) http.Handler
+	CreateRateLimitHandler(group string) func(http.Handler) http.Handler
 }
 
 type MiddlewareHandlerFactory struct {
-	log         *slog.Logger
-	userRepo    storage.UserRepository
-	sessionRepo storage.SessionRepository
-	cfg         *config.Config
+	log           *slog.Logger
+	userRepo      storage.UserRepository
+	sessionRepo   storage.SessionRepository
+	rateLimitRepo storage.RateLimitRepository
+	cfg           *config.Config
 }
 
-func NewMiddlewareHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository, cfg *config.Config) *MiddlewareHandlerFactory {
+func NewMiddlewareHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository, rateLimitRepo storage.RateLimitRepository, cfg *config.Config) *MiddlewareHandlerFactory {
 	return &MiddlewareHandlerFactory{
-		log:         log,
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
-		cfg:         cfg,
+		log:           log,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		rateLimitRepo: rateLimitRepo,
+		cfg:           cfg,
 	}
 }
 
@@ -37,3 +43,17 @@ func (f *MiddlewareHandlerFactory) CreateJWTAuthHandler() func(http.Handler) htt
 func (f *MiddlewareHandlerFactory) CreateActiveSessionHandler() func(http.Handler) http.Handler {
 	return mwworkout.WithActiveSessionCheck(f.log, f.sessionRepo)
 }
+
+func (f *MiddlewareHandlerFactory) CreateRequireRoleHandler(roles ...string) func(http.Handler) http.Handler {
+	return mwrole.RequireRole(f.log, roles...)
+}
+
+// CreateRateLimitHandler returns the limiter for the route group from the config, groups without a rule are not limited.
+func (f *MiddlewareHandlerFactory) CreateRateLimitHandler(group string) func(http.Handler) http.Handler {
+	rule, ok := f.cfg.RateLimitCfg.Groups[group]
+	if !ok || rule.Limit <= 0 || rule.Window <= 0 {
+		f.log.Warn("Rate limit is not configured for the route group", slog.String("group", group))
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return mwratelimit.WithRateLimit(f.log, f.rateLimitRepo, group, rule)
+}
@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"GYMBRO/internal/config"
 	"GYMBRO/internal/http-server/handlers/records/add"
 	"GYMBRO/internal/http-server/handlers/records/delete"
 	"GYMBRO/internal/storage"
@@ -14,21 +15,25 @@ type RecordsHandlerFactory interface {
 }
 
 type RecordHandlerFactory struct {
-	log         *slog.Logger
-	sessionRepo storage.SessionRepository
-	userRepo    storage.UserRepository
+	log          *slog.Logger
+	sessionRepo  storage.SessionRepository
+	userRepo     storage.UserRepository
+	exerciseRepo storage.ExerciseRepository
+	cfg          *config.Config
 }
 
-func NewRecordHandlerFactory(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository) *RecordHandlerFactory {
+func NewRecordHandlerFactory(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository, exerciseRepo storage.ExerciseRepository, cfg *config.Config) *RecordHandlerFactory {
 	return &RecordHandlerFactory{
-		log:         log,
-		sessionRepo: sessionRepo,
-		userRepo:    userRepo,
+		log:          log,
+		sessionRepo:  sessionRepo,
+		userRepo:     userRepo,
+		exerciseRepo: exerciseRepo,
+		cfg:          cfg,
 	}
 }
 
 func (f *RecordHandlerFactory) CreateAddHandler() http.HandlerFunc {
-	return add.NewAddHandler(f.log, f.sessionRepo, f.userRepo)
+	return add.NewAddHandler(f.log, f.sessionRepo, f.userRepo, f.exerciseRepo, f.cfg)
 }
 
 func (f *RecordHandlerFactory) CreateDeleteHandler() http.HandlerFunc {
@@ -0,0 +1,33 @@
+package factory
+
+import (
+	"GYMBRO/internal/http-server/handlers/seasons"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type SeasonsHandlerFactory interface {
+	CreateListHandler() http.HandlerFunc
+	CreateStandingsHandler() http.HandlerFunc
+}
+
+type SeasonHandlerFactory struct {
+	log        *slog.Logger
+	seasonRepo storage.SeasonRepository
+}
+
+func NewSeasonHandlerFactory(log *slog.Logger, seasonRepo storage.SeasonRepository) *SeasonHandlerFactory {
+	return &SeasonHandlerFactory{
+		log:        log,
+		seasonRepo: seasonRepo,
+	}
+}
+
+func (f *SeasonHandlerFactory) CreateListHandler() http.HandlerFunc {
+	return seasons.NewListHandler(f.log, f.seasonRepo)
+}
+
+func (f *SeasonHandlerFactory) CreateStandingsHandler() http.HandlerFunc {
+	return seasons.NewStandingsHandler(f.log, f.seasonRepo)
+}
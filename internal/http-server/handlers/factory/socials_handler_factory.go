@@ -0,0 +1,81 @@
+package factory
+
+import (
+	"GYMBRO/internal/http-server/handlers/social/feed"
+	"GYMBRO/internal/http-server/handlers/social/follows"
+	"GYMBRO/internal/http-server/handlers/social/presence"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type SocialsHandlerFactory interface {
+	CreateFollowHandler() http.HandlerFunc
+	CreateUnfollowHandler() http.HandlerFunc
+	CreateAcceptHandler() http.HandlerFunc
+	CreateRemoveFollowerHandler() http.HandlerFunc
+	CreateFollowersHandler() http.HandlerFunc
+	CreateFollowingHandler() http.HandlerFunc
+	CreateFeedHandler() http.HandlerFunc
+	CreateClanPresenceHandler() http.HandlerFunc
+	CreateGymPresenceHandler() http.HandlerFunc
+	CreateGymCountHandler() http.HandlerFunc
+}
+
+type SocialHandlerFactory struct {
+	log         *slog.Logger
+	userRepo    storage.UserRepository
+	followRepo  storage.FollowRepository
+	eventRepo   storage.EventRepository
+	sessionRepo storage.SessionRepository
+}
+
+func NewSocialHandlerFactory(log *slog.Logger, userRepo storage.UserRepository, followRepo storage.FollowRepository, eventRepo storage.EventRepository, sessionRepo storage.SessionRepository) *SocialHandlerFactory {
+	return &SocialHandlerFactory{
+		log:         log,
+		userRepo:    userRepo,
+		followRepo:  followRepo,
+		eventRepo:   eventRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+func (f *SocialHandlerFactory) CreateFollowHandler() http.HandlerFunc {
+	return follows.NewFollowHandler(f.log, f.userRepo, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateUnfollowHandler() http.HandlerFunc {
+	return follows.NewUnfollowHandler(f.log, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateAcceptHandler() http.HandlerFunc {
+	return follows.NewAcceptHandler(f.log, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateRemoveFollowerHandler() http.HandlerFunc {
+	return follows.NewRemoveFollowerHandler(f.log, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateFollowersHandler() http.HandlerFunc {
+	return follows.NewFollowersHandler(f.log, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateFollowingHandler() http.HandlerFunc {
+	return follows.NewFollowingHandler(f.log, f.followRepo)
+}
+
+func (f *SocialHandlerFactory) CreateFeedHandler() http.HandlerFunc {
+	return feed.NewFeedHandler(f.log, f.eventRepo)
+}
+
+func (f *SocialHandlerFactory) CreateClanPresenceHandler() http.HandlerFunc {
+	return presence.NewClanHandler(f.log, f.userRepo, f.sessionRepo)
+}
+
+func (f *SocialHandlerFactory) CreateGymPresenceHandler() http.HandlerFunc {
+	return presence.NewGymHandler(f.log, f.userRepo, f.sessionRepo)
+}
+
+func (f *SocialHandlerFactory) CreateGymCountHandler() http.HandlerFunc {
+	return presence.NewGymCountHandler(f.log, f.userRepo, f.sessionRepo)
+}
@@ -0,0 +1,34 @@
+package factory
+
+import (
+	"GYMBRO/internal/http-server/handlers/stats/progress"
+	"GYMBRO/internal/http-server/handlers/stats/volume"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type StatsHandlerFactory interface {
+	CreateVolumeHandler() http.HandlerFunc
+	CreateProgressHandler() http.HandlerFunc
+}
+
+type StatHandlerFactory struct {
+	log       *slog.Logger
+	statsRepo storage.StatsRepository
+}
+
+func NewStatHandlerFactory(log *slog.Logger, statsRepo storage.StatsRepository) *StatHandlerFactory {
+	return &StatHandlerFactory{
+		log:       log,
+		statsRepo: statsRepo,
+	}
+}
+
+func (f *StatHandlerFactory) CreateVolumeHandler() http.HandlerFunc {
+	return volume.NewVolumeHandler(f.log, f.statsRepo)
+}
+
+func (f *StatHandlerFactory) CreateProgressHandler() http.HandlerFunc {
+	return progress.NewProgressHandler(f.log, f.statsRepo)
+}
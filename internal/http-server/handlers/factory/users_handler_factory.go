@@ -2,10 +2,15 @@ package factory
 
 import (
 	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/handlers/users/identities"
 	"GYMBRO/internal/http-server/handlers/users/login"
 	"GYMBRO/internal/http-server/handlers/users/logout"
+	"GYMBRO/internal/http-server/handlers/users/merge"
 	"GYMBRO/internal/http-server/handlers/users/oauth"
+	"GYMBRO/internal/http-server/handlers/users/profile"
 	"GYMBRO/internal/http-server/handlers/users/register"
+	"GYMBRO/internal/http-server/handlers/users/unlink"
+	"GYMBRO/internal/lib/mailer"
 	"GYMBRO/internal/storage"
 	"log/slog"
 	"net/http"
@@ -18,19 +23,34 @@ type UsersHandlerFactory interface {
 	CreateOAuthCallbackHandler() http.HandlerFunc
 	CreateOAuthLoginHandler() http.HandlerFunc
 	CreateOAuthLogoutHandler() http.HandlerFunc
+	CreateOAuthLinkHandler() http.HandlerFunc
+	CreateIdentitiesHandler() http.HandlerFunc
+	CreateUnlinkHandler() http.HandlerFunc
+	CreateMergeHandler() http.HandlerFunc
+	CreateProfileHandler() http.HandlerFunc
+	CreateBodyWeightHandler() http.HandlerFunc
+	CreatePrivacyHandler() http.HandlerFunc
 }
 
 type UserHandlerFactory struct {
-	log  *slog.Logger
-	repo storage.UserRepository
-	cfg  *config.Config
+	log             *slog.Logger
+	repo            storage.UserRepository
+	sessionRepo     storage.SessionRepository
+	lockoutRepo     storage.LockoutRepository
+	achievementRepo storage.AchievementRepository
+	mailer          mailer.Mailer
+	cfg             *config.Config
 }
 
-func NewUserHandlerFactory(log *slog.Logger, repo storage.UserRepository, cfg *config.Config) *UserHandlerFactory {
+func NewUserHandlerFactory(log *slog.Logger, repo storage.UserRepository, sessionRepo storage.SessionRepository, lockoutRepo storage.LockoutRepository, achievementRepo storage.AchievementRepository, mailer mailer.Mailer, cfg *config.Config) *UserHandlerFactory {
 	return &UserHandlerFactory{
-		log:  log,
-		repo: repo,
-		cfg:  cfg,
+		log:             log,
+		repo:            repo,
+		sessionRepo:     sessionRepo,
+		lockoutRepo:     lockoutRepo,
+		achievementRepo: achievementRepo,
+		mailer:          mailer,
+		cfg:             cfg,
 	}
 }
 
@@ -39,7 +59,7 @@ func (f *UserHandlerFactory) CreateRegisterHandler() http.HandlerFunc {
 }
 
 func (f *UserHandlerFactory) CreateLoginHandler() http.HandlerFunc {
-	return login.NewLoginHandler(f.log, f.repo, f.cfg)
+	return login.NewLoginHandler(f.log, f.repo, f.lockoutRepo, f.mailer, f.cfg)
 }
 
 func (f *UserHandlerFactory) CreateLogoutHandler() http.HandlerFunc {
@@ -57,3 +77,31 @@ func (f *UserHandlerFactory) CreateOAuthLoginHandler() http.HandlerFunc {
 func (f *UserHandlerFactory) CreateOAuthLogoutHandler() http.HandlerFunc {
 	return oauth.NewOAuthLogoutHandler(f.log)
 }
+
+func (f *UserHandlerFactory) CreateOAuthLinkHandler() http.HandlerFunc {
+	return oauth.NewOAuthLinkHandler(f.log)
+}
+
+func (f *UserHandlerFactory) CreateIdentitiesHandler() http.HandlerFunc {
+	return identities.NewIdentitiesHandler(f.log, f.repo)
+}
+
+func (f *UserHandlerFactory) CreateUnlinkHandler() http.HandlerFunc {
+	return unlink.NewUnlinkHandler(f.log, f.repo)
+}
+
+func (f *UserHandlerFactory) CreateMergeHandler() http.HandlerFunc {
+	return merge.NewMergeHandler(f.log, f.repo, f.sessionRepo, f.cfg)
+}
+
+func (f *UserHandlerFactory) CreateProfileHandler() http.HandlerFunc {
+	return profile.NewProfileHandler(f.log, f.repo, f.achievementRepo)
+}
+
+func (f *UserHandlerFactory) CreateBodyWeightHandler() http.HandlerFunc {
+	return profile.NewBodyWeightHandler(f.log, f.repo)
+}
+
+func (f *UserHandlerFactory) CreatePrivacyHandler() http.HandlerFunc {
+	return profile.NewPrivacyHandler(f.log, f.repo)
+}
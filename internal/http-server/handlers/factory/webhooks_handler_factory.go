@@ -0,0 +1,56 @@
+package factory
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/handlers/webhooks"
+	"GYMBRO/internal/storage"
+	"log/slog"
+	"net/http"
+)
+
+type WebhooksHandlerFactory interface {
+	CreateCreateHandler() http.HandlerFunc
+	CreateListHandler() http.HandlerFunc
+	CreateDeleteHandler() http.HandlerFunc
+	CreateDeliveriesHandler() http.HandlerFunc
+	CreateDeadLettersHandler() http.HandlerFunc
+	CreateRetryHandler() http.HandlerFunc
+}
+
+type WebhookHandlerFactory struct {
+	log         *slog.Logger
+	webhookRepo storage.WebhookRepository
+	cfg         *config.Config
+}
+
+func NewWebhookHandlerFactory(log *slog.Logger, webhookRepo storage.WebhookRepository, cfg *config.Config) *WebhookHandlerFactory {
+	return &WebhookHandlerFactory{
+		log:         log,
+		webhookRepo: webhookRepo,
+		cfg:         cfg,
+	}
+}
+
+func (f *WebhookHandlerFactory) CreateCreateHandler() http.HandlerFunc {
+	return webhooks.NewCreateHandler(f.log, f.webhookRepo, f.cfg)
+}
+
+func (f *WebhookHandlerFactory) CreateListHandler() http.HandlerFunc {
+	return webhooks.NewListHandler(f.log, f.webhookRepo)
+}
+
+func (f *WebhookHandlerFactory) CreateDeleteHandler() http.HandlerFunc {
+	return webhooks.NewDeleteHandler(f.log, f.webhookRepo)
+}
+
+func (f *WebhookHandlerFactory) CreateDeliveriesHandler() http.HandlerFunc {
+	return webhooks.NewDeliveriesHandler(f.log, f.webhookRepo)
+}
+
+func (f *WebhookHandlerFactory) CreateDeadLettersHandler() http.HandlerFunc {
+	return webhooks.NewDeadLettersHandler(f.log, f.webhookRepo)
+}
+
+func (f *WebhookHandlerFactory) CreateRetryHandler() http.HandlerFunc {
+	return webhooks.NewRetryHandler(f.log, f.webhookRepo)
+}
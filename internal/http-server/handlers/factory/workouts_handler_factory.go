@@ -2,8 +2,11 @@ package factory
 
 import (
 	"GYMBRO/internal/http-server/handlers/workouts/end"
+	"GYMBRO/internal/http-server/handlers/workouts/export"
 	getwo "GYMBRO/internal/http-server/handlers/workouts/get"
 	"GYMBRO/internal/http-server/handlers/workouts/start"
+	"GYMBRO/internal/http-server/handlers/workouts/stream"
+	"GYMBRO/internal/lib/finalizer"
 	"GYMBRO/internal/storage"
 	"log/slog"
 	"net/http"
@@ -13,32 +16,48 @@ type WorkoutsHandlerFactory interface {
 	CreateStartHandler() http.HandlerFunc
 	CreateEndHandler() http.HandlerFunc
 	CreateGetWorkoutHandler() http.HandlerFunc
+	CreateExportHandler() http.HandlerFunc
+	CreateStreamHandler(done <-chan struct{}) http.HandlerFunc
 }
 
 type WorkoutHandlerFactory struct {
 	log         *slog.Logger
 	workoutRepo storage.WorkoutRepository
 	sessionRepo storage.SessionRepository
+	subscriber  storage.SessionSubscriber
 	userRepo    storage.UserRepository
+	gymRepo     storage.GymRepository
+	finalizer   *finalizer.Finalizer
 }
 
-func NewWorkoutHandlerFactory(log *slog.Logger, workoutRepo storage.WorkoutRepository, sessionRepo storage.SessionRepository, userRepo storage.UserRepository) *WorkoutHandlerFactory {
+func NewWorkoutHandlerFactory(log *slog.Logger, workoutRepo storage.WorkoutRepository, sessionRepo storage.SessionRepository, subscriber storage.SessionSubscriber, userRepo storage.UserRepository, gymRepo storage.GymRepository, finalizer *finalizer.Finalizer) *WorkoutHandlerFactory {
 	return &WorkoutHandlerFactory{
 		log:         log,
 		workoutRepo: workoutRepo,
 		sessionRepo: sessionRepo,
+		subscriber:  subscriber,
 		userRepo:    userRepo,
+		gymRepo:     gymRepo,
+		finalizer:   finalizer,
 	}
 }
 
 func (f *WorkoutHandlerFactory) CreateStartHandler() http.HandlerFunc {
-	return start.NewStartHandler(f.log, f.sessionRepo, f.userRepo)
+	return start.NewStartHandler(f.log, f.sessionRepo, f.userRepo, f.gymRepo)
 }
 
 func (f *WorkoutHandlerFactory) CreateEndHandler() http.HandlerFunc {
-	return end.NewEndHandler(f.log, f.sessionRepo, f.workoutRepo, f.userRepo)
+	return end.NewEndHandler(f.log, f.sessionRepo, f.finalizer)
 }
 
 func (f *WorkoutHandlerFactory) CreateGetWorkoutHandler() http.HandlerFunc {
 	return getwo.NewGetWorkoutHandler(f.log, f.workoutRepo)
 }
+
+func (f *WorkoutHandlerFactory) CreateExportHandler() http.HandlerFunc {
+	return export.NewExportHandler(f.log, f.workoutRepo)
+}
+
+func (f *WorkoutHandlerFactory) CreateStreamHandler(done <-chan struct{}) http.HandlerFunc {
+	return stream.NewStreamHandler(f.log, f.sessionRepo, f.subscriber, done)
+}
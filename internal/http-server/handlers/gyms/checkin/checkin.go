@@ -0,0 +1,116 @@
+package checkin
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TokenTTL is how long a check-in QR code can be scanned, a screenshot sent to a friend is useless after it.
+const TokenTTL = 2 * time.Minute
+
+type VerifyRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// NewCheckInHandler creates an HTTP handler that checks the authenticated user in at the gym.
+// The user needs an active subscription to the gym, the response carries a short-lived token to show
+// as a QR code. (2 gymRepo calls)
+func NewCheckInHandler(log *slog.Logger, gymRepo storage.GymRepository, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.gyms.checkin.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		gymID, err := strconv.Atoi(chi.URLParam(r, "gymID"))
+		if err != nil {
+			log.Debug("Invalid gym ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid gym ID", "Gym ID should be a number")
+		}
+
+		now := time.Now()
+		subscription, err := gymRepo.GetActiveSubscription(r.Context(), &userID, gymID, now)
+		if err != nil {
+			log.Debug("Failed to GET active subscription", slog.Int("gym_id", gymID), slog.Any("error", err))
+			return err
+		}
+
+		checkIn := &storage.CheckIn{
+			CheckInID:        storage.GenerateUID(),
+			FkUserId:         userID,
+			FkGymId:          gymID,
+			FkSubscriptionId: subscription.SubscriptionId,
+			CreatedAt:        now,
+		}
+		if err := gymRepo.CreateCheckIn(r.Context(), checkIn); err != nil {
+			log.Error("Failed to CREATE check-in", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		expiresAt := now.Add(TokenTTL)
+		token, err := jwt.NewCheckInToken(checkIn, expiresAt, cfg.SecretKey)
+		if err != nil {
+			log.Error("Failed to sign check-in token", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Debug("Checked in", slog.Int("gym_id", gymID), slog.String("check_in_id", checkIn.CheckInID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewCheckInResponse(checkIn, subscription, token, expiresAt)))
+		return nil
+	})
+}
+
+// NewVerifyHandler creates an HTTP handler for gym staff that verifies a scanned check-in token.
+// The subscription is checked again, so a code of a subscription cancelled since is not accepted. (1 userRepo call, 1 gymRepo call)
+func NewVerifyHandler(log *slog.Logger, userRepo storage.UserRepository, gymRepo storage.GymRepository, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.gyms.checkin.NewVerify"
+		staffID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", staffID))
+
+		var request VerifyRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		token, err := jwt.ParseCheckInToken(request.Token, cfg.SecretKey)
+		if err != nil {
+			log.Debug("Invalid check-in token", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid or expired check-in code", "Ask the member to check in again")
+		}
+		log = log.With(slog.String("member_id", token.UserID), slog.Int("gym_id", token.GymID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &token.UserID)
+		if err != nil {
+			log.Debug("Failed to GET member", slog.Any("error", err))
+			return err
+		}
+
+		subscription, err := gymRepo.GetActiveSubscription(r.Context(), &token.UserID, token.GymID, time.Now())
+		if err != nil {
+			log.Debug("Failed to GET active subscription", slog.Any("error", err))
+			return err
+		}
+
+		log.Info("Check-in verified", slog.String("check_in_id", token.CheckInID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewCheckInVerificationResponse(token.CheckInID, user, subscription)))
+		return nil
+	})
+}
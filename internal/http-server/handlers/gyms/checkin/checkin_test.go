@@ -0,0 +1,234 @@
+package checkin_test
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/handlers/gyms/checkin"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckInHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cfg := &config.Config{JWTCfg: config.JWTCfg{SecretKey: "test"}}
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	subscription := &storage.Subscription{SubscriptionId: "sub123", FkUserId: userIDValue, FkGymId: 7, EndDate: time.Now().AddDate(0, 1, 0)}
+
+	tests := []struct {
+		name               string
+		url                string
+		setupMock          func(gymRepo *mocks.GymRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name: "Success",
+			url:  "/gyms/7/check-in",
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("GetActiveSubscription", mock.Anything, userID, 7, mock.AnythingOfType("time.Time")).Return(subscription, nil)
+				gymRepo.On("CreateCheckIn", mock.Anything, mock.MatchedBy(func(c *storage.CheckIn) bool {
+					return c.FkUserId == userIDValue && c.FkGymId == 7 && c.FkSubscriptionId == "sub123"
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "InvalidGymID",
+			url:                "/gyms/abc/check-in",
+			setupMock:          func(gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name: "NoSubscription",
+			url:  "/gyms/7/check-in",
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("GetActiveSubscription", mock.Anything, userID, 7, mock.AnythingOfType("time.Time")).Return(nil, storage.ErrNoSubscription)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNoSubscription},
+		},
+		{
+			name: "CreateCheckInError",
+			url:  "/gyms/7/check-in",
+			setupMock: func(gymRepo *mocks.GymRepository) {
+				gymRepo.On("GetActiveSubscription", mock.Anything, userID, 7, mock.AnythingOfType("time.Time")).Return(subscription, nil)
+				gymRepo.On("CreateCheckIn", mock.Anything, mock.AnythingOfType("*storage.CheckIn")).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gymRepo := mocks.NewGymRepository(t)
+			tt.setupMock(gymRepo)
+
+			r := chi.NewRouter()
+			r.Post("/gyms/{gymID}/check-in", checkin.NewCheckInHandler(logger, gymRepo, cfg))
+
+			req := httptest.NewRequest("POST", tt.url, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data struct {
+					Token string `json:"token"`
+				} `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			if tt.expectedStatusCode == http.StatusOK {
+				token, err := jwt.ParseCheckInToken(response.Data.Token, cfg.SecretKey)
+				require.NoError(t, err)
+				require.Equal(t, userIDValue, token.UserID)
+				require.Equal(t, 7, token.GymID)
+			}
+
+			gymRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerifyHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cfg := &config.Config{JWTCfg: config.JWTCfg{SecretKey: "test"}}
+
+	memberIDValue := "user123"
+	memberID := &memberIDValue
+	checkIn := &storage.CheckIn{CheckInID: "check-in123", FkUserId: memberIDValue, FkGymId: 7}
+	subscription := &storage.Subscription{SubscriptionId: "sub123", FkUserId: memberIDValue, FkGymId: 7, EndDate: time.Now().AddDate(0, 1, 0)}
+
+	validToken, _ := jwt.NewCheckInToken(checkIn, time.Now().Add(checkin.TokenTTL), cfg.SecretKey)
+	expiredToken, _ := jwt.NewCheckInToken(checkIn, time.Now().Add(-time.Minute), cfg.SecretKey)
+	foreignToken, _ := jwt.NewCheckInToken(checkIn, time.Now().Add(checkin.TokenTTL), "another secret")
+	authToken, _ := jwt.NewToken(storage.User{UserId: memberIDValue}, time.Minute, cfg.SecretKey)
+
+	tests := []struct {
+		name               string
+		request            checkin.VerifyRequest
+		setupMock          func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:    "Success",
+			request: checkin.VerifyRequest{Token: validToken},
+			setupMock: func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				userRepo.On("GetUserByID", mock.Anything, memberID).Return(&storage.User{UserId: memberIDValue, Username: "member"}, nil)
+				gymRepo.On("GetActiveSubscription", mock.Anything, memberID, 7, mock.AnythingOfType("time.Time")).Return(subscription, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "MissingToken",
+			request:            checkin.VerifyRequest{},
+			setupMock:          func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "ExpiredToken",
+			request:            checkin.VerifyRequest{Token: expiredToken},
+			setupMock:          func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "ForgedToken",
+			request:            checkin.VerifyRequest{Token: foreignToken},
+			setupMock:          func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "AuthTokenIsNotCheckInToken",
+			request:            checkin.VerifyRequest{Token: authToken},
+			setupMock:          func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:    "SubscriptionCancelled",
+			request: checkin.VerifyRequest{Token: validToken},
+			setupMock: func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				userRepo.On("GetUserByID", mock.Anything, memberID).Return(&storage.User{UserId: memberIDValue}, nil)
+				gymRepo.On("GetActiveSubscription", mock.Anything, memberID, 7, mock.AnythingOfType("time.Time")).Return(nil, storage.ErrNoSubscription)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNoSubscription},
+		},
+		{
+			name:    "MemberNotFound",
+			request: checkin.VerifyRequest{Token: validToken},
+			setupMock: func(userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				userRepo.On("GetUserByID", mock.Anything, memberID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			gymRepo := mocks.NewGymRepository(t)
+			tt.setupMock(userRepo, gymRepo)
+
+			handler := checkin.NewVerifyHandler(logger, userRepo, gymRepo, cfg)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/gyms/check-ins/verify", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "staff123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			userRepo.AssertExpectations(t)
+			gymRepo.AssertExpectations(t)
+		})
+	}
+}
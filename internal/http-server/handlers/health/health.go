@@ -0,0 +1,135 @@
+package health
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Readiness is flipped by the server when it starts shutting down, so the orchestrator
+// stops sending new requests before the listener is closed.
+type Readiness struct {
+	draining  atomic.Bool
+	initOnce  sync.Once
+	drainOnce sync.Once
+	done      chan struct{}
+}
+
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+	r.drainOnce.Do(func() { close(r.doneChan()) })
+}
+
+// Done is closed when the server starts shutting down. Long-lived responses (event streams) end then,
+// the server would wait for them until the shutdown timeout otherwise.
+func (r *Readiness) Done() <-chan struct{} {
+	return r.doneChan()
+}
+
+func (r *Readiness) doneChan() chan struct{} {
+	r.initOnce.Do(func() { r.done = make(chan struct{}) })
+	return r.done
+}
+
+func (r *Readiness) Draining() bool {
+	return r.draining.Load()
+}
+
+type Check struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Version   *uint   `json:"version,omitempty"`
+	Expected  *uint   `json:"expected,omitempty"`
+}
+
+type Result struct {
+	Draining bool              `json:"draining"`
+	Checks   map[string]*Check `json:"checks,omitempty"`
+}
+
+// NewLivenessHandler creates an HTTP handler that only says the process is up and serving.
+func NewLivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+	}
+}
+
+// NewReadinessHandler creates an HTTP handler that checks the dependencies: pings Postgres and Redis
+// and compares the applied migration version with the one the server was built for.
+// It fails without checking anything once the server is shutting down. (2 pings, 1 migrationRepo call)
+func NewReadinessHandler(log *slog.Logger, readiness *Readiness, db storage.Pinger, redis storage.Pinger, migrationRepo storage.MigrationRepository, expectedVersion uint) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.health.NewReadiness"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())))
+
+		if readiness.Draining() {
+			return resp.NewError(http.StatusServiceUnavailable, resp.CodeNotReady, "Server is shutting down", "Send requests to another instance").WithData(Result{Draining: true})
+		}
+
+		result := Result{Checks: map[string]*Check{
+			"postgres": runCheck(r.Context(), db.Ping),
+			"redis":    runCheck(r.Context(), redis.Ping),
+		}}
+
+		var version uint
+		migrationsCheck := runCheck(r.Context(), func(ctx context.Context) error {
+			var (
+				dirty bool
+				err   error
+			)
+			version, dirty, err = migrationRepo.MigrationVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if dirty {
+				return fmt.Errorf("migration %d is dirty", version)
+			}
+			if version != expectedVersion {
+				return fmt.Errorf("database is at version %d, expected %d", version, expectedVersion)
+			}
+			return nil
+		})
+		migrationsCheck.Version, migrationsCheck.Expected = &version, &expectedVersion
+		result.Checks["migrations"] = migrationsCheck
+
+		for name, check := range result.Checks {
+			if check.Status != resp.StatusOK {
+				log.Warn("Dependency check failed", slog.String("dependency", name), slog.String("error", check.Error))
+				return resp.NewError(http.StatusServiceUnavailable, resp.CodeNotReady, "Service is not ready", "Some dependencies are unavailable, see data.checks").WithData(result)
+			}
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(result))
+		return nil
+	})
+}
+
+func runCheck(ctx context.Context, fn func(context.Context) error) *Check {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	check := &Check{
+		Status:    resp.StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = resp.StatusError
+		check.Error = err.Error()
+	}
+	return check
+}
@@ -0,0 +1,170 @@
+package health_test
+
+import (
+	"GYMBRO/internal/http-server/handlers/health"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage/mocks"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	health.NewLivenessHandler().ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response resp.DetailedResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Equal(t, resp.StatusOK, response.Status)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	const expectedVersion uint = 4
+
+	tests := []struct {
+		name               string
+		draining           bool
+		setupMock          func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+		failedChecks       []string
+	}{
+		{
+			name: "Success",
+			setupMock: func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {
+				db.On("Ping", mock.Anything).Return(nil)
+				redis.On("Ping", mock.Anything).Return(nil)
+				migrationRepo.On("MigrationVersion", mock.Anything).Return(expectedVersion, false, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "PostgresDown",
+			setupMock: func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {
+				db.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+				redis.On("Ping", mock.Anything).Return(nil)
+				migrationRepo.On("MigrationVersion", mock.Anything).Return(uint(0), false, errors.New("connection refused"))
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotReady},
+			failedChecks:       []string{"postgres", "migrations"},
+		},
+		{
+			name: "RedisDown",
+			setupMock: func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {
+				db.On("Ping", mock.Anything).Return(nil)
+				redis.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+				migrationRepo.On("MigrationVersion", mock.Anything).Return(expectedVersion, false, nil)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotReady},
+			failedChecks:       []string{"redis"},
+		},
+		{
+			name: "OutdatedMigrations",
+			setupMock: func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {
+				db.On("Ping", mock.Anything).Return(nil)
+				redis.On("Ping", mock.Anything).Return(nil)
+				migrationRepo.On("MigrationVersion", mock.Anything).Return(expectedVersion-1, false, nil)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotReady},
+			failedChecks:       []string{"migrations"},
+		},
+		{
+			name: "DirtyMigration",
+			setupMock: func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {
+				db.On("Ping", mock.Anything).Return(nil)
+				redis.On("Ping", mock.Anything).Return(nil)
+				migrationRepo.On("MigrationVersion", mock.Anything).Return(expectedVersion, true, nil)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotReady},
+			failedChecks:       []string{"migrations"},
+		},
+		{
+			name:               "Draining",
+			draining:           true,
+			setupMock:          func(db *mocks.Pinger, redis *mocks.Pinger, migrationRepo *mocks.MigrationRepository) {},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotReady},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewPinger(t)
+			redis := mocks.NewPinger(t)
+			migrationRepo := mocks.NewMigrationRepository(t)
+			tt.setupMock(db, redis, migrationRepo)
+
+			readiness := &health.Readiness{}
+			if tt.draining {
+				readiness.Drain()
+			}
+
+			handler := health.NewReadinessHandler(logger, readiness, db, redis, migrationRepo, expectedVersion)
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data health.Result `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			require.Equal(t, tt.draining, response.Data.Draining)
+			for _, name := range tt.failedChecks {
+				require.Equal(t, resp.StatusError, response.Data.Checks[name].Status)
+			}
+			if !tt.draining {
+				require.Len(t, response.Data.Checks, 3)
+			}
+
+			db.AssertExpectations(t)
+			redis.AssertExpectations(t)
+			migrationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReadinessDone(t *testing.T) {
+	readiness := &health.Readiness{}
+
+	select {
+	case <-readiness.Done():
+		t.Fatal("Done is closed before Drain")
+	default:
+	}
+
+	readiness.Drain()
+	readiness.Drain()
+
+	select {
+	case <-readiness.Done():
+	default:
+		t.Fatal("Done is not closed after Drain")
+	}
+	require.True(t, readiness.Draining())
+}
@@ -0,0 +1,21 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// Spec is the OpenAPI 3 document describing every route of the API.
+// Keep it in sync with the router and the request / response structs, tests will fail otherwise.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// NewHandler creates an HTTP handler that serves the OpenAPI document.
+func NewHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(Spec)
+	}
+}
@@ -0,0 +1,172 @@
+package openapi_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/admin/challenges"
+	"GYMBRO/internal/http-server/handlers/admin/subscriptions"
+	"GYMBRO/internal/http-server/handlers/admin/users"
+	"GYMBRO/internal/http-server/handlers/gyms/checkin"
+	"GYMBRO/internal/http-server/handlers/health"
+	"GYMBRO/internal/http-server/handlers/openapi"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/users/login"
+	"GYMBRO/internal/http-server/handlers/users/merge"
+	"GYMBRO/internal/http-server/handlers/users/profile"
+	"GYMBRO/internal/http-server/handlers/webhooks"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type schema struct {
+	Required   []string               `json:"required"`
+	Properties map[string]interface{} `json:"properties"`
+	Enum       []string               `json:"enum"`
+}
+
+type document struct {
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadSpec(t *testing.T) document {
+	var doc document
+	require.NoError(t, json.Unmarshal(openapi.Spec, &doc))
+	return doc
+}
+
+func TestHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	openapi.NewHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	require.True(t, json.Valid(rr.Body.Bytes()))
+}
+
+// TestSchemasMatchStructs checks that every schema has exactly the JSON fields of its struct
+// and that fields required by the validator are required in the schema too.
+func TestSchemasMatchStructs(t *testing.T) {
+	doc := loadSpec(t)
+
+	structs := map[string]interface{}{
+		"DetailedResponse":     resp.DetailedResponse{},
+		"Problem":              resp.Problem{},
+		"RegisterRequest":      dto.RegisterRequest{},
+		"LoginRequest":         login.Request{},
+		"MergeRequest":         merge.Request{},
+		"RoleRequest":          users.RoleRequest{},
+		"Identity":             dto.IdentityResponse{},
+		"BodyWeightRequest":    profile.BodyWeightRequest{},
+		"PrivacyRequest":       profile.PrivacyRequest{},
+		"Profile":              dto.ProfileResponse{},
+		"Achievement":          dto.AchievementResponse{},
+		"Follow":               dto.FollowResponse{},
+		"FeedEvent":            dto.EventResponse{},
+		"Feed":                 dto.FeedResponse{},
+		"Presence":             dto.PresenceResponse{},
+		"GymPresence":          dto.GymPresenceResponse{},
+		"SubscriptionRequest":  subscriptions.Request{},
+		"Subscription":         dto.SubscriptionResponse{},
+		"CheckIn":              dto.CheckInResponse{},
+		"VerifyCheckInRequest": checkin.VerifyRequest{},
+		"CheckInVerification":  dto.CheckInVerificationResponse{},
+		"ChallengeRequest":     challenges.Request{},
+		"Challenge":            dto.ChallengeResponse{},
+		"ChallengeResult":      dto.ChallengeResultResponse{},
+		"ChallengeProgress":    dto.ChallengeProgressResponse{},
+		"ClanResult":           dto.ClanResultResponse{},
+		"ClanResults":          dto.ClanResultsResponse{},
+		"Season":               dto.SeasonResponse{},
+		"Standing":             dto.StandingResponse{},
+		"Standings":            dto.StandingsResponse{},
+		"RecordRequest":        dto.RecordRequest{},
+		"Record":               dto.RecordResponse{},
+		"FlaggedRecord":        dto.FlaggedRecordResponse{},
+		"WebhookRequest":       webhooks.Request{},
+		"Webhook":              dto.WebhookResponse{},
+		"WebhookDelivery":      dto.DeliveryResponse{},
+		"WorkoutWithRecords":   dto.WorkoutResponse{},
+		"WorkoutSession":       dto.SessionResponse{},
+		"ExerciseRequest":      dto.ExerciseRequest{},
+		"Exercise":             dto.ExerciseResponse{},
+		"MuscleGroupRequest":   dto.MuscleGroupRequest{},
+		"MuscleGroup":          dto.MuscleGroupResponse{},
+		"Volume":               dto.VolumeResponse{},
+		"VolumePeriod":         dto.VolumePeriodResponse{},
+		"MuscleGroupVolume":    dto.MuscleGroupVolumeResponse{},
+		"Progress":             dto.ProgressResponse{},
+		"ProgressPoint":        dto.ProgressPointResponse{},
+		"TopSet":               dto.TopSetResponse{},
+		"ReadinessResult":      health.Result{},
+		"ReadinessCheck":       health.Check{},
+	}
+
+	for name, value := range structs {
+		t.Run(name, func(t *testing.T) {
+			s, ok := doc.Components.Schemas[name]
+			require.True(t, ok, "schema %s is missing", name)
+
+			var fields, required []string
+			typ := reflect.TypeOf(value)
+			for i := 0; i < typ.NumField(); i++ {
+				field := typ.Field(i)
+				jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+				if jsonName == "" || jsonName == "-" {
+					continue
+				}
+				fields = append(fields, jsonName)
+				if slices.Contains(strings.Split(field.Tag.Get("validate"), ","), "required") {
+					required = append(required, jsonName)
+				}
+			}
+
+			var properties []string
+			for property := range s.Properties {
+				properties = append(properties, property)
+			}
+			require.ElementsMatch(t, fields, properties, "properties of %s differ from the struct", name)
+			require.Subset(t, s.Required, required, "fields required by the validator are not required in %s", name)
+		})
+	}
+}
+
+// TestErrorCodes checks that ErrorCode lists exactly the Code* constants of the response package.
+func TestErrorCodes(t *testing.T) {
+	doc := loadSpec(t)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../response/response.go", nil, 0)
+	require.NoError(t, err)
+
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+				continue
+			}
+			lit, ok := spec.Values[i].(*ast.BasicLit)
+			require.True(t, ok, "%s is not a string literal", name.Name)
+			code, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			codes = append(codes, code)
+		}
+		return true
+	})
+
+	require.NotEmpty(t, codes)
+	require.ElementsMatch(t, codes, doc.Components.Schemas["ErrorCode"].Enum)
+}
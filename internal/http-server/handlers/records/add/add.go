@@ -1,8 +1,12 @@
 package add
 
 import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/plausibility"
 	"GYMBRO/internal/lib/points"
 	"GYMBRO/internal/lib/validation"
 	"GYMBRO/internal/storage"
@@ -14,52 +18,60 @@ import (
 )
 
 // NewAddHandler creates an HTTP handler for adding a new workout record.
-// It decodes the request, validates it, updates the workout session with new points,
-// and responds with the appropriate status. (2 sessionRepo calls, 1 userRepo call)
-func NewAddHandler(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// It decodes the request, validates it, checks the record is plausible, updates the workout session with new points,
+// and responds with the saved record. Held records keep their points for the review but do not add them to the session,
+// excluded records earn none, and both flag the user. (2 sessionRepo calls, 1 exerciseRepo call, 1-2 userRepo calls)
+func NewAddHandler(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository, exerciseRepo storage.ExerciseRepository, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.workouts.records.add.New"
 		userID := jwt.GetUserIDFromContext(r.Context())
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
 
-		var record storage.Record
-		if err := render.DecodeJSON(r.Body, &record); err != nil {
-			log.Warn("Failed to decode request", slog.Any("error", err), slog.Any("request", r.Body))
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("Failed to decode request", resp.CodeBadRequest, "Check the request fields for typos or naming errors"))
-			return
+		var request dto.RecordRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
 		}
-		log.Debug("Request body decoded", slog.Any("record", record))
+		log.Debug("Request body decoded", slog.Any("record", request))
 
-		if err := validation.ValidateStruct(log, &record); err != nil {
-			validation.HandleValidationError(w, r, err)
-			return
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
 		}
+		record := request.ToRecord()
 
-		activeSession, err := sessionRepo.GetSession(&userID)
+		activeSession, err := sessionRepo.GetSession(r.Context(), &userID)
 		if err != nil {
 			log.Error("Can't GET session", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		record.FkWorkoutId = activeSession.SessionID
 		record.RecordId = storage.GenerateUID()
 
+		exercise, err := exerciseRepo.GetExercise(r.Context(), record.FkExerciseId)
+		if err != nil {
+			if errors.Is(err, storage.ErrExerciseNotFound) {
+				log.Debug("Exercise not found", slog.Int("exercise_id", record.FkExerciseId))
+				return err
+			}
+			log.Error("Failed to GET exercise", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
 		hasMax := true
-		userMax, err := userRepo.GetUserMax(&userID, &record.FkExerciseId)
+		userMax, err := userRepo.GetUserMax(r.Context(), &userID, &record.FkExerciseId)
 		if err != nil {
 			if errors.Is(err, storage.ErrNoMaxes) {
 				hasMax = false
 			} else {
 				log.Error("Failed to GET userMax", slog.Any("error", err))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-				return
+				return resp.Internal(err)
 			}
 		}
 
+		verdict := plausibility.Check(cfg.PlausibilityCfg, exercise, userMax, record)
+		record.Status, record.Reason = verdict.Status, verdict.Reason
+
 		if !hasMax || (userMax != nil && userMax.MaxWeight < record.Weight) {
 			userMax = &storage.Max{
 				UserID:     userID,
@@ -70,18 +82,32 @@ func NewAddHandler(log *slog.Logger, sessionRepo storage.SessionRepository, user
 		}
 
 		record.Points = points.CalculatePoints(userMax.MaxWeight, userMax.Reps, record.Weight, record.Reps, 100)
+		if record.Status == storage.RecordExcluded {
+			record.Points = 0
+		}
 
-		activeSession.Records = append(activeSession.Records, record)
-		activeSession.Points += record.Points
+		activeSession.Records = append(activeSession.Records, *record)
+		if record.Counts() {
+			activeSession.Points += record.Points
+		}
 
-		if err := sessionRepo.UpdateSession(&userID, activeSession); err != nil {
+		if err := sessionRepo.UpdateSession(r.Context(), &userID, activeSession); err != nil {
 			log.Error("Failed to UPDATE session", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
+		}
+		metrics.RecordsAdded.Inc()
+
+		if !record.Counts() {
+			metrics.RecordsFlagged.WithLabelValues(record.Status).Inc()
+			log.Info("Record flagged", slog.String("record_id", record.RecordId), slog.String("status", record.Status), slog.String("reason", record.Reason))
+			if err := userRepo.SetUserFlagged(r.Context(), &userID, true); err != nil {
+				log.Error("Failed to FLAG user", slog.Any("error", err))
+				return resp.Internal(err)
+			}
 		}
 
 		render.Status(r, http.StatusOK)
-		render.JSON(w, r, resp.OK())
-	}
+		render.JSON(w, r, resp.Data(dto.NewRecordResponse(record)))
+		return nil
+	})
 }
@@ -1,6 +1,8 @@
 package add_test
 
 import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
 	"GYMBRO/internal/http-server/handlers/records/add"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/lib/jwt"
@@ -22,14 +24,14 @@ import (
 func TestAddHandler(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 
-	validRecord := storage.Record{
-		RecordId:     "record123",
-		FkWorkoutId:  "session123",
+	validRecord := dto.RecordRequest{
 		FkExerciseId: 1,
 		Reps:         10,
 		Weight:       100,
 	}
 
+	cfg := &config.Config{PlausibilityCfg: config.PlausibilityCfg{DefaultWeightLimit: 500, MaxJump: 1.3}}
+
 	userIDValue := "user123"
 	userID := &userIDValue
 
@@ -37,40 +39,44 @@ func TestAddHandler(t *testing.T) {
 		name               string
 		userID             string
 		reqBody            interface{}
-		setupMock          func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository)
+		setupMock          func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository)
 		expectedStatusCode int
 		expectedResponse   resp.DetailedResponse
+		// expectedStatus is the status of the added record, ok if empty
+		expectedStatus string
 	}{
 		{
 			name:    "Success",
 			userID:  "user123",
 			reqBody: validRecord,
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 				}, nil)
-				userRepo.On("GetUserMax", userID, &validRecord.FkExerciseId).Return(&storage.Max{
-					MaxWeight: 10,
+				exerciseRepo.On("GetExercise", mock.Anything, validRecord.FkExerciseId).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(&storage.Max{
+					MaxWeight: 90,
 					Reps:      10,
 				}, nil)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(nil)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
 		},
 		{
-			name:               "InvalidRequest",
-			userID:             "user123",
-			reqBody:            "xxx",
-			setupMock:          func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {},
+			name:    "InvalidRequest",
+			userID:  "user123",
+			reqBody: "xxx",
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
 		},
 		{
 			name:    "ValidationError",
 			userID:  "user123",
-			reqBody: storage.Record{FkWorkoutId: "", RecordId: ""},
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
+			reqBody: dto.RecordRequest{},
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
 			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
@@ -79,8 +85,8 @@ func TestAddHandler(t *testing.T) {
 			name:    "SessionNotFound",
 			userID:  "user123",
 			reqBody: validRecord,
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -89,15 +95,16 @@ func TestAddHandler(t *testing.T) {
 			name:    "UpdateSessionError",
 			userID:  "user123",
 			reqBody: validRecord,
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 				}, nil)
-				userRepo.On("GetUserMax", userID, &validRecord.FkExerciseId).Return(&storage.Max{
-					MaxWeight: 10,
+				exerciseRepo.On("GetExercise", mock.Anything, validRecord.FkExerciseId).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(&storage.Max{
+					MaxWeight: 90,
 					Reps:      10,
 				}, nil)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(errors.New("db error"))
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -106,12 +113,13 @@ func TestAddHandler(t *testing.T) {
 			name:    "NoMax",
 			userID:  "user123",
 			reqBody: validRecord,
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 				}, nil)
-				userRepo.On("GetUserMax", userID, &validRecord.FkExerciseId).Return(nil, storage.ErrNoMaxes)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(nil)
+				exerciseRepo.On("GetExercise", mock.Anything, validRecord.FkExerciseId).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(nil, storage.ErrNoMaxes)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -120,23 +128,116 @@ func TestAddHandler(t *testing.T) {
 			name:    "CantGetMax",
 			userID:  "user123",
 			reqBody: validRecord,
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
+					SessionID: "session123",
+				}, nil)
+				exerciseRepo.On("GetExercise", mock.Anything, validRecord.FkExerciseId).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(nil, errors.New("some error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:    "Held",
+			userID:  "user123",
+			reqBody: dto.RecordRequest{FkExerciseId: 1, Reps: 10, Weight: 200},
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
+					SessionID: "session123",
+				}, nil)
+				exerciseRepo.On("GetExercise", mock.Anything, 1).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(&storage.Max{
+					MaxWeight: 100,
+					Reps:      10,
+				}, nil)
+				// the held record keeps its points for the review, the session does not get them
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.MatchedBy(func(session *storage.WorkoutSession) bool {
+					return session.Points == 0 && session.Records[0].Status == storage.RecordHeld && session.Records[0].Points == 100
+				})).Return(nil)
+				userRepo.On("SetUserFlagged", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordHeld,
+		},
+		{
+			name:    "OverDefaultLimit",
+			userID:  "user123",
+			reqBody: dto.RecordRequest{FkExerciseId: 1, Reps: 1, Weight: 501},
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
+					SessionID: "session123",
+				}, nil)
+				exerciseRepo.On("GetExercise", mock.Anything, 1).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(nil, storage.ErrNoMaxes)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.MatchedBy(func(session *storage.WorkoutSession) bool {
+					return session.Points == 0 && session.Records[0].Status == storage.RecordExcluded && session.Records[0].Points == 0
+				})).Return(nil)
+				userRepo.On("SetUserFlagged", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordExcluded,
+		},
+		{
+			name:    "OverExerciseLimit",
+			userID:  "user123",
+			reqBody: validRecord,
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
+					SessionID: "session123",
+				}, nil)
+				exerciseRepo.On("GetExercise", mock.Anything, 1).Return(&storage.Exercise{ExerciseId: 1, WeightLimit: 80}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(&storage.Max{
+					MaxWeight: 100,
+					Reps:      10,
+				}, nil)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
+				userRepo.On("SetUserFlagged", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.RecordExcluded,
+		},
+		{
+			name:    "CantFlagUser",
+			userID:  "user123",
+			reqBody: dto.RecordRequest{FkExerciseId: 1, Reps: 1, Weight: 600},
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 				}, nil)
-				userRepo.On("GetUserMax", userID, &validRecord.FkExerciseId).Return(nil, errors.New("some error"))
+				exerciseRepo.On("GetExercise", mock.Anything, 1).Return(&storage.Exercise{ExerciseId: 1}, nil)
+				userRepo.On("GetUserMax", mock.Anything, userID, &validRecord.FkExerciseId).Return(nil, storage.ErrNoMaxes)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
+				userRepo.On("SetUserFlagged", mock.Anything, userID, true).Return(errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
 		},
+		{
+			name:    "ExerciseNotFound",
+			userID:  "user123",
+			reqBody: validRecord,
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
+					SessionID: "session123",
+				}, nil)
+				exerciseRepo.On("GetExercise", mock.Anything, 1).Return(nil, storage.ErrExerciseNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionRepo := new(mocks.SessionRepository)
 			userRepo := new(mocks.UserRepository)
+			exerciseRepo := new(mocks.ExerciseRepository)
 
-			tt.setupMock(sessionRepo, userRepo)
+			tt.setupMock(sessionRepo, userRepo, exerciseRepo)
 
 			reqBody, err := json.Marshal(tt.reqBody)
 			require.NoError(t, err)
@@ -147,7 +248,7 @@ func TestAddHandler(t *testing.T) {
 
 			rr := httptest.NewRecorder()
 
-			handler := add.NewAddHandler(logger, sessionRepo, userRepo)
+			handler := add.NewAddHandler(logger, sessionRepo, userRepo, exerciseRepo, cfg)
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, tt.expectedStatusCode, rr.Code)
@@ -157,6 +258,19 @@ func TestAddHandler(t *testing.T) {
 			require.NoError(t, err)
 
 			require.Equal(t, tt.expectedResponse.Status, actualResp.Status)
+			if rr.Code == http.StatusOK {
+				expectedStatus := tt.expectedStatus
+				if expectedStatus == "" {
+					expectedStatus = storage.RecordOK
+				}
+				require.Equal(t, expectedStatus, actualResp.Data.(map[string]interface{})["status"])
+			} else {
+				require.Equal(t, tt.expectedResponse.Code, actualResp.Code)
+			}
+
+			sessionRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			exerciseRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -15,17 +15,15 @@ import (
 // It retrieves the user's active session, removes the specified record,
 // updates the session, and adjusts the user's points. (2 sessionRepo calls)
 func NewDeleteHandler(log *slog.Logger, sessionRepo storage.SessionRepository) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.workouts.records.delete.New"
 		userID := jwt.GetUserIDFromContext(r.Context())
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
 
-		activeSession, err := sessionRepo.GetSession(&userID)
+		activeSession, err := sessionRepo.GetSession(r.Context(), &userID)
 		if err != nil {
 			log.Error("Cant GET session", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		points := 0
@@ -35,7 +33,10 @@ func NewDeleteHandler(log *slog.Logger, sessionRepo storage.SessionRepository) h
 		found := false
 		for i, record := range activeSession.Records {
 			if record.RecordId == recordID {
-				points = record.Points
+				// held and excluded records never added their points to the session
+				if record.Counts() {
+					points = record.Points
+				}
 				activeSession.Records = append(activeSession.Records[:i], activeSession.Records[i+1:]...)
 				found = true
 				break
@@ -43,21 +44,18 @@ func NewDeleteHandler(log *slog.Logger, sessionRepo storage.SessionRepository) h
 		}
 		if !found {
 			log.Debug("Record not found", slog.Any("record_id", recordID))
-			render.Status(r, http.StatusNotFound)
-			render.JSON(w, r, resp.Error("Record not found", resp.CodeNotFound, "Maybe this record doesnt exist"))
-			return
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "Record not found", "Maybe this record doesnt exist")
 		}
 
 		activeSession.Points -= points
 
-		if err := sessionRepo.UpdateSession(&userID, activeSession); err != nil {
+		if err := sessionRepo.UpdateSession(r.Context(), &userID, activeSession); err != nil {
 			log.Error("Failed to UPDATE workout", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
-	}
+		return nil
+	})
 }
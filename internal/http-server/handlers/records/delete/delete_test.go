@@ -54,12 +54,12 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 					Records:   []storage.Record{record1, record2},
 					Points:    1000,
 				}, nil)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(nil)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -69,7 +69,7 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "nonexistentRecord",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 					Records:   []storage.Record{record1, record2},
 					Points:    1000,
@@ -83,7 +83,7 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, errors.New("db error"))
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -93,12 +93,12 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 					Records:   []storage.Record{record1, record2},
 					Points:    1000,
 				}, nil)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(errors.New("db error"))
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -108,7 +108,7 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -118,7 +118,7 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 					Records:   []storage.Record{},
 					Points:    1000,
@@ -132,12 +132,12 @@ func TestDeleteHandler(t *testing.T) {
 			userID:   "user123",
 			recordID: "record1",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", userID).Return(&storage.WorkoutSession{
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(&storage.WorkoutSession{
 					SessionID: "session123",
 					Records:   []storage.Record{record1},
 					Points:    1000,
 				}, nil)
-				sessionRepo.On("UpdateSession", userID, mock.Anything).Return(nil)
+				sessionRepo.On("UpdateSession", mock.Anything, userID, mock.Anything).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
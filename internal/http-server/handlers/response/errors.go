@@ -0,0 +1,183 @@
+package resp
+
+import (
+	"GYMBRO/internal/storage"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const ContentTypeProblem = "application/problem+json"
+
+// APIError is an error that knows how it should be shown to the client.
+// Handlers return it and WriteError writes it either as DetailedResponse or as an RFC 7807 problem.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Advice  string
+	Data    interface{}
+	// Err is the cause, it is never shown to the client
+	Err error
+}
+
+func NewError(status int, code, msg, advice string) *APIError {
+	return &APIError{Status: status, Code: code, Message: msg, Advice: advice}
+}
+
+// Internal returns the generic internal error, err is only kept as the cause.
+func Internal(err error) *APIError {
+	return &APIError{Status: http.StatusInternalServerError, Code: CodeInternalError, Message: "Internal error", Advice: "Please try again later", Err: err}
+}
+
+// AccountDisabled returns the error disabled users get, both at login and on every request with an older token.
+func AccountDisabled() *APIError {
+	return NewError(http.StatusForbidden, CodeAccountDisabled, "Your account is disabled", "Contact support if you think this is a mistake")
+}
+
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// WithData returns a copy of the error that carries data for the client.
+func (e *APIError) WithData(data interface{}) *APIError {
+	c := *e
+	c.Data = data
+	return &c
+}
+
+// Wrap returns a copy of the error with err as the cause.
+func (e *APIError) Wrap(err error) *APIError {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// storageErrors maps the storage sentinels to what the client sees when a handler returns them as is.
+var storageErrors = map[error]*APIError{
+	storage.ErrUserNotFound:        NewError(http.StatusNotFound, CodeNotFound, "User not found", "Check the user ID"),
+	storage.ErrUserExists:          NewError(http.StatusBadRequest, CodeUserExists, "User already exists", "User with this email already exists. Check email for typos or try to login"),
+	storage.ErrWorkoutNotFound:     NewError(http.StatusNotFound, CodeNotFound, "Workout not found", "The requested workout does not exist"),
+	storage.ErrNoSession:           NewError(http.StatusNotFound, CodeNoActiveWorkout, "No active workout at this time", "Start a workout first"),
+	storage.ErrNoMaxes:             NewError(http.StatusNotFound, CodeNotFound, "No maxes yet", "Add some records first"),
+	storage.ErrIdentityExists:      NewError(http.StatusConflict, CodeIdentityLinked, "This identity is linked to another account", "Unlink it there first"),
+	storage.ErrIdentityNotFound:    NewError(http.StatusNotFound, CodeNotFound, "Identity not found", "This provider is not linked to your account"),
+	storage.ErrExerciseNotFound:    NewError(http.StatusNotFound, CodeNotFound, "Exercise not found", "Check the exercise ID"),
+	storage.ErrExerciseExists:      NewError(http.StatusConflict, CodeAlreadyExists, "Exercise already exists", "Choose another name"),
+	storage.ErrExerciseInUse:       NewError(http.StatusConflict, CodeExerciseInUse, "Exercise is in use", "Records, maxes or challenges of users reference it, rename it instead"),
+	storage.ErrMuscleGroupNotFound: NewError(http.StatusNotFound, CodeNotFound, "Muscle group not found", "Check the muscle group ID"),
+	storage.ErrMuscleGroupExists:   NewError(http.StatusConflict, CodeAlreadyExists, "Muscle group already exists", "Choose another name"),
+	storage.ErrFollowExists:        NewError(http.StatusConflict, CodeAlreadyExists, "You already follow this user", "Wait for the user to accept your request if their profile is private"),
+	storage.ErrFollowNotFound:      NewError(http.StatusNotFound, CodeNotFound, "Follow not found", "Check the user ID"),
+	storage.ErrGymNotFound:         NewError(http.StatusNotFound, CodeNotFound, "Gym not found", "Check the gym ID"),
+	storage.ErrNoSubscription:      NewError(http.StatusForbidden, CodeNoSubscription, "No active subscription to this gym", "Buy or renew a subscription at the gym reception"),
+	storage.ErrCheckInNotFound:     NewError(http.StatusNotFound, CodeNotFound, "Check-in not found", "Check in at the gym first"),
+	storage.ErrClanNotFound:        NewError(http.StatusNotFound, CodeNotFound, "Clan not found", "Check the clan ID"),
+	storage.ErrChallengeNotFound:   NewError(http.StatusNotFound, CodeNotFound, "Challenge not found", "Check the challenge ID"),
+	storage.ErrChallengeSettled:    NewError(http.StatusConflict, CodeAlreadyExists, "Challenge already settled", "Its results are final"),
+	storage.ErrRecordNotFound:      NewError(http.StatusNotFound, CodeNotFound, "Record not found", "Check the record ID, records of active workouts can not be reviewed"),
+	storage.ErrRecordNotHeld:       NewError(http.StatusConflict, CodeRecordReviewed, "Record is not held", "Only held records can be approved"),
+	storage.ErrRecordVoided:        NewError(http.StatusConflict, CodeRecordReviewed, "Record already voided", "Its points were taken back already"),
+	storage.ErrSeasonNotFound:      NewError(http.StatusNotFound, CodeNotFound, "Season not found", "Check the season ID, the first season starts with the server"),
+	storage.ErrSeasonExists:        NewError(http.StatusConflict, CodeAlreadyExists, "Season already exists", "Another server started it, try again"),
+	storage.ErrSeasonEnded:         NewError(http.StatusConflict, CodeAlreadyExists, "Season already ended", "Another server rolled it over, try again"),
+	storage.ErrWebhookNotFound:     NewError(http.StatusNotFound, CodeNotFound, "Webhook not found", "Check the webhook ID"),
+	storage.ErrWebhookLimit:        NewError(http.StatusConflict, CodeWebhookLimit, "Too many webhooks", "Delete a webhook you no longer use first"),
+	storage.ErrDeliveryNotFound:    NewError(http.StatusNotFound, CodeNotFound, "Delivery not found", "Check the delivery ID"),
+	storage.ErrDeliveryNotDead:     NewError(http.StatusConflict, CodeDeliveryNotDead, "Delivery is not dead", "Only dead deliveries can be retried, pending ones are retried on their own"),
+}
+
+// FromError converts any error to an APIError: APIErrors are kept, storage sentinels are mapped,
+// everything else becomes the internal error.
+func FromError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	for sentinel, mapped := range storageErrors {
+		if errors.Is(err, sentinel) {
+			return mapped.Wrap(err)
+		}
+	}
+	return Internal(err)
+}
+
+// WriteError writes the error as an RFC 7807 problem if the client accepts application/problem+json,
+// and as DetailedResponse otherwise.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := FromError(err)
+
+	if !acceptsProblem(r) {
+		response := Error(apiErr.Message, apiErr.Code, apiErr.Advice)
+		response.Data = apiErr.Data
+		render.Status(r, apiErr.Status)
+		render.JSON(w, r, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(apiErr.Status)
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:      "urn:gymbro:problem:" + strings.ReplaceAll(strings.ToLower(apiErr.Code), "_", "-"),
+		Title:     apiErr.Message,
+		Status:    apiErr.Status,
+		Detail:    apiErr.Advice,
+		Instance:  r.URL.Path,
+		Code:      apiErr.Code,
+		RequestID: middleware.GetReqID(r.Context()),
+		Data:      apiErr.Data,
+	})
+}
+
+// Problem is the RFC 7807 problem details object, extended with the error code, request ID and data.
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	Code      string      `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+func acceptsProblem(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		if strings.TrimSpace(mediaType) == ContentTypeProblem {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerFunc is an HTTP handler that returns the error instead of writing it.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle adapts HandlerFunc to http.HandlerFunc, returned errors are written by WriteError.
+// Handlers log the APIErrors they build themselves, but an unknown error returned as is
+// was not expected by the handler, so it is logged here.
+func Handle(log *slog.Logger, h HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+		apiErr := FromError(err)
+		if !errors.As(err, new(*APIError)) && apiErr.Status >= http.StatusInternalServerError {
+			log.Error("Unexpected error", slog.Any("request_id", middleware.GetReqID(r.Context())), slog.Any("error", err))
+		}
+		WriteError(w, r, apiErr)
+	}
+}
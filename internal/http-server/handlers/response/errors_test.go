@@ -0,0 +1,115 @@
+package resp_test
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name           string
+		err            error
+		accept         string
+		expectedStatus int
+		expectedCode   string
+		expectedType   string
+		expectProblem  bool
+	}{
+		{
+			name:           "APIErrorEnvelope",
+			err:            resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Bad", "Fix it"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   resp.CodeBadRequest,
+		},
+		{
+			name:           "APIErrorProblem",
+			err:            resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Bad", "Fix it"),
+			accept:         "application/json;q=0.9, application/problem+json",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   resp.CodeBadRequest,
+			expectedType:   "urn:gymbro:problem:bad-request",
+			expectProblem:  true,
+		},
+		{
+			name:           "WrappedSentinel",
+			err:            fmt.Errorf("storage.postgresql.GetWorkout: %w", storage.ErrWorkoutNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   resp.CodeNotFound,
+		},
+		{
+			name:           "SentinelProblem",
+			err:            storage.ErrNoSession,
+			accept:         resp.ContentTypeProblem,
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   resp.CodeNoActiveWorkout,
+			expectedType:   "urn:gymbro:problem:no-active-workout",
+			expectProblem:  true,
+		},
+		{
+			name:           "UnknownError",
+			err:            errors.New("connection reset"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := resp.Handle(logger, func(w http.ResponseWriter, r *http.Request) error {
+				return tt.err
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/workouts/1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code)
+
+			if !tt.expectProblem {
+				var response resp.DetailedResponse
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+				require.Equal(t, resp.StatusError, response.Status)
+				require.Equal(t, tt.expectedCode, response.Code)
+				require.NotContains(t, response.Error, "connection reset")
+				return
+			}
+
+			require.Equal(t, resp.ContentTypeProblem, rr.Header().Get("Content-Type"))
+			var problem resp.Problem
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+			require.Equal(t, tt.expectedType, problem.Type)
+			require.Equal(t, tt.expectedStatus, problem.Status)
+			require.Equal(t, tt.expectedCode, problem.Code)
+			require.Equal(t, "/api/v1/workouts/1", problem.Instance)
+		})
+	}
+}
+
+func TestWithData(t *testing.T) {
+	base := resp.NewError(http.StatusServiceUnavailable, resp.CodeNotReady, "Not ready", "")
+	withData := base.WithData(map[string]bool{"draining": true})
+
+	require.Nil(t, base.Data)
+	require.NotNil(t, withData.Data)
+
+	rr := httptest.NewRecorder()
+	resp.WriteError(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil), withData)
+
+	var response resp.DetailedResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	require.Equal(t, map[string]interface{}{"draining": true}, response.Data)
+}
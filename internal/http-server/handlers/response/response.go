@@ -21,6 +21,19 @@ const (
 	CodeNoActiveWorkout = "NO_ACTIVE_WORKOUT"
 	CodeUnauthorized    = "UNAUTHORIZED"
 	CodeForbidden       = "FORBIDDEN"
+	CodeLinkRequired    = "LINK_REQUIRED"
+	CodeIdentityLinked  = "IDENTITY_LINKED"
+	CodeLastLoginMethod = "LAST_LOGIN_METHOD"
+	CodeAccountDisabled = "ACCOUNT_DISABLED"
+	CodeAlreadyExists   = "ALREADY_EXISTS"
+	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	CodeAccountLocked   = "ACCOUNT_LOCKED"
+	CodeNotReady        = "NOT_READY"
+	CodeNoSubscription  = "NO_SUBSCRIPTION"
+	CodeRecordReviewed  = "RECORD_REVIEWED"
+	CodeDeliveryNotDead = "DELIVERY_NOT_DEAD"
+	CodeExerciseInUse   = "EXERCISE_IN_USE"
+	CodeWebhookLimit    = "WEBHOOK_LIMIT"
 )
 
 func OK() DetailedResponse {
@@ -0,0 +1,60 @@
+package resp
+
+import (
+	"github.com/stretchr/testify/require"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// unmappedStorageErrors are the storage sentinels handlers never return as is, with the reason.
+// They become the internal error when they are returned anyway.
+var unmappedStorageErrors = map[string]string{}
+
+// TestStorageErrorsMapped fails when a storage sentinel is added without mapping it in storageErrors
+// or listing it in unmappedStorageErrors. Sentinels are found in the storage package by their errors.New message.
+func TestStorageErrorsMapped(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../../storage/storage.go", nil, 0)
+	require.NoError(t, err)
+
+	sentinels := make(map[string]string)
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Err") || i >= len(spec.Values) {
+				continue
+			}
+			call, ok := spec.Values[i].(*ast.CallExpr)
+			require.True(t, ok && len(call.Args) == 1, "%s is not errors.New", name.Name)
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			require.True(t, ok, "%s has no literal message", name.Name)
+			message, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			sentinels[name.Name] = message
+		}
+		return true
+	})
+	require.NotEmpty(t, sentinels)
+
+	mapped := make(map[string]bool, len(storageErrors))
+	for sentinel := range storageErrors {
+		mapped[sentinel.Error()] = true
+	}
+	for name, message := range sentinels {
+		_, excluded := unmappedStorageErrors[name]
+		if excluded {
+			require.False(t, mapped[message], "%s is mapped, remove it from unmappedStorageErrors", name)
+			continue
+		}
+		require.True(t, mapped[message], "%s is not in storageErrors, map it or list it in unmappedStorageErrors", name)
+	}
+	for name := range unmappedStorageErrors {
+		require.Contains(t, sentinels, name, "%s is not a storage sentinel", name)
+	}
+}
@@ -0,0 +1,103 @@
+package seasons
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+	// Current can be used instead of the ID of the current season.
+	Current = "current"
+)
+
+// NewListHandler creates an HTTP handler that lists the seasons, the current one first. (1 seasonRepo call)
+func NewListHandler(log *slog.Logger, seasonRepo storage.SeasonRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.seasons.NewList"
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())))
+
+		seasons, err := seasonRepo.GetSeasons(r.Context())
+		if err != nil {
+			log.Error("Failed to GET seasons", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewSeasonsResponse(seasons)))
+		return nil
+	})
+}
+
+// NewStandingsHandler creates an HTTP handler that returns the ranked users, clans or gyms of a season by their seasonal points.
+// They are computed live while the season runs and read from the archive once it ended. (2 seasonRepo calls)
+func NewStandingsHandler(log *slog.Logger, seasonRepo storage.SeasonRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.seasons.NewStandings"
+		param := chi.URLParam(r, "seasonID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", jwt.GetUserIDFromContext(r.Context())), slog.String("season_id", param))
+
+		kind := r.URL.Query().Get("kind")
+		switch kind {
+		case "":
+			kind = storage.StandingUsers
+		case storage.StandingUsers, storage.StandingClans, storage.StandingGyms:
+		default:
+			log.Debug("Invalid kind", slog.String("kind", kind))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid kind", "Use users, clans or gyms")
+		}
+
+		limit := DefaultLimit
+		if param := r.URL.Query().Get("limit"); param != "" {
+			parsed, err := strconv.Atoi(param)
+			if err != nil || parsed < 1 || parsed > MaxLimit {
+				log.Debug("Invalid limit", slog.String("limit", param))
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid limit", "Use a number from 1 to "+strconv.Itoa(MaxLimit))
+			}
+			limit = parsed
+		}
+
+		var (
+			season *storage.Season
+			err    error
+		)
+		if param == Current {
+			season, err = seasonRepo.GetCurrentSeason(r.Context())
+		} else {
+			seasonID, convErr := strconv.Atoi(param)
+			if convErr != nil {
+				log.Debug("Invalid season ID", slog.Any("error", convErr))
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid season ID", "Use a number or current")
+			}
+			season, err = seasonRepo.GetSeason(r.Context(), seasonID)
+		}
+		if err != nil {
+			log.Debug("Failed to GET season", slog.Any("error", err))
+			return err
+		}
+
+		var standings []*storage.Standing
+		if season.EndedAt != nil {
+			standings, err = seasonRepo.GetStandings(r.Context(), season.SeasonID, kind, limit)
+		} else {
+			standings, err = seasonRepo.GetLiveStandings(r.Context(), kind, limit)
+		}
+		if err != nil {
+			log.Error("Failed to GET standings", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewStandingsResponse(season, kind, standings)))
+		return nil
+	})
+}
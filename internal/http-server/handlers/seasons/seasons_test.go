@@ -0,0 +1,188 @@
+package seasons_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/seasons"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+)
+
+func TestSeasonHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	endedAt := time.Date(2024, 4, 1, 0, 5, 0, 0, time.UTC)
+	ended := &storage.Season{SeasonID: 1, StartTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), EndedAt: &endedAt}
+	current := &storage.Season{SeasonID: 2, StartTime: ended.EndTime, EndTime: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)}
+
+	archived := []*storage.Standing{
+		{FkSeasonId: 1, Kind: storage.StandingClans, SubjectID: "clan1", Name: "Lifters", Points: 130, Rank: 1},
+		{FkSeasonId: 1, Kind: storage.StandingClans, SubjectID: "clan2", Name: "Runners", Points: 130, Rank: 1},
+	}
+	live := []*storage.Standing{{Kind: storage.StandingUsers, SubjectID: "user123", Name: "bro", Points: 40, Rank: 1}}
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(seasonRepo *mocks.SeasonRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedData       interface{}
+	}{
+		{
+			name: "List",
+			path: "/seasons",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetSeasons", mock.Anything).Return([]*storage.Season{current, ended}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: []dto.SeasonResponse{
+				{SeasonId: 2, StartTime: current.StartTime, EndTime: current.EndTime, Status: dto.SeasonActive},
+				{SeasonId: 1, StartTime: ended.StartTime, EndTime: ended.EndTime, Status: dto.SeasonEnded, EndedAt: &endedAt},
+			},
+		},
+		{
+			name: "EmptyList",
+			path: "/seasons",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetSeasons", mock.Anything).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       []dto.SeasonResponse{},
+		},
+		{
+			name: "LiveStandings",
+			path: "/seasons/current/standings",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetCurrentSeason", mock.Anything).Return(current, nil)
+				seasonRepo.On("GetLiveStandings", mock.Anything, storage.StandingUsers, seasons.DefaultLimit).Return(live, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: dto.StandingsResponse{Season: dto.NewSeasonResponse(current), Kind: storage.StandingUsers,
+				Standings: []dto.StandingResponse{{Rank: 1, SubjectId: "user123", Name: "bro", Points: 40}}},
+		},
+		{
+			name: "CurrentByID",
+			path: "/seasons/2/standings?kind=gyms&limit=5",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetSeason", mock.Anything, 2).Return(current, nil)
+				seasonRepo.On("GetLiveStandings", mock.Anything, storage.StandingGyms, 5).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       dto.StandingsResponse{Season: dto.NewSeasonResponse(current), Kind: storage.StandingGyms, Standings: []dto.StandingResponse{}},
+		},
+		{
+			name: "ArchivedStandings",
+			path: "/seasons/1/standings?kind=clans",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetSeason", mock.Anything, 1).Return(ended, nil)
+				seasonRepo.On("GetStandings", mock.Anything, 1, storage.StandingClans, seasons.DefaultLimit).Return(archived, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: dto.StandingsResponse{Season: dto.NewSeasonResponse(ended), Kind: storage.StandingClans, Standings: []dto.StandingResponse{
+				{Rank: 1, SubjectId: "clan1", Name: "Lifters", Points: 130},
+				{Rank: 1, SubjectId: "clan2", Name: "Runners", Points: 130},
+			}},
+		},
+		{
+			name: "NoSeasonYet",
+			path: "/seasons/current/standings",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetCurrentSeason", mock.Anything).Return(nil, storage.ErrSeasonNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "SeasonNotFound",
+			path: "/seasons/9/standings",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetSeason", mock.Anything, 9).Return(nil, storage.ErrSeasonNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name:               "InvalidSeasonID",
+			path:               "/seasons/last/standings",
+			setupMock:          func(seasonRepo *mocks.SeasonRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "InvalidKind",
+			path:               "/seasons/current/standings?kind=exercises",
+			setupMock:          func(seasonRepo *mocks.SeasonRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "InvalidLimit",
+			path:               "/seasons/current/standings?limit=1000",
+			setupMock:          func(seasonRepo *mocks.SeasonRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name: "StandingsError",
+			path: "/seasons/current/standings",
+			setupMock: func(seasonRepo *mocks.SeasonRepository) {
+				seasonRepo.On("GetCurrentSeason", mock.Anything).Return(current, nil)
+				seasonRepo.On("GetLiveStandings", mock.Anything, storage.StandingUsers, seasons.DefaultLimit).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seasonRepo := mocks.NewSeasonRepository(t)
+			tt.setupMock(seasonRepo)
+
+			r := chi.NewRouter()
+			r.Get("/seasons", seasons.NewListHandler(logger, seasonRepo))
+			r.Get("/seasons/{seasonID}/standings", seasons.NewStandingsHandler(logger, seasonRepo))
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "user123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				Code string          `json:"code"`
+				Data json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedData != nil {
+				expected, err := json.Marshal(tt.expectedData)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(response.Data))
+			}
+		})
+	}
+}
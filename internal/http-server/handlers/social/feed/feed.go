@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"encoding/base64"
+	"errors"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// NewFeedHandler creates an HTTP handler that returns a page of events of the users the authenticated user follows:
+// finished workouts, personal records and achievements, newest first. Pages are chained with the returned cursor. (1 eventRepo call)
+func NewFeedHandler(log *slog.Logger, eventRepo storage.EventRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.feed.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		limit := DefaultLimit
+		if param := r.URL.Query().Get("limit"); param != "" {
+			parsed, err := strconv.Atoi(param)
+			if err != nil || parsed < 1 || parsed > MaxLimit {
+				log.Debug("Invalid limit", slog.String("limit", param))
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid limit", "Use a number from 1 to "+strconv.Itoa(MaxLimit))
+			}
+			limit = parsed
+		}
+
+		var cursor *storage.FeedCursor
+		if param := r.URL.Query().Get("cursor"); param != "" {
+			parsed, err := DecodeCursor(param)
+			if err != nil {
+				log.Debug("Invalid cursor", slog.Any("error", err))
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid cursor", "Use the next_cursor of the previous page")
+			}
+			cursor = parsed
+		}
+
+		// one more event tells whether there is a next page
+		events, err := eventRepo.GetFeed(r.Context(), &userID, cursor, limit+1)
+		if err != nil {
+			log.Error("Failed to GET feed", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		nextCursor := ""
+		if len(events) > limit {
+			events = events[:limit]
+			last := events[len(events)-1]
+			nextCursor = EncodeCursor(&storage.FeedCursor{CreatedAt: last.CreatedAt, EventID: last.EventID})
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFeedResponse(events, nextCursor)))
+		return nil
+	})
+}
+
+// EncodeCursor makes an opaque cursor of the last event of a page.
+func EncodeCursor(cursor *storage.FeedCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.EventID))
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor.
+func DecodeCursor(s string) (*storage.FeedCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, eventID, ok := strings.Cut(string(data), "|")
+	if !ok || eventID == "" {
+		return nil, errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.FeedCursor{CreatedAt: t, EventID: eventID}, nil
+}
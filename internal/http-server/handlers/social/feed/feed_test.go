@@ -0,0 +1,149 @@
+package feed_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/social/feed"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFeedHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	newest := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	events := []*storage.Event{
+		{EventID: "e3", UserID: "user456", Type: storage.EventAchievement, Code: "first_workout", CreatedAt: newest},
+		{EventID: "e2", UserID: "user456", Type: storage.EventPersonalRecord, WorkoutID: "w1", ExerciseID: 1, Weight: 100, Reps: 5, CreatedAt: newest.Add(-time.Minute)},
+		{EventID: "e1", UserID: "user456", Type: storage.EventWorkoutFinished, WorkoutID: "w1", Points: 120, CreatedAt: newest.Add(-2 * time.Minute)},
+	}
+	cursor := &storage.FeedCursor{CreatedAt: newest.Add(-time.Minute), EventID: "e2"}
+
+	tests := []struct {
+		name               string
+		query              string
+		setupMock          func(eventRepo *mocks.EventRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedEvents     []string
+		expectedCursor     *storage.FeedCursor
+	}{
+		{
+			name:  "DefaultLimit",
+			query: "",
+			setupMock: func(eventRepo *mocks.EventRepository) {
+				eventRepo.On("GetFeed", mock.Anything, userID, (*storage.FeedCursor)(nil), feed.DefaultLimit+1).Return(events, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedEvents:     []string{"e3", "e2", "e1"},
+		},
+		{
+			name:  "NextPage",
+			query: "?limit=2",
+			setupMock: func(eventRepo *mocks.EventRepository) {
+				eventRepo.On("GetFeed", mock.Anything, userID, (*storage.FeedCursor)(nil), 3).Return(events, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedEvents:     []string{"e3", "e2"},
+			expectedCursor:     cursor,
+		},
+		{
+			name:  "WithCursor",
+			query: "?limit=2&cursor=" + feed.EncodeCursor(cursor),
+			setupMock: func(eventRepo *mocks.EventRepository) {
+				eventRepo.On("GetFeed", mock.Anything, userID, cursor, 3).Return(events[2:], nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedEvents:     []string{"e1"},
+		},
+		{
+			name:               "InvalidLimit",
+			query:              "?limit=0",
+			setupMock:          func(eventRepo *mocks.EventRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "LimitTooLarge",
+			query:              "?limit=101",
+			setupMock:          func(eventRepo *mocks.EventRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "InvalidCursor",
+			query:              "?cursor=not-a-cursor",
+			setupMock:          func(eventRepo *mocks.EventRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:  "RepoError",
+			query: "",
+			setupMock: func(eventRepo *mocks.EventRepository) {
+				eventRepo.On("GetFeed", mock.Anything, userID, (*storage.FeedCursor)(nil), feed.DefaultLimit+1).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventRepo := mocks.NewEventRepository(t)
+			tt.setupMock(eventRepo)
+
+			handler := feed.NewFeedHandler(logger, eventRepo)
+
+			req := httptest.NewRequest("GET", "/feed"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data dto.FeedResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+
+			if tt.expectedEvents != nil {
+				var ids []string
+				for _, event := range response.Data.Events {
+					ids = append(ids, event.EventId)
+				}
+				require.Equal(t, tt.expectedEvents, ids)
+			}
+			if tt.expectedCursor != nil {
+				next, err := feed.DecodeCursor(response.Data.NextCursor)
+				require.NoError(t, err)
+				require.True(t, tt.expectedCursor.CreatedAt.Equal(next.CreatedAt))
+				require.Equal(t, tt.expectedCursor.EventID, next.EventID)
+			} else if tt.expectedStatusCode == http.StatusOK {
+				require.Empty(t, response.Data.NextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package follows
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NewFollowHandler creates an HTTP handler to follow the user from the URL.
+// Following a private profile creates a pending request the user has to accept. (1 userRepo call, 1 followRepo call)
+func NewFollowHandler(log *slog.Logger, userRepo storage.UserRepository, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewFollow"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("target_id", targetID))
+
+		if targetID == userID {
+			log.Debug("User tried to follow themselves")
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "You can not follow yourself", "Check the user ID")
+		}
+
+		target, err := userRepo.GetUserByID(r.Context(), &targetID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+
+		follow := &storage.Follow{
+			FollowerID: userID,
+			FolloweeID: targetID,
+			Status:     storage.FollowAccepted,
+			CreatedAt:  time.Now().UTC(),
+		}
+		if target.IsPrivate {
+			follow.Status = storage.FollowPending
+		}
+
+		if err := followRepo.Follow(r.Context(), follow); err != nil {
+			log.Debug("Failed to FOLLOW user", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("User followed", slog.String("status", follow.Status))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFollowResponse(follow)))
+		return nil
+	})
+}
+
+// NewUnfollowHandler creates an HTTP handler to unfollow the user from the URL or to cancel a pending request. (1 followRepo call)
+func NewUnfollowHandler(log *slog.Logger, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewUnfollow"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		targetID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("target_id", targetID))
+
+		if err := followRepo.Unfollow(r.Context(), &userID, &targetID); err != nil {
+			log.Debug("Failed to UNFOLLOW user", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("User unfollowed")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewAcceptHandler creates an HTTP handler to accept a pending follow request of the user from the URL. (1 followRepo call)
+func NewAcceptHandler(log *slog.Logger, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewAccept"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		followerID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("follower_id", followerID))
+
+		if err := followRepo.AcceptFollow(r.Context(), &followerID, &userID); err != nil {
+			log.Debug("Failed to ACCEPT follow", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("Follow request accepted")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewRemoveFollowerHandler creates an HTTP handler to remove a follower or to decline their pending request. (1 followRepo call)
+func NewRemoveFollowerHandler(log *slog.Logger, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewRemoveFollower"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		followerID := chi.URLParam(r, "userID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("follower_id", followerID))
+
+		if err := followRepo.Unfollow(r.Context(), &followerID, &userID); err != nil {
+			log.Debug("Failed to REMOVE follower", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("Follower removed")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewFollowersHandler creates an HTTP handler that lists the followers of the authenticated user, pending requests included. (1 followRepo call)
+func NewFollowersHandler(log *slog.Logger, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewFollowers"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		followers, err := followRepo.GetFollowers(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET followers", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFollowsResponse(followers)))
+		return nil
+	})
+}
+
+// NewFollowingHandler creates an HTTP handler that lists the users the authenticated user follows or asked to follow. (1 followRepo call)
+func NewFollowingHandler(log *slog.Logger, followRepo storage.FollowRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.follows.NewFollowing"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		following, err := followRepo.GetFollowing(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET following", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewFollowsResponse(following)))
+		return nil
+	})
+}
@@ -0,0 +1,226 @@
+package follows_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/social/follows"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFollowsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	targetIDValue := "user456"
+	targetID := &targetIDValue
+	followedAt := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		setupMock          func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+		expectedStatus     string
+	}{
+		{
+			name:   "FollowPublic",
+			method: "POST",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(&storage.User{UserId: "user456"}, nil)
+				followRepo.On("Follow", mock.Anything, mock.MatchedBy(func(f *storage.Follow) bool {
+					return f.FollowerID == "user123" && f.FolloweeID == "user456" && f.Status == storage.FollowAccepted
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.FollowAccepted,
+		},
+		{
+			name:   "FollowPrivate",
+			method: "POST",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(&storage.User{UserId: "user456", IsPrivate: true}, nil)
+				followRepo.On("Follow", mock.Anything, mock.MatchedBy(func(f *storage.Follow) bool {
+					return f.Status == storage.FollowPending
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedStatus:     storage.FollowPending,
+		},
+		{
+			name:               "FollowSelf",
+			method:             "POST",
+			url:                "/users/user123/follow",
+			setupMock:          func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "FollowUserNotFound",
+			method: "POST",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "FollowTwice",
+			method: "POST",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				userRepo.On("GetUserByID", mock.Anything, targetID).Return(&storage.User{UserId: "user456"}, nil)
+				followRepo.On("Follow", mock.Anything, mock.Anything).Return(storage.ErrFollowExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAlreadyExists},
+		},
+		{
+			name:   "UnfollowSuccess",
+			method: "DELETE",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("Unfollow", mock.Anything, userID, targetID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "UnfollowNotFound",
+			method: "DELETE",
+			url:    "/users/user456/follow",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("Unfollow", mock.Anything, userID, targetID).Return(storage.ErrFollowNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "AcceptSuccess",
+			method: "POST",
+			url:    "/users/me/followers/user456/accept",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("AcceptFollow", mock.Anything, targetID, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "AcceptNotFound",
+			method: "POST",
+			url:    "/users/me/followers/user456/accept",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("AcceptFollow", mock.Anything, targetID, userID).Return(storage.ErrFollowNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "RemoveFollowerSuccess",
+			method: "DELETE",
+			url:    "/users/me/followers/user456",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("Unfollow", mock.Anything, targetID, userID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "FollowersSuccess",
+			method: "GET",
+			url:    "/users/me/followers",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("GetFollowers", mock.Anything, userID).Return([]*storage.Follow{
+					{FollowerID: "user456", FolloweeID: "user123", Status: storage.FollowPending, CreatedAt: followedAt},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "FollowersError",
+			method: "GET",
+			url:    "/users/me/followers",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("GetFollowers", mock.Anything, userID).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:   "FollowingSuccess",
+			method: "GET",
+			url:    "/users/me/following",
+			setupMock: func(userRepo *mocks.UserRepository, followRepo *mocks.FollowRepository) {
+				followRepo.On("GetFollowing", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			followRepo := mocks.NewFollowRepository(t)
+			tt.setupMock(userRepo, followRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Get("/users/me/followers", follows.NewFollowersHandler(logger, followRepo))
+			r.Post("/users/me/followers/{userID}/accept", follows.NewAcceptHandler(logger, followRepo))
+			r.Delete("/users/me/followers/{userID}", follows.NewRemoveFollowerHandler(logger, followRepo))
+			r.Get("/users/me/following", follows.NewFollowingHandler(logger, followRepo))
+			r.Post("/users/{userID}/follow", follows.NewFollowHandler(logger, userRepo, followRepo))
+			r.Delete("/users/{userID}/follow", follows.NewUnfollowHandler(logger, followRepo))
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			if tt.expectedStatus != "" {
+				var follow struct {
+					Data dto.FollowResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &follow))
+				require.Equal(t, tt.expectedStatus, follow.Data.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package presence shows who is training right now. Presence is read from the session storage, the one that knows
+// when a session is started, ended or auto-ended, instead of the is_active flag of users. Private users are left out
+// of the lists (they see themselves), but they are counted in the number of users training at a gym, it does not tell who they are.
+package presence
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// NewClanHandler creates an HTTP handler that returns the members of the authenticated user's clan
+// who are training right now, the longest training first. (2 userRepo calls, 1 sessionRepo call)
+func NewClanHandler(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.presence.NewClan"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+		if user.FkClanId == "" || user.FkClanId == storage.DefaultClanID {
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "You are not in a clan", "Join a clan to see which of its members are training")
+		}
+
+		members, err := userRepo.GetClanMembers(r.Context(), &user.FkClanId)
+		if err != nil {
+			log.Error("Failed to GET clan members", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		active, err := training(r.Context(), sessionRepo, members)
+		if err != nil {
+			log.Error("Failed to GET sessions", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(visible(active, userID)))
+		return nil
+	})
+}
+
+// NewGymHandler creates an HTTP handler that returns the users of the authenticated user's gym
+// who are training right now, the longest training first. (2 userRepo calls, 1 sessionRepo call)
+func NewGymHandler(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.presence.NewGym"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+		if user.FkGymId == storage.DefaultGymID {
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "You have no gym", "Pick a gym to see who is training there")
+		}
+
+		members, err := userRepo.GetGymMembers(r.Context(), user.FkGymId)
+		if err != nil {
+			log.Error("Failed to GET gym members", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		active, err := training(r.Context(), sessionRepo, members)
+		if err != nil {
+			log.Error("Failed to GET sessions", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(visible(active, userID)))
+		return nil
+	})
+}
+
+// NewGymCountHandler creates an HTTP handler that returns the number of users training at the gym right now.
+// (1 userRepo call, 1 sessionRepo call)
+func NewGymCountHandler(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.social.presence.NewGymCount"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		gymID, err := strconv.Atoi(chi.URLParam(r, "gymID"))
+		if err != nil {
+			log.Debug("Invalid gym ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid gym ID", "Gym ID should be a number")
+		}
+
+		members, err := userRepo.GetGymMembers(r.Context(), gymID)
+		if err != nil {
+			log.Error("Failed to GET gym members", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		active, err := training(r.Context(), sessionRepo, members)
+		if err != nil {
+			log.Error("Failed to GET sessions", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.GymPresenceResponse{GymId: gymID, Active: len(active)}))
+		return nil
+	})
+}
+
+// member is a user who is training right now.
+type member struct {
+	user    *storage.User
+	session *storage.WorkoutSession
+}
+
+// training returns the members who have a session.
+func training(ctx context.Context, sessionRepo storage.SessionRepository, members []*storage.User) ([]member, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+	users := make(map[string]*storage.User, len(members))
+	userIDs := make([]string, 0, len(members))
+	for _, user := range members {
+		users[user.UserId] = user
+		userIDs = append(userIDs, user.UserId)
+	}
+
+	sessions, err := sessionRepo.GetSessions(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]member, 0, len(sessions))
+	for _, session := range sessions {
+		if user, ok := users[session.UserID]; ok {
+			active = append(active, member{user: user, session: session})
+		}
+	}
+	return active, nil
+}
+
+// visible leaves out the private users other than the viewer and sorts the rest by the start of their session.
+func visible(active []member, viewerID string) []dto.PresenceResponse {
+	res := make([]dto.PresenceResponse, 0, len(active))
+	for _, m := range active {
+		if m.user.IsPrivate && m.user.UserId != viewerID {
+			continue
+		}
+		res = append(res, dto.NewPresenceResponse(m.user, m.session))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].TrainingSince.Equal(res[j].TrainingSince) {
+			return res[i].TrainingSince.Before(res[j].TrainingSince)
+		}
+		return res[i].UserId < res[j].UserId
+	})
+	return res
+}
@@ -0,0 +1,189 @@
+package presence_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/social/presence"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+)
+
+func TestPresenceHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	clanID := "clan1"
+
+	me := &storage.User{UserId: "user123", Username: "me", FkClanId: clanID, FkGymId: 7, IsPrivate: true}
+	public := &storage.User{UserId: "user456", Username: "public", FkClanId: clanID, FkGymId: 7}
+	private := &storage.User{UserId: "user789", Username: "private", FkClanId: clanID, FkGymId: 7, IsPrivate: true}
+	resting := &storage.User{UserId: "user000", Username: "resting", FkClanId: clanID, FkGymId: 7}
+	members := []*storage.User{me, public, private, resting}
+	memberIDs := []string{"user123", "user456", "user789", "user000"}
+
+	earlier := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	later := earlier.Add(30 * time.Minute)
+	sessions := []*storage.WorkoutSession{
+		{UserID: "user123", StartTime: later, LastUpdated: later},
+		{UserID: "user789", StartTime: earlier, LastUpdated: later},
+		{UserID: "user456", StartTime: earlier, LastUpdated: later},
+	}
+	// the viewer sees themselves although they are private, the other private user is left out
+	visible := []dto.PresenceResponse{
+		{UserId: "user456", Username: "public", TrainingSince: earlier, LastUpdated: later},
+		{UserId: "user123", Username: "me", TrainingSince: later, LastUpdated: later},
+	}
+
+	tests := []struct {
+		name               string
+		path               string
+		setupMock          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedData       interface{}
+	}{
+		{
+			name: "Clan",
+			path: "/presence/clan",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				userRepo.On("GetClanMembers", mock.Anything, &clanID).Return(members, nil)
+				sessionRepo.On("GetSessions", mock.Anything, memberIDs).Return(sessions, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       visible,
+		},
+		{
+			name: "NotInClan",
+			path: "/presence/clan",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", FkClanId: storage.DefaultClanID}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "Gym",
+			path: "/presence/gym",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				userRepo.On("GetGymMembers", mock.Anything, 7).Return(members, nil)
+				sessionRepo.On("GetSessions", mock.Anything, memberIDs).Return(sessions, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       visible,
+		},
+		{
+			name: "NoGym",
+			path: "/presence/gym",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", FkGymId: storage.DefaultGymID}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name: "NobodyTraining",
+			path: "/presence/gym",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				userRepo.On("GetGymMembers", mock.Anything, 7).Return(members, nil)
+				sessionRepo.On("GetSessions", mock.Anything, memberIDs).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       []dto.PresenceResponse{},
+		},
+		{
+			name: "GymCount",
+			path: "/presence/gyms/7",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetGymMembers", mock.Anything, 7).Return(members, nil)
+				sessionRepo.On("GetSessions", mock.Anything, memberIDs).Return(sessions, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			// private users are counted
+			expectedData: dto.GymPresenceResponse{GymId: 7, Active: 3},
+		},
+		{
+			name: "EmptyGymCount",
+			path: "/presence/gyms/8",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetGymMembers", mock.Anything, 8).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       dto.GymPresenceResponse{GymId: 8, Active: 0},
+		},
+		{
+			name:               "InvalidGymID",
+			path:               "/presence/gyms/main",
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name: "SessionStorageError",
+			path: "/presence/clan",
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(me, nil)
+				userRepo.On("GetClanMembers", mock.Anything, &clanID).Return(members, nil)
+				sessionRepo.On("GetSessions", mock.Anything, memberIDs).Return(nil, errors.New("redis error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			sessionRepo := mocks.NewSessionRepository(t)
+			tt.setupMock(userRepo, sessionRepo)
+
+			r := chi.NewRouter()
+			r.Get("/presence/clan", presence.NewClanHandler(logger, userRepo, sessionRepo))
+			r.Get("/presence/gym", presence.NewGymHandler(logger, userRepo, sessionRepo))
+			r.Get("/presence/gyms/{gymID}", presence.NewGymCountHandler(logger, userRepo, sessionRepo))
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				Code string          `json:"code"`
+				Data json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedData != nil {
+				expected, err := json.Marshal(tt.expectedData)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expected), string(response.Data))
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/points"
+	"GYMBRO/internal/lib/stats"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewProgressHandler creates an HTTP handler to get user's progress in an exercise.
+// For every workout with the exercise in the date range it returns the best estimated 1RM, the top set and the volume.
+// The period query parameter (workout by default, week or month) downsamples them: the best 1RM and top set
+// of the period are kept and volumes are summed. (1 statsRepo call)
+func NewProgressHandler(log *slog.Logger, statsRepo storage.StatsRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.stats.progress.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		exerciseID, err := strconv.Atoi(chi.URLParam(r, "exerciseID"))
+		if err != nil {
+			log.Debug("Invalid exercise ID", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid exercise ID", "Exercise ID should be a number")
+		}
+
+		period := strings.ToLower(r.URL.Query().Get("period"))
+		if period == "" {
+			period = stats.PeriodWorkout
+		}
+		if period != stats.PeriodWorkout && !stats.ValidPeriod(period) {
+			log.Debug("Unsupported period", slog.String("period", period))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Unsupported period", "Use one of the periods: workout, week, month")
+		}
+
+		from, to, err := stats.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			log.Debug("Invalid date range", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid date range", "Use YYYY-MM-DD dates and make sure 'from' is not after 'to'")
+		}
+
+		sets, err := statsRepo.GetExerciseSets(r.Context(), &userID, exerciseID, from, to)
+		if err != nil {
+			if errors.Is(err, storage.ErrExerciseNotFound) {
+				log.Debug("Exercise not found", slog.Int("exercise_id", exerciseID))
+				return err
+			}
+			log.Error("Failed to GET exercise sets", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.ProgressResponse{ExerciseId: exerciseID, Period: period, Progress: summarize(sets, period)}))
+		return nil
+	})
+}
+
+// summarize groups the sets (ordered by start time) by workout or period.
+func summarize(sets []*storage.ExerciseSet, period string) []dto.ProgressPointResponse {
+	progress := make([]dto.ProgressPointResponse, 0)
+	var (
+		current  *dto.ProgressPointResponse
+		key      string
+		workouts map[string]bool
+	)
+	for _, set := range sets {
+		start, setKey := set.StartTime, set.WorkoutID
+		if period != stats.PeriodWorkout {
+			start = stats.PeriodStart(set.StartTime, period)
+			setKey = start.Format(stats.DateLayout)
+		}
+		if current == nil || key != setKey {
+			point := dto.ProgressPointResponse{Start: start}
+			if period == stats.PeriodWorkout {
+				point.WorkoutID = set.WorkoutID
+			}
+			progress = append(progress, point)
+			current, key = &progress[len(progress)-1], setKey
+			workouts = make(map[string]bool)
+		}
+		if !workouts[set.WorkoutID] {
+			workouts[set.WorkoutID] = true
+			current.Workouts++
+		}
+
+		current.Sets++
+		current.Volume += set.Reps * set.Weight
+		// rounded to 0.1, more precision is noise for an estimate
+		current.EstimatedMax = math.Max(current.EstimatedMax, math.Round(points.EstimateOneRepMax(set.Weight, set.Reps)*10)/10)
+		top := &current.TopSet
+		if set.Weight > top.Weight || set.Weight == top.Weight && set.Reps > top.Reps {
+			top.Weight, top.Reps = set.Weight, set.Reps
+		}
+	}
+	return progress
+}
@@ -0,0 +1,169 @@
+package progress_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/stats/progress"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+)
+
+func TestProgressHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	// two workouts in the week starting on 2024-03-04 and one in the next week
+	tuesday := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	friday := time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 3, 11, 7, 0, 0, 0, time.UTC)
+	sets := []*storage.ExerciseSet{
+		{WorkoutID: "w1", StartTime: tuesday, Reps: 10, Weight: 80},
+		{WorkoutID: "w1", StartTime: tuesday, Reps: 3, Weight: 100},
+		{WorkoutID: "w1", StartTime: tuesday, Reps: 5, Weight: 100},
+		{WorkoutID: "w2", StartTime: friday, Reps: 1, Weight: 110},
+		{WorkoutID: "w3", StartTime: monday, Reps: 5, Weight: 105},
+	}
+
+	tests := []struct {
+		name               string
+		exerciseID         string
+		query              string
+		setupMock          func(statsRepo *mocks.StatsRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedData       *dto.ProgressResponse
+	}{
+		{
+			name:       "PerWorkout",
+			exerciseID: "3",
+			query:      "?from=2024-03-01&to=2024-03-31",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 3, from, to).Return(sets, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: &dto.ProgressResponse{ExerciseId: 3, Period: "workout", Progress: []dto.ProgressPointResponse{
+				{Start: tuesday, WorkoutID: "w1", Workouts: 1, Sets: 3, EstimatedMax: 116.7, TopSet: dto.TopSetResponse{Weight: 100, Reps: 5}, Volume: 1600},
+				{Start: friday, WorkoutID: "w2", Workouts: 1, Sets: 1, EstimatedMax: 113.7, TopSet: dto.TopSetResponse{Weight: 110, Reps: 1}, Volume: 110},
+				{Start: monday, WorkoutID: "w3", Workouts: 1, Sets: 1, EstimatedMax: 122.5, TopSet: dto.TopSetResponse{Weight: 105, Reps: 5}, Volume: 525},
+			}},
+		},
+		{
+			name:       "PerWeek",
+			exerciseID: "3",
+			query:      "?period=week",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 3, time.Time{}, mock.Anything).Return(sets, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: &dto.ProgressResponse{ExerciseId: 3, Period: "week", Progress: []dto.ProgressPointResponse{
+				{Start: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Workouts: 2, Sets: 4, EstimatedMax: 116.7, TopSet: dto.TopSetResponse{Weight: 110, Reps: 1}, Volume: 1710},
+				{Start: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), Workouts: 1, Sets: 1, EstimatedMax: 122.5, TopSet: dto.TopSetResponse{Weight: 105, Reps: 5}, Volume: 525},
+			}},
+		},
+		{
+			name:       "PerMonth",
+			exerciseID: "3",
+			query:      "?period=month",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 3, time.Time{}, mock.Anything).Return(sets, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: &dto.ProgressResponse{ExerciseId: 3, Period: "month", Progress: []dto.ProgressPointResponse{
+				{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Workouts: 3, Sets: 5, EstimatedMax: 122.5, TopSet: dto.TopSetResponse{Weight: 110, Reps: 1}, Volume: 2235},
+			}},
+		},
+		{
+			name:       "NoSets",
+			exerciseID: "3",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 3, time.Time{}, mock.Anything).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       &dto.ProgressResponse{ExerciseId: 3, Period: "workout", Progress: []dto.ProgressPointResponse{}},
+		},
+		{
+			name:       "ExerciseNotFound",
+			exerciseID: "42",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 42, time.Time{}, mock.Anything).Return(nil, storage.ErrExerciseNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       resp.CodeNotFound,
+		},
+		{
+			name:               "InvalidExerciseID",
+			exerciseID:         "bench",
+			setupMock:          func(statsRepo *mocks.StatsRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "UnsupportedPeriod",
+			exerciseID:         "3",
+			query:              "?period=day",
+			setupMock:          func(statsRepo *mocks.StatsRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:       "StorageError",
+			exerciseID: "3",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetExerciseSets", mock.Anything, userID, 3, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statsRepo := mocks.NewStatsRepository(t)
+			tt.setupMock(statsRepo)
+
+			r := chi.NewRouter()
+			r.Get("/stats/exercises/{exerciseID}/progress", progress.NewProgressHandler(logger, statsRepo))
+
+			req := httptest.NewRequest("GET", "/stats/exercises/"+tt.exerciseID+"/progress"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				Code string                `json:"code"`
+				Data *dto.ProgressResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedData != nil {
+				require.Equal(t, tt.expectedData, response.Data)
+			}
+		})
+	}
+}
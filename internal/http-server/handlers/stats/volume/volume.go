@@ -0,0 +1,91 @@
+package volume
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/stats"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// NewVolumeHandler creates an HTTP handler to get user's training volume per muscle group.
+// It sums sets, reps and tonnage of the workouts started in the date range per week or month (period query parameter,
+// week by default). Periods without workouts are left out. (1 statsRepo call)
+func NewVolumeHandler(log *slog.Logger, statsRepo storage.StatsRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.stats.volume.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		period := strings.ToLower(r.URL.Query().Get("period"))
+		if period == "" {
+			period = stats.PeriodWeek
+		}
+		if !stats.ValidPeriod(period) {
+			log.Debug("Unsupported period", slog.String("period", period))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Unsupported period", "Use one of the periods: week, month")
+		}
+
+		from, to, err := stats.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			log.Debug("Invalid date range", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid date range", "Use YYYY-MM-DD dates and make sure 'from' is not after 'to'")
+		}
+
+		volumes, err := statsRepo.GetMuscleGroupVolume(r.Context(), &userID, from, to)
+		if err != nil {
+			log.Error("Failed to GET muscle group volume", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.VolumeResponse{Period: period, Periods: groupByPeriod(volumes, period)}))
+		return nil
+	})
+}
+
+// groupByPeriod sums the volumes (ordered by start time) of every muscle group per period.
+func groupByPeriod(volumes []*storage.MuscleGroupVolume, period string) []dto.VolumePeriodResponse {
+	periods := make([]dto.VolumePeriodResponse, 0)
+	var (
+		current  *dto.VolumePeriodResponse
+		groups   map[int]int
+		workouts map[string]bool
+	)
+	for _, volume := range volumes {
+		start := stats.PeriodStart(volume.StartTime, period).Format(stats.DateLayout)
+		if current == nil || current.Start != start {
+			periods = append(periods, dto.VolumePeriodResponse{Start: start, MuscleGroups: []dto.MuscleGroupVolumeResponse{}})
+			current = &periods[len(periods)-1]
+			groups = make(map[int]int)
+			workouts = make(map[string]bool)
+		}
+		if !workouts[volume.WorkoutID] {
+			workouts[volume.WorkoutID] = true
+			current.Workouts++
+		}
+
+		i, ok := groups[volume.MuscleGroupId]
+		if !ok {
+			i = len(current.MuscleGroups)
+			groups[volume.MuscleGroupId] = i
+			current.MuscleGroups = append(current.MuscleGroups, dto.MuscleGroupVolumeResponse{MuscleGroupId: volume.MuscleGroupId, Name: volume.MuscleGroupName})
+		}
+		current.MuscleGroups[i].Sets += volume.Sets
+		current.MuscleGroups[i].Reps += volume.Reps
+		current.MuscleGroups[i].Tonnage += volume.Tonnage
+	}
+
+	for _, p := range periods {
+		sort.Slice(p.MuscleGroups, func(i, j int) bool {
+			return p.MuscleGroups[i].MuscleGroupId < p.MuscleGroups[j].MuscleGroupId
+		})
+	}
+	return periods
+}
@@ -0,0 +1,146 @@
+package volume_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	"GYMBRO/internal/http-server/handlers/stats/volume"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+)
+
+func TestVolumeHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	// Tuesday and Sunday of the week starting on 2024-03-04, and Monday of the next one
+	tuesday := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 3, 11, 7, 0, 0, 0, time.UTC)
+	volumes := []*storage.MuscleGroupVolume{
+		{WorkoutID: "w1", StartTime: tuesday, MuscleGroupId: 2, MuscleGroupName: "Back", Sets: 3, Reps: 30, Tonnage: 3000},
+		{WorkoutID: "w1", StartTime: tuesday, MuscleGroupId: 1, MuscleGroupName: "Chest", Sets: 4, Reps: 20, Tonnage: 2000},
+		{WorkoutID: "w2", StartTime: sunday, MuscleGroupId: 1, MuscleGroupName: "Chest", Sets: 2, Reps: 10, Tonnage: 1000},
+		{WorkoutID: "w3", StartTime: monday, MuscleGroupId: 1, MuscleGroupName: "Chest", Sets: 1, Reps: 5, Tonnage: 500},
+	}
+
+	tests := []struct {
+		name               string
+		query              string
+		setupMock          func(statsRepo *mocks.StatsRepository)
+		expectedStatusCode int
+		expectedCode       string
+		expectedData       *dto.VolumeResponse
+	}{
+		{
+			name:  "Weeks",
+			query: "?from=2024-03-01&to=2024-03-31",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+				statsRepo.On("GetMuscleGroupVolume", mock.Anything, userID, from, to).Return(volumes, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: &dto.VolumeResponse{Period: "week", Periods: []dto.VolumePeriodResponse{
+				{Start: "2024-03-04", Workouts: 2, MuscleGroups: []dto.MuscleGroupVolumeResponse{
+					{MuscleGroupId: 1, Name: "Chest", Sets: 6, Reps: 30, Tonnage: 3000},
+					{MuscleGroupId: 2, Name: "Back", Sets: 3, Reps: 30, Tonnage: 3000},
+				}},
+				{Start: "2024-03-11", Workouts: 1, MuscleGroups: []dto.MuscleGroupVolumeResponse{
+					{MuscleGroupId: 1, Name: "Chest", Sets: 1, Reps: 5, Tonnage: 500},
+				}},
+			}},
+		},
+		{
+			name:  "Month",
+			query: "?period=month",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetMuscleGroupVolume", mock.Anything, userID, time.Time{}, mock.Anything).Return(volumes, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData: &dto.VolumeResponse{Period: "month", Periods: []dto.VolumePeriodResponse{
+				{Start: "2024-03-01", Workouts: 3, MuscleGroups: []dto.MuscleGroupVolumeResponse{
+					{MuscleGroupId: 1, Name: "Chest", Sets: 7, Reps: 35, Tonnage: 3500},
+					{MuscleGroupId: 2, Name: "Back", Sets: 3, Reps: 30, Tonnage: 3000},
+				}},
+			}},
+		},
+		{
+			name:  "NoWorkouts",
+			query: "?period=week",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetMuscleGroupVolume", mock.Anything, userID, time.Time{}, mock.Anything).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCode:       resp.StatusOK,
+			expectedData:       &dto.VolumeResponse{Period: "week", Periods: []dto.VolumePeriodResponse{}},
+		},
+		{
+			name:               "UnsupportedPeriod",
+			query:              "?period=year",
+			setupMock:          func(statsRepo *mocks.StatsRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "InvalidRange",
+			query:              "?from=2024-03-31&to=2024-03-01",
+			setupMock:          func(statsRepo *mocks.StatsRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:  "StorageError",
+			query: "",
+			setupMock: func(statsRepo *mocks.StatsRepository) {
+				statsRepo.On("GetMuscleGroupVolume", mock.Anything, userID, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statsRepo := mocks.NewStatsRepository(t)
+			tt.setupMock(statsRepo)
+
+			handler := volume.NewVolumeHandler(logger, statsRepo)
+
+			req := httptest.NewRequest("GET", "/stats/volume"+tt.query, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				Code string              `json:"code"`
+				Data *dto.VolumeResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedCode, response.Code)
+			if tt.expectedData != nil {
+				require.Equal(t, tt.expectedData, response.Data)
+			}
+		})
+	}
+}
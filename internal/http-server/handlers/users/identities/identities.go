@@ -0,0 +1,31 @@
+package identities
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// NewIdentitiesHandler creates an HTTP handler that lists OAuth identities linked to the authenticated user. (1 userRepo call)
+func NewIdentitiesHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.identities.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		identities, err := userRepo.GetUserIdentities(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET identities", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewIdentitiesResponse(identities)))
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package identities_test
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/users/identities"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdentitiesHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	tests := []struct {
+		name               string
+		setupMock          func(userRepo *mocks.UserRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+		expectedCount      int
+	}{
+		{
+			name: "Success",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{
+					{Provider: "google", Subject: "g1", FkUserId: "user123"},
+					{Provider: "github", Subject: "gh1", FkUserId: "user123"},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK, Code: resp.StatusOK},
+			expectedCount:      2,
+		},
+		{
+			name: "NoIdentities",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK, Code: resp.StatusOK},
+			expectedCount:      0,
+		},
+		{
+			name: "RepoError",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			tt.setupMock(userRepo)
+
+			handler := identities.NewIdentitiesHandler(logger, userRepo)
+
+			req := httptest.NewRequest("GET", "/users/identities", nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data []storage.Identity `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			require.Equal(t, tt.expectedResponse.Code, response.Code)
+			require.Len(t, response.Data, tt.expectedCount)
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
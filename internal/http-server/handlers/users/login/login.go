@@ -2,16 +2,22 @@ package login
 
 import (
 	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/mailer"
 	"GYMBRO/internal/lib/validation"
 	"GYMBRO/internal/storage"
+	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -22,54 +28,70 @@ type Request struct {
 
 // NewLoginHandler creates an HTTP handler for user authentication.
 // It handles login requests by validating the input, checking credentials,
-// and issuing a JWT token upon successful authentication. (1 userRepo call)
-func NewLoginHandler(log *slog.Logger, userRepo storage.UserRepository, cfg *config.Config) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// and issuing a JWT token upon successful authentication.
+// Failed attempts are counted per email, and the account is locked for a while after too many of them,
+// the lock is checked before the password is. Disabled users get no token, it is checked after the password,
+// so it does not tell whether the account exists. (1 userRepo call, 2 lockoutRepo calls)
+func NewLoginHandler(log *slog.Logger, userRepo storage.UserRepository, lockoutRepo storage.LockoutRepository, mail mailer.Mailer, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.users.login.New"
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())))
 
 		var request Request
-		err := render.DecodeJSON(r.Body, &request)
+		err := dto.Decode(r, &request)
 		if err != nil {
 			log.Warn("Failed to decode request", slog.Any("error", err))
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("Failed to decode request", resp.CodeBadRequest, "Check the request fields for typos or naming errors"))
-			return
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
 		}
 		log.Debug("Request body decoded", slog.Any("request", request))
 
 		if err := validation.ValidateStruct(log, &request); err != nil {
-			validation.HandleValidationError(w, r, err)
-			return
+			return validation.Error(err)
 		}
 
-		usr, err := userRepo.GetUserByEmail(&request.Email)
+		lockedUntil, err := lockoutRepo.GetLockout(r.Context(), &request.Email)
+		if err != nil {
+			log.Error("Failed to GET lockout", slog.Any("error", err))
+		} else if !lockedUntil.IsZero() {
+			log.Debug("Account is locked", slog.Time("locked_until", lockedUntil))
+			return lockedError(w, lockedUntil)
+		}
+
+		usr, err := userRepo.GetUserByEmail(r.Context(), &request.Email)
 		if err != nil {
 			if errors.Is(err, storage.ErrUserNotFound) {
 				log.Debug("Invalid credentials", slog.Any("request", request))
-				render.Status(r, http.StatusBadRequest)
-				render.JSON(w, r, resp.Error("Invalid credentials", resp.CodeBadRequest, "Check your email and password and try again"))
-				return
+				// failures are counted and locked for unknown emails as well, so the lock does not reveal which accounts exist
+				if lockedUntil := registerFailure(r.Context(), log, lockoutRepo, mail, cfg, &request.Email, nil); !lockedUntil.IsZero() {
+					return lockedError(w, lockedUntil)
+				}
+				return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid credentials", "Check your email and password and try again")
 			}
 			log.Error("Failed to GET user", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Failed to login", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.NewError(http.StatusInternalServerError, resp.CodeInternalError, "Failed to login", "Please try again later")
 		}
 
 		if err := bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(request.Password)); err != nil {
 			log.Debug("Invalid credentials", slog.Any("request", request))
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("Invalid credentials", resp.CodeBadRequest, "Check your email and password and try again"))
-			return
+			if lockedUntil := registerFailure(r.Context(), log, lockoutRepo, mail, cfg, &request.Email, usr); !lockedUntil.IsZero() {
+				return lockedError(w, lockedUntil)
+			}
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid credentials", "Check your email and password and try again")
+		}
+
+		if err := lockoutRepo.ResetFailedLogins(r.Context(), &request.Email); err != nil {
+			log.Error("Failed to RESET failed logins", slog.Any("error", err))
+		}
+
+		if usr.IsDisabled {
+			log.Debug("User is disabled", slog.String("user_id", usr.UserId))
+			return resp.AccountDisabled()
 		}
 
 		token, err := jwt.NewToken(*usr, cfg.JWTLifetime, cfg.SecretKey)
 		if err != nil {
 			log.Error("Failed to GENERATE token", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		http.SetCookie(w, &http.Cookie{
@@ -83,5 +105,36 @@ func NewLoginHandler(log *slog.Logger, userRepo storage.UserRepository, cfg *con
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
 		http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+		return nil
+	})
+}
+
+// registerFailure counts the failed login and notifies the owner if it locked the account.
+// Returns the time the account is locked until, or zero time if it was not locked.
+func registerFailure(ctx context.Context, log *slog.Logger, lockoutRepo storage.LockoutRepository, mail mailer.Mailer, cfg *config.Config, email *string, usr *storage.User) time.Time {
+	lockedUntil, err := lockoutRepo.RegisterFailedLogin(ctx, email, cfg.MaxAttempts, cfg.LockoutCfg.Window, cfg.BaseDuration, cfg.MaxDuration)
+	if err != nil {
+		log.Error("Failed to REGISTER failed login", slog.Any("error", err))
+		return time.Time{}
+	}
+	if lockedUntil.IsZero() {
+		return lockedUntil
+	}
+
+	log.Warn("Account locked", slog.Time("locked_until", lockedUntil))
+	if usr != nil {
+		body := fmt.Sprintf("Hi %s,\n\nthere were too many failed attempts to log in to your account, so it is locked until %s.\n"+
+			"If it was not you, consider changing your password.", usr.Username, lockedUntil.UTC().Format(time.RFC1123))
+		if err := mail.Send(usr.Email, "Your GYMBRO account is temporarily locked", body); err != nil {
+			log.Error("Failed to SEND lockout email", slog.Any("error", err))
+		}
 	}
+	return lockedUntil
+}
+
+// lockedError sets Retry-After and returns the error for a locked account.
+func lockedError(w http.ResponseWriter, lockedUntil time.Time) error {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	return resp.NewError(http.StatusLocked, resp.CodeAccountLocked, "Account is temporarily locked", "Too many failed login attempts, try again later")
 }
@@ -4,11 +4,15 @@ import (
 	"GYMBRO/internal/config"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/http-server/handlers/users/login"
+	jwtlib "GYMBRO/internal/lib/jwt"
+	mailermocks "GYMBRO/internal/lib/mailer/mocks"
 	"GYMBRO/internal/storage"
 	"GYMBRO/internal/storage/mocks"
 	"bytes"
 	"encoding/json"
 	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 	"io"
@@ -20,7 +24,10 @@ import (
 )
 
 func TestLoginHandler(t *testing.T) {
-	cfg := &config.Config{JWTCfg: config.JWTCfg{SecretKey: "test", JWTLifetime: time.Hour}}
+	cfg := &config.Config{
+		JWTCfg:     config.JWTCfg{SecretKey: "test", JWTLifetime: time.Hour},
+		LockoutCfg: config.LockoutCfg{MaxAttempts: 5, Window: 15 * time.Minute, BaseDuration: time.Minute, MaxDuration: 24 * time.Hour},
+	}
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
@@ -31,9 +38,11 @@ func TestLoginHandler(t *testing.T) {
 	tests := []struct {
 		name               string
 		reqBody            interface{}
-		setupMock          func(userRepo *mocks.UserRepository)
+		setupMock          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer)
 		expectedStatusCode int
 		expectedResponse   resp.DetailedResponse
+		// expectedRole is the role claim of the token set as the cookie
+		expectedRole string
 	}{
 		{
 			name: "Success",
@@ -41,19 +50,48 @@ func TestLoginHandler(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(&storage.User{
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
 					Email:    "test@example.com",
 					Password: string(hashedPassword),
+					Role:     storage.RoleModerator,
 				}, nil)
+				lockoutRepo.On("ResetFailedLogins", mock.Anything, email).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			expectedRole:       storage.RoleModerator,
+		},
+		{
+			name: "Disabled",
+			reqBody: login.Request{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
+					Email:      "test@example.com",
+					Password:   string(hashedPassword),
+					IsDisabled: true,
+				}, nil)
+				lockoutRepo.On("ResetFailedLogins", mock.Anything, email).Return(nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAccountDisabled},
 		},
 		{
 			name:               "InvalidRequest",
 			reqBody:            "invalid-json",
-			setupMock:          func(userRepo *mocks.UserRepository) {},
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "UnknownField",
+			reqBody:            map[string]string{"email": "test@example.com", "password": "password123", "role": "admin"},
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
 		},
@@ -63,7 +101,7 @@ func TestLoginHandler(t *testing.T) {
 				Email:    "invalid-email",
 				Password: "",
 			},
-			setupMock:          func(userRepo *mocks.UserRepository) {},
+			setupMock:          func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
 		},
@@ -73,8 +111,10 @@ func TestLoginHandler(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(nil, storage.ErrUserNotFound)
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, storage.ErrUserNotFound)
+				lockoutRepo.On("RegisterFailedLogin", mock.Anything, email, 5, 15*time.Minute, time.Minute, 24*time.Hour).Return(time.Time{}, nil)
 			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
@@ -85,11 +125,13 @@ func TestLoginHandler(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "wrongpassword",
 			},
-			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(&storage.User{
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
 					Email:    "test@example.com",
 					Password: string(hashedPassword),
 				}, nil)
+				lockoutRepo.On("RegisterFailedLogin", mock.Anything, email, 5, 15*time.Minute, time.Minute, 24*time.Hour).Return(time.Time{}, nil)
 			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
@@ -100,19 +142,84 @@ func TestLoginHandler(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
-			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(nil, errors.New("database error"))
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, errors.New("database error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
 		},
+		{
+			name: "AccountLocked",
+			reqBody: login.Request{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Now().Add(time.Minute), nil)
+			},
+			expectedStatusCode: http.StatusLocked,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAccountLocked},
+		},
+		{
+			name: "LockedByThisAttempt",
+			reqBody: login.Request{
+				Email:    "test@example.com",
+				Password: "wrongpassword",
+			},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
+					Username: "test",
+					Email:    "test@example.com",
+					Password: string(hashedPassword),
+				}, nil)
+				lockoutRepo.On("RegisterFailedLogin", mock.Anything, email, 5, 15*time.Minute, time.Minute, 24*time.Hour).Return(time.Now().Add(time.Minute), nil)
+				mail.On("Send", "test@example.com", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusLocked,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAccountLocked},
+		},
+		{
+			name: "UnknownEmailLockedByThisAttempt",
+			reqBody: login.Request{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, nil)
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, storage.ErrUserNotFound)
+				lockoutRepo.On("RegisterFailedLogin", mock.Anything, email, 5, 15*time.Minute, time.Minute, 24*time.Hour).Return(time.Now().Add(time.Minute), nil)
+			},
+			expectedStatusCode: http.StatusLocked,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAccountLocked},
+		},
+		{
+			name: "LockoutStorageError",
+			reqBody: login.Request{
+				Email:    "test@example.com",
+				Password: "password123",
+			},
+			setupMock: func(userRepo *mocks.UserRepository, lockoutRepo *mocks.LockoutRepository, mail *mailermocks.Mailer) {
+				lockoutRepo.On("GetLockout", mock.Anything, email).Return(time.Time{}, errors.New("redis down"))
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
+					Email:    "test@example.com",
+					Password: string(hashedPassword),
+				}, nil)
+				lockoutRepo.On("ResetFailedLogins", mock.Anything, email).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := mocks.NewUserRepository(t)
-			tt.setupMock(userRepo)
-			handler := login.NewLoginHandler(logger, userRepo, cfg)
+			lockoutRepo := mocks.NewLockoutRepository(t)
+			mail := mailermocks.NewMailer(t)
+			tt.setupMock(userRepo, lockoutRepo, mail)
+			handler := login.NewLoginHandler(logger, userRepo, lockoutRepo, mail, cfg)
 			reqBody, _ := json.Marshal(tt.reqBody)
 			req := httptest.NewRequest("POST", "/users/login", bytes.NewBuffer(reqBody))
 			req.Header.Set("Content-Type", "application/json")
@@ -122,6 +229,9 @@ func TestLoginHandler(t *testing.T) {
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedStatusCode == http.StatusLocked {
+				require.Equal(t, "60", rr.Header().Get("Retry-After"))
+			}
 
 			var response resp.DetailedResponse
 			err := json.Unmarshal(rr.Body.Bytes(), &response)
@@ -131,8 +241,17 @@ func TestLoginHandler(t *testing.T) {
 			if tt.expectedResponse.Code != "" {
 				require.Equal(t, tt.expectedResponse.Code, response.Code)
 			}
+			if tt.expectedRole != "" {
+				cookies := rr.Result().Cookies()
+				require.Len(t, cookies, 1)
+				token, err := jwtlib.ValidateJWT(cookies[0].Value, cfg.SecretKey)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedRole, token.Claims.(jwt.MapClaims)["role"])
+			}
 
 			userRepo.AssertExpectations(t)
+			lockoutRepo.AssertExpectations(t)
+			mail.AssertExpectations(t)
 		})
 	}
 }
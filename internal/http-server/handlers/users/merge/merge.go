@@ -0,0 +1,82 @@
+package merge
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	MergeToken string `json:"merge_token" validate:"required"`
+	Confirm    bool   `json:"confirm"`
+}
+
+// NewMergeHandler creates an HTTP handler that merges another account into the authenticated one.
+// The other account is identified by a merge token issued by the OAuth link callback, and the request
+// has to be explicitly confirmed. Accounts with an active workout or with identities of the same provider can not be merged.
+// (1 sessionRepo call, 1 userRepo call)
+func NewMergeHandler(log *slog.Logger, userRepo storage.UserRepository, sessionRepo storage.SessionRepository, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.merge.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		var request Request
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		if !request.Confirm {
+			log.Debug("Merge is not confirmed")
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Merge is not confirmed", "Set confirm to true to merge the accounts. This can not be undone")
+		}
+
+		targetID, sourceID, err := jwt.ParseMergeToken(request.MergeToken, cfg.SecretKey)
+		if err != nil || targetID != userID || sourceID == userID {
+			log.Warn("Invalid merge token", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid merge token", "Link the provider again to get a new merge token")
+		}
+		log = log.With(slog.String("source_id", sourceID))
+
+		_, err = sessionRepo.GetSession(r.Context(), &sourceID)
+		if err == nil {
+			log.Debug("Source account has active workout")
+			return resp.NewError(http.StatusConflict, resp.CodeActiveWorkout, "Other account has active workout", "End the workout of the other account before merging")
+		}
+		if !errors.Is(err, storage.ErrNoSession) {
+			log.Error("Cant GET session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		if err := userRepo.MergeUsers(r.Context(), &userID, &sourceID); err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Debug("User to merge not found")
+				return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "Account not found", "Maybe this account was already merged")
+			}
+			if errors.Is(err, storage.ErrIdentityExists) {
+				log.Debug("Both accounts have an identity of the same provider")
+				return resp.NewError(http.StatusConflict, resp.CodeIdentityLinked, "Both accounts have an identity of the same provider", "Unlink that provider from one of the accounts first")
+			}
+			log.Error("Failed to MERGE users", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Info("Accounts merged")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
@@ -0,0 +1,163 @@
+package merge_test
+
+import (
+	"GYMBRO/internal/config"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/users/merge"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMergeHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cfg := &config.Config{JWTCfg: config.JWTCfg{SecretKey: "test"}}
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	sourceIDValue := "user456"
+	sourceID := &sourceIDValue
+
+	validToken, _ := jwt.NewMergeToken(userIDValue, sourceIDValue, time.Minute, cfg.SecretKey)
+	foreignToken, _ := jwt.NewMergeToken("user789", sourceIDValue, time.Minute, cfg.SecretKey)
+	expiredToken, _ := jwt.NewMergeToken(userIDValue, sourceIDValue, -time.Minute, cfg.SecretKey)
+	authToken, _ := jwt.NewToken(storage.User{UserId: sourceIDValue}, time.Minute, cfg.SecretKey)
+
+	tests := []struct {
+		name               string
+		request            merge.Request
+		setupMock          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:    "Success",
+			request: merge.Request{MergeToken: validToken, Confirm: true},
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("GetSession", mock.Anything, sourceID).Return(nil, storage.ErrNoSession)
+				userRepo.On("MergeUsers", mock.Anything, userID, sourceID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "NotConfirmed",
+			request:            merge.Request{MergeToken: validToken},
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "MissingToken",
+			request:            merge.Request{Confirm: true},
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "TokenForAnotherUser",
+			request:            merge.Request{MergeToken: foreignToken, Confirm: true},
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "ExpiredToken",
+			request:            merge.Request{MergeToken: expiredToken, Confirm: true},
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "AuthTokenIsNotMergeToken",
+			request:            merge.Request{MergeToken: authToken, Confirm: true},
+			setupMock:          func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:    "SourceHasActiveWorkout",
+			request: merge.Request{MergeToken: validToken, Confirm: true},
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("GetSession", mock.Anything, sourceID).Return(&storage.WorkoutSession{UserID: sourceIDValue}, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeActiveWorkout},
+		},
+		{
+			name:    "SourceAlreadyMerged",
+			request: merge.Request{MergeToken: validToken, Confirm: true},
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("GetSession", mock.Anything, sourceID).Return(nil, storage.ErrNoSession)
+				userRepo.On("MergeUsers", mock.Anything, userID, sourceID).Return(storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:    "SameProvider",
+			request: merge.Request{MergeToken: validToken, Confirm: true},
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("GetSession", mock.Anything, sourceID).Return(nil, storage.ErrNoSession)
+				userRepo.On("MergeUsers", mock.Anything, userID, sourceID).Return(storage.ErrIdentityExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeIdentityLinked},
+		},
+		{
+			name:    "MergeError",
+			request: merge.Request{MergeToken: validToken, Confirm: true},
+			setupMock: func(userRepo *mocks.UserRepository, sessionRepo *mocks.SessionRepository) {
+				sessionRepo.On("GetSession", mock.Anything, sourceID).Return(nil, storage.ErrNoSession)
+				userRepo.On("MergeUsers", mock.Anything, userID, sourceID).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			sessionRepo := mocks.NewSessionRepository(t)
+			tt.setupMock(userRepo, sessionRepo)
+
+			handler := merge.NewMergeHandler(logger, userRepo, sessionRepo, cfg)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/users/merge", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			userRepo.AssertExpectations(t)
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
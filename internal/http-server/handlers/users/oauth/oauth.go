@@ -7,34 +7,118 @@ import (
 	"GYMBRO/internal/storage"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/gorilla/sessions"
 	"github.com/markbates/goth"
 	"github.com/markbates/goth/gothic"
+	"github.com/markbates/goth/providers/github"
 	"github.com/markbates/goth/providers/google"
+	"github.com/markbates/goth/providers/openidConnect"
 	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 )
 
-func NewOAuth(cfg *config.Config) {
+// Supported provider types for config.OAuthProviderCfg.Type
+const (
+	TypeGoogle = "google"
+	TypeGitHub = "github"
+	TypeOIDC   = "oidc"
+)
+
+// NewOAuth configures the gothic session store and registers every OAuth provider from the config, with its callback
+// at <public base URL><apiPrefix>/users/oauth/<provider>/callback. Providers without credentials are skipped,
+// so the app can run with any subset of them (or none).
+func NewOAuth(cfg *config.Config, log *slog.Logger, apiPrefix string) error {
+	const op = "handlers.users.oauth.NewOAuth"
+	log = log.With(slog.String("op", op))
+
 	store := sessions.NewCookieStore([]byte(cfg.SecretKey))
 	store.MaxAge(86400 * 7)
 	store.Options = &sessions.Options{
 		Path:     "/",
 		HttpOnly: true,
-		Secure:   cfg.Env == "prod",
+		Secure:   strings.HasPrefix(cfg.PublicBaseURL(), "https://"),
 	}
 
 	gothic.Store = store
-	goth.UseProviders(google.New(cfg.GoogleKey, cfg.GoogleSecret, "http://"+cfg.Address+"/users/oauth/google/callback", "https://www.googleapis.com/auth/userinfo.profile", "https://www.googleapis.com/auth/userinfo.email"))
+
+	var providers []goth.Provider
+	for _, providerCfg := range cfg.Providers {
+		if providerCfg.Key == "" || providerCfg.Secret == "" {
+			log.Warn("OAuth provider has no credentials, skipping", slog.String("provider", providerCfg.Name))
+			continue
+		}
+		provider, err := newProvider(providerCfg, cfg.PublicBaseURL()+apiPrefix+"/users/oauth/"+providerCfg.Name+"/callback")
+		if err != nil {
+			return fmt.Errorf("%s: provider %s: %w", op, providerCfg.Name, err)
+		}
+		providers = append(providers, provider)
+		log.Info("OAuth provider registered", slog.String("provider", providerCfg.Name), slog.String("type", providerCfg.Type))
+	}
+
+	goth.ClearProviders()
+	goth.UseProviders(providers...)
+	return nil
+}
+
+func newProvider(providerCfg config.OAuthProviderCfg, callbackURL string) (goth.Provider, error) {
+	switch providerCfg.Type {
+	case TypeGoogle:
+		scopes := providerCfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"https://www.googleapis.com/auth/userinfo.profile", "https://www.googleapis.com/auth/userinfo.email"}
+		}
+		provider := google.New(providerCfg.Key, providerCfg.Secret, callbackURL, scopes...)
+		provider.SetName(providerCfg.Name)
+		return provider, nil
+	case TypeGitHub:
+		scopes := providerCfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"read:user", "user:email"}
+		}
+		provider := github.New(providerCfg.Key, providerCfg.Secret, callbackURL, scopes...)
+		provider.SetName(providerCfg.Name)
+		return provider, nil
+	case TypeOIDC:
+		if providerCfg.DiscoveryURL == "" {
+			return nil, errors.New("discovery_url is required for oidc providers")
+		}
+		scopes := providerCfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "profile", "email"}
+		}
+		provider, err := openidConnect.New(providerCfg.Key, providerCfg.Secret, callbackURL, providerCfg.DiscoveryURL, scopes...)
+		if err != nil {
+			return nil, err
+		}
+		provider.SetName(providerCfg.Name)
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", providerCfg.Type)
+	}
 }
 
+// linkSessionName is the cookie that marks an OAuth flow started by NewOAuthLinkHandler.
+// It is separate from the gothic session, because gothic rewrites its own cookie when the flow begins.
+const (
+	linkSessionName = "oauth-link"
+	linkUserKey     = "user_id"
+	linkMaxAge      = 10 * 60
+	mergeTokenTTL   = 10 * time.Minute
+)
+
+// NewOAuthCallbackHandler completes the OAuth flow.
+// When the flow was started by NewOAuthLinkHandler it links the identity to the logged-in user.
+// Otherwise, it resolves the user by provider subject and logs them in, registering a new user if nobody
+// has this identity or email. An existing account with the same email is never logged in implicitly,
+// its owner has to link the provider first. Disabled users are not logged in. (2+ userRepo calls)
 func NewOAuthCallbackHandler(log *slog.Logger, userRepo storage.UserRepository, cfg *config.Config) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.users.oauth.NewCallbackHandler"
 		provider := chi.URLParam(r, "provider")
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("provider", provider))
@@ -42,57 +126,57 @@ func NewOAuthCallbackHandler(log *slog.Logger, userRepo storage.UserRepository,
 		ctx := context.WithValue(r.Context(), "provider", provider)
 		r = r.WithContext(ctx)
 
+		linkUserID := popLinkUserID(w, r)
+
 		user, err := gothic.CompleteUserAuth(w, r)
 		if err != nil {
 			log.Error("Failed to complete OAuth", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeOAuthError, "Please try again later"))
-			return
+			return resp.NewError(http.StatusInternalServerError, resp.CodeOAuthError, "Internal error", "Please try again later")
 		}
 		log.Debug("Completed OAuth")
 
-		dbUser, err := userRepo.GetUserByEmail(&user.Email)
+		if linkUserID != "" {
+			return linkIdentity(w, r, log, userRepo, cfg, linkUserID, provider, &user)
+		}
+
+		dbUser, err := userRepo.GetUserByIdentity(r.Context(), &provider, &user.UserID)
 		if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
-			log.Error("Failed to GET user", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			log.Error("Failed to GET user by identity", slog.Any("error", err))
+			return resp.Internal(err)
 		}
 
 		if dbUser == nil {
-			username := strings.Split(user.Email, "@")[0]
-			newUser := storage.User{
-				UserId:   storage.GenerateUID(),
-				Email:    user.Email,
-				Username: username,
-				GoogleId: user.UserID,
+			existingUser, err := userRepo.GetUserByEmail(r.Context(), &user.Email)
+			if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+				log.Error("Failed to GET user", slog.Any("error", err))
+				return resp.Internal(err)
 			}
-			id, err := userRepo.RegisterNewUser(&newUser)
+			if existingUser != nil {
+				log.Debug("Account with this email exists but identity is not linked", slog.String("user_id", existingUser.UserId))
+				return resp.NewError(http.StatusConflict, resp.CodeLinkRequired, "Account with this email already exists", "Login to your account and link this provider in account settings")
+			}
+
+			dbUser, err = registerOAuthUser(r.Context(), userRepo, provider, &user)
 			if err != nil {
-				if errors.Is(err, storage.ErrUserExists) {
+				if errors.Is(err, storage.ErrUserExists) || errors.Is(err, storage.ErrIdentityExists) {
 					log.Warn("User already exists")
-					render.Status(r, http.StatusBadRequest)
-					render.JSON(w, r, resp.Error("User exists", resp.CodeUserExists, "User with this username or email already exists. Try again with another username or email"))
-					return
+					return resp.NewError(http.StatusBadRequest, resp.CodeUserExists, "User exists", "User with this username or email already exists. Try again with another username or email")
 				}
 				log.Error("Failed to SAVE user", slog.Any("error", err))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-				return
+				return resp.Internal(err)
 			}
 			log.Debug("Registered new OAuth user")
-			dbUser = &newUser
-			dbUser.UserId = *id
-		} else {
-			log.Debug("User already exists")
+		}
+
+		if dbUser.IsDisabled {
+			log.Debug("User is disabled", slog.String("user_id", dbUser.UserId))
+			return resp.AccountDisabled()
 		}
 
 		token, err := jwt.NewToken(*dbUser, cfg.JWTLifetime, cfg.SecretKey)
 		if err != nil {
 			log.Error("Failed to GENERATE token", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		http.SetCookie(w, &http.Cookie{
@@ -106,11 +190,134 @@ func NewOAuthCallbackHandler(log *slog.Logger, userRepo storage.UserRepository,
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
 		http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+		return nil
+	})
+}
+
+// registerOAuthUser creates a user for a new identity. The username is taken from the email,
+// and a random suffix is added once if somebody already has that username.
+func registerOAuthUser(ctx context.Context, userRepo storage.UserRepository, provider string, user *goth.User) (*storage.User, error) {
+	username := strings.Split(user.Email, "@")[0]
+	for attempt := 0; ; attempt++ {
+		newUser := storage.User{
+			UserId:   storage.GenerateUID(),
+			Email:    user.Email,
+			Username: username,
+		}
+		identity := storage.Identity{
+			Provider: provider,
+			Subject:  user.UserID,
+			FkUserId: newUser.UserId,
+			Email:    user.Email,
+		}
+		id, err := userRepo.RegisterOAuthUser(ctx, &newUser, &identity)
+		if errors.Is(err, storage.ErrUserExists) && attempt == 0 {
+			username = username + "-" + storage.GenerateUID()[:6]
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		newUser.UserId = *id
+		return &newUser, nil
+	}
+}
+
+// linkIdentity attaches the identity to the user who started the link flow. If the identity already belongs
+// to another account, it returns an error with a short-lived merge token that can be confirmed at /api/v1/users/merge.
+func linkIdentity(w http.ResponseWriter, r *http.Request, log *slog.Logger, userRepo storage.UserRepository, cfg *config.Config, userID string, provider string, user *goth.User) error {
+	log = log.With(slog.String("user_id", userID))
+
+	owner, err := userRepo.GetUserByIdentity(r.Context(), &provider, &user.UserID)
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		log.Error("Failed to GET user by identity", slog.Any("error", err))
+		return resp.Internal(err)
+	}
+
+	if owner != nil && owner.UserId == userID {
+		log.Debug("Identity is already linked")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	}
+
+	if owner != nil {
+		mergeToken, err := jwt.NewMergeToken(userID, owner.UserId, mergeTokenTTL, cfg.SecretKey)
+		if err != nil {
+			log.Error("Failed to GENERATE merge token", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+		log.Debug("Identity belongs to another account", slog.String("owner_id", owner.UserId))
+		return resp.NewError(http.StatusConflict, resp.CodeIdentityLinked, "This identity is linked to another account", "Confirm merging that account into yours at /api/v1/users/merge with the merge token").
+			WithData(map[string]string{"merge_token": mergeToken})
+	}
+
+	identity := storage.Identity{
+		Provider: provider,
+		Subject:  user.UserID,
+		FkUserId: userID,
+		Email:    user.Email,
+	}
+	if err := userRepo.AddUserIdentity(r.Context(), &identity); err != nil {
+		if errors.Is(err, storage.ErrIdentityExists) {
+			log.Debug("Provider is already linked to this account")
+			return resp.NewError(http.StatusConflict, resp.CodeIdentityLinked, "Provider is already linked", "Unlink current identity of this provider first")
+		}
+		log.Error("Failed to ADD identity", slog.Any("error", err))
+		return resp.Internal(err)
+	}
+
+	log.Debug("Identity linked")
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, resp.OK())
+	return nil
+}
+
+// popLinkUserID returns the user that started the link flow (if any) and clears the link cookie.
+func popLinkUserID(w http.ResponseWriter, r *http.Request) string {
+	session, err := gothic.Store.Get(r, linkSessionName)
+	if err != nil || session.IsNew {
+		return ""
 	}
+	userID, _ := session.Values[linkUserKey].(string)
+	session.Options.MaxAge = -1
+	_ = session.Save(r, w)
+	return userID
+}
+
+// NewOAuthLinkHandler starts an OAuth flow that links the provider identity to the authenticated user
+// instead of logging in. The user is remembered in a signed cookie until the callback.
+func NewOAuthLinkHandler(log *slog.Logger) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.oauth.NewLinkHandler"
+		provider := chi.URLParam(r, "provider")
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("provider", provider), slog.String("user_id", userID))
+
+		if _, err := goth.GetProvider(provider); err != nil {
+			log.Debug("Unknown provider")
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "Provider not found", "Check the provider name")
+		}
+
+		session, _ := gothic.Store.New(r, linkSessionName)
+		session.Values[linkUserKey] = userID
+		session.Options.MaxAge = linkMaxAge
+		if err := session.Save(r, w); err != nil {
+			log.Error("Failed to SAVE link session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		ctx := context.WithValue(r.Context(), "provider", provider)
+		r = r.WithContext(ctx)
+
+		log.Debug("Starting OAuth link")
+		gothic.BeginAuthHandler(w, r)
+		return nil
+	})
 }
 
 func NewOAuthLogoutHandler(log *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.users.oauth.NewLogoutHandler"
 		provider := chi.URLParam(r, "provider")
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("provider", provider))
@@ -120,24 +327,22 @@ func NewOAuthLogoutHandler(log *slog.Logger) http.HandlerFunc {
 
 		if err := gothic.Logout(w, r); err != nil {
 			log.Error("Failed to logout", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeOAuthError, "Please try again later"))
-		} else {
-			session, _ := gothic.Store.Get(r, "auth-session")
-			session.Options.MaxAge = -1
-			err := session.Save(r, w)
-			if err != nil {
-				log.Error("Failed to delete user data from session", slog.Any("error", err))
-			}
+			return resp.NewError(http.StatusInternalServerError, resp.CodeOAuthError, "Internal error", "Please try again later").Wrap(err)
+		}
+		session, _ := gothic.Store.Get(r, "auth-session")
+		session.Options.MaxAge = -1
+		if err := session.Save(r, w); err != nil {
+			log.Error("Failed to delete user data from session", slog.Any("error", err))
 		}
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
-		http.Redirect(w, r, "/users/logout", http.StatusTemporaryRedirect)
-	}
+		http.Redirect(w, r, "/api/v1/users/logout", http.StatusTemporaryRedirect)
+		return nil
+	})
 }
 
 func NewOAuthLoginHandler(log *slog.Logger) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.users.oauth.NewLoginHandler"
 		provider := chi.URLParam(r, "provider")
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("provider", provider))
@@ -153,5 +358,6 @@ func NewOAuthLoginHandler(log *slog.Logger) http.HandlerFunc {
 		} else {
 			gothic.BeginAuthHandler(w, r)
 		}
-	}
+		return nil
+	})
 }
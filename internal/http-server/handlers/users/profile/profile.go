@@ -0,0 +1,107 @@
+package profile
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type BodyWeightRequest struct {
+	BodyWeight int `json:"body_weight" validate:"required,gte=1,lte=500"`
+}
+
+type PrivacyRequest struct {
+	IsPrivate *bool `json:"is_private" validate:"required"`
+}
+
+// NewProfileHandler creates an HTTP handler that returns the authenticated user's profile with the awarded achievements.
+// (1 userRepo call, 1 achievementRepo call)
+func NewProfileHandler(log *slog.Logger, userRepo storage.UserRepository, achievementRepo storage.AchievementRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.profile.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Debug("Failed to GET user", slog.Any("error", err))
+			return err
+		}
+
+		achievements, err := achievementRepo.GetAchievements(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET achievements", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewProfileResponse(user, achievements)))
+		return nil
+	})
+}
+
+// NewBodyWeightHandler creates an HTTP handler that sets the authenticated user's body weight,
+// it is needed for bodyweight achievements. (1 userRepo call)
+func NewBodyWeightHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.profile.NewBodyWeight"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		var request BodyWeightRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		if err := userRepo.SetUserBodyWeight(r.Context(), &userID, request.BodyWeight); err != nil {
+			log.Debug("Failed to SET body weight", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("Body weight set", slog.Int("body_weight", request.BodyWeight))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewPrivacyHandler creates an HTTP handler that makes the authenticated user's profile private or public.
+// Following a private profile needs an approval, requests sent before the change are kept as they are. (1 userRepo call)
+func NewPrivacyHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.profile.NewPrivacy"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		var request PrivacyRequest
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		if err := userRepo.SetUserPrivate(r.Context(), &userID, *request.IsPrivate); err != nil {
+			log.Debug("Failed to SET privacy", slog.Any("error", err))
+			return err
+		}
+
+		log.Debug("Privacy set", slog.Bool("is_private", *request.IsPrivate))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
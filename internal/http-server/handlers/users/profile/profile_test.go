@@ -0,0 +1,295 @@
+package profile_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/users/profile"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProfileHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	awardedAt := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		setupMock            func(userRepo *mocks.UserRepository, achievementRepo *mocks.AchievementRepository)
+		expectedStatusCode   int
+		expectedResponse     resp.DetailedResponse
+		expectedAchievements []dto.AchievementResponse
+	}{
+		{
+			name: "Success",
+			setupMock: func(userRepo *mocks.UserRepository, achievementRepo *mocks.AchievementRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", Username: "bro", BodyWeight: 80}, nil)
+				achievementRepo.On("GetAchievements", mock.Anything, userID).Return([]*storage.Achievement{
+					{UserID: "user123", Code: "first_workout", AwardedAt: awardedAt},
+					{UserID: "user123", Code: "retired_badge", AwardedAt: awardedAt},
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK, Code: resp.StatusOK},
+			expectedAchievements: []dto.AchievementResponse{
+				{Code: "first_workout", Name: "First Step", Description: "Finish your first workout", AwardedAt: awardedAt},
+				{Code: "retired_badge", AwardedAt: awardedAt},
+			},
+		},
+		{
+			name: "NoAchievements",
+			setupMock: func(userRepo *mocks.UserRepository, achievementRepo *mocks.AchievementRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
+				achievementRepo.On("GetAchievements", mock.Anything, userID).Return(nil, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponse:     resp.DetailedResponse{Status: resp.StatusOK, Code: resp.StatusOK},
+			expectedAchievements: []dto.AchievementResponse{},
+		},
+		{
+			name: "UserNotFound",
+			setupMock: func(userRepo *mocks.UserRepository, achievementRepo *mocks.AchievementRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name: "AchievementsError",
+			setupMock: func(userRepo *mocks.UserRepository, achievementRepo *mocks.AchievementRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
+				achievementRepo.On("GetAchievements", mock.Anything, userID).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			achievementRepo := mocks.NewAchievementRepository(t)
+			tt.setupMock(userRepo, achievementRepo)
+
+			handler := profile.NewProfileHandler(logger, userRepo, achievementRepo)
+
+			req := httptest.NewRequest("GET", "/users/me", nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data dto.ProfileResponse `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			require.Equal(t, tt.expectedResponse.Code, response.Code)
+			if tt.expectedAchievements != nil {
+				require.Equal(t, tt.expectedAchievements, response.Data.Achievements)
+			}
+		})
+	}
+}
+
+func TestBodyWeightHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	tests := []struct {
+		name               string
+		requestBody        interface{}
+		setupMock          func(userRepo *mocks.UserRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:        "Success",
+			requestBody: profile.BodyWeightRequest{BodyWeight: 82},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserBodyWeight", mock.Anything, userID, 82).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "InvalidJSON",
+			requestBody:        "{",
+			setupMock:          func(userRepo *mocks.UserRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "ValidationError",
+			requestBody:        profile.BodyWeightRequest{BodyWeight: 0},
+			setupMock:          func(userRepo *mocks.UserRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError},
+		},
+		{
+			name:        "RepoError",
+			requestBody: profile.BodyWeightRequest{BodyWeight: 82},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserBodyWeight", mock.Anything, userID, 82).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			tt.setupMock(userRepo)
+
+			handler := profile.NewBodyWeightHandler(logger, userRepo)
+
+			var body []byte
+			if s, ok := tt.requestBody.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest("PUT", "/users/me/body-weight", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+		})
+	}
+}
+
+func TestPrivacyHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	private, public := true, false
+
+	tests := []struct {
+		name               string
+		requestBody        interface{}
+		setupMock          func(userRepo *mocks.UserRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:        "MakePrivate",
+			requestBody: profile.PrivacyRequest{IsPrivate: &private},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserPrivate", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "MakePublic",
+			requestBody: profile.PrivacyRequest{IsPrivate: &public},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserPrivate", mock.Anything, userID, false).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "InvalidJSON",
+			requestBody:        "{",
+			setupMock:          func(userRepo *mocks.UserRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "ValidationError",
+			requestBody:        map[string]interface{}{},
+			setupMock:          func(userRepo *mocks.UserRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError},
+		},
+		{
+			name:        "UserNotFound",
+			requestBody: profile.PrivacyRequest{IsPrivate: &private},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserPrivate", mock.Anything, userID, true).Return(storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:        "RepoError",
+			requestBody: profile.PrivacyRequest{IsPrivate: &private},
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("SetUserPrivate", mock.Anything, userID, true).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			tt.setupMock(userRepo)
+
+			handler := profile.NewPrivacyHandler(logger, userRepo)
+
+			var body []byte
+			if s, ok := tt.requestBody.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest("PUT", "/users/me/privacy", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+		})
+	}
+}
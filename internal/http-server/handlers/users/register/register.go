@@ -1,6 +1,7 @@
 package register
 
 import (
+	"GYMBRO/internal/http-server/dto"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/lib/validation"
 	"GYMBRO/internal/storage"
@@ -16,67 +17,52 @@ import (
 // It decodes the request body, validates the user data, checks for existing users,
 // hashes the password, and registers the new user, redirecting to the login page upon success. (2 userRepo calls)
 func NewRegisterHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.users.register.New"
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())))
 
-		var user storage.User
-		err := render.DecodeJSON(r.Body, &user)
+		var request dto.RegisterRequest
+		err := dto.Decode(r, &request)
 		if err != nil {
 			log.Warn("Failed to decode request", slog.Any("error", err))
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("Failed to decode request", resp.CodeBadRequest, "Check the request fields for typos or naming errors"))
-			return
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos, naming errors or extras")
 		}
-		log.Debug("Request body decoded", slog.Any("request", user))
+		log.Debug("Request body decoded", slog.String("username", request.Username), slog.String("email", request.Email))
 
-		if err := validation.ValidateStruct(log, &user); err != nil {
-			validation.HandleValidationError(w, r, err)
-			return
-		}
-		if user.UserId != "" || user.Points != 0 || user.GoogleId != "" || user.FkGymId != 0 || user.FkClanId != "" {
-			log.Warn("User wanted to set restricted field", slog.Any("user", user))
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("You do not have permission to set some fields", resp.CodeBadRequest, "Check the request fields for extras"))
-			return
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
 		}
+		user := request.ToUser()
 
-		existingUser, err := userRepo.GetUserByEmail(&user.Email)
+		existingUser, err := userRepo.GetUserByEmail(r.Context(), &user.Email)
 		if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
 			log.Error("Failed to GET user", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 		if existingUser != nil {
-			render.Status(r, http.StatusBadRequest)
-			render.JSON(w, r, resp.Error("User already exists", resp.CodeUserExists, "User with this email already exists. Check email for typos or try to login"))
-			return
+			return resp.NewError(http.StatusBadRequest, resp.CodeUserExists, "User already exists", "User with this email already exists. Check email for typos or try to login")
 		}
 
 		passHash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		if err != nil {
 			log.Error("Failed to GENERATE password", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		user.Password = string(passHash)
 		user.UserId = storage.GenerateUID()
 
-		_, err = userRepo.RegisterNewUser(&user)
+		_, err = userRepo.RegisterNewUser(r.Context(), user)
 		if err != nil {
 			log.Error("Failed to SAVE user", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
 		log.Debug("Registered user")
 
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
-		http.Redirect(w, r, "/users/login", http.StatusTemporaryRedirect)
-	}
+		http.Redirect(w, r, "/api/v1/users/login", http.StatusTemporaryRedirect)
+		return nil
+	})
 }
@@ -1,6 +1,7 @@
 package register_test
 
 import (
+	"GYMBRO/internal/http-server/dto"
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/http-server/handlers/users/register"
 	"GYMBRO/internal/storage"
@@ -32,14 +33,14 @@ func TestRegisterHandler(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			reqBody: storage.User{
+			reqBody: dto.RegisterRequest{
 				Username: "testuser",
 				Email:    "test@example.com",
 				Password: "password123",
 			},
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(nil, storage.ErrUserNotFound)
-				userRepo.On("RegisterNewUser", mock.Anything).Return(func() *string {
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, storage.ErrUserNotFound)
+				userRepo.On("RegisterNewUser", mock.Anything, mock.Anything).Return(func() *string {
 					id := "new_user_id"
 					return &id
 				}(), nil)
@@ -56,7 +57,7 @@ func TestRegisterHandler(t *testing.T) {
 		},
 		{
 			name: "ValidationFailed",
-			reqBody: storage.User{
+			reqBody: dto.RegisterRequest{
 				Username: "testuser",
 				Email:    "invalid-email",
 				Password: "password123",
@@ -67,13 +68,13 @@ func TestRegisterHandler(t *testing.T) {
 		},
 		{
 			name: "UserAlreadyExists",
-			reqBody: storage.User{
+			reqBody: dto.RegisterRequest{
 				Username: "testuser",
 				Email:    "test@example.com",
 				Password: "password123",
 			},
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(&storage.User{
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(&storage.User{
 					Email: "test@example.com",
 				}, nil)
 			},
@@ -82,39 +83,39 @@ func TestRegisterHandler(t *testing.T) {
 		},
 		{
 			name: "RegistrationError",
-			reqBody: storage.User{
+			reqBody: dto.RegisterRequest{
 				Username: "testuser",
 				Email:    "test@example.com",
 				Password: "password123",
 			},
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(nil, storage.ErrUserNotFound)
-				userRepo.On("RegisterNewUser", mock.Anything).Return(nil, errors.New("database error"))
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, storage.ErrUserNotFound)
+				userRepo.On("RegisterNewUser", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
 		},
 		{
 			name: "InternalServerError",
-			reqBody: storage.User{
+			reqBody: dto.RegisterRequest{
 				Username: "testuser",
 				Email:    "test@example.com",
 				Password: "password123",
 			},
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByEmail", email).Return(nil, errors.New("database error"))
+				userRepo.On("GetUserByEmail", mock.Anything, email).Return(nil, errors.New("database error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
 		},
 		{
 			name: "RestrictedFieldSet",
-			reqBody: storage.User{
-				Username: "testuser",
-				Email:    "test@example.com",
-				Password: "password123",
-				UserId:   "some_id",
-				Points:   100,
+			reqBody: map[string]interface{}{
+				"username": "testuser",
+				"email":    "test@example.com",
+				"password": "password123",
+				"user_id":  "some_id",
+				"points":   100,
 			},
 			setupMock:          func(userRepo *mocks.UserRepository) {},
 			expectedStatusCode: http.StatusBadRequest,
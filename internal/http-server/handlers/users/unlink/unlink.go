@@ -0,0 +1,69 @@
+package unlink
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// NewUnlinkHandler creates an HTTP handler that detaches an OAuth identity from the authenticated user.
+// It refuses to remove the last way to login (no password and no identities of other providers). (3 userRepo calls)
+func NewUnlinkHandler(log *slog.Logger, userRepo storage.UserRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.users.unlink.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		provider := chi.URLParam(r, "provider")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("provider", provider))
+
+		user, err := userRepo.GetUserByID(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET user", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		identities, err := userRepo.GetUserIdentities(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET identities", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		found := false
+		otherProviders := make(map[string]bool)
+		for _, identity := range identities {
+			if identity.Provider == provider {
+				found = true
+				continue
+			}
+			otherProviders[identity.Provider] = true
+		}
+		if !found {
+			log.Debug("Identity not found")
+			return resp.NewError(http.StatusNotFound, resp.CodeNotFound, "Identity not found", "This provider is not linked to your account")
+		}
+
+		if user.Password == "" && len(otherProviders) == 0 {
+			log.Debug("Refused to unlink the last login method")
+			return resp.NewError(http.StatusConflict, resp.CodeLastLoginMethod, "Can not unlink the last login method", "Link another provider first")
+		}
+
+		if err := userRepo.DeleteUserIdentity(r.Context(), &userID, &provider); err != nil {
+			if errors.Is(err, storage.ErrIdentityNotFound) {
+				log.Debug("Identity not found")
+				return err
+			}
+			log.Error("Failed to DELETE identity", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Debug("Identity unlinked")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package unlink_test
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/users/unlink"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUnlinkHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	providerValue := "google"
+	provider := &providerValue
+
+	google := &storage.Identity{Provider: "google", Subject: "g1", FkUserId: "user123"}
+	github := &storage.Identity{Provider: "github", Subject: "gh1", FkUserId: "user123"}
+	secondGoogle := &storage.Identity{Provider: "google", Subject: "g2", FkUserId: "user123"}
+
+	tests := []struct {
+		name               string
+		setupMock          func(userRepo *mocks.UserRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name: "SuccessWithPassword",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", Password: "hash"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{google}, nil)
+				userRepo.On("DeleteUserIdentity", mock.Anything, userID, provider).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "SuccessWithAnotherIdentity",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{google, github}, nil)
+				userRepo.On("DeleteUserIdentity", mock.Anything, userID, provider).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "LastLoginMethod",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{google}, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeLastLoginMethod},
+		},
+		{
+			name: "LastProviderWithTwoIdentities",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{google, secondGoogle}, nil)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeLastLoginMethod},
+		},
+		{
+			name: "NotLinked",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", Password: "hash"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{github}, nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name: "DeleteError",
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", Password: "hash"}, nil)
+				userRepo.On("GetUserIdentities", mock.Anything, userID).Return([]*storage.Identity{google}, nil)
+				userRepo.On("DeleteUserIdentity", mock.Anything, userID, provider).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			tt.setupMock(userRepo)
+
+			r := chi.NewRouter()
+			r.Delete("/users/identities/{provider}", unlink.NewUnlinkHandler(logger, userRepo))
+
+			req := httptest.NewRequest("DELETE", "/users/identities/"+providerValue, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package webhooks
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/validation"
+	"GYMBRO/internal/storage"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+	// SecretPrefix starts every webhook secret, so leaked ones are easy to spot.
+	SecretPrefix = "whsec_"
+)
+
+// Request is a webhook for the events of the caller, or of the members of the gym GymID.
+// Only moderators and admins can create gym webhooks. Payloads are only sent over HTTPS.
+type Request struct {
+	URL    string   `json:"url" validate:"required,http_url,startswith=https://,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=workout.ended pr.achieved session.auto_ended"`
+	GymID  *int     `json:"gym_id" validate:"omitempty,gte=0"`
+}
+
+// NewCreateHandler creates an HTTP handler that creates a webhook with a new signing secret,
+// it is returned only in this response. A user manages at most webhooks_cfg.max_per_owner webhooks. (1 webhookRepo call)
+func NewCreateHandler(log *slog.Logger, webhookRepo storage.WebhookRepository, cfg *config.Config) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewCreate"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		var request Request
+		if err := dto.Decode(r, &request); err != nil {
+			log.Warn("Failed to decode request", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Failed to decode request", "Check the request fields for typos or naming errors")
+		}
+
+		if err := validation.ValidateStruct(log, &request); err != nil {
+			return validation.Error(err)
+		}
+
+		webhook := &storage.Webhook{
+			WebhookID: storage.GenerateUID(),
+			OwnerID:   userID,
+			Kind:      storage.WebhookUser,
+			SubjectID: userID,
+			URL:       request.URL,
+			Events:    request.Events,
+			CreatedAt: time.Now().UTC(),
+		}
+		if request.GymID != nil {
+			if role := jwt.GetRoleFromContext(r.Context()); role != storage.RoleModerator && role != storage.RoleAdmin {
+				log.Warn("Gym webhook denied", slog.String("role", role))
+				return resp.NewError(http.StatusForbidden, resp.CodeForbidden, "Forbidden", "Only moderators and admins can create gym webhooks")
+			}
+			webhook.Kind = storage.WebhookGym
+			webhook.SubjectID = strconv.Itoa(*request.GymID)
+		}
+
+		secret, err := newSecret()
+		if err != nil {
+			log.Error("Failed to GENERATE secret", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+		webhook.Secret = secret
+
+		if err := webhookRepo.CreateWebhook(r.Context(), webhook, cfg.WebhooksCfg.MaxPerOwner); err != nil {
+			if errors.Is(err, storage.ErrGymNotFound) || errors.Is(err, storage.ErrUserNotFound) || errors.Is(err, storage.ErrWebhookLimit) {
+				log.Debug("Failed to CREATE webhook", slog.Any("error", err))
+				return err
+			}
+			log.Error("Failed to CREATE webhook", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		log.Info("Webhook created", slog.String("webhook_id", webhook.WebhookID), slog.String("kind", webhook.Kind), slog.String("subject_id", webhook.SubjectID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewWebhookResponse(webhook, true)))
+		return nil
+	})
+}
+
+// NewListHandler creates an HTTP handler that lists the webhooks of the caller, the oldest first. (1 webhookRepo call)
+func NewListHandler(log *slog.Logger, webhookRepo storage.WebhookRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewList"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		webhooks, err := webhookRepo.GetWebhooks(r.Context(), &userID)
+		if err != nil {
+			log.Error("Failed to GET webhooks", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewWebhooksResponse(webhooks)))
+		return nil
+	})
+}
+
+// NewDeleteHandler creates an HTTP handler that deletes the webhook from the URL with its deliveries. (1 webhookRepo call)
+func NewDeleteHandler(log *slog.Logger, webhookRepo storage.WebhookRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewDelete"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		webhookID := chi.URLParam(r, "webhookID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("webhook_id", webhookID))
+
+		if err := webhookRepo.DeleteWebhook(r.Context(), &userID, &webhookID); err != nil {
+			return webhookError(log, err)
+		}
+
+		log.Info("Webhook deleted")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+// NewDeliveriesHandler creates an HTTP handler that returns the delivery log of the webhook from the URL, the newest first.
+// The status query parameter keeps the deliveries with that status and the limit one caps the log. (1 webhookRepo call)
+func NewDeliveriesHandler(log *slog.Logger, webhookRepo storage.WebhookRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewDeliveries"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		webhookID := chi.URLParam(r, "webhookID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("webhook_id", webhookID))
+
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "", storage.DeliveryPending, storage.DeliveryDelivered, storage.DeliveryDead:
+		default:
+			log.Debug("Invalid status", slog.String("status", status))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid status", "Use pending, delivered or dead")
+		}
+		limit, err := parseLimit(log, r)
+		if err != nil {
+			return err
+		}
+
+		deliveries, err := webhookRepo.GetDeliveries(r.Context(), &userID, webhookID, status, limit)
+		if err != nil {
+			return webhookError(log, err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewDeliveriesResponse(deliveries)))
+		return nil
+	})
+}
+
+// NewDeadLettersHandler creates an HTTP handler that lists the dead deliveries of all webhooks of the caller, the newest first.
+// The limit query parameter caps the list. (1 webhookRepo call)
+func NewDeadLettersHandler(log *slog.Logger, webhookRepo storage.WebhookRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewDeadLetters"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		limit, err := parseLimit(log, r)
+		if err != nil {
+			return err
+		}
+
+		deliveries, err := webhookRepo.GetDeliveries(r.Context(), &userID, "", storage.DeliveryDead, limit)
+		if err != nil {
+			log.Error("Failed to GET dead deliveries", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.Data(dto.NewDeliveriesResponse(deliveries)))
+		return nil
+	})
+}
+
+// NewRetryHandler creates an HTTP handler that queues the dead delivery from the URL again, the dispatcher sends it
+// on its next run with a fresh set of attempts. (1 webhookRepo call)
+func NewRetryHandler(log *slog.Logger, webhookRepo storage.WebhookRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.webhooks.NewRetry"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		deliveryID := chi.URLParam(r, "deliveryID")
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID), slog.String("delivery_id", deliveryID))
+
+		if err := webhookRepo.RetryDelivery(r.Context(), &userID, &deliveryID, time.Now().UTC()); err != nil {
+			return webhookError(log, err)
+		}
+
+		log.Info("Delivery queued again")
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp.OK())
+		return nil
+	})
+}
+
+func parseLimit(log *slog.Logger, r *http.Request) (int, error) {
+	param := r.URL.Query().Get("limit")
+	if param == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(param)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		log.Debug("Invalid limit", slog.String("limit", param))
+		return 0, resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid limit", "Use a number from 1 to "+strconv.Itoa(MaxLimit))
+	}
+	return limit, nil
+}
+
+// newSecret returns 32 random bytes as hex after SecretPrefix.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return SecretPrefix + hex.EncodeToString(b), nil
+}
+
+// webhookError logs unexpected errors, the webhook and delivery errors are mapped by resp.
+func webhookError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrWebhookNotFound), errors.Is(err, storage.ErrDeliveryNotFound), errors.Is(err, storage.ErrDeliveryNotDead):
+		log.Debug("Webhook request failed", slog.Any("error", err))
+		return err
+	default:
+		log.Error("Failed to ACCESS webhook", slog.Any("error", err))
+		return resp.Internal(err)
+	}
+}
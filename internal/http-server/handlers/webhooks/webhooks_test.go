@@ -0,0 +1,400 @@
+package webhooks_test
+
+import (
+	"GYMBRO/internal/config"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/webhooks"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	cfg := &config.Config{WebhooksCfg: config.WebhooksCfg{MaxPerOwner: 10}}
+
+	gymID := 3
+	webhook := func(kind, subjectID string) interface{} {
+		return mock.MatchedBy(func(w *storage.Webhook) bool {
+			return w.WebhookID != "" && w.OwnerID == "user123" && w.Kind == kind && w.SubjectID == subjectID &&
+				strings.HasPrefix(w.Secret, webhooks.SecretPrefix) && w.URL == "https://partner.example.com/hooks"
+		})
+	}
+
+	tests := []struct {
+		name               string
+		role               string
+		requestBody        interface{}
+		setupMock          func(webhookRepo *mocks.WebhookRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:        "UserWebhook",
+			role:        storage.RoleUser,
+			requestBody: webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded, storage.WebhookPRAchieved}},
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("CreateWebhook", mock.Anything, webhook(storage.WebhookUser, "user123"), 10).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:        "GymWebhook",
+			role:        storage.RoleModerator,
+			requestBody: webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}, GymID: &gymID},
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("CreateWebhook", mock.Anything, webhook(storage.WebhookGym, "3"), 10).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "GymWebhookForbidden",
+			role:               storage.RoleUser,
+			requestBody:        webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}, GymID: &gymID},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeForbidden},
+		},
+		{
+			name:        "GymNotFound",
+			role:        storage.RoleAdmin,
+			requestBody: webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}, GymID: &gymID},
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("CreateWebhook", mock.Anything, webhook(storage.WebhookGym, "3"), 10).Return(storage.ErrGymNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:               "UnknownEvent",
+			role:               storage.RoleUser,
+			requestBody:        webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{"workout.started"}},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "NoEvents",
+			role:               storage.RoleUser,
+			requestBody:        webhooks.Request{URL: "https://partner.example.com/hooks"},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "NotHTTPURL",
+			role:               storage.RoleUser,
+			requestBody:        webhooks.Request{URL: "ftp://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:               "NotHTTPS",
+			role:               storage.RoleUser,
+			requestBody:        webhooks.Request{URL: "http://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeValidationError},
+		},
+		{
+			name:        "Limit",
+			role:        storage.RoleUser,
+			requestBody: webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}},
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("CreateWebhook", mock.Anything, webhook(storage.WebhookUser, "user123"), 10).Return(storage.ErrWebhookLimit)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeWebhookLimit},
+		},
+		{
+			name:               "InvalidJSON",
+			role:               storage.RoleUser,
+			requestBody:        "invalid json",
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "UnknownField",
+			role:               storage.RoleUser,
+			requestBody:        map[string]interface{}{"url": "https://partner.example.com/hooks", "events": []string{storage.WebhookWorkoutEnded}, "secret": "whsec_mine"},
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:        "InternalError",
+			role:        storage.RoleUser,
+			requestBody: webhooks.Request{URL: "https://partner.example.com/hooks", Events: []string{storage.WebhookWorkoutEnded}},
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("CreateWebhook", mock.Anything, mock.Anything, 10).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhookRepo := mocks.NewWebhookRepository(t)
+			tt.setupMock(webhookRepo)
+
+			handler := webhooks.NewCreateHandler(logger, webhookRepo, cfg)
+
+			var body []byte
+			if s, ok := tt.requestBody.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+			req := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "user123")
+			ctx = context.WithValue(ctx, jwt.RoleKey, tt.role)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response struct {
+				resp.DetailedResponse
+				Data struct {
+					Secret string `json:"secret"`
+				} `json:"data"`
+			}
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			if rr.Code == http.StatusOK {
+				require.True(t, strings.HasPrefix(response.Data.Secret, webhooks.SecretPrefix), "the secret is not returned on create")
+			}
+
+			webhookRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	webhookIDValue := "webhook1"
+	webhookID := &webhookIDValue
+	deliveryIDValue := "delivery1"
+	deliveryID := &deliveryIDValue
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	webhook := &storage.Webhook{WebhookID: "webhook1", OwnerID: "user123", Kind: storage.WebhookUser, SubjectID: "user123",
+		URL: "https://partner.example.com/hooks", Secret: "whsec_secret", Events: []string{storage.WebhookWorkoutEnded}, CreatedAt: created}
+	dead := &storage.WebhookDelivery{DeliveryID: "delivery1", WebhookID: "webhook1", Event: storage.WebhookWorkoutEnded, Payload: `{"id":"1"}`,
+		Status: storage.DeliveryDead, Attempts: 8, NextAttemptAt: created, LastStatusCode: 500, LastError: "unexpected status 500 Internal Server Error", CreatedAt: created}
+
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		setupMock          func(webhookRepo *mocks.WebhookRepository)
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+		hidesSecret        bool
+	}{
+		{
+			name:   "ListSuccess",
+			method: "GET",
+			url:    "/webhooks",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetWebhooks", mock.Anything, userID).Return([]*storage.Webhook{webhook}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+			hidesSecret:        true,
+		},
+		{
+			name:   "ListInternalError",
+			method: "GET",
+			url:    "/webhooks",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetWebhooks", mock.Anything, userID).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:   "DeleteSuccess",
+			method: "DELETE",
+			url:    "/webhooks/webhook1",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("DeleteWebhook", mock.Anything, userID, webhookID).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "DeleteNotFound",
+			method: "DELETE",
+			url:    "/webhooks/webhook1",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("DeleteWebhook", mock.Anything, userID, webhookID).Return(storage.ErrWebhookNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "DeliveriesSuccess",
+			method: "GET",
+			url:    "/webhooks/webhook1/deliveries?status=dead&limit=10",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetDeliveries", mock.Anything, userID, "webhook1", storage.DeliveryDead, 10).Return([]*storage.WebhookDelivery{dead}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "DeliveriesDefaults",
+			method: "GET",
+			url:    "/webhooks/webhook1/deliveries",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetDeliveries", mock.Anything, userID, "webhook1", "", webhooks.DefaultLimit).Return([]*storage.WebhookDelivery{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "DeliveriesInvalidStatus",
+			method:             "GET",
+			url:                "/webhooks/webhook1/deliveries?status=failed",
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:               "DeliveriesInvalidLimit",
+			method:             "GET",
+			url:                "/webhooks/webhook1/deliveries?limit=1000",
+			setupMock:          func(webhookRepo *mocks.WebhookRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeBadRequest},
+		},
+		{
+			name:   "DeliveriesWebhookNotFound",
+			method: "GET",
+			url:    "/webhooks/webhook1/deliveries",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetDeliveries", mock.Anything, userID, "webhook1", "", webhooks.DefaultLimit).Return(nil, storage.ErrWebhookNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+		{
+			name:   "DeadLettersSuccess",
+			method: "GET",
+			url:    "/webhooks/dead-letters",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetDeliveries", mock.Anything, userID, "", storage.DeliveryDead, webhooks.DefaultLimit).Return([]*storage.WebhookDelivery{dead}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "DeadLettersInternalError",
+			method: "GET",
+			url:    "/webhooks/dead-letters",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("GetDeliveries", mock.Anything, userID, "", storage.DeliveryDead, webhooks.DefaultLimit).Return(nil, errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+		},
+		{
+			name:   "RetrySuccess",
+			method: "POST",
+			url:    "/webhooks/deliveries/delivery1/retry",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("RetryDelivery", mock.Anything, userID, deliveryID, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "RetryNotDead",
+			method: "POST",
+			url:    "/webhooks/deliveries/delivery1/retry",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("RetryDelivery", mock.Anything, userID, deliveryID, mock.AnythingOfType("time.Time")).Return(storage.ErrDeliveryNotDead)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeDeliveryNotDead},
+		},
+		{
+			name:   "RetryNotFound",
+			method: "POST",
+			url:    "/webhooks/deliveries/delivery1/retry",
+			setupMock: func(webhookRepo *mocks.WebhookRepository) {
+				webhookRepo.On("RetryDelivery", mock.Anything, userID, deliveryID, mock.AnythingOfType("time.Time")).Return(storage.ErrDeliveryNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhookRepo := mocks.NewWebhookRepository(t)
+			tt.setupMock(webhookRepo)
+
+			r := chi.NewRouter()
+			r.Get("/webhooks", webhooks.NewListHandler(logger, webhookRepo))
+			r.Get("/webhooks/dead-letters", webhooks.NewDeadLettersHandler(logger, webhookRepo))
+			r.Post("/webhooks/deliveries/{deliveryID}/retry", webhooks.NewRetryHandler(logger, webhookRepo))
+			r.Delete("/webhooks/{webhookID}", webhooks.NewDeleteHandler(logger, webhookRepo))
+			r.Get("/webhooks/{webhookID}/deliveries", webhooks.NewDeliveriesHandler(logger, webhookRepo))
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "user123")
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+			if tt.hidesSecret {
+				require.NotContains(t, rr.Body.String(), webhook.Secret)
+			}
+
+			webhookRepo.AssertExpectations(t)
+		})
+	}
+}
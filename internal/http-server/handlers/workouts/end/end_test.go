@@ -3,7 +3,12 @@ package end_test
 import (
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"GYMBRO/internal/http-server/handlers/workouts/end"
+	achievementsmocks "GYMBRO/internal/lib/achievements/mocks"
+	eventsmocks "GYMBRO/internal/lib/events/mocks"
+	"GYMBRO/internal/lib/finalizer"
 	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/webhooks"
+	webhooksmocks "GYMBRO/internal/lib/webhooks/mocks"
 	"GYMBRO/internal/storage"
 	"GYMBRO/internal/storage/mocks"
 	"context"
@@ -28,14 +33,14 @@ func TestEndHandler(t *testing.T) {
 	tests := []struct {
 		name               string
 		userID             string
-		setupMock          func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository)
+		setupMock          func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier)
 		expectedStatusCode int
 		expectedResponse   resp.DetailedResponse
 	}{
 		{
 			name:   "Success",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
@@ -43,12 +48,89 @@ func TestEndHandler(t *testing.T) {
 						{FkExerciseId: 1, Weight: 100, Reps: 10},
 					},
 				}
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{}, nil)
-				userRepo.On("SetUserMax", userID, mock.Anything).Return(nil)
-				workoutRepo.On("SaveWorkout", session).Return(nil)
-				sessionRepo.On("DeleteSession", userID).Return(nil)
-				userRepo.On("ChangeStatus", userID, false).Return(nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.MatchedBy(func(messages []*webhooks.Message) bool {
+					return len(messages) == 2 && messages[0].Event == storage.WebhookWorkoutEnded && messages[1].Event == storage.WebhookPRAchieved
+				})).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "EvaluateAchievementsError",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				session := &storage.WorkoutSession{
+					UserID:    "user123",
+					SessionID: "session123",
+					Records: []storage.Record{
+						{FkExerciseId: 1, Weight: 100, Reps: 10},
+					},
+				}
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, errors.New("evaluate error"))
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "EmitEventsError",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				session := &storage.WorkoutSession{
+					UserID:    "user123",
+					SessionID: "session123",
+					Records: []storage.Record{
+						{FkExerciseId: 1, Weight: 100, Reps: 10},
+					},
+				}
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(errors.New("emit error"))
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "NotifyWebhooksError",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				session := &storage.WorkoutSession{
+					UserID:    "user123",
+					SessionID: "session123",
+					Records: []storage.Record{
+						{FkExerciseId: 1, Weight: 100, Reps: 10},
+					},
+				}
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.Anything).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("notify error"))
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -56,8 +138,8 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "SessionNotFound",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -65,14 +147,14 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "SaveWorkoutError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
 				}
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{}, nil)
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				workoutRepo.On("SaveWorkout", session).Return(errors.New("save workout error"))
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(errors.New("save workout error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -80,15 +162,15 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "DeleteSessionError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
 				}
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{}, nil)
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				workoutRepo.On("SaveWorkout", session).Return(nil)
-				sessionRepo.On("DeleteSession", userID).Return(errors.New("delete session error"))
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(errors.New("delete session error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -96,16 +178,16 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "UserStatusError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
 				}
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{}, nil)
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				workoutRepo.On("SaveWorkout", session).Return(nil)
-				sessionRepo.On("DeleteSession", userID).Return(nil)
-				userRepo.On("ChangeStatus", userID, false).Return(errors.New("user status error"))
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{}, nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(errors.New("user status error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -113,7 +195,7 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "GetUserMaxesError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
@@ -121,8 +203,8 @@ func TestEndHandler(t *testing.T) {
 						{FkExerciseId: 1, Weight: 100, Reps: 10},
 					},
 				}
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				userRepo.On("GetUserMaxes", userID).Return(nil, errors.New("get user maxes error"))
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return(nil, errors.New("get user maxes error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -130,7 +212,7 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "NewRecordSetSuccess",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
@@ -139,12 +221,39 @@ func TestEndHandler(t *testing.T) {
 					},
 				}
 				userMax := &storage.Max{ExerciseId: 1, MaxWeight: 90, Reps: 8}
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{userMax}, nil)
-				userRepo.On("SetUserMax", userID, mock.AnythingOfType("*storage.Max")).Return(nil)
-				workoutRepo.On("SaveWorkout", session).Return(nil)
-				sessionRepo.On("DeleteSession", userID).Return(nil)
-				userRepo.On("ChangeStatus", userID, false).Return(nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{userMax}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.AnythingOfType("*storage.Max")).Return(nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "HeldRecordIsNotMax",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
+				session := &storage.WorkoutSession{
+					UserID:    "user123",
+					SessionID: "session123",
+					Records: []storage.Record{
+						{FkExerciseId: 1, Weight: 200, Reps: 10, Status: storage.RecordHeld},
+					},
+				}
+				userMax := &storage.Max{ExerciseId: 1, MaxWeight: 90, Reps: 8}
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{userMax}, nil)
+				workoutRepo.On("SaveWorkout", mock.Anything, session).Return(nil)
+				sessionRepo.On("DeleteSession", mock.Anything, userID).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, false).Return(nil)
+				evaluator.On("Evaluate", mock.Anything, "user123").Return(nil, nil)
+				emitter.On("Emit", mock.Anything, mock.Anything).Return(nil)
+				notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -152,7 +261,7 @@ func TestEndHandler(t *testing.T) {
 		{
 			name:   "SetUserMaxError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, workoutRepo *mocks.WorkoutRepository, userRepo *mocks.UserRepository, evaluator *achievementsmocks.Evaluator, emitter *eventsmocks.Emitter, notifier *webhooksmocks.Notifier) {
 				session := &storage.WorkoutSession{
 					UserID:    "user123",
 					SessionID: "session123",
@@ -161,9 +270,9 @@ func TestEndHandler(t *testing.T) {
 					},
 				}
 				userMax := &storage.Max{ExerciseId: 1, MaxWeight: 90, Reps: 8}
-				sessionRepo.On("GetSession", userID).Return(session, nil)
-				userRepo.On("GetUserMaxes", userID).Return([]*storage.Max{userMax}, nil)
-				userRepo.On("SetUserMax", userID, mock.AnythingOfType("*storage.Max")).Return(errors.New("set user max error"))
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+				userRepo.On("GetUserMaxes", mock.Anything, userID).Return([]*storage.Max{userMax}, nil)
+				userRepo.On("SetUserMax", mock.Anything, userID, mock.AnythingOfType("*storage.Max")).Return(errors.New("set user max error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -175,9 +284,12 @@ func TestEndHandler(t *testing.T) {
 			sessionRepo := mocks.NewSessionRepository(t)
 			workoutRepo := mocks.NewWorkoutRepository(t)
 			userRepo := mocks.NewUserRepository(t)
-			tt.setupMock(sessionRepo, workoutRepo, userRepo)
+			evaluator := achievementsmocks.NewEvaluator(t)
+			emitter := eventsmocks.NewEmitter(t)
+			notifier := webhooksmocks.NewNotifier(t)
+			tt.setupMock(sessionRepo, workoutRepo, userRepo, evaluator, emitter, notifier)
 
-			handler := end.NewEndHandler(logger, sessionRepo, workoutRepo, userRepo)
+			handler := end.NewEndHandler(logger, sessionRepo, finalizer.New(sessionRepo, workoutRepo, userRepo, mocks.NewModerationRepository(t), evaluator, emitter, notifier))
 
 			req := httptest.NewRequest("POST", "/workouts/end", nil)
 			ctx := context.WithValue(req.Context(), jwt.UserKey, tt.userID)
@@ -0,0 +1,244 @@
+package export
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/stats"
+	"GYMBRO/internal/storage"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatICS  = "ics"
+)
+
+// encoder writes workouts to the response one by one, so the export never has to be buffered.
+type encoder interface {
+	contentType() string
+	begin(w io.Writer) error
+	workout(w io.Writer, workout *storage.WorkoutWithRecords) error
+	end(w io.Writer) error
+}
+
+// NewExportHandler creates an HTTP handler to export user's workout history.
+// It parses the format and the date range, streams workouts from the repository
+// and writes them as CSV, JSON or iCalendar without holding the whole history in memory. (1 workoutRepo call)
+func NewExportHandler(log *slog.Logger, workoutRepo storage.WorkoutRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.workouts.export.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format, _ = r.Context().Value(middleware.URLFormatCtxKey).(string)
+		}
+
+		var enc encoder
+		switch strings.ToLower(format) {
+		case FormatCSV:
+			enc = &csvEncoder{}
+		case FormatJSON:
+			enc = &jsonEncoder{}
+		case FormatICS:
+			enc = &icsEncoder{}
+		default:
+			log.Debug("Unsupported export format", slog.String("format", format))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Unsupported export format", "Use one of the formats: csv, json, ics")
+		}
+
+		from, to, err := stats.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			log.Debug("Invalid date range", slog.Any("error", err))
+			return resp.NewError(http.StatusBadRequest, resp.CodeBadRequest, "Invalid date range", "Use YYYY-MM-DD dates and make sure 'from' is not after 'to'")
+		}
+
+		flusher, _ := w.(http.Flusher)
+		started := false
+		start := func() error {
+			started = true
+			w.Header().Set("Content-Type", enc.contentType())
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="workouts.%s"`, strings.ToLower(format)))
+			w.WriteHeader(http.StatusOK)
+			return enc.begin(w)
+		}
+
+		exported := 0
+		err = workoutRepo.StreamWorkouts(r.Context(), &userID, from, to, func(workout *storage.WorkoutWithRecords) error {
+			if !started {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			if err := enc.workout(w, workout); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			exported++
+			return nil
+		})
+		if err != nil {
+			log.Error("Failed to EXPORT workouts", slog.Any("error", err), slog.Int("exported", exported))
+			if !started {
+				return resp.Internal(err)
+			}
+			// the status is already sent, the client sees a truncated file
+			return nil
+		}
+
+		if !started {
+			if err := start(); err != nil {
+				log.Error("Failed to WRITE export", slog.Any("error", err))
+				return nil
+			}
+		}
+		if err := enc.end(w); err != nil {
+			log.Error("Failed to WRITE export", slog.Any("error", err))
+			return nil
+		}
+
+		log.Debug("Workouts exported", slog.String("format", format), slog.Int("exported", exported))
+		return nil
+	})
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvEncoder) contentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *csvEncoder) begin(w io.Writer) error {
+	e.writer = csv.NewWriter(w)
+	return e.write([]string{"workout_id", "start_time", "end_time", "workout_points", "record_id", "exercise_id", "reps", "weight", "record_points"})
+}
+
+// workout writes one line per record. Workouts without records still get a line with empty record columns.
+func (e *csvEncoder) workout(_ io.Writer, workout *storage.WorkoutWithRecords) error {
+	head := []string{workout.WorkoutID, workout.StartTime.Format(time.RFC3339), workout.EndTime.Format(time.RFC3339), strconv.Itoa(workout.Points)}
+	if len(workout.Records) == 0 {
+		return e.write(append(head, "", "", "", "", ""))
+	}
+	for _, record := range workout.Records {
+		line := append(append([]string{}, head...),
+			record.RecordId,
+			strconv.Itoa(record.FkExerciseId),
+			strconv.Itoa(record.Reps),
+			strconv.Itoa(record.Weight),
+			strconv.Itoa(record.Points),
+		)
+		if err := e.write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvEncoder) end(_ io.Writer) error {
+	return nil
+}
+
+func (e *csvEncoder) write(line []string) error {
+	if err := e.writer.Write(line); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+type jsonEncoder struct {
+	count int
+}
+
+func (e *jsonEncoder) contentType() string {
+	return "application/json"
+}
+
+func (e *jsonEncoder) begin(w io.Writer) error {
+	_, err := io.WriteString(w, "[")
+	return err
+}
+
+func (e *jsonEncoder) workout(w io.Writer, workout *storage.WorkoutWithRecords) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	data, err := json.Marshal(dto.NewWorkoutResponse(workout))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) end(w io.Writer) error {
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+// icsEncoder writes every workout as a VEVENT (RFC 5545).
+type icsEncoder struct{}
+
+const icsTimeLayout = "20060102T150405Z"
+
+func (e *icsEncoder) contentType() string {
+	return "text/calendar; charset=utf-8"
+}
+
+func (e *icsEncoder) begin(w io.Writer) error {
+	return writeICSLines(w,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//GYMBRO//Workouts export//EN",
+		"CALSCALE:GREGORIAN",
+	)
+}
+
+func (e *icsEncoder) workout(w io.Writer, workout *storage.WorkoutWithRecords) error {
+	return writeICSLines(w,
+		"BEGIN:VEVENT",
+		"UID:"+workout.WorkoutID+"@gymbro",
+		"DTSTAMP:"+time.Now().UTC().Format(icsTimeLayout),
+		"DTSTART:"+workout.StartTime.UTC().Format(icsTimeLayout),
+		"DTEND:"+workout.EndTime.UTC().Format(icsTimeLayout),
+		"SUMMARY:"+escapeICS(fmt.Sprintf("Workout (%d points)", workout.Points)),
+		"DESCRIPTION:"+escapeICS(fmt.Sprintf("%d sets logged", len(workout.Records))),
+		"END:VEVENT",
+	)
+}
+
+func (e *icsEncoder) end(w io.Writer) error {
+	return writeICSLines(w, "END:VCALENDAR")
+}
+
+func writeICSLines(w io.Writer, lines ...string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
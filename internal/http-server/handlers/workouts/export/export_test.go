@@ -0,0 +1,192 @@
+package export_test
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/workouts/export"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	workouts := []*storage.WorkoutWithRecords{
+		{
+			WorkoutID: "workout1",
+			UserID:    "user123",
+			StartTime: start,
+			EndTime:   start.Add(time.Hour),
+			Points:    150,
+			Records: []storage.Record{
+				{RecordId: "record1", FkWorkoutId: "workout1", FkExerciseId: 1, Reps: 5, Weight: 100, Points: 100},
+				{RecordId: "record2", FkWorkoutId: "workout1", FkExerciseId: 2, Reps: 10, Weight: 50, Points: 50},
+			},
+		},
+		{
+			WorkoutID: "workout2",
+			UserID:    "user123",
+			StartTime: start.AddDate(0, 0, 2),
+			EndTime:   start.AddDate(0, 0, 2).Add(time.Hour),
+		},
+	}
+
+	streamAll := func(_ context.Context, _ *string, _ time.Time, _ time.Time, fn func(*storage.WorkoutWithRecords) error) error {
+		for _, workout := range workouts {
+			if err := fn(workout); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name                string
+		url                 string
+		setupMock           func(woRepo *mocks.WorkoutRepository)
+		expectedStatusCode  int
+		expectedContentType string
+		expectedCode        string
+		checkBody           func(t *testing.T, body string)
+	}{
+		{
+			name: "CSV",
+			url:  "/workouts/export?format=csv&from=2024-05-01&to=2024-05-31",
+			setupMock: func(woRepo *mocks.WorkoutRepository) {
+				from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+				woRepo.On("StreamWorkouts", mock.Anything, userID, from, to, mock.Anything).Return(streamAll)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			checkBody: func(t *testing.T, body string) {
+				lines := strings.Split(strings.TrimSpace(body), "\n")
+				require.Len(t, lines, 4)
+				require.True(t, strings.HasPrefix(lines[0], "workout_id,"))
+				require.Contains(t, lines[1], "record1")
+				require.Contains(t, lines[2], "record2")
+				require.True(t, strings.HasPrefix(lines[3], "workout2,"))
+			},
+		},
+		{
+			name: "JSON",
+			url:  "/workouts/export?format=json",
+			setupMock: func(woRepo *mocks.WorkoutRepository) {
+				woRepo.On("StreamWorkouts", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(streamAll)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+			checkBody: func(t *testing.T, body string) {
+				var exported []dto.WorkoutResponse
+				require.NoError(t, json.Unmarshal([]byte(body), &exported))
+				require.Len(t, exported, 2)
+				require.Len(t, exported[0].Records, 2)
+			},
+		},
+		{
+			name: "JSONEmpty",
+			url:  "/workouts/export?format=json",
+			setupMock: func(woRepo *mocks.WorkoutRepository) {
+				woRepo.On("StreamWorkouts", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+			checkBody: func(t *testing.T, body string) {
+				require.Equal(t, "[]", body)
+			},
+		},
+		{
+			name: "ICSFromExtension",
+			url:  "/workouts/export.ics",
+			setupMock: func(woRepo *mocks.WorkoutRepository) {
+				woRepo.On("StreamWorkouts", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(streamAll)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/calendar",
+			checkBody: func(t *testing.T, body string) {
+				require.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+				require.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+				require.Contains(t, body, "DTSTART:20240501T100000Z")
+				require.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+			},
+		},
+		{
+			name:               "UnsupportedFormat",
+			url:                "/workouts/export?format=xml",
+			setupMock:          func(woRepo *mocks.WorkoutRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name:               "InvalidRange",
+			url:                "/workouts/export?format=csv&from=2024-06-01&to=2024-05-01",
+			setupMock:          func(woRepo *mocks.WorkoutRepository) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       resp.CodeBadRequest,
+		},
+		{
+			name: "StreamError",
+			url:  "/workouts/export?format=csv",
+			setupMock: func(woRepo *mocks.WorkoutRepository) {
+				woRepo.On("StreamWorkouts", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			woRepo := mocks.NewWorkoutRepository(t)
+			tt.setupMock(woRepo)
+
+			r := chi.NewRouter()
+			r.Use(middleware.URLFormat)
+			r.Get("/workouts/export", export.NewExportHandler(logger, woRepo))
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			if tt.expectedCode != "" {
+				var response resp.DetailedResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				require.Equal(t, resp.StatusError, response.Status)
+				require.Equal(t, tt.expectedCode, response.Code)
+			}
+			if tt.expectedContentType != "" {
+				require.True(t, strings.HasPrefix(rr.Header().Get("Content-Type"), tt.expectedContentType))
+			}
+			if tt.checkBody != nil {
+				tt.checkBody(t, rr.Body.String())
+			}
+
+			woRepo.AssertExpectations(t)
+		})
+	}
+}
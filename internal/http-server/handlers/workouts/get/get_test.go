@@ -7,6 +7,7 @@ import (
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"io"
 	"log/slog"
@@ -39,7 +40,7 @@ func TestGetWorkoutHandler(t *testing.T) {
 			userID:    "user123",
 			workoutID: "workout123",
 			setupMock: func(woRepo *mocks.WorkoutRepository) {
-				woRepo.On("GetWorkout", workoutID).Return(&storage.WorkoutWithRecords{WorkoutID: "workout123", UserID: "user123"}, nil)
+				woRepo.On("GetWorkout", mock.Anything, workoutID).Return(&storage.WorkoutWithRecords{WorkoutID: "workout123", UserID: "user123"}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK, Code: resp.StatusOK},
@@ -49,7 +50,7 @@ func TestGetWorkoutHandler(t *testing.T) {
 			userID:    "user123",
 			workoutID: "workout123",
 			setupMock: func(woRepo *mocks.WorkoutRepository) {
-				woRepo.On("GetWorkout", workoutID).Return(nil, storage.ErrWorkoutNotFound)
+				woRepo.On("GetWorkout", mock.Anything, workoutID).Return(nil, storage.ErrWorkoutNotFound)
 			},
 			expectedStatusCode: http.StatusNotFound,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeNotFound},
@@ -59,7 +60,7 @@ func TestGetWorkoutHandler(t *testing.T) {
 			userID:    "user123",
 			workoutID: "workout123",
 			setupMock: func(woRepo *mocks.WorkoutRepository) {
-				woRepo.On("GetWorkout", workoutID).Return(nil, errors.New("db error"))
+				woRepo.On("GetWorkout", mock.Anything, workoutID).Return(nil, errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -69,7 +70,7 @@ func TestGetWorkoutHandler(t *testing.T) {
 			userID:    "user456",
 			workoutID: "workout123",
 			setupMock: func(woRepo *mocks.WorkoutRepository) {
-				woRepo.On("GetWorkout", workoutID).Return(&storage.WorkoutWithRecords{WorkoutID: "workout123", UserID: "user123"}, nil)
+				woRepo.On("GetWorkout", mock.Anything, workoutID).Return(&storage.WorkoutWithRecords{WorkoutID: "workout123", UserID: "user123"}, nil)
 			},
 			expectedStatusCode: http.StatusForbidden,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeForbidden},
@@ -12,28 +12,28 @@ import (
 	"time"
 )
 
+// CheckInWindow is how long after a gym check-in a started workout is counted as done in that gym.
+const CheckInWindow = 3 * time.Hour
+
 // NewStartHandler creates an HTTP handler to start a new workout session.
-// It checks for existing active sessions, creates a new session, and updates user status. (2 sessionRepo calls, 1 userRepo call)
-func NewStartHandler(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+// It checks for existing active sessions, creates a new session in the gym of the last check-in within CheckInWindow,
+// and updates user status. (2 sessionRepo calls, 1 userRepo call, 1 gymRepo call)
+func NewStartHandler(log *slog.Logger, sessionRepo storage.SessionRepository, userRepo storage.UserRepository, gymRepo storage.GymRepository) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
 		const op = "handlers.workouts.start.New"
 		userID := jwt.GetUserIDFromContext(r.Context())
 		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
 
-		activeSession, err := sessionRepo.GetSession(&userID)
+		activeSession, err := sessionRepo.GetSession(r.Context(), &userID)
 		if activeSession != nil {
 			log.Debug("User already has active workout", slog.String("user_id", userID))
-			render.Status(r, http.StatusConflict)
-			render.JSON(w, r, resp.Error("Already has active workout", resp.CodeActiveWorkout, "End current workout to start new one"))
-			return
+			return resp.NewError(http.StatusConflict, resp.CodeActiveWorkout, "Already has active workout", "End current workout to start new one")
 		}
 
 		if err != nil {
 			if !errors.Is(err, storage.ErrNoSession) {
 				log.Error("Cant get session", slog.String("user_id", userID), slog.Any("error", err))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-				return
+				return resp.Internal(err)
 			}
 		}
 
@@ -46,22 +46,28 @@ func NewStartHandler(log *slog.Logger, sessionRepo storage.SessionRepository, us
 			Points:      0,
 		}
 
-		if err := sessionRepo.CreateSession(session); err != nil {
+		// the workout still starts without a gym if the check-in can not be read
+		checkIn, err := gymRepo.GetLastCheckIn(r.Context(), &userID, session.StartTime.Add(-CheckInWindow))
+		switch {
+		case err == nil:
+			session.GymID = checkIn.FkGymId
+		case !errors.Is(err, storage.ErrCheckInNotFound):
+			log.Error("Failed to GET last check-in", slog.Any("error", err))
+		}
+
+		if err := sessionRepo.CreateSession(r.Context(), session); err != nil {
 			log.Error("Failed to CREATE session", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
-		if err := userRepo.ChangeStatus(&userID, true); err != nil {
+		if err := userRepo.ChangeStatus(r.Context(), &userID, true); err != nil {
 			log.Error("Failed to CHANGE user status", slog.Any("error", err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
-			return
+			return resp.Internal(err)
 		}
 
-		log.Debug("Workout started", slog.String("session_id", session.SessionID))
+		log.Debug("Workout started", slog.String("session_id", session.SessionID), slog.Int("gym_id", session.GymID))
 		render.Status(r, http.StatusOK)
 		render.JSON(w, r, resp.OK())
-	}
+		return nil
+	})
 }
@@ -28,17 +28,48 @@ func TestStartHandler(t *testing.T) {
 	tests := []struct {
 		name               string
 		userID             string
-		setupMock          func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository)
+		setupMock          func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository)
 		expectedStatusCode int
 		expectedResponse   resp.DetailedResponse
 	}{
 		{
 			name:   "Success",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
-				sessionRepo.On("CreateSession", mock.AnythingOfType("*storage.WorkoutSession")).Return(nil)
-				userRepo.On("ChangeStatus", userID, true).Return(nil)
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+				gymRepo.On("GetLastCheckIn", mock.Anything, userID, mock.Anything).Return(nil, storage.ErrCheckInNotFound)
+				sessionRepo.On("CreateSession", mock.Anything, mock.AnythingOfType("*storage.WorkoutSession")).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "CheckedInGym",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+				gymRepo.On("GetLastCheckIn", mock.Anything, userID, mock.MatchedBy(func(since time.Time) bool {
+					return time.Since(since) >= start.CheckInWindow
+				})).Return(&storage.CheckIn{CheckInID: "check-in123", FkUserId: "user123", FkGymId: 7}, nil)
+				sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *storage.WorkoutSession) bool {
+					return s.GymID == 7
+				})).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, true).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:   "CheckInError",
+			userID: "user123",
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+				gymRepo.On("GetLastCheckIn", mock.Anything, userID, mock.Anything).Return(nil, errors.New("db error"))
+				sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *storage.WorkoutSession) bool {
+					return s.GymID == storage.DefaultGymID
+				})).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, true).Return(nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -46,8 +77,8 @@ func TestStartHandler(t *testing.T) {
 		{
 			name:   "GetSessionError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, errors.New("db error"))
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -55,9 +86,10 @@ func TestStartHandler(t *testing.T) {
 		{
 			name:   "CreateSessionError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
-				sessionRepo.On("CreateSession", mock.AnythingOfType("*storage.WorkoutSession")).Return(errors.New("db error"))
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+				gymRepo.On("GetLastCheckIn", mock.Anything, userID, mock.Anything).Return(nil, storage.ErrCheckInNotFound)
+				sessionRepo.On("CreateSession", mock.Anything, mock.AnythingOfType("*storage.WorkoutSession")).Return(errors.New("db error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -65,14 +97,14 @@ func TestStartHandler(t *testing.T) {
 		{
 			name:   "UserHasActiveSession",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
 				activeSession := &storage.WorkoutSession{
 					SessionID:   "session123",
 					UserID:      "user123",
 					StartTime:   time.Now(),
 					LastUpdated: time.Now(),
 				}
-				sessionRepo.On("GetSession", userID).Return(activeSession, nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(activeSession, nil)
 			},
 			expectedStatusCode: http.StatusConflict,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeActiveWorkout},
@@ -80,10 +112,11 @@ func TestStartHandler(t *testing.T) {
 		{
 			name:   "UserStatusError",
 			userID: "user123",
-			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository) {
-				sessionRepo.On("GetSession", userID).Return(nil, storage.ErrNoSession)
-				sessionRepo.On("CreateSession", mock.AnythingOfType("*storage.WorkoutSession")).Return(nil)
-				userRepo.On("ChangeStatus", userID, true).Return(errors.New("user status error"))
+			setupMock: func(sessionRepo *mocks.SessionRepository, userRepo *mocks.UserRepository, gymRepo *mocks.GymRepository) {
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+				gymRepo.On("GetLastCheckIn", mock.Anything, userID, mock.Anything).Return(nil, storage.ErrCheckInNotFound)
+				sessionRepo.On("CreateSession", mock.Anything, mock.AnythingOfType("*storage.WorkoutSession")).Return(nil)
+				userRepo.On("ChangeStatus", mock.Anything, userID, true).Return(errors.New("user status error"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
@@ -94,9 +127,10 @@ func TestStartHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionRepo := mocks.NewSessionRepository(t)
 			userRepo := mocks.NewUserRepository(t)
-			tt.setupMock(sessionRepo, userRepo)
+			gymRepo := mocks.NewGymRepository(t)
+			tt.setupMock(sessionRepo, userRepo, gymRepo)
 
-			handler := start.NewStartHandler(logger, sessionRepo, userRepo)
+			handler := start.NewStartHandler(logger, sessionRepo, userRepo, gymRepo)
 
 			req := httptest.NewRequest("POST", "/workouts/start", nil)
 			ctx := context.WithValue(req.Context(), jwt.UserKey, tt.userID)
@@ -118,6 +152,7 @@ func TestStartHandler(t *testing.T) {
 			}
 
 			sessionRepo.AssertExpectations(t)
+			gymRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"GYMBRO/internal/http-server/dto"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// EventSnapshot is the first event of a stream, it carries the session at the time of connecting (null if none).
+	EventSnapshot = "snapshot"
+	// HeartbeatInterval keeps proxies from closing idle streams.
+	HeartbeatInterval = 15 * time.Second
+	// retry tells EventSource how long to wait before reconnecting, in milliseconds.
+	retry = 3000
+)
+
+// NewStreamHandler creates an HTTP handler that streams the active workout session of the authenticated user
+// as Server-Sent Events: a snapshot first, then an event on every change made by any device on any replica.
+// The stream ends when the client disconnects or the server shuts down (done is closed). (1 subscriber call, 1 sessionRepo call)
+func NewStreamHandler(log *slog.Logger, sessionRepo storage.SessionRepository, subscriber storage.SessionSubscriber, done <-chan struct{}) http.HandlerFunc {
+	return resp.Handle(log, func(w http.ResponseWriter, r *http.Request) error {
+		const op = "handlers.workouts.stream.New"
+		userID := jwt.GetUserIDFromContext(r.Context())
+		log = log.With(slog.String("op", op), slog.Any("request_id", middleware.GetReqID(r.Context())), slog.String("user_id", userID))
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// subscribe before reading the snapshot, so a change made in between is not lost
+		events, err := subscriber.SubscribeSessions(ctx, &userID)
+		if err != nil {
+			log.Error("Failed to SUBSCRIBE to session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		session, err := sessionRepo.GetSession(ctx, &userID)
+		if err != nil && !errors.Is(err, storage.ErrNoSession) {
+			log.Error("Failed to GET session", slog.Any("error", err))
+			return resp.Internal(err)
+		}
+
+		rc := http.NewResponseController(w)
+		// the server write timeout is meant for regular requests, a stream is written to for as long as it is open
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			log.Warn("Failed to reset write deadline", slog.Any("error", err))
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		// nginx buffers responses by default
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		metrics.SessionStreams.Inc()
+		defer metrics.SessionStreams.Dec()
+		log.Debug("Session stream opened")
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", retry); err != nil {
+			return nil
+		}
+		if err := writeEvent(w, rc, EventSnapshot, session); err != nil {
+			log.Debug("Failed to write snapshot", slog.Any("error", err))
+			return nil
+		}
+
+		heartbeat := time.NewTicker(HeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("Session stream closed by client")
+				return nil
+			case <-done:
+				log.Debug("Session stream closed by shutdown")
+				return nil
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return nil
+				}
+				if err := rc.Flush(); err != nil {
+					return nil
+				}
+			case event, ok := <-events:
+				if !ok {
+					// the client reconnects and gets a fresh snapshot
+					log.Warn("Session subscription lost")
+					return nil
+				}
+				if err := writeEvent(w, rc, event.Type, event.Session); err != nil {
+					log.Debug("Failed to write event", slog.Any("error", err))
+					return nil
+				}
+			}
+		}
+	})
+}
+
+// writeEvent writes the session (null if nil) as an event of the type and flushes it to the client.
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, eventType string, session *storage.WorkoutSession) error {
+	data := []byte("null")
+	if session != nil {
+		var err error
+		if data, err = json.Marshal(dto.NewSessionResponse(session)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
@@ -0,0 +1,172 @@
+package stream_test
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/http-server/handlers/workouts/stream"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	userIDValue := "user123"
+	userID := &userIDValue
+	session := &storage.WorkoutSession{UserID: "user123", SessionID: "session123", Records: []storage.Record{}}
+	updated := &storage.WorkoutSession{UserID: "user123", SessionID: "session123", Points: 10, Records: []storage.Record{{RecordId: "record1", FkExerciseId: 1, Reps: 5, Weight: 100, Points: 10}}}
+
+	// closed returns a channel with the events that is already closed, as if the subscription was lost after them
+	closed := func(events ...*storage.SessionEvent) <-chan *storage.SessionEvent {
+		ch := make(chan *storage.SessionEvent, len(events))
+		for _, event := range events {
+			ch <- event
+		}
+		close(ch)
+		return ch
+	}
+
+	tests := []struct {
+		name               string
+		shutdown           bool
+		setupMock          func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber)
+		expectedStatusCode int
+		expectedCode       string
+		expectedEvents     []string
+		expectedData       []string
+	}{
+		{
+			name: "Changes",
+			setupMock: func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber) {
+				subscriber.On("SubscribeSessions", mock.Anything, userID).Return(closed(
+					&storage.SessionEvent{Type: storage.SessionUpdated, UserID: "user123", Session: updated},
+					&storage.SessionEvent{Type: storage.SessionDeleted, UserID: "user123"},
+				), nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedEvents:     []string{stream.EventSnapshot, storage.SessionUpdated, storage.SessionDeleted},
+			expectedData:       []string{"session123", "record1", "null"},
+		},
+		{
+			name: "NoSession",
+			setupMock: func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber) {
+				subscriber.On("SubscribeSessions", mock.Anything, userID).Return(closed(
+					&storage.SessionEvent{Type: storage.SessionCreated, UserID: "user123", Session: session},
+				), nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, storage.ErrNoSession)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedEvents:     []string{stream.EventSnapshot, storage.SessionCreated},
+			expectedData:       []string{"null", "session123"},
+		},
+		{
+			name:     "Shutdown",
+			shutdown: true,
+			setupMock: func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber) {
+				subscriber.On("SubscribeSessions", mock.Anything, userID).Return(make(<-chan *storage.SessionEvent), nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(session, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedEvents:     []string{stream.EventSnapshot},
+			expectedData:       []string{"session123"},
+		},
+		{
+			name: "SubscribeError",
+			setupMock: func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber) {
+				subscriber.On("SubscribeSessions", mock.Anything, userID).Return(nil, errors.New("redis error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+		{
+			name: "GetSessionError",
+			setupMock: func(sessionRepo *mocks.SessionRepository, subscriber *mocks.SessionSubscriber) {
+				subscriber.On("SubscribeSessions", mock.Anything, userID).Return(closed(), nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(nil, errors.New("redis error"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       resp.CodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewSessionRepository(t)
+			subscriber := mocks.NewSessionSubscriber(t)
+			tt.setupMock(sessionRepo, subscriber)
+
+			done := make(chan struct{})
+			if tt.shutdown {
+				close(done)
+			}
+			handler := stream.NewStreamHandler(logger, sessionRepo, subscriber, done)
+
+			req := httptest.NewRequest("GET", "/workouts/active/stream", nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, userIDValue)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			if tt.expectedCode != "" {
+				var response resp.DetailedResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				require.Equal(t, tt.expectedCode, response.Code)
+				return
+			}
+
+			require.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+			require.True(t, rr.Flushed)
+
+			var names, data []string
+			for _, block := range strings.Split(rr.Body.String(), "\n\n") {
+				for _, line := range strings.Split(block, "\n") {
+					if name, ok := strings.CutPrefix(line, "event: "); ok {
+						names = append(names, name)
+					}
+					if payload, ok := strings.CutPrefix(line, "data: "); ok {
+						data = append(data, payload)
+					}
+				}
+			}
+			require.Equal(t, tt.expectedEvents, names)
+			require.Len(t, data, len(tt.expectedData))
+			for i, expected := range tt.expectedData {
+				require.Contains(t, data[i], expected)
+			}
+		})
+	}
+}
+
+func TestStreamHandlerClientGone(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	sessionRepo := mocks.NewSessionRepository(t)
+	subscriber := mocks.NewSessionSubscriber(t)
+	subscriber.On("SubscribeSessions", mock.Anything, mock.Anything).Return(make(<-chan *storage.SessionEvent), nil)
+	sessionRepo.On("GetSession", mock.Anything, mock.Anything).Return(nil, storage.ErrNoSession)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), jwt.UserKey, "user123"))
+	cancel()
+	req := httptest.NewRequest("GET", "/workouts/active/stream", nil).WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	stream.NewStreamHandler(logger, sessionRepo, subscriber, make(chan struct{})).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), "event: snapshot")
+}
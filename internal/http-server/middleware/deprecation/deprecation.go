@@ -0,0 +1,18 @@
+package mwdeprecation
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// New marks responses of deprecated routes with the Deprecation header and points to the route
+// that replaces them (the same path under successorPrefix) with a successor-version link.
+func New(successorPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, r.URL.Path))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package mwdeprecation_test
+
+import (
+	mwdeprecation "GYMBRO/internal/http-server/middleware/deprecation"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	api := chi.NewRouter()
+	api.Get("/workouts/{workoutID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := chi.NewRouter()
+	r.Mount("/api/v1", api)
+	r.With(mwdeprecation.New("/api/v1")).Mount("/", api)
+
+	tests := []struct {
+		name        string
+		url         string
+		deprecation string
+		link        string
+	}{
+		{
+			name:        "DeprecatedAlias",
+			url:         "/workouts/workout123",
+			deprecation: "true",
+			link:        `</api/v1/workouts/workout123>; rel="successor-version"`,
+		},
+		{
+			name: "VersionedRoute",
+			url:  "/api/v1/workouts/workout123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, tt.deprecation, rr.Header().Get("Deprecation"))
+			require.Equal(t, tt.link, rr.Header().Get("Link"))
+		})
+	}
+}
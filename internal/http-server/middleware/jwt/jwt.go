@@ -6,15 +6,16 @@ import (
 	jwtlib "GYMBRO/internal/lib/jwt"
 	"GYMBRO/internal/storage"
 	"context"
+	"errors"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/render"
 	"github.com/golang-jwt/jwt/v5"
 	"log/slog"
 	"net/http"
 )
 
 // WithJWTAuth adds user authentication to requests by validating JWT tokens.
-// It verifies the token, retrieves the user, and injects the user ID into the request context.
+// It verifies the token, retrieves the user, rejects disabled accounts, and injects the user ID and role into the request context.
+// The role is taken from the storage rather than from the claims, so role changes apply immediately.
 func WithJWTAuth(log *slog.Logger, userRepo storage.UserRepository, cfg *config.Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -25,43 +26,60 @@ func WithJWTAuth(log *slog.Logger, userRepo storage.UserRepository, cfg *config.
 			tokenString := jwtlib.GetTokenFromRequest(r)
 			if tokenString == "" {
 				log.Debug("User is not authenticated")
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, resp.Error("You are not authenticated", resp.CodeUnauthorized, "You need to login first"))
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "You are not authenticated", "You need to login first"))
 				return
 			}
 
 			token, err := jwtlib.ValidateJWT(tokenString, cfg.SecretKey)
 			if err != nil {
 				log.Warn("Failed to validate JWT", slog.Any("error", err))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please logout and login again, or try again later"))
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "Invalid token", "Please logout and login again"))
 				return
 			}
 			if !token.Valid {
 				log.Warn("Got invalid token", slog.Any("token", token))
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, resp.Error("Invalid token", resp.CodeInternalError, "Please logout and login again, or try again later"))
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "Invalid token", "Please logout and login again"))
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
 				log.Warn("Failed to parse JWT claims")
-				render.Status(r, http.StatusUnauthorized)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please logout and login again, or try again later"))
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "Invalid token", "Please logout and login again"))
 				return
 			}
 
-			userID := claims["uid"].(string)
-			user, err := userRepo.GetUserByID(&userID)
+			userID, ok := claims["uid"].(string)
+			if !ok {
+				log.Warn("JWT has no user ID")
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "Invalid token", "Please logout and login again"))
+				return
+			}
+			user, err := userRepo.GetUserByID(r.Context(), &userID)
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Warn("JWT belongs to a deleted user", slog.String("user_id", userID))
+				resp.WriteError(w, r, resp.NewError(http.StatusUnauthorized, resp.CodeUnauthorized, "Invalid token", "Please logout and login again"))
+				return
+			}
 			if err != nil {
 				log.Error("Failed to GET user", slog.Any("error", err), slog.String("user_id", userID))
-				render.Status(r, http.StatusInternalServerError)
-				render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
+				resp.WriteError(w, r, resp.Internal(err))
 				return
 			}
 
+			if user.IsDisabled {
+				log.Debug("User is disabled", slog.String("user_id", userID))
+				resp.WriteError(w, r, resp.AccountDisabled())
+				return
+			}
+
+			role := user.Role
+			if role == "" {
+				role = storage.RoleUser
+			}
+
 			ctx := context.WithValue(r.Context(), jwtlib.UserKey, user.UserId)
+			ctx = context.WithValue(ctx, jwtlib.RoleKey, role)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"io"
 	"log/slog"
@@ -43,7 +44,7 @@ func TestWithJWTAuth(t *testing.T) {
 				return x
 			}(),
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByID", userID).Return(&storage.User{UserId: "user123"}, nil)
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123"}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
@@ -65,8 +66,36 @@ func TestWithJWTAuth(t *testing.T) {
 				return tokenString
 			}(),
 			setupMock:          func(userRepo *mocks.UserRepository) {},
-			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeUnauthorized},
+		},
+		{
+			name: "TokenWithoutUserID",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"typ": "merge",
+				})
+				tokenString, _ := token.SignedString([]byte(cfg.SecretKey))
+				return tokenString
+			}(),
+			setupMock:          func(userRepo *mocks.UserRepository) {},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeUnauthorized},
+		},
+		{
+			name: "DisabledUser",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"uid": "user123",
+				})
+				tokenString, _ := token.SignedString([]byte(cfg.SecretKey))
+				return tokenString
+			}(),
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(&storage.User{UserId: "user123", IsDisabled: true}, nil)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeAccountDisabled},
 		},
 		{
 			name: "UserNotFound",
@@ -78,11 +107,26 @@ func TestWithJWTAuth(t *testing.T) {
 				return tokenString
 			}(),
 			setupMock: func(userRepo *mocks.UserRepository) {
-				userRepo.On("GetUserByID", userID).Return(nil, errors.New("user not found"))
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(nil, errors.New("user not found"))
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeInternalError},
 		},
+		{
+			name: "DeletedUser",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"uid": "user123",
+				})
+				tokenString, _ := token.SignedString([]byte(cfg.SecretKey))
+				return tokenString
+			}(),
+			setupMock: func(userRepo *mocks.UserRepository) {
+				userRepo.On("GetUserByID", mock.Anything, userID).Return(nil, storage.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeUnauthorized},
+		},
 	}
 
 	for _, tt := range tests {
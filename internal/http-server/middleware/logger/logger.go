@@ -1,6 +1,7 @@
 package mwlogger
 
 import (
+	"GYMBRO/internal/lib/tracing"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
 	"net/http"
@@ -19,6 +20,7 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
+				slog.String("trace_id", tracing.TraceID(r.Context())),
 			)
 
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
@@ -0,0 +1,38 @@
+package mwmetrics
+
+import (
+	"GYMBRO/internal/lib/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// New counts requests and measures their latency by route pattern (e.g. /workouts/{workoutID}) and status.
+// The pattern is known only after routing, so it is read when the request is served. Requests that did
+// not match any route are labeled "unmatched" to keep the label set small.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+
+			defer func() {
+				route := "unmatched"
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				labels := []string{r.Method, route, strconv.Itoa(status)}
+				metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+				metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package mwmetrics_test
+
+import (
+	mwmetrics "GYMBRO/internal/http-server/middleware/metrics"
+	"GYMBRO/internal/lib/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(mwmetrics.New())
+	r.Route("/workouts", func(r chi.Router) {
+		r.Get("/{workoutID}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+	})
+
+	tests := []struct {
+		name   string
+		url    string
+		labels []string
+	}{
+		{
+			name:   "RoutePattern",
+			url:    "/workouts/workout123",
+			labels: []string{"GET", "/workouts/{workoutID}", "404"},
+		},
+		{
+			name:   "Unmatched",
+			url:    "/nothing/here",
+			labels: []string{"GET", "unmatched", "404"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(tt.labels...))
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, before+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(tt.labels...)))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package mwratelimit
+
+import (
+	"GYMBRO/internal/config"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+const (
+	KeyByIP   = "ip"
+	KeyByUser = "user"
+)
+
+// WithRateLimit limits requests of the route group to rule.Limit per rule.Window, counting them per IP or per user.
+// The IP is taken from RemoteAddr, so middleware.RealIP has to be used before it.
+// If the limiter storage is unavailable requests are let through, the limiter should not take the whole API down.
+func WithRateLimit(log *slog.Logger, rateLimitRepo storage.RateLimitRepository, group string, rule config.RateLimitRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.mwratelimit.WithRateLimit"
+			reqID := middleware.GetReqID(r.Context())
+			log := log.With(slog.String("op", op), slog.Any("request_id", reqID), slog.String("group", group))
+
+			key := group + ":" + KeyByIP + ":" + clientIP(r)
+			if rule.KeyBy == KeyByUser {
+				if userID := jwt.GetUserIDFromContext(r.Context()); userID != "" {
+					key = group + ":" + KeyByUser + ":" + userID
+				}
+			}
+
+			allowed, retryAfter, err := rateLimitRepo.Allow(r.Context(), key, rule.Limit, rule.Window)
+			if err != nil {
+				log.Error("Failed to CHECK rate limit", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				log.Warn("Rate limit exceeded", slog.String("key", key))
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				resp.WriteError(w, r, resp.NewError(http.StatusTooManyRequests, resp.CodeTooManyRequests, "Too many requests", "Wait a bit before trying again"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address set by middleware.RealIP, stripping the port if RealIP had nothing to set.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,119 @@
+package mwratelimit_test
+
+import (
+	"GYMBRO/internal/config"
+	resp "GYMBRO/internal/http-server/handlers/response"
+	mwratelimit "GYMBRO/internal/http-server/middleware/ratelimit"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	ipRule := config.RateLimitRule{Limit: 10, Window: time.Minute, KeyBy: mwratelimit.KeyByIP}
+	userRule := config.RateLimitRule{Limit: 100, Window: time.Minute, KeyBy: mwratelimit.KeyByUser}
+
+	tests := []struct {
+		name               string
+		rule               config.RateLimitRule
+		userID             string
+		setupMock          func(rateLimitRepo *mocks.RateLimitRepository)
+		expectedStatusCode int
+		expectedRetryAfter string
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name: "AllowedByIP",
+			rule: ipRule,
+			setupMock: func(rateLimitRepo *mocks.RateLimitRepository) {
+				rateLimitRepo.On("Allow", mock.Anything, "auth:ip:10.0.0.1", 10, time.Minute).Return(true, time.Duration(0), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "LimitedByIP",
+			rule: ipRule,
+			setupMock: func(rateLimitRepo *mocks.RateLimitRepository) {
+				rateLimitRepo.On("Allow", mock.Anything, "auth:ip:10.0.0.1", 10, time.Minute).Return(false, 1500*time.Millisecond, nil)
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedRetryAfter: "2",
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeTooManyRequests},
+		},
+		{
+			name:   "KeyedByUser",
+			rule:   userRule,
+			userID: "user123",
+			setupMock: func(rateLimitRepo *mocks.RateLimitRepository) {
+				rateLimitRepo.On("Allow", mock.Anything, "auth:user:user123", 100, time.Minute).Return(true, time.Duration(0), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "UserRuleWithoutUser",
+			rule: userRule,
+			setupMock: func(rateLimitRepo *mocks.RateLimitRepository) {
+				rateLimitRepo.On("Allow", mock.Anything, "auth:ip:10.0.0.1", 100, time.Minute).Return(true, time.Duration(0), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name: "StorageErrorFailsOpen",
+			rule: ipRule,
+			setupMock: func(rateLimitRepo *mocks.RateLimitRepository) {
+				rateLimitRepo.On("Allow", mock.Anything, "auth:ip:10.0.0.1", 10, time.Minute).Return(false, time.Duration(0), errors.New("redis down"))
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rateLimitRepo := mocks.NewRateLimitRepository(t)
+			tt.setupMock(rateLimitRepo)
+
+			handler := mwratelimit.WithRateLimit(logger, rateLimitRepo, "auth", tt.rule)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				render.Status(r, http.StatusOK)
+				render.JSON(w, r, resp.OK())
+			}))
+
+			req := httptest.NewRequest("POST", "/users/login", nil)
+			req.RemoteAddr = "10.0.0.1:54321"
+			if tt.userID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), jwt.UserKey, tt.userID))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+			require.Equal(t, tt.expectedRetryAfter, rr.Header().Get("Retry-After"))
+
+			var response resp.DetailedResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+
+			rateLimitRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package mwrole
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	"GYMBRO/internal/lib/jwt"
+	"github.com/go-chi/chi/v5/middleware"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// RequireRole allows the request only if the authenticated user has one of the given roles.
+// It has to be used after mwjwt.WithJWTAuth, which puts the role into the request context.
+func RequireRole(log *slog.Logger, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.mwrole.RequireRole"
+			reqID := middleware.GetReqID(r.Context())
+			userID := jwt.GetUserIDFromContext(r.Context())
+			role := jwt.GetRoleFromContext(r.Context())
+			log := log.With(slog.String("op", op), slog.Any("request_id", reqID), slog.String("user_id", userID), slog.String("role", role))
+
+			if !slices.Contains(roles, role) {
+				log.Warn("Access denied", slog.Any("required_roles", roles))
+				resp.WriteError(w, r, resp.NewError(http.StatusForbidden, resp.CodeForbidden, "Forbidden", "You do not have permission to access this resource"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
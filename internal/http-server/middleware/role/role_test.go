@@ -0,0 +1,86 @@
+package mwrole_test
+
+import (
+	resp "GYMBRO/internal/http-server/handlers/response"
+	mwrole "GYMBRO/internal/http-server/middleware/role"
+	"GYMBRO/internal/lib/jwt"
+	"GYMBRO/internal/storage"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireRole(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+
+	tests := []struct {
+		name               string
+		role               string
+		allowed            []string
+		expectedStatusCode int
+		expectedResponse   resp.DetailedResponse
+	}{
+		{
+			name:               "Allowed",
+			role:               storage.RoleAdmin,
+			allowed:            []string{storage.RoleAdmin},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "OneOfRoles",
+			role:               storage.RoleModerator,
+			allowed:            []string{storage.RoleModerator, storage.RoleAdmin},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusOK},
+		},
+		{
+			name:               "Forbidden",
+			role:               storage.RoleUser,
+			allowed:            []string{storage.RoleModerator, storage.RoleAdmin},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeForbidden},
+		},
+		{
+			name:               "NoRole",
+			role:               "",
+			allowed:            []string{storage.RoleAdmin},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   resp.DetailedResponse{Status: resp.StatusError, Code: resp.CodeForbidden},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := mwrole.RequireRole(logger, tt.allowed...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				render.Status(r, http.StatusOK)
+				render.JSON(w, r, resp.OK())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			ctx := context.WithValue(req.Context(), jwt.UserKey, "user123")
+			if tt.role != "" {
+				ctx = context.WithValue(ctx, jwt.RoleKey, tt.role)
+			}
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedStatusCode, rr.Code)
+
+			var response resp.DetailedResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedResponse.Status, response.Status)
+			if tt.expectedResponse.Code != "" {
+				require.Equal(t, tt.expectedResponse.Code, response.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package mwtracing
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// New starts a server span for every request, continuing the trace from the W3C traceparent header if there is one.
+// The span is renamed to the route pattern (e.g. "GET /workouts/{workoutID}") once the request is routed,
+// and gets the request_id from middleware.RequestID, so it has to be used after it.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span := trace.SpanFromContext(r.Context())
+			span.SetAttributes(attribute.String("request_id", middleware.GetReqID(r.Context())))
+
+			next.ServeHTTP(w, r)
+
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				span.SetName(r.Method + " " + rctx.RoutePattern())
+				span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+			}
+		})
+		return otelhttp.NewHandler(inner, "HTTP request")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"GYMBRO/internal/storage"
 	"errors"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/render"
 	"log/slog"
 	"net/http"
 )
@@ -20,21 +19,19 @@ func WithActiveSessionCheck(log *slog.Logger, sessionRepo storage.SessionReposit
 			userID := jwt.GetUserIDFromContext(r.Context())
 			log = log.With(slog.String("op", op), slog.Any("request_id", reqID), slog.String("user_id", userID))
 
-			session, err := sessionRepo.GetSession(&userID)
+			session, err := sessionRepo.GetSession(r.Context(), &userID)
 
 			if err != nil {
 				if !errors.Is(err, storage.ErrNoSession) {
 					log.Error("Cant GET session", slog.Any("error", err))
-					render.Status(r, http.StatusInternalServerError)
-					render.JSON(w, r, resp.Error("Internal error", resp.CodeInternalError, "Please try again later"))
+					resp.WriteError(w, r, resp.NewError(http.StatusInternalServerError, resp.CodeInternalError, "Internal error", "Please try again later"))
 					return
 				}
 			}
 
 			if session == nil {
 				log.Debug("No active session")
-				render.Status(r, http.StatusForbidden)
-				render.JSON(w, r, resp.Error("No active workout at this time", resp.CodeNoActiveWorkout, "You need to start workout first"))
+				resp.WriteError(w, r, resp.NewError(http.StatusForbidden, resp.CodeNoActiveWorkout, "No active workout at this time", "You need to start workout first"))
 				return
 			}
 
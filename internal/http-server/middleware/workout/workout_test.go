@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/go-chi/render"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"io"
 	"log/slog"
@@ -40,7 +41,7 @@ func TestWithActiveSessionCheck(t *testing.T) {
 					SessionID: "session123",
 					UserID:    "user123",
 				}
-				sessionRepo.On("GetSession", userID).Return(activeSession, nil)
+				sessionRepo.On("GetSession", mock.Anything, userID).Return(activeSession, nil)
 			},
 			userID:             "user123",
 			expectedStatusCode: http.StatusOK,
@@ -49,7 +50,7 @@ func TestWithActiveSessionCheck(t *testing.T) {
 		{
 			name: "NoActiveSession",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", extraUserID).Return(nil, storage.ErrNoSession)
+				sessionRepo.On("GetSession", mock.Anything, extraUserID).Return(nil, storage.ErrNoSession)
 			},
 			userID:             "user456",
 			expectedStatusCode: http.StatusForbidden,
@@ -58,7 +59,7 @@ func TestWithActiveSessionCheck(t *testing.T) {
 		{
 			name: "SessionRepoError",
 			setupMock: func(sessionRepo *mocks.SessionRepository) {
-				sessionRepo.On("GetSession", extraUserID).Return(nil, errors.New("db error"))
+				sessionRepo.On("GetSession", mock.Anything, extraUserID).Return(nil, errors.New("db error"))
 			},
 			userID:             "user456",
 			expectedStatusCode: http.StatusInternalServerError,
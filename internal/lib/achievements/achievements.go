@@ -0,0 +1,183 @@
+package achievements
+
+import (
+	"GYMBRO/internal/lib/stats"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Evaluator --output=./mocks
+type Evaluator interface {
+	Evaluate(ctx context.Context, userID string) ([]*storage.Achievement, error)
+}
+
+// Engine evaluates Rules against the user's history and stores the ones that are met.
+type Engine struct {
+	rules           []Rule
+	userRepo        storage.UserRepository
+	workoutRepo     storage.WorkoutRepository
+	exerciseRepo    storage.ExerciseRepository
+	achievementRepo storage.AchievementRepository
+}
+
+func New(rules []Rule, userRepo storage.UserRepository, workoutRepo storage.WorkoutRepository, exerciseRepo storage.ExerciseRepository, achievementRepo storage.AchievementRepository) *Engine {
+	return &Engine{
+		rules:           rules,
+		userRepo:        userRepo,
+		workoutRepo:     workoutRepo,
+		exerciseRepo:    exerciseRepo,
+		achievementRepo: achievementRepo,
+	}
+}
+
+// progress is what the user has done so far, computed once per evaluation.
+type progress struct {
+	workouts int
+	streak   int
+	tonnage  int
+}
+
+// Evaluate awards every rule the user meets and did not have yet, it is called when a workout is finalized.
+// It returns the new achievements. The history is only read if some rule is still missing.
+func (e *Engine) Evaluate(ctx context.Context, userID string) ([]*storage.Achievement, error) {
+	const op = "lib.achievements.Evaluate"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	owned, err := e.achievementRepo.GetAchievements(ctx, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	awarded := make(map[string]bool, len(owned))
+	for _, achievement := range owned {
+		awarded[achievement.Code] = true
+	}
+
+	var pending []Rule
+	for _, rule := range e.rules {
+		if !awarded[rule.Code] {
+			pending = append(pending, rule)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	p, err := e.progress(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now().UTC()
+	var earned []*storage.Achievement
+	for _, rule := range pending {
+		met, err := e.met(ctx, userID, rule, p)
+		if err != nil {
+			return earned, fmt.Errorf("%s: %w", op, err)
+		}
+		if !met {
+			continue
+		}
+		achievement := &storage.Achievement{UserID: userID, Code: rule.Code, AwardedAt: now}
+		isNew, err := e.achievementRepo.AwardAchievement(ctx, achievement)
+		if err != nil {
+			return earned, fmt.Errorf("%s: %w", op, err)
+		}
+		// another finalization could have awarded it in the meantime
+		if isNew {
+			earned = append(earned, achievement)
+		}
+	}
+	return earned, nil
+}
+
+func (e *Engine) met(ctx context.Context, userID string, rule Rule, p progress) (bool, error) {
+	switch rule.Kind {
+	case KindWorkouts:
+		return p.workouts >= rule.Threshold, nil
+	case KindWeeklyStreak:
+		return p.streak >= rule.Threshold, nil
+	case KindTonnage:
+		return p.tonnage >= rule.Threshold, nil
+	case KindBodyweightLift:
+		return e.bodyweightLift(ctx, userID, rule)
+	default:
+		return false, fmt.Errorf("unknown kind %q of rule %s", rule.Kind, rule.Code)
+	}
+}
+
+// progress streams the whole history, so it is not held in memory.
+func (e *Engine) progress(ctx context.Context, userID string) (progress, error) {
+	var p progress
+	weeks := make(map[time.Time]bool)
+	err := e.workoutRepo.StreamWorkouts(ctx, &userID, time.Time{}, time.Now().AddDate(0, 0, 1), func(workout *storage.WorkoutWithRecords) error {
+		p.workouts++
+		weeks[stats.PeriodStart(workout.StartTime, stats.PeriodWeek)] = true
+		for _, record := range workout.Records {
+			if record.Counts() {
+				p.tonnage += record.Reps * record.Weight
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return p, err
+	}
+	p.streak = longestStreak(weeks)
+	return p, nil
+}
+
+// longestStreak returns the longest run of consecutive weeks.
+func longestStreak(weeks map[time.Time]bool) int {
+	starts := make([]time.Time, 0, len(weeks))
+	for week := range weeks {
+		starts = append(starts, week)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	longest, current := 0, 0
+	for i, week := range starts {
+		if i > 0 && starts[i-1].AddDate(0, 0, 7).Equal(week) {
+			current++
+		} else {
+			current = 1
+		}
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+// bodyweightLift compares user's max on the rule's exercise with the body weight. Users without a body weight never meet it.
+func (e *Engine) bodyweightLift(ctx context.Context, userID string, rule Rule) (bool, error) {
+	user, err := e.userRepo.GetUserByID(ctx, &userID)
+	if err != nil {
+		return false, err
+	}
+	if user.BodyWeight <= 0 {
+		return false, nil
+	}
+
+	exercises, err := e.exerciseRepo.GetExercises(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, exercise := range exercises {
+		if exercise.Name != rule.Exercise {
+			continue
+		}
+		userMax, err := e.userRepo.GetUserMax(ctx, &userID, &exercise.ExerciseId)
+		if errors.Is(err, storage.ErrNoMaxes) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return userMax.MaxWeight*100 >= user.BodyWeight*rule.Threshold, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,303 @@
+package achievements
+
+import (
+	"GYMBRO/internal/lib/stats"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// weeksOf returns the weeks the workouts started at fall in, the way progress collects them.
+func weeksOf(t *testing.T, starts ...string) map[time.Time]bool {
+	weeks := make(map[time.Time]bool)
+	for _, start := range starts {
+		parsed, err := time.Parse(time.RFC3339, start)
+		require.NoError(t, err)
+		weeks[stats.PeriodStart(parsed, stats.PeriodWeek)] = true
+	}
+	return weeks
+}
+
+func TestLongestStreak(t *testing.T) {
+	var year []string
+	for week := time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC); week.Year() == 2020; week = week.AddDate(0, 0, 7) {
+		year = append(year, week.Format(time.RFC3339))
+	}
+
+	tests := []struct {
+		name     string
+		starts   []string
+		expected int
+	}{
+		{name: "NoWorkouts", expected: 0},
+		{name: "OneWorkout", starts: []string{"2024-03-06T18:00:00Z"}, expected: 1},
+		{name: "MondayAndSundayAreOneWeek", starts: []string{"2024-03-04T00:00:00Z", "2024-03-10T23:59:59Z"}, expected: 1},
+		{name: "SundayThenMonday", starts: []string{"2024-03-10T23:59:59Z", "2024-03-11T00:00:00Z"}, expected: 2},
+		{name: "WeekSkipped", starts: []string{"2024-03-04T18:00:00Z", "2024-03-18T18:00:00Z"}, expected: 1},
+		{name: "ThirteenDaysApartInConsecutiveWeeks", starts: []string{"2024-03-04T08:00:00Z", "2024-03-17T20:00:00Z"}, expected: 2},
+		{name: "EightDaysApartWithWeekSkipped", starts: []string{"2024-03-10T08:00:00Z", "2024-03-18T08:00:00Z"}, expected: 1},
+		{name: "LongestOfTwoRuns", starts: []string{
+			"2024-01-01T18:00:00Z", "2024-01-08T18:00:00Z",
+			"2024-01-22T18:00:00Z", "2024-01-29T18:00:00Z", "2024-02-05T18:00:00Z",
+		}, expected: 3},
+		{name: "OrderDoesNotMatter", starts: []string{"2024-01-15T18:00:00Z", "2024-01-01T18:00:00Z", "2024-01-08T18:00:00Z"}, expected: 3},
+		{name: "AcrossNewYear", starts: []string{"2024-12-23T18:00:00Z", "2025-01-01T18:00:00Z", "2025-01-06T18:00:00Z"}, expected: 3},
+		{name: "AcrossLeapDay", starts: []string{"2024-02-29T18:00:00Z", "2024-03-04T18:00:00Z"}, expected: 2},
+		// Monday 01:00 in UTC+2 is still Sunday in UTC, weeks are UTC weeks
+		{name: "OffsetBeforeUTCMonday", starts: []string{"2024-02-26T18:00:00Z", "2024-03-04T01:00:00+02:00"}, expected: 1},
+		{name: "OffsetAfterUTCMonday", starts: []string{"2024-02-26T18:00:00Z", "2024-03-03T22:00:00-03:00"}, expected: 2},
+		{name: "AcrossDSTChange", starts: []string{"2024-03-25T18:00:00+01:00", "2024-04-01T18:00:00+02:00"}, expected: 2},
+		{name: "WholeYear", starts: year, expected: len(year)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, longestStreak(weeksOf(t, tt.starts...)))
+		})
+	}
+}
+
+// history returns a StreamWorkouts mock call that streams the workouts.
+func history(workouts ...*storage.WorkoutWithRecords) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(4).(func(*storage.WorkoutWithRecords) error)
+		for _, workout := range workouts {
+			if err := fn(workout); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func workoutAt(start string, records ...storage.Record) *storage.WorkoutWithRecords {
+	startTime, _ := time.Parse(time.RFC3339, start)
+	return &storage.WorkoutWithRecords{UserID: "user123", WorkoutID: start, StartTime: startTime, EndTime: startTime.Add(time.Hour), Records: records}
+}
+
+func TestRules(t *testing.T) {
+	ctx := context.Background()
+	userID := "user123"
+	benchID := 3
+	bench := []*storage.Exercise{{ExerciseId: 1, Name: "Squat"}, {ExerciseId: benchID, Name: "Bench Press"}}
+	bodyweight := Rule{Code: "bodyweight_bench", Kind: KindBodyweightLift, Threshold: 100, Exercise: "Bench Press"}
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		workouts  []*storage.WorkoutWithRecords
+		setupMock func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository)
+		met       bool
+	}{
+		{
+			name:     "WorkoutsBelowThreshold",
+			rule:     Rule{Code: "workouts", Kind: KindWorkouts, Threshold: 2},
+			workouts: []*storage.WorkoutWithRecords{workoutAt("2024-03-04T18:00:00Z")},
+		},
+		{
+			name:     "WorkoutsAtThreshold",
+			rule:     Rule{Code: "workouts", Kind: KindWorkouts, Threshold: 2},
+			workouts: []*storage.WorkoutWithRecords{workoutAt("2024-03-04T18:00:00Z"), workoutAt("2024-03-05T18:00:00Z")},
+			met:      true,
+		},
+		{
+			name:     "StreakInConsecutiveWeeks",
+			rule:     Rule{Code: "streak", Kind: KindWeeklyStreak, Threshold: 2},
+			workouts: []*storage.WorkoutWithRecords{workoutAt("2024-03-10T18:00:00Z"), workoutAt("2024-03-11T18:00:00Z")},
+			met:      true,
+		},
+		{
+			name:     "StreakNotInTheSameWeek",
+			rule:     Rule{Code: "streak", Kind: KindWeeklyStreak, Threshold: 2},
+			workouts: []*storage.WorkoutWithRecords{workoutAt("2024-03-04T18:00:00Z"), workoutAt("2024-03-10T18:00:00Z")},
+		},
+		{
+			name: "TonnageAtThreshold",
+			rule: Rule{Code: "tonnage", Kind: KindTonnage, Threshold: 1000},
+			workouts: []*storage.WorkoutWithRecords{
+				workoutAt("2024-03-04T18:00:00Z", storage.Record{Reps: 5, Weight: 100}),
+				workoutAt("2024-03-05T18:00:00Z", storage.Record{Reps: 5, Weight: 100, Status: storage.RecordOK}),
+			},
+			met: true,
+		},
+		{
+			name: "TonnageLeavesOutRecordsThatDoNotCount",
+			rule: Rule{Code: "tonnage", Kind: KindTonnage, Threshold: 1000},
+			workouts: []*storage.WorkoutWithRecords{workoutAt("2024-03-04T18:00:00Z",
+				storage.Record{Reps: 5, Weight: 100},
+				storage.Record{Reps: 5, Weight: 100, Status: storage.RecordHeld},
+				storage.Record{Reps: 5, Weight: 100, Status: storage.RecordExcluded},
+				storage.Record{Reps: 5, Weight: 100, Status: storage.RecordVoided},
+			)},
+		},
+		{
+			name: "BodyweightLifted",
+			rule: bodyweight,
+			setupMock: func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				userRepo.On("GetUserByID", mock.Anything, &userID).Return(&storage.User{UserId: userID, BodyWeight: 80}, nil)
+				exerciseRepo.On("GetExercises", mock.Anything).Return(bench, nil)
+				userRepo.On("GetUserMax", mock.Anything, &userID, &benchID).Return(&storage.Max{UserID: userID, ExerciseId: benchID, MaxWeight: 80, Reps: 1}, nil)
+			},
+			met: true,
+		},
+		{
+			name: "BodyweightNotLifted",
+			rule: bodyweight,
+			setupMock: func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				userRepo.On("GetUserByID", mock.Anything, &userID).Return(&storage.User{UserId: userID, BodyWeight: 80}, nil)
+				exerciseRepo.On("GetExercises", mock.Anything).Return(bench, nil)
+				userRepo.On("GetUserMax", mock.Anything, &userID, &benchID).Return(&storage.Max{UserID: userID, ExerciseId: benchID, MaxWeight: 79, Reps: 1}, nil)
+			},
+		},
+		{
+			name: "BodyweightNotSet",
+			rule: bodyweight,
+			setupMock: func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				userRepo.On("GetUserByID", mock.Anything, &userID).Return(&storage.User{UserId: userID}, nil)
+			},
+		},
+		{
+			name: "BodyweightNoMax",
+			rule: bodyweight,
+			setupMock: func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				userRepo.On("GetUserByID", mock.Anything, &userID).Return(&storage.User{UserId: userID, BodyWeight: 80}, nil)
+				exerciseRepo.On("GetExercises", mock.Anything).Return(bench, nil)
+				userRepo.On("GetUserMax", mock.Anything, &userID, &benchID).Return(nil, storage.ErrNoMaxes)
+			},
+		},
+		{
+			name: "BodyweightExerciseMissing",
+			rule: bodyweight,
+			setupMock: func(userRepo *mocks.UserRepository, exerciseRepo *mocks.ExerciseRepository) {
+				userRepo.On("GetUserByID", mock.Anything, &userID).Return(&storage.User{UserId: userID, BodyWeight: 80}, nil)
+				exerciseRepo.On("GetExercises", mock.Anything).Return(bench[:1], nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewUserRepository(t)
+			workoutRepo := mocks.NewWorkoutRepository(t)
+			exerciseRepo := mocks.NewExerciseRepository(t)
+			achievementRepo := mocks.NewAchievementRepository(t)
+			if tt.setupMock != nil {
+				tt.setupMock(userRepo, exerciseRepo)
+			}
+			achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(nil, nil)
+			workoutRepo.On("StreamWorkouts", mock.Anything, &userID, time.Time{}, mock.Anything, mock.Anything).Run(history(tt.workouts...)).Return(nil)
+			if tt.met {
+				achievementRepo.On("AwardAchievement", mock.Anything, mock.MatchedBy(func(a *storage.Achievement) bool {
+					return a.UserID == userID && a.Code == tt.rule.Code
+				})).Return(true, nil)
+			}
+
+			earned, err := New([]Rule{tt.rule}, userRepo, workoutRepo, exerciseRepo, achievementRepo).Evaluate(ctx, userID)
+			require.NoError(t, err)
+			if tt.met {
+				require.Len(t, earned, 1)
+				require.Equal(t, tt.rule.Code, earned[0].Code)
+			} else {
+				require.Empty(t, earned)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	ctx := context.Background()
+	userID := "user123"
+	rules := []Rule{
+		{Code: "first_workout", Kind: KindWorkouts, Threshold: 1},
+		{Code: "workouts_10", Kind: KindWorkouts, Threshold: 10},
+	}
+	workouts := []*storage.WorkoutWithRecords{workoutAt("2024-03-04T18:00:00Z")}
+	first := mock.MatchedBy(func(a *storage.Achievement) bool { return a.UserID == userID && a.Code == "first_workout" })
+
+	t.Run("AwardedOnce", func(t *testing.T) {
+		workoutRepo := mocks.NewWorkoutRepository(t)
+		achievementRepo := mocks.NewAchievementRepository(t)
+		engine := New(rules, mocks.NewUserRepository(t), workoutRepo, mocks.NewExerciseRepository(t), achievementRepo)
+		workoutRepo.On("StreamWorkouts", mock.Anything, &userID, time.Time{}, mock.Anything, mock.Anything).Run(history(workouts...)).Return(nil).Twice()
+		achievementRepo.On("AwardAchievement", mock.Anything, first).Return(true, nil).Once()
+
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(nil, nil).Once()
+		earned, err := engine.Evaluate(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, earned, 1)
+		require.Equal(t, "first_workout", earned[0].Code)
+
+		// the next finalization finds it owned and does not award it again
+		owned := []*storage.Achievement{{UserID: userID, Code: "first_workout", AwardedAt: earned[0].AwardedAt}}
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(owned, nil).Once()
+		earned, err = engine.Evaluate(ctx, userID)
+		require.NoError(t, err)
+		require.Empty(t, earned)
+	})
+
+	t.Run("AwardedConcurrently", func(t *testing.T) {
+		workoutRepo := mocks.NewWorkoutRepository(t)
+		achievementRepo := mocks.NewAchievementRepository(t)
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(nil, nil)
+		workoutRepo.On("StreamWorkouts", mock.Anything, &userID, time.Time{}, mock.Anything, mock.Anything).Run(history(workouts...)).Return(nil)
+		// another finalization stored it first
+		achievementRepo.On("AwardAchievement", mock.Anything, first).Return(false, nil)
+
+		earned, err := New(rules, mocks.NewUserRepository(t), workoutRepo, mocks.NewExerciseRepository(t), achievementRepo).Evaluate(ctx, userID)
+		require.NoError(t, err)
+		require.Empty(t, earned)
+	})
+
+	t.Run("EverythingOwned", func(t *testing.T) {
+		achievementRepo := mocks.NewAchievementRepository(t)
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return([]*storage.Achievement{
+			{UserID: userID, Code: "first_workout"}, {UserID: userID, Code: "workouts_10"},
+		}, nil)
+
+		// the history is not read
+		earned, err := New(rules, mocks.NewUserRepository(t), mocks.NewWorkoutRepository(t), mocks.NewExerciseRepository(t), achievementRepo).Evaluate(ctx, userID)
+		require.NoError(t, err)
+		require.Empty(t, earned)
+	})
+
+	t.Run("UnknownKind", func(t *testing.T) {
+		workoutRepo := mocks.NewWorkoutRepository(t)
+		achievementRepo := mocks.NewAchievementRepository(t)
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(nil, nil)
+		workoutRepo.On("StreamWorkouts", mock.Anything, &userID, time.Time{}, mock.Anything, mock.Anything).Return(nil)
+
+		_, err := New([]Rule{{Code: "odd", Kind: "odd"}}, mocks.NewUserRepository(t), workoutRepo, mocks.NewExerciseRepository(t), achievementRepo).Evaluate(ctx, userID)
+		require.Error(t, err)
+	})
+
+	t.Run("StorageError", func(t *testing.T) {
+		achievementRepo := mocks.NewAchievementRepository(t)
+		achievementRepo.On("GetAchievements", mock.Anything, &userID).Return(nil, errors.New("db error"))
+
+		_, err := New(rules, mocks.NewUserRepository(t), mocks.NewWorkoutRepository(t), mocks.NewExerciseRepository(t), achievementRepo).Evaluate(ctx, userID)
+		require.Error(t, err)
+	})
+}
+
+// TestRulesDeclared checks the declared rules can be evaluated and their codes, which are stored, are unique.
+func TestRulesDeclared(t *testing.T) {
+	codes := make(map[string]bool, len(Rules))
+	for _, rule := range Rules {
+		require.False(t, codes[rule.Code], "%s is declared twice", rule.Code)
+		codes[rule.Code] = true
+		require.Positive(t, rule.Threshold, rule.Code)
+		require.Contains(t, []Kind{KindWorkouts, KindWeeklyStreak, KindTonnage, KindBodyweightLift}, rule.Kind, rule.Code)
+		if rule.Kind == KindBodyweightLift {
+			require.NotEmpty(t, rule.Exercise, rule.Code)
+		}
+		found, ok := Find(rule.Code)
+		require.True(t, ok)
+		require.Equal(t, rule, found)
+	}
+	_, ok := Find("missing")
+	require.False(t, ok)
+}
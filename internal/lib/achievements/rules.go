@@ -0,0 +1,52 @@
+package achievements
+
+// Kind tells which progress value a rule compares with its threshold.
+type Kind string
+
+const (
+	// KindWorkouts is met when the user has finished Threshold workouts.
+	KindWorkouts Kind = "workouts"
+	// KindWeeklyStreak is met when the user trained in Threshold consecutive weeks.
+	KindWeeklyStreak Kind = "weekly_streak"
+	// KindTonnage is met when the user lifted Threshold kg in total (reps * weight of every set).
+	KindTonnage Kind = "tonnage"
+	// KindBodyweightLift is met when the user's max on Exercise is at least Threshold percent of the body weight.
+	KindBodyweightLift Kind = "bodyweight_lift"
+)
+
+// Rule is a declarative achievement. Adding a badge is adding a line to Rules,
+// the evaluator only knows how to compute the progress of every Kind.
+type Rule struct {
+	Code        string
+	Name        string
+	Description string
+	Kind        Kind
+	Threshold   int
+	// Exercise is the exercise name for KindBodyweightLift
+	Exercise string
+}
+
+// Rules are the achievements users can earn. Codes are stored, so they must never change.
+var Rules = []Rule{
+	{Code: "first_workout", Name: "First Step", Description: "Finish your first workout", Kind: KindWorkouts, Threshold: 1},
+	{Code: "workouts_10", Name: "Regular", Description: "Finish 10 workouts", Kind: KindWorkouts, Threshold: 10},
+	{Code: "workouts_50", Name: "Dedicated", Description: "Finish 50 workouts", Kind: KindWorkouts, Threshold: 50},
+	{Code: "workouts_100", Name: "Centurion", Description: "Finish 100 workouts", Kind: KindWorkouts, Threshold: 100},
+	{Code: "streak_4_weeks", Name: "On a Roll", Description: "Train 4 weeks in a row", Kind: KindWeeklyStreak, Threshold: 4},
+	{Code: "streak_12_weeks", Name: "Habit", Description: "Train 12 weeks in a row", Kind: KindWeeklyStreak, Threshold: 12},
+	{Code: "streak_52_weeks", Name: "Year Round", Description: "Train 52 weeks in a row", Kind: KindWeeklyStreak, Threshold: 52},
+	{Code: "tonnage_10000", Name: "Ten Tonnes", Description: "Lift 10 000 kg in total", Kind: KindTonnage, Threshold: 10000},
+	{Code: "tonnage_100000", Name: "Hundred Tonnes", Description: "Lift 100 000 kg in total", Kind: KindTonnage, Threshold: 100000},
+	{Code: "tonnage_1000000", Name: "Megatonne", Description: "Lift 1 000 000 kg in total", Kind: KindTonnage, Threshold: 1000000},
+	{Code: "bodyweight_bench", Name: "Own Weight", Description: "Bench press your body weight", Kind: KindBodyweightLift, Threshold: 100, Exercise: "Bench Press"},
+}
+
+// Find returns the rule with the code.
+func Find(code string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Code == code {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Emitter --output=./mocks
+type Emitter interface {
+	Emit(context.Context, []*storage.Event) error
+}
+
+// StoreEmitter stores the events, they make up the feeds of the followers.
+type StoreEmitter struct {
+	eventRepo storage.EventRepository
+}
+
+func New(eventRepo storage.EventRepository) *StoreEmitter {
+	return &StoreEmitter{eventRepo: eventRepo}
+}
+
+// Emit stores the events. Nothing is stored for no events.
+func (e *StoreEmitter) Emit(ctx context.Context, events []*storage.Event) error {
+	const op = "lib.events.Emit"
+	if len(events) == 0 {
+		return nil
+	}
+	if err := e.eventRepo.AddEvents(ctx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Finalized returns the events of a finalized workout: the workout itself, the new maxes set in it and
+// the achievements it earned. Empty workouts are not saved, so they have no events.
+func Finalized(workout *storage.WorkoutSession, maxes []*storage.Max, achievements []*storage.Achievement) []*storage.Event {
+	if len(workout.Records) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	events := []*storage.Event{{
+		EventID:   storage.GenerateUID(),
+		UserID:    workout.UserID,
+		Type:      storage.EventWorkoutFinished,
+		WorkoutID: workout.SessionID,
+		Points:    workout.Points,
+		CreatedAt: now,
+	}}
+	for _, max := range maxes {
+		events = append(events, personalRecord(workout.UserID, workout.SessionID, max, now))
+	}
+	for _, achievement := range achievements {
+		events = append(events, achieved(workout.UserID, workout.SessionID, achievement, now))
+	}
+	return events
+}
+
+// Approved returns the events of an approved held record: the new max it set, nil if it did not,
+// and the achievements it earned. They belong to the workout of the record.
+func Approved(record *storage.FlaggedRecord, max *storage.Max, achievements []*storage.Achievement) []*storage.Event {
+	now := time.Now().UTC()
+	var events []*storage.Event
+	if max != nil {
+		events = append(events, personalRecord(record.UserID, record.FkWorkoutId, max, now))
+	}
+	for _, achievement := range achievements {
+		events = append(events, achieved(record.UserID, record.FkWorkoutId, achievement, now))
+	}
+	return events
+}
+
+func personalRecord(userID, workoutID string, max *storage.Max, now time.Time) *storage.Event {
+	return &storage.Event{
+		EventID:    storage.GenerateUID(),
+		UserID:     userID,
+		Type:       storage.EventPersonalRecord,
+		WorkoutID:  workoutID,
+		ExerciseID: max.ExerciseId,
+		Weight:     max.MaxWeight,
+		Reps:       max.Reps,
+		CreatedAt:  now,
+	}
+}
+
+func achieved(userID, workoutID string, achievement *storage.Achievement, now time.Time) *storage.Event {
+	return &storage.Event{
+		EventID:   storage.GenerateUID(),
+		UserID:    userID,
+		Type:      storage.EventAchievement,
+		WorkoutID: workoutID,
+		Code:      achievement.Code,
+		CreatedAt: now,
+	}
+}
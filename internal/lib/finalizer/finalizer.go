@@ -0,0 +1,167 @@
+package finalizer
+
+import (
+	"GYMBRO/internal/lib/achievements"
+	"GYMBRO/internal/lib/events"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/lib/webhooks"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// prBonus is the points a record earns on top for setting a new max.
+const prBonus = 50
+
+// Finalizer ends workout sessions, the same way whether the user ends them, the scheduler after inactivity or an admin.
+// Held records approved later get the credit for a new max from it too.
+type Finalizer struct {
+	sessionRepo    storage.SessionRepository
+	workoutRepo    storage.WorkoutRepository
+	userRepo       storage.UserRepository
+	moderationRepo storage.ModerationRepository
+	evaluator      achievements.Evaluator
+	emitter        events.Emitter
+	notifier       webhooks.Notifier
+}
+
+func New(sessionRepo storage.SessionRepository, workoutRepo storage.WorkoutRepository, userRepo storage.UserRepository, moderationRepo storage.ModerationRepository, evaluator achievements.Evaluator, emitter events.Emitter, notifier webhooks.Notifier) *Finalizer {
+	return &Finalizer{
+		sessionRepo:    sessionRepo,
+		workoutRepo:    workoutRepo,
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+		evaluator:      evaluator,
+		emitter:        emitter,
+		notifier:       notifier,
+	}
+}
+
+// Finalize sets the new maxes among the records that count and gives their bonus, saves the workout, deletes the session
+// and marks the user inactive, any error of these is returned. Then achievements are evaluated and the workout, new maxes
+// and achievements are emitted to the feed and queued for the webhooks, failing to do so is only logged.
+// source is who ended the workout, metrics.SourceScheduler sends session.auto_ended instead of workout.ended.
+// (2+ userRepo calls, 1 workoutRepo call, 1 sessionRepo call, 1 evaluation, 1 emit, 1 notify)
+func (f *Finalizer) Finalize(ctx context.Context, log *slog.Logger, session *storage.WorkoutSession, source string) error {
+	const op = "lib.finalizer.Finalize"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	newMaxes, err := f.setMaxes(ctx, session)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := f.workoutRepo.SaveWorkout(ctx, session); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := f.sessionRepo.DeleteSession(ctx, &session.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := f.userRepo.ChangeStatus(ctx, &session.UserID, false); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	metrics.WorkoutsFinished.WithLabelValues(source).Inc()
+
+	earned, err := f.evaluator.Evaluate(ctx, session.UserID)
+	if err != nil {
+		log.Error("Failed to EVALUATE achievements", slog.Any("error", err))
+	}
+	for _, achievement := range earned {
+		metrics.AchievementsAwarded.WithLabelValues(achievement.Code).Inc()
+		log.Debug("Achievement awarded", slog.String("code", achievement.Code))
+	}
+
+	if err := f.emitter.Emit(ctx, events.Finalized(session, newMaxes, earned)); err != nil {
+		log.Error("Failed to EMIT workout events", slog.Any("error", err))
+	}
+	if err := f.notifier.Notify(ctx, webhooks.Finalized(session, newMaxes, source == metrics.SourceScheduler)); err != nil {
+		log.Error("Failed to NOTIFY webhooks", slog.Any("error", err))
+	}
+	return nil
+}
+
+// Approve approves the held record. If it beats the max of the user it becomes the max with the bonus, like in Finalize,
+// and the new max is emitted to the feed and queued for the webhooks. Then achievements are evaluated, the records count
+// for them now. Only approving can fail, the rest is logged. (1 moderationRepo call, 1 evaluation, 0-1 emit, 0-1 notify)
+func (f *Finalizer) Approve(ctx context.Context, log *slog.Logger, recordID *string) (*storage.FlaggedRecord, error) {
+	const op = "lib.finalizer.Approve"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	record, newMax, err := f.moderationRepo.ApproveRecord(ctx, recordID, prBonus)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if newMax != nil {
+		metrics.PRsSet.Inc()
+	}
+
+	earned, err := f.evaluator.Evaluate(ctx, record.UserID)
+	if err != nil {
+		log.Error("Failed to EVALUATE achievements", slog.Any("error", err))
+	}
+	for _, achievement := range earned {
+		metrics.AchievementsAwarded.WithLabelValues(achievement.Code).Inc()
+		log.Debug("Achievement awarded", slog.String("code", achievement.Code))
+	}
+
+	if err := f.emitter.Emit(ctx, events.Approved(record, newMax, earned)); err != nil {
+		log.Error("Failed to EMIT record events", slog.Any("error", err))
+	}
+	if err := f.notifier.Notify(ctx, webhooks.Approved(record, newMax)); err != nil {
+		log.Error("Failed to NOTIFY webhooks", slog.Any("error", err))
+	}
+	return record, nil
+}
+
+// setMaxes stores the best record of every exercise that beats the user's max (heavier, or as heavy with more reps)
+// and adds the bonus to it and to the session. Records that do not count are skipped.
+func (f *Finalizer) setMaxes(ctx context.Context, session *storage.WorkoutSession) ([]*storage.Max, error) {
+	best := make(map[int]int)
+	for i, record := range session.Records {
+		if !record.Counts() {
+			continue
+		}
+		current, exists := best[record.FkExerciseId]
+		if !exists || record.Weight > session.Records[current].Weight ||
+			(record.Weight == session.Records[current].Weight && record.Reps > session.Records[current].Reps) {
+			best[record.FkExerciseId] = i
+		}
+	}
+
+	dbMaxes, err := f.userRepo.GetUserMaxes(ctx, &session.UserID)
+	if err != nil && !errors.Is(err, storage.ErrNoMaxes) {
+		return nil, err
+	}
+	dbMaxMap := make(map[int]*storage.Max, len(dbMaxes))
+	for _, dbMax := range dbMaxes {
+		dbMaxMap[dbMax.ExerciseId] = dbMax
+	}
+
+	var newMaxes []*storage.Max
+	for exerciseID, i := range best {
+		record := &session.Records[i]
+		dbMax, exists := dbMaxMap[exerciseID]
+		if exists && (record.Weight < dbMax.MaxWeight || (record.Weight == dbMax.MaxWeight && record.Reps <= dbMax.Reps)) {
+			continue
+		}
+		newMax := &storage.Max{
+			UserID:     session.UserID,
+			ExerciseId: exerciseID,
+			MaxWeight:  record.Weight,
+			Reps:       record.Reps,
+		}
+		if err := f.userRepo.SetUserMax(ctx, &session.UserID, newMax); err != nil {
+			return nil, err
+		}
+		newMaxes = append(newMaxes, newMax)
+		metrics.PRsSet.Inc()
+		record.Points += prBonus
+		session.Points += prBonus
+	}
+	return newMaxes, nil
+}
@@ -3,6 +3,7 @@ package jwt
 import (
 	"GYMBRO/internal/storage"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
@@ -11,18 +12,98 @@ import (
 
 type contextKey string
 
-const UserKey contextKey = "uid"
+const (
+	UserKey contextKey = "uid"
+	RoleKey contextKey = "role"
+)
 
+// NewToken issues the login token of the user with their role at login. The JWT middleware does not trust the role claim,
+// it loads the current role from storage, so a role change or a disabled account takes effect before the token expires.
 func NewToken(usr storage.User, duration time.Duration, secret string) (string, error) {
 	claims := jwt.MapClaims{
 		"uid":      usr.UserId,
 		"username": usr.Username,
+		"role":     usr.Role,
 		"exp":      time.Now().Add(duration).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
 
+// NewMergeToken issues a short-lived token that allows the target user to merge the source account into theirs.
+// It proves that whoever holds it has just authenticated as the source account through OAuth.
+func NewMergeToken(targetID, sourceID string, duration time.Duration, secret string) (string, error) {
+	claims := jwt.MapClaims{
+		"typ":    "merge",
+		"target": targetID,
+		"source": sourceID,
+		"exp":    time.Now().Add(duration).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// ParseMergeToken validates a token issued by NewMergeToken and returns target and source user IDs.
+func ParseMergeToken(tokenString, secret string) (string, string, error) {
+	token, err := ValidateJWT(tokenString, secret)
+	if err != nil {
+		return "", "", err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != "merge" {
+		return "", "", errors.New("not a merge token")
+	}
+	target, okTarget := claims["target"].(string)
+	source, okSource := claims["source"].(string)
+	if !okTarget || !okSource {
+		return "", "", errors.New("malformed merge token")
+	}
+	return target, source, nil
+}
+
+// CheckInToken is what a check-in QR code proves: the user checked in at the gym and the code has not expired yet.
+type CheckInToken struct {
+	CheckInID string
+	UserID    string
+	GymID     int
+	ExpiresAt time.Time
+}
+
+// NewCheckInToken issues a short-lived token for the check-in QR code gym staff scan.
+// It has no "uid" claim, so it can not be used to authenticate.
+func NewCheckInToken(checkIn *storage.CheckIn, expiresAt time.Time, secret string) (string, error) {
+	claims := jwt.MapClaims{
+		"typ":      "check-in",
+		"check_in": checkIn.CheckInID,
+		"user":     checkIn.FkUserId,
+		"gym":      checkIn.FkGymId,
+		"exp":      expiresAt.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// ParseCheckInToken validates a token issued by NewCheckInToken.
+func ParseCheckInToken(tokenString, secret string) (*CheckInToken, error) {
+	token, err := ValidateJWT(tokenString, secret)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != "check-in" {
+		return nil, errors.New("not a check-in token")
+	}
+	checkInID, okCheckIn := claims["check_in"].(string)
+	userID, okUser := claims["user"].(string)
+	// numbers are decoded as float64
+	gymID, okGym := claims["gym"].(float64)
+	expiresAt, err := claims.GetExpirationTime()
+	if !okCheckIn || !okUser || !okGym || err != nil || expiresAt == nil {
+		return nil, errors.New("malformed check-in token")
+	}
+	return &CheckInToken{CheckInID: checkInID, UserID: userID, GymID: int(gymID), ExpiresAt: expiresAt.Time}, nil
+}
+
 func GetTokenFromRequest(r *http.Request) string {
 	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
 		return authHeader
@@ -51,3 +132,10 @@ func GetUserIDFromContext(ctx context.Context) string {
 	}
 	return ""
 }
+
+func GetRoleFromContext(ctx context.Context) string {
+	if role, ok := ctx.Value(RoleKey).(string); ok {
+		return role
+	}
+	return ""
+}
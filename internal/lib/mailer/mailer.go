@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"GYMBRO/internal/config"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Mailer --output=./mocks
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// New returns an SMTP mailer, or a mailer that only logs messages if no SMTP host is configured (local env).
+func New(cfg config.MailerCfg, log *slog.Logger) Mailer {
+	if cfg.SMTPHost == "" {
+		return &LogMailer{log: log}
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+type SMTPMailer struct {
+	cfg config.MailerCfg
+}
+
+// Send sends a plain text email.
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	const op = "lib.mailer.Send"
+	addr := net.JoinHostPort(m.cfg.SMTPHost, strconv.Itoa(m.cfg.SMTPPort))
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.SMTPHost)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.cfg.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(addr, auth, m.cfg.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+type LogMailer struct {
+	log *slog.Logger
+}
+
+// Send writes the email to the log instead of sending it.
+func (m *LogMailer) Send(to string, subject string, body string) error {
+	m.log.Info("Email", slog.String("to", to), slog.String("subject", subject), slog.String("body", body))
+	return nil
+}
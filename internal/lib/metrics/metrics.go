@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"math"
+)
+
+const namespace = "gymbro"
+
+const (
+	SourceUser      = "user"
+	SourceScheduler = "scheduler"
+	SourceAdmin     = "admin"
+)
+
+const (
+	WebhookDelivered = "delivered"
+	WebhookRetry     = "retry"
+	WebhookDead      = "dead"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RedisCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Redis command latency by command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command"})
+
+	SchedulerEndedSessions = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_ended_sessions",
+		Help:      "Number of inactive sessions ended per session scheduler run.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 25, 50, 100},
+	})
+
+	WorkoutsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workouts_finished_total",
+		Help:      "Number of finished workouts by who finished them (user, scheduler, admin).",
+	}, []string{"source"})
+
+	RecordsAdded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_added_total",
+		Help:      "Number of records added to workout sessions.",
+	})
+
+	PRsSet = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "personal_records_total",
+		Help:      "Number of personal records (new maxes) set.",
+	})
+
+	RecordsFlagged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_flagged_total",
+		Help:      "Number of records that failed the plausibility checks by status (held, excluded).",
+	}, []string{"status"})
+
+	RecordsReviewed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_reviewed_total",
+		Help:      "Number of records reviewed by moderators by action (approve, void).",
+	}, []string{"action"})
+
+	AchievementsAwarded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "achievements_awarded_total",
+		Help:      "Number of achievements awarded by code.",
+	}, []string{"code"})
+
+	ChallengesSettled = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "challenges_settled_total",
+		Help:      "Number of clan challenges settled by the challenge scheduler.",
+	})
+
+	SeasonsRolledOver = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "seasons_rolled_over_total",
+		Help:      "Number of seasons ended by the season scheduler.",
+	})
+
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Number of webhook delivery attempts by result (delivered, retry, dead).",
+	}, []string{"result"})
+
+	SessionStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "session_streams",
+		Help:      "Number of open workout session event streams.",
+	})
+)
+
+// RegisterActiveSessions exposes the number of active workout sessions, counted on every scrape.
+func RegisterActiveSessions(count func(context.Context) (int, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Number of active workout sessions.",
+	}, func() float64 {
+		n, err := count(context.Background())
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(n)
+	})
+}
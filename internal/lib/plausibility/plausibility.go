@@ -0,0 +1,38 @@
+// Package plausibility tells if a logged set is believable, compared to the absolute limit of the exercise
+// and to what the user lifted before.
+package plausibility
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/points"
+	"GYMBRO/internal/storage"
+	"fmt"
+)
+
+// Verdict is the status a record is saved with and why, Reason is empty for records that are ok.
+type Verdict struct {
+	Status string
+	Reason string
+}
+
+// Check excludes sets heavier than the weight limit of the exercise and holds sets whose estimated one rep max
+// jumps more than cfg.MaxJump times over the best of the user. best is nil when the user has no history of the exercise,
+// then only the limit is checked.
+func Check(cfg config.PlausibilityCfg, exercise *storage.Exercise, best *storage.Max, record *storage.Record) Verdict {
+	limit := exercise.WeightLimit
+	if limit <= 0 {
+		limit = cfg.DefaultWeightLimit
+	}
+	if limit > 0 && record.Weight > limit {
+		return Verdict{Status: storage.RecordExcluded, Reason: fmt.Sprintf("%d kg is over the limit of %d kg", record.Weight, limit)}
+	}
+
+	if best != nil && cfg.MaxJump > 0 {
+		bestMax := points.EstimateOneRepMax(best.MaxWeight, best.Reps)
+		setMax := points.EstimateOneRepMax(record.Weight, record.Reps)
+		if bestMax > 0 && setMax > bestMax*cfg.MaxJump {
+			return Verdict{Status: storage.RecordHeld, Reason: fmt.Sprintf("estimated 1RM of %.0f kg is %.1fx the best of %.0f kg", setMax, setMax/bestMax, bestMax)}
+		}
+	}
+	return Verdict{Status: storage.RecordOK}
+}
@@ -0,0 +1,115 @@
+package plausibility_test
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/plausibility"
+	"GYMBRO/internal/storage"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	cfg := config.PlausibilityCfg{DefaultWeightLimit: 500, MaxJump: 1.3}
+	exercise := &storage.Exercise{ExerciseId: 1, Name: "Squat"}
+	// the estimated 1RM of 100 kg for 5 is 116.65 kg, sets are held above 151.6 kg
+	best := &storage.Max{ExerciseId: 1, MaxWeight: 100, Reps: 5}
+
+	tests := []struct {
+		name     string
+		cfg      config.PlausibilityCfg
+		exercise *storage.Exercise
+		best     *storage.Max
+		record   storage.Record
+		expected plausibility.Verdict
+	}{
+		{
+			name:     "FirstLift",
+			record:   storage.Record{Weight: 400, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "FirstLiftOverDefaultLimit",
+			record:   storage.Record{Weight: 501, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordExcluded, Reason: "501 kg is over the limit of 500 kg"},
+		},
+		{
+			name:     "AtDefaultLimit",
+			record:   storage.Record{Weight: 500, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "OverExerciseLimit",
+			exercise: &storage.Exercise{ExerciseId: 2, Name: "Curl", WeightLimit: 120},
+			record:   storage.Record{Weight: 121, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordExcluded, Reason: "121 kg is over the limit of 120 kg"},
+		},
+		{
+			name:     "ExerciseLimitOverDefault",
+			exercise: &storage.Exercise{ExerciseId: 3, Name: "Leg Press", WeightLimit: 1000},
+			record:   storage.Record{Weight: 800, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "NoLimit",
+			cfg:      config.PlausibilityCfg{MaxJump: 1.3},
+			record:   storage.Record{Weight: 2000, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "WithinJump",
+			best:     best,
+			record:   storage.Record{Weight: 125, Reps: 5},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "LighterThanBest",
+			best:     best,
+			record:   storage.Record{Weight: 60, Reps: 10},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "JumpHeld",
+			best:     best,
+			record:   storage.Record{Weight: 140, Reps: 5},
+			expected: plausibility.Verdict{Status: storage.RecordHeld, Reason: "estimated 1RM of 163 kg is 1.4x the best of 117 kg"},
+		},
+		{
+			// the same weight for many more reps is a jump of the estimated max too
+			name:     "RepsJumpHeld",
+			best:     &storage.Max{ExerciseId: 1, MaxWeight: 100, Reps: 1},
+			record:   storage.Record{Weight: 100, Reps: 12},
+			expected: plausibility.Verdict{Status: storage.RecordHeld, Reason: "estimated 1RM of 140 kg is 1.4x the best of 103 kg"},
+		},
+		{
+			name:     "OverLimitIsExcludedNotHeld",
+			best:     best,
+			record:   storage.Record{Weight: 600, Reps: 1},
+			expected: plausibility.Verdict{Status: storage.RecordExcluded, Reason: "600 kg is over the limit of 500 kg"},
+		},
+		{
+			name:     "JumpCheckOff",
+			cfg:      config.PlausibilityCfg{DefaultWeightLimit: 500},
+			best:     best,
+			record:   storage.Record{Weight: 300, Reps: 5},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+		{
+			name:     "BestWithoutWeight",
+			best:     &storage.Max{ExerciseId: 1},
+			record:   storage.Record{Weight: 100, Reps: 5},
+			expected: plausibility.Verdict{Status: storage.RecordOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg == (config.PlausibilityCfg{}) {
+				tt.cfg = cfg
+			}
+			if tt.exercise == nil {
+				tt.exercise = exercise
+			}
+			require.Equal(t, tt.expected, plausibility.Check(tt.cfg, tt.exercise, tt.best, &tt.record))
+		})
+	}
+}
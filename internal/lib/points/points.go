@@ -5,11 +5,16 @@ import (
 )
 
 func CalculatePoints(maxWeight, maxReps, currentWeight, currentReps, base int) int {
-	dbMax := float64(maxWeight) * (1 + 0.0333*float64(maxReps))
-	currSet := float64(currentWeight) * (1 + 0.0333*float64(currentReps))
+	dbMax := EstimateOneRepMax(maxWeight, maxReps)
+	currSet := EstimateOneRepMax(currentWeight, currentReps)
 
 	percentage := currSet / dbMax
 	points := percentage * float64(base)
 
 	return int(math.Round(points))
 }
+
+// EstimateOneRepMax estimates the weight that could be lifted once from a set of reps (Epley formula).
+func EstimateOneRepMax(weight, reps int) float64 {
+	return float64(weight) * (1 + 0.0333*float64(reps))
+}
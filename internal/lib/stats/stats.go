@@ -0,0 +1,61 @@
+// Package stats has the helpers shared by the statistics endpoints: date ranges and time periods
+// the history is grouped by.
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// PeriodWorkout keeps every workout on its own, it is not a period ValidPeriod accepts.
+	PeriodWorkout = "workout"
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+
+	DateLayout = "2006-01-02"
+)
+
+// ValidPeriod tells if the history can be grouped by period.
+func ValidPeriod(period string) bool {
+	return period == PeriodWeek || period == PeriodMonth
+}
+
+// PeriodStart returns the start of the week (weeks start on Monday) or month t falls into, in UTC.
+func PeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		// Sunday is 0, it is the last day of the week
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+}
+
+// ParseRange parses optional YYYY-MM-DD bounds. Both bounds are inclusive,
+// so the returned upper bound is the start of the day after 'to'.
+func ParseRange(fromParam, toParam string) (time.Time, time.Time, error) {
+	from := time.Time{}
+	to := time.Now().AddDate(0, 0, 1)
+
+	if fromParam != "" {
+		parsed, err := time.Parse(DateLayout, fromParam)
+		if err != nil {
+			return from, to, err
+		}
+		from = parsed
+	}
+	if toParam != "" {
+		parsed, err := time.Parse(DateLayout, toParam)
+		if err != nil {
+			return from, to, err
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from %s is after to %s", fromParam, toParam)
+	}
+	return from, to, nil
+}
@@ -4,14 +4,13 @@ import (
 	resp "GYMBRO/internal/http-server/handlers/response"
 	"errors"
 	"fmt"
-	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
 	"strings"
 )
 
-func ValidationError(errs validator.ValidationErrors) resp.DetailedResponse {
+func ValidationError(errs validator.ValidationErrors) *resp.APIError {
 	var errMsgs []string
 
 	for _, err := range errs {
@@ -25,12 +24,7 @@ func ValidationError(errs validator.ValidationErrors) resp.DetailedResponse {
 		}
 	}
 
-	return resp.DetailedResponse{
-		Status: resp.StatusError,
-		Error:  strings.Join(errMsgs, ", "),
-		Code:   resp.CodeValidationError,
-		Advice: "Check the input fields for validation errors",
-	}
+	return resp.NewError(http.StatusBadRequest, resp.CodeValidationError, strings.Join(errMsgs, ", "), "Check the input fields for validation errors")
 }
 
 func ValidateStruct(log *slog.Logger, s interface{}) error {
@@ -41,10 +35,11 @@ func ValidateStruct(log *slog.Logger, s interface{}) error {
 	return nil
 }
 
-func HandleValidationError(w http.ResponseWriter, r *http.Request, err error) {
+// Error converts the error returned by ValidateStruct to the one handlers return.
+func Error(err error) error {
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
-		render.Status(r, http.StatusBadRequest)
-		render.JSON(w, r, ValidationError(ve))
+		return ValidationError(ve)
 	}
+	return resp.Internal(err)
 }
@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Headers of a delivery. The signature is "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret of the webhook,
+// receivers recompute it and check the timestamp is recent to reject forged and replayed requests.
+const (
+	HeaderEvent     = "X-Gymbro-Event"
+	HeaderDelivery  = "X-Gymbro-Delivery"
+	HeaderTimestamp = "X-Gymbro-Timestamp"
+	HeaderSignature = "X-Gymbro-Signature"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Notifier --output=./mocks
+type Notifier interface {
+	Notify(context.Context, []*Message) error
+}
+
+// Message is an event of a user, it is sent to the webhooks of the user and of their gym.
+type Message struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	UserID    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// WorkoutData is the data of workout.ended and session.auto_ended.
+type WorkoutData struct {
+	UserID    string    `json:"user_id"`
+	WorkoutID string    `json:"workout_id"`
+	StartTime time.Time `json:"start_time"`
+	EndedAt   time.Time `json:"ended_at"`
+	Points    int       `json:"points"`
+	Records   int       `json:"records"`
+	GymID     int       `json:"gym_id,omitempty"`
+}
+
+// RecordData is the data of pr.achieved.
+type RecordData struct {
+	UserID     string `json:"user_id"`
+	WorkoutID  string `json:"workout_id"`
+	ExerciseID int    `json:"exercise_id"`
+	Weight     int    `json:"weight"`
+	Reps       int    `json:"reps"`
+}
+
+// StoreNotifier queues the messages as deliveries, the dispatcher sends them.
+type StoreNotifier struct {
+	webhookRepo storage.WebhookRepository
+}
+
+func New(webhookRepo storage.WebhookRepository) *StoreNotifier {
+	return &StoreNotifier{webhookRepo: webhookRepo}
+}
+
+// Notify queues a delivery of every message for each webhook subscribed to it. Nothing is queued for no messages.
+func (n *StoreNotifier) Notify(ctx context.Context, messages []*Message) error {
+	const op = "lib.webhooks.Notify"
+	for _, message := range messages {
+		payload, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		delivery := &storage.WebhookDelivery{
+			Event:         message.Event,
+			Payload:       string(payload),
+			NextAttemptAt: message.CreatedAt,
+			CreatedAt:     message.CreatedAt,
+		}
+		if _, err := n.webhookRepo.AddDeliveries(ctx, &message.UserID, delivery); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return nil
+}
+
+// Finalized returns the messages of a finalized workout: workout.ended, or session.auto_ended if the scheduler ended it,
+// and pr.achieved for the new maxes set in it. The workout ended at its last update, the end time it is saved with.
+// Empty workouts are not saved, so they have no messages.
+func Finalized(workout *storage.WorkoutSession, maxes []*storage.Max, autoEnded bool) []*Message {
+	if len(workout.Records) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	event := storage.WebhookWorkoutEnded
+	if autoEnded {
+		event = storage.WebhookSessionAutoEnded
+	}
+	messages := []*Message{{
+		ID:        storage.GenerateUID(),
+		Event:     event,
+		UserID:    workout.UserID,
+		CreatedAt: now,
+		Data: WorkoutData{
+			UserID:    workout.UserID,
+			WorkoutID: workout.SessionID,
+			StartTime: workout.StartTime,
+			EndedAt:   workout.LastUpdated.UTC(),
+			Points:    workout.Points,
+			Records:   len(workout.Records),
+			GymID:     workout.GymID,
+		},
+	}}
+	for _, max := range maxes {
+		messages = append(messages, prAchieved(workout.UserID, workout.SessionID, max, now))
+	}
+	return messages
+}
+
+// Approved returns pr.achieved for the new max an approved held record set, nothing if it did not set one.
+func Approved(record *storage.FlaggedRecord, max *storage.Max) []*Message {
+	if max == nil {
+		return nil
+	}
+	return []*Message{prAchieved(record.UserID, record.FkWorkoutId, max, time.Now().UTC())}
+}
+
+func prAchieved(userID, workoutID string, max *storage.Max, now time.Time) *Message {
+	return &Message{
+		ID:        storage.GenerateUID(),
+		Event:     storage.WebhookPRAchieved,
+		UserID:    userID,
+		CreatedAt: now,
+		Data: RecordData{
+			UserID:     userID,
+			WorkoutID:  workoutID,
+			ExerciseID: max.ExerciseId,
+			Weight:     max.MaxWeight,
+			Reps:       max.Reps,
+		},
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
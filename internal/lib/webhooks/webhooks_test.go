@@ -0,0 +1,79 @@
+package webhooks_test
+
+import (
+	"GYMBRO/internal/lib/webhooks"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// TestSign checks the signature against HMAC-SHA256 computed independently, so receivers in any language can verify it.
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		expected  string
+	}{
+		{
+			name:      "Body",
+			secret:    "whsec_test",
+			timestamp: "1700000000",
+			body:      `{"id":"d1","event":"workout.ended"}`,
+			expected:  "510e06cf0cd68a19c474856eaa130bf7f836ddea1ddbe45214d8bebdb788243c",
+		},
+		{
+			name:      "EmptyBody",
+			secret:    "whsec_test",
+			timestamp: "1700000000",
+			expected:  "5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, webhooks.Sign(tt.secret, tt.timestamp, []byte(tt.body)))
+		})
+	}
+
+	// the timestamp is signed, a replayed body with a new timestamp does not verify
+	require.NotEqual(t, webhooks.Sign("whsec_test", "1700000000", []byte("{}")), webhooks.Sign("whsec_test", "1700000001", []byte("{}")))
+	require.NotEqual(t, webhooks.Sign("whsec_test", "1700000000", []byte("{}")), webhooks.Sign("whsec_other", "1700000000", []byte("{}")))
+}
+
+func TestNotify(t *testing.T) {
+	ctx := context.Background()
+	userID := "user123"
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	message := &webhooks.Message{ID: "m1", Event: storage.WebhookPRAchieved, UserID: userID, CreatedAt: createdAt,
+		Data: webhooks.RecordData{UserID: userID, WorkoutID: "w1", ExerciseID: 1, Weight: 100, Reps: 5}}
+	// the user ID of the message is only used to find the webhooks
+	payload := `{"id":"m1","event":"pr.achieved","created_at":"2024-05-01T10:00:00Z",` +
+		`"data":{"user_id":"user123","workout_id":"w1","exercise_id":1,"weight":100,"reps":5}}`
+
+	t.Run("QueuesEveryMessage", func(t *testing.T) {
+		webhookRepo := mocks.NewWebhookRepository(t)
+		webhookRepo.On("AddDeliveries", ctx, &userID, &storage.WebhookDelivery{Event: storage.WebhookPRAchieved, Payload: payload,
+			NextAttemptAt: createdAt, CreatedAt: createdAt}).Return(2, nil).Twice()
+
+		require.NoError(t, webhooks.New(webhookRepo).Notify(ctx, []*webhooks.Message{message, message}))
+	})
+
+	t.Run("NoMessages", func(t *testing.T) {
+		webhookRepo := mocks.NewWebhookRepository(t)
+		require.NoError(t, webhooks.New(webhookRepo).Notify(ctx, nil))
+	})
+
+	t.Run("StorageError", func(t *testing.T) {
+		webhookRepo := mocks.NewWebhookRepository(t)
+		webhookRepo.On("AddDeliveries", ctx, &userID, mock.Anything).Return(0, errors.New("db error")).Once()
+
+		require.Error(t, webhooks.New(webhookRepo).Notify(ctx, []*webhooks.Message{message, message}))
+	})
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ChallengeScheduler settles the clan challenges that ended. It waits for the session lifetime after the end,
+// so workouts started in the window and still in progress are saved by the session scheduler first.
+type ChallengeScheduler struct {
+	challengeRepo storage.ChallengeRepository
+	checkInterval time.Duration
+	settleDelay   time.Duration
+	log           *slog.Logger
+}
+
+func NewChallengeScheduler(challengeRepo storage.ChallengeRepository, cfg *config.Config, log *slog.Logger) *ChallengeScheduler {
+	return &ChallengeScheduler{
+		challengeRepo: challengeRepo,
+		checkInterval: cfg.SchedulerInterval,
+		settleDelay:   cfg.SessionLifetime,
+		log:           log,
+	}
+}
+
+// Start runs the scheduler every check interval until ctx is done. A run in progress is finished first,
+// wg is done once the scheduler stopped.
+func (s *ChallengeScheduler) Start(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(s.checkInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				settled := s.settleEndedChallenges(time.Now())
+				if settled > 0 {
+					s.log.Info("Scheduler settleEndedChallenges finished", slog.Int("settled_challenges", settled))
+				}
+			}
+		}
+	}()
+}
+
+func (s *ChallengeScheduler) settleEndedChallenges(now time.Time) int {
+	ctx, span := tracing.Start(context.Background(), "services.ChallengeScheduler.settleEndedChallenges")
+	defer span.End()
+
+	challenges, err := s.challengeRepo.GetChallenges(ctx, false)
+	if err != nil {
+		s.log.Error("Scheduler cant GET challenges", slog.Any("error", err))
+		return 0
+	}
+
+	settled := 0
+	// challenges are ordered by end time, the rest ended later
+	for _, challenge := range challenges {
+		if now.Before(challenge.EndTime.Add(s.settleDelay)) {
+			break
+		}
+		results, err := s.challengeRepo.SettleChallenge(ctx, &challenge.ChallengeID, now)
+		if errors.Is(err, storage.ErrChallengeSettled) {
+			// another replica settled it
+			continue
+		}
+		if err != nil {
+			s.log.Error("Scheduler cant SETTLE challenge", slog.String("challenge_id", challenge.ChallengeID), slog.Any("error", err))
+			continue
+		}
+		metrics.ChallengesSettled.Inc()
+		settled++
+
+		completed := 0
+		for _, result := range results {
+			if result.Completed {
+				completed++
+			}
+		}
+		s.log.Info("Challenge settled", slog.String("challenge_id", challenge.ChallengeID), slog.Int("clans", len(results)), slog.Int("completed", completed))
+	}
+
+	return settled
+}
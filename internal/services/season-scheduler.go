@@ -0,0 +1,115 @@
+package services
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// seasonLock is held by the replica that rolls over the season, the others skip the run.
+const seasonLock = "season-rollover"
+
+// SeasonScheduler starts the first season and rolls over the current one once it ends. Every replica runs it,
+// the lock makes sure only one of them does the work, the conditional end of the season in the storage covers the rest.
+type SeasonScheduler struct {
+	seasonRepo    storage.SeasonRepository
+	locker        storage.Locker
+	checkInterval time.Duration
+	length        time.Duration
+	log           *slog.Logger
+}
+
+func NewSeasonScheduler(seasonRepo storage.SeasonRepository, locker storage.Locker, cfg *config.Config, log *slog.Logger) *SeasonScheduler {
+	return &SeasonScheduler{
+		seasonRepo:    seasonRepo,
+		locker:        locker,
+		checkInterval: cfg.SchedulerInterval,
+		length:        cfg.SeasonsCfg.Length,
+		log:           log,
+	}
+}
+
+// Start runs the scheduler every check interval until ctx is done. A run in progress is finished first,
+// wg is done once the scheduler stopped.
+func (s *SeasonScheduler) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if s.length <= 0 {
+		s.log.Warn("Season scheduler is not started, the season length is not positive")
+		return
+	}
+	ticker := time.NewTicker(s.checkInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+		// the first season should not wait for the first tick
+		s.rolloverSeason(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.rolloverSeason(time.Now())
+			}
+		}
+	}()
+}
+
+func (s *SeasonScheduler) rolloverSeason(now time.Time) {
+	ctx, span := tracing.Start(context.Background(), "services.SeasonScheduler.rolloverSeason")
+	defer span.End()
+
+	unlock, ok, err := s.locker.TryLock(ctx, seasonLock)
+	if err != nil {
+		s.log.Error("Scheduler cant take the season lock", slog.Any("error", err))
+		return
+	}
+	if !ok {
+		// another replica is on it
+		return
+	}
+	defer unlock()
+
+	now = now.UTC()
+	current, err := s.seasonRepo.GetCurrentSeason(ctx)
+	if errors.Is(err, storage.ErrSeasonNotFound) {
+		start := now.Truncate(24 * time.Hour)
+		first := &storage.Season{SeasonID: 1, StartTime: start, EndTime: start.Add(s.length)}
+		if err := s.seasonRepo.StartSeason(ctx, first); err != nil && !errors.Is(err, storage.ErrSeasonExists) {
+			s.log.Error("Scheduler cant START season", slog.Any("error", err))
+			return
+		}
+		s.log.Info("Season started", slog.Int("season_id", first.SeasonID), slog.Time("end_time", first.EndTime))
+		return
+	}
+	if err != nil {
+		s.log.Error("Scheduler cant GET current season", slog.Any("error", err))
+		return
+	}
+	if now.Before(current.EndTime) {
+		return
+	}
+
+	// seasons keep their length, a scheduler that was down for longer than a season skips the missed ones
+	start := current.EndTime
+	for !now.Before(start.Add(s.length)) {
+		start = start.Add(s.length)
+	}
+	next := &storage.Season{SeasonID: current.SeasonID + 1, StartTime: start, EndTime: start.Add(s.length)}
+	err = s.seasonRepo.RolloverSeason(ctx, current.SeasonID, now, next)
+	if errors.Is(err, storage.ErrSeasonEnded) || errors.Is(err, storage.ErrSeasonExists) {
+		// another replica rolled it over
+		return
+	}
+	if err != nil {
+		s.log.Error("Scheduler cant ROLLOVER season", slog.Int("season_id", current.SeasonID), slog.Any("error", err))
+		return
+	}
+	metrics.SeasonsRolledOver.Inc()
+	s.log.Info("Season rolled over", slog.Int("season_id", current.SeasonID), slog.Int("next_season_id", next.SeasonID), slog.Time("end_time", next.EndTime))
+}
@@ -2,44 +2,62 @@ package services
 
 import (
 	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/finalizer"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
 	"GYMBRO/internal/storage"
+	"context"
 	"log/slog"
+	"sync"
 	"time"
 )
 
 type SessionScheduler struct {
 	sessionRepo       storage.SessionRepository
-	workoutRepo       storage.WorkoutRepository
+	finalizer         *finalizer.Finalizer
 	checkInterval     time.Duration
 	inactivityTimeout time.Duration
 	log               *slog.Logger
 }
 
-func NewSessionScheduler(sessionRepo storage.SessionRepository, workoutRepo storage.WorkoutRepository, cfg *config.Config, log *slog.Logger) *SessionScheduler {
+func NewSessionScheduler(sessionRepo storage.SessionRepository, finalizer *finalizer.Finalizer, cfg *config.Config, log *slog.Logger) *SessionScheduler {
 	return &SessionScheduler{
 		sessionRepo:       sessionRepo,
-		workoutRepo:       workoutRepo,
+		finalizer:         finalizer,
 		checkInterval:     cfg.SchedulerInterval,
 		inactivityTimeout: cfg.SessionLifetime,
 		log:               log,
 	}
 }
 
-func (s *SessionScheduler) Start() {
+// Start runs the scheduler every check interval until ctx is done. A run in progress is finished first,
+// wg is done once the scheduler stopped.
+func (s *SessionScheduler) Start(ctx context.Context, wg *sync.WaitGroup) {
 	ticker := time.NewTicker(s.checkInterval)
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
 		for {
 			select {
+			case <-ctx.Done():
+				return
 			case <-ticker.C:
 				ended := s.processInactiveSessions(s.inactivityTimeout)
+				metrics.SchedulerEndedSessions.Observe(float64(ended))
 				s.log.Info("Scheduler processInactiveSessions finished", slog.Int("ended_sessions", ended))
 			}
 		}
 	}()
 }
 
+// processInactiveSessions finalizes the sessions not updated for inactivityDuration like the end handler does,
+// so they get their maxes, PR bonus, achievements, feed events and webhooks too.
 func (s *SessionScheduler) processInactiveSessions(inactivityDuration time.Duration) int {
-	sessions, err := s.sessionRepo.GetAllSessions()
+	ctx, span := tracing.Start(context.Background(), "services.SessionScheduler.processInactiveSessions")
+	defer span.End()
+
+	sessions, err := s.sessionRepo.GetAllSessions(ctx)
 	if err != nil {
 		s.log.Error("Scheduler cant GET sessions", slog.Any("error", err))
 	}
@@ -48,16 +66,11 @@ func (s *SessionScheduler) processInactiveSessions(inactivityDuration time.Durat
 
 	for _, session := range sessions {
 		if session != nil && time.Since(session.LastUpdated) > inactivityDuration {
-
-			err := s.workoutRepo.SaveWorkout(session)
-			if err != nil {
-				s.log.Error("Scheduler cant SAVE workout", slog.Any("error", err))
+			log := s.log.With(slog.String("user_id", session.UserID), slog.String("session_id", session.SessionID))
+			if err := s.finalizer.Finalize(ctx, log, session, metrics.SourceScheduler); err != nil {
+				log.Error("Scheduler cant FINALIZE workout", slog.Any("error", err))
 				continue
 			}
-			err = s.sessionRepo.DeleteSession(&session.UserID)
-			if err != nil {
-				s.log.Error("Scheduler cant DELETE session", slog.Any("error", err))
-			}
 			endedSessions++
 		}
 	}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/lib/webhooks"
+	"GYMBRO/internal/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// webhookLock is held by the replica that sends the due deliveries, the others skip the run so nothing is sent twice.
+const webhookLock = "webhook-delivery"
+
+// maxErrorLength caps the error kept on a failed delivery.
+const maxErrorLength = 512
+
+// errBlockedAddress fails the deliveries to addresses that are not public.
+var errBlockedAddress = errors.New("address is not public")
+
+// blockedPrefixes are the ranges netip has no check for: this network, shared address space (carrier-grade NAT,
+// some cloud metadata services), IETF protocol assignments, benchmarking and reserved addresses.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// WebhookDispatcher sends the due webhook deliveries. A 2xx response delivers them, anything else is retried
+// with exponential backoff until the attempts run out and the delivery is dead. A receiver asking to wait longer
+// with Retry-After is waited for, up to the max backoff.
+type WebhookDispatcher struct {
+	webhookRepo   storage.WebhookRepository
+	locker        storage.Locker
+	client        *http.Client
+	checkInterval time.Duration
+	batchSize     int
+	maxAttempts   int
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	log           *slog.Logger
+}
+
+func NewWebhookDispatcher(webhookRepo storage.WebhookRepository, locker storage.Locker, cfg *config.Config, log *slog.Logger) *WebhookDispatcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address checked, deliveries go straight to the receiver
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: checkAddress}).DialContext
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		locker:      locker,
+		client: &http.Client{
+			Timeout:   cfg.WebhooksCfg.RequestTimeout,
+			Transport: transport,
+			// a redirect is a failed delivery, the receiver should register the final URL
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		checkInterval: cfg.WebhooksCfg.Interval,
+		batchSize:     cfg.WebhooksCfg.BatchSize,
+		maxAttempts:   cfg.WebhooksCfg.DeliveryAttempts,
+		backoff:       cfg.WebhooksCfg.Backoff,
+		maxBackoff:    cfg.WebhooksCfg.MaxBackoff,
+		log:           log,
+	}
+}
+
+// Start sends the due deliveries every check interval until ctx is done. A batch in progress is finished first,
+// wg is done once the dispatcher stopped.
+func (d *WebhookDispatcher) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if d.checkInterval <= 0 || d.batchSize <= 0 {
+		d.log.Warn("Webhook dispatcher is not started, the interval or batch size is not positive")
+		return
+	}
+	ticker := time.NewTicker(d.checkInterval)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.dispatch(time.Now())
+			}
+		}
+	}()
+}
+
+func (d *WebhookDispatcher) dispatch(now time.Time) {
+	ctx, span := tracing.Start(context.Background(), "services.WebhookDispatcher.dispatch")
+	defer span.End()
+
+	unlock, ok, err := d.locker.TryLock(ctx, webhookLock)
+	if err != nil {
+		d.log.Error("Dispatcher cant take the webhook lock", slog.Any("error", err))
+		return
+	}
+	if !ok {
+		// another replica is on it
+		return
+	}
+	defer unlock()
+
+	deliveries, err := d.webhookRepo.GetDueDeliveries(ctx, now.UTC(), d.batchSize)
+	if err != nil {
+		d.log.Error("Dispatcher cant GET due deliveries", slog.Any("error", err))
+		return
+	}
+
+	for _, pending := range deliveries {
+		delivery := &pending.WebhookDelivery
+		statusCode, requested, err := d.send(ctx, pending)
+		attemptedAt := time.Now().UTC()
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		result := metrics.WebhookDelivered
+		switch {
+		case err == nil:
+			delivery.Status = storage.DeliveryDelivered
+			delivery.LastError = ""
+			delivery.DeliveredAt = &attemptedAt
+		case delivery.Attempts >= d.maxAttempts:
+			delivery.Status = storage.DeliveryDead
+			delivery.LastError = truncate(err.Error(), maxErrorLength)
+			result = metrics.WebhookDead
+		default:
+			delivery.LastError = truncate(err.Error(), maxErrorLength)
+			wait := d.retryAfter(delivery.Attempts)
+			if requested > wait {
+				wait = min(requested, d.maxBackoff)
+			}
+			delivery.NextAttemptAt = attemptedAt.Add(wait)
+			result = metrics.WebhookRetry
+		}
+		metrics.WebhookDeliveries.WithLabelValues(result).Inc()
+
+		if err := d.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			d.log.Error("Dispatcher cant UPDATE delivery", slog.String("delivery_id", delivery.DeliveryID), slog.Any("error", err))
+			continue
+		}
+		if delivery.Status == storage.DeliveryDead {
+			d.log.Warn("Webhook delivery is dead", slog.String("delivery_id", delivery.DeliveryID),
+				slog.String("webhook_id", delivery.WebhookID), slog.String("error", delivery.LastError))
+		}
+	}
+	if len(deliveries) > 0 {
+		d.log.Info("Dispatcher sent webhook deliveries", slog.Int("deliveries", len(deliveries)))
+	}
+}
+
+// send posts the payload signed with the secret of the webhook. It returns the status code of the response, 0 without one,
+// how long the response asks to wait before the next attempt with Retry-After, and an error unless the status code is 2xx.
+func (d *WebhookDispatcher) send(ctx context.Context, pending *storage.PendingDelivery) (int, time.Duration, error) {
+	body := []byte(pending.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pending.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GYMBRO-Webhooks")
+	req.Header.Set(webhooks.HeaderEvent, pending.Event)
+	req.Header.Set(webhooks.HeaderDelivery, pending.DeliveryID)
+	req.Header.Set(webhooks.HeaderTimestamp, timestamp)
+	req.Header.Set(webhooks.HeaderSignature, "sha256="+webhooks.Sign(pending.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer res.Body.Close()
+	// drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, parseRetryAfter(res.Header.Get("Retry-After"), time.Now()), fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return res.StatusCode, 0, nil
+}
+
+// parseRetryAfter returns the wait a Retry-After header asks for, in seconds or as an HTTP date. It is 0 for no or a malformed header.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// checkAddress refuses to connect to loopback, private, link-local (the 169.254.169.254 metadata service among them),
+// multicast, unspecified and the other non-public addresses, so webhooks can't reach the internal network.
+// It runs for the resolved address of every connection, a host name that resolves to such an address later is refused too.
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", errBlockedAddress, ip)
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", errBlockedAddress, ip)
+		}
+	}
+	return nil
+}
+
+// retryAfter is the backoff doubled for every attempt after the first, capped at the max backoff.
+func (d *WebhookDispatcher) retryAfter(attempts int) time.Duration {
+	wait := d.backoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return min(wait, d.maxBackoff)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"GYMBRO/internal/config"
+	"GYMBRO/internal/lib/webhooks"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/mocks"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestDispatcher(webhookRepo storage.WebhookRepository, locker storage.Locker) *WebhookDispatcher {
+	cfg := &config.Config{WebhooksCfg: config.WebhooksCfg{Interval: time.Minute, RequestTimeout: 5 * time.Second, BatchSize: 10,
+		DeliveryAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}}
+	return NewWebhookDispatcher(webhookRepo, locker, cfg, slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+}
+
+func TestDispatch(t *testing.T) {
+	const secret = "whsec_test"
+	payload := `{"id":"d1","event":"workout.ended"}`
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		attempts   int
+		// expected is the delivery as it is updated, NextAttemptAt and DeliveredAt are checked by wait
+		expected *storage.WebhookDelivery
+		// wait is how long after the attempt the next one is due, 0 when delivered or dead
+		wait time.Duration
+	}{
+		{
+			name:     "Delivered",
+			status:   http.StatusNoContent,
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryDelivered, Attempts: 1, LastStatusCode: http.StatusNoContent},
+		},
+		{
+			name:     "RetriedWithBackoff",
+			status:   http.StatusInternalServerError,
+			attempts: 1,
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryPending, Attempts: 2, LastStatusCode: http.StatusInternalServerError,
+				LastError: "unexpected status 500 Internal Server Error"},
+			wait: 2 * time.Minute,
+		},
+		{
+			name:       "RetryAfterLongerThanBackoff",
+			status:     http.StatusServiceUnavailable,
+			retryAfter: "600",
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryPending, Attempts: 1, LastStatusCode: http.StatusServiceUnavailable,
+				LastError: "unexpected status 503 Service Unavailable"},
+			wait: 10 * time.Minute,
+		},
+		{
+			name:       "RetryAfterShorterThanBackoff",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "5",
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryPending, Attempts: 1, LastStatusCode: http.StatusTooManyRequests,
+				LastError: "unexpected status 429 Too Many Requests"},
+			wait: time.Minute,
+		},
+		{
+			name:       "RetryAfterCapped",
+			status:     http.StatusServiceUnavailable,
+			retryAfter: "86400",
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryPending, Attempts: 1, LastStatusCode: http.StatusServiceUnavailable,
+				LastError: "unexpected status 503 Service Unavailable"},
+			wait: time.Hour,
+		},
+		{
+			name:     "DeadAfterLastAttempt",
+			status:   http.StatusBadGateway,
+			attempts: 2,
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryDead, Attempts: 3, LastStatusCode: http.StatusBadGateway,
+				LastError: "unexpected status 502 Bad Gateway"},
+		},
+		{
+			name:     "RedirectFails",
+			status:   http.StatusFound,
+			expected: &storage.WebhookDelivery{Status: storage.DeliveryPending, Attempts: 1, LastStatusCode: http.StatusFound, LastError: "unexpected status 302 Found"},
+			wait:     time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received.Add(1)
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, payload, string(body))
+				require.Equal(t, storage.WebhookWorkoutEnded, r.Header.Get(webhooks.HeaderEvent))
+				require.Equal(t, "d1", r.Header.Get(webhooks.HeaderDelivery))
+				timestamp := r.Header.Get(webhooks.HeaderTimestamp)
+				sent, err := strconv.ParseInt(timestamp, 10, 64)
+				require.NoError(t, err)
+				require.WithinDuration(t, time.Now(), time.Unix(sent, 0), time.Minute)
+				require.Equal(t, "sha256="+webhooks.Sign(secret, timestamp, body), r.Header.Get(webhooks.HeaderSignature))
+
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(srv.Close)
+
+			webhookRepo := mocks.NewWebhookRepository(t)
+			locker := mocks.NewLocker(t)
+			dispatcher := newTestDispatcher(webhookRepo, locker)
+			// the receiver listens on loopback, the tests of checkAddress cover the guard
+			dispatcher.client.Transport = srv.Client().Transport
+
+			now := time.Now()
+			pending := &storage.PendingDelivery{
+				WebhookDelivery: storage.WebhookDelivery{DeliveryID: "d1", WebhookID: "wh1", Event: storage.WebhookWorkoutEnded, Payload: payload,
+					Status: storage.DeliveryPending, Attempts: tt.attempts, NextAttemptAt: now},
+				URL:    srv.URL + "/hooks",
+				Secret: secret,
+			}
+			var unlocked bool
+			locker.On("TryLock", mock.Anything, webhookLock).Return(func() { unlocked = true }, true, nil)
+			webhookRepo.On("GetDueDeliveries", mock.Anything, now.UTC(), 10).Return([]*storage.PendingDelivery{pending}, nil)
+			var updated *storage.WebhookDelivery
+			webhookRepo.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(*storage.WebhookDelivery)
+			}).Return(nil)
+
+			dispatcher.dispatch(now)
+			attemptedAt := time.Now()
+
+			require.Equal(t, int32(1), received.Load())
+			require.True(t, unlocked)
+			require.NotNil(t, updated)
+			require.Equal(t, "d1", updated.DeliveryID)
+			require.Equal(t, tt.expected.Status, updated.Status)
+			require.Equal(t, tt.expected.Attempts, updated.Attempts)
+			require.Equal(t, tt.expected.LastStatusCode, updated.LastStatusCode)
+			require.Equal(t, tt.expected.LastError, updated.LastError)
+			switch updated.Status {
+			case storage.DeliveryDelivered:
+				require.NotNil(t, updated.DeliveredAt)
+				require.WithinDuration(t, attemptedAt, *updated.DeliveredAt, 5*time.Second)
+			case storage.DeliveryDead:
+				require.Nil(t, updated.DeliveredAt)
+				require.Equal(t, now, updated.NextAttemptAt, "a dead delivery is not scheduled again")
+			default:
+				require.Nil(t, updated.DeliveredAt)
+				require.WithinDuration(t, attemptedAt.Add(tt.wait), updated.NextAttemptAt, 5*time.Second)
+			}
+		})
+	}
+}
+
+func TestDispatchLockHeldElsewhere(t *testing.T) {
+	webhookRepo := mocks.NewWebhookRepository(t)
+	locker := mocks.NewLocker(t)
+	locker.On("TryLock", mock.Anything, webhookLock).Return(nil, false, nil)
+
+	// nothing is read or sent while another replica holds the lock
+	newTestDispatcher(webhookRepo, locker).dispatch(time.Now())
+}
+
+func TestDispatchBlockedAddress(t *testing.T) {
+	var received atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+
+	webhookRepo := mocks.NewWebhookRepository(t)
+	locker := mocks.NewLocker(t)
+	now := time.Now()
+	pending := &storage.PendingDelivery{
+		WebhookDelivery: storage.WebhookDelivery{DeliveryID: "d1", WebhookID: "wh1", Event: storage.WebhookWorkoutEnded, Payload: "{}",
+			Status: storage.DeliveryPending, NextAttemptAt: now},
+		URL:    srv.URL,
+		Secret: "whsec_test",
+	}
+	locker.On("TryLock", mock.Anything, webhookLock).Return(func() {}, true, nil)
+	webhookRepo.On("GetDueDeliveries", mock.Anything, now.UTC(), 10).Return([]*storage.PendingDelivery{pending}, nil)
+	var updated *storage.WebhookDelivery
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*storage.WebhookDelivery)
+	}).Return(nil)
+
+	// the dispatcher's own client refuses the receiver on loopback before anything is sent
+	newTestDispatcher(webhookRepo, locker).dispatch(now)
+
+	require.Zero(t, received.Load())
+	require.NotNil(t, updated)
+	require.Equal(t, storage.DeliveryPending, updated.Status)
+	require.Equal(t, 1, updated.Attempts)
+	require.Zero(t, updated.LastStatusCode)
+	require.Contains(t, updated.LastError, errBlockedAddress.Error())
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		allowed bool
+	}{
+		{name: "Loopback", address: "127.0.0.1:443"},
+		{name: "LoopbackRange", address: "127.10.0.1:443"},
+		{name: "LoopbackIPv6", address: "[::1]:443"},
+		{name: "RFC1918ClassA", address: "10.1.2.3:443"},
+		{name: "RFC1918ClassB", address: "172.16.0.1:443"},
+		{name: "RFC1918ClassC", address: "192.168.1.1:443"},
+		{name: "UniqueLocalIPv6", address: "[fd00:ec2::254]:80"},
+		{name: "Metadata", address: "169.254.169.254:80"},
+		{name: "LinkLocal", address: "169.254.1.1:443"},
+		{name: "LinkLocalIPv6", address: "[fe80::1]:443"},
+		{name: "IPv4MappedLoopback", address: "[::ffff:127.0.0.1]:443"},
+		{name: "IPv4MappedMetadata", address: "[::ffff:169.254.169.254]:80"},
+		{name: "IPv4MappedPrivate", address: "[::ffff:10.0.0.1]:443"},
+		{name: "Unspecified", address: "0.0.0.0:443"},
+		{name: "UnspecifiedIPv6", address: "[::]:443"},
+		{name: "SharedAddressSpace", address: "100.100.100.200:80"},
+		{name: "Multicast", address: "224.0.0.1:443"},
+		{name: "Broadcast", address: "255.255.255.255:443"},
+		{name: "Public", address: "93.184.216.34:443", allowed: true},
+		{name: "PublicIPv6", address: "[2606:4700:4700::1111]:443", allowed: true},
+		{name: "IPv4MappedPublic", address: "[::ffff:93.184.216.34]:443", allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAddress("tcp", tt.address, nil)
+			if tt.allowed {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, errors.Is(err, errBlockedAddress), "%s is allowed: %v", tt.address, err)
+		})
+	}
+
+	require.Error(t, checkAddress("tcp", "not an address", nil))
+}
+
+func TestRetryAfter(t *testing.T) {
+	dispatcher := newTestDispatcher(nil, nil)
+	for attempts, expected := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		6:  32 * time.Minute,
+		7:  time.Hour,
+		50: time.Hour,
+	} {
+		require.Equal(t, expected, dispatcher.retryAfter(attempts), "attempts %d", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "120", expected: 2 * time.Minute},
+		{value: "0", expected: 0},
+		{value: "-5", expected: 0},
+		{value: "Wed, 01 May 2024 10:05:00 GMT", expected: 5 * time.Minute},
+		{value: "Wed, 01 May 2024 09:00:00 GMT", expected: 0},
+		{value: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, parseRetryAfter(tt.value, now), "Retry-After %q", tt.value)
+	}
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+	"time"
+)
+
+// AwardAchievement stores the achievement unless the user already has it.
+func (s *Storage) AwardAchievement(_ context.Context, achievement *storage.Achievement) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.award(achievement.UserID, achievement.Code, achievement.AwardedAt), nil
+}
+
+// GetAchievements returns user's achievements ordered by award time.
+func (s *Storage) GetAchievements(_ context.Context, userID *string) ([]*storage.Achievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var achievements []*storage.Achievement
+	for code, awardedAt := range s.achievements[*userID] {
+		achievements = append(achievements, &storage.Achievement{UserID: *userID, Code: code, AwardedAt: awardedAt})
+	}
+	sort.Slice(achievements, func(i, j int) bool {
+		if !achievements[i].AwardedAt.Equal(achievements[j].AwardedAt) {
+			return achievements[i].AwardedAt.Before(achievements[j].AwardedAt)
+		}
+		return achievements[i].Code < achievements[j].Code
+	})
+	return achievements, nil
+}
+
+// award keeps the earliest award time of the code, it reports whether the user did not have it. s.mu must be held.
+func (s *Storage) award(userID, code string, awardedAt time.Time) bool {
+	awarded, ok := s.achievements[userID]
+	if !ok {
+		awarded = make(map[string]time.Time)
+		s.achievements[userID] = awarded
+	}
+	current, ok := awarded[code]
+	if !ok || awardedAt.Before(current) {
+		awarded[code] = awardedAt
+	}
+	return !ok
+}
@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CreateChallenge stores a challenge.
+func (s *Storage) CreateChallenge(_ context.Context, challenge *storage.Challenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if challenge.Goal == storage.GoalTonnage {
+		if _, ok := s.exercises[challenge.FkExerciseId]; !ok {
+			return storage.ErrExerciseNotFound
+		}
+	}
+	s.challenges[challenge.ChallengeID] = copyChallenge(challenge)
+	return nil
+}
+
+// GetChallenge retrieves a challenge by its ID.
+func (s *Storage) GetChallenge(_ context.Context, challengeID *string) (*storage.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	challenge, ok := s.challenges[*challengeID]
+	if !ok {
+		return nil, storage.ErrChallengeNotFound
+	}
+	return copyChallenge(challenge), nil
+}
+
+// GetChallenges retrieves the challenges not settled yet ordered by end time, or the settled ones, the last ended first.
+func (s *Storage) GetChallenges(_ context.Context, settled bool) ([]*storage.Challenge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var challenges []*storage.Challenge
+	for _, challenge := range s.challenges {
+		if (challenge.SettledAt != nil) == settled {
+			challenges = append(challenges, copyChallenge(challenge))
+		}
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		a, b := challenges[i], challenges[j]
+		if !a.EndTime.Equal(b.EndTime) {
+			return a.EndTime.Before(b.EndTime) != settled
+		}
+		return a.ChallengeID < b.ChallengeID
+	})
+	return challenges, nil
+}
+
+// GetChallengeProgress computes the live progress of the clans in the challenge.
+func (s *Storage) GetChallengeProgress(_ context.Context, challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	const op = "storage.memory.GetChallengeProgress"
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	results, err := s.challengeProgress(challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+// challengeProgress sums the workouts started in the challenge window per clan of their users, mu has to be held.
+// Users in the default clan are not in a clan and are left out.
+func (s *Storage) challengeProgress(challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	switch challenge.Goal {
+	case storage.GoalPoints, storage.GoalWorkouts, storage.GoalTonnage:
+	default:
+		return nil, fmt.Errorf("unknown goal %q", challenge.Goal)
+	}
+
+	byClan := make(map[string]*storage.ChallengeResult)
+	var results []*storage.ChallengeResult
+	for _, workout := range s.workouts {
+		if workout.StartTime.Before(challenge.StartTime) || !workout.StartTime.Before(challenge.EndTime) {
+			continue
+		}
+		user, ok := s.users[workout.UserID]
+		if !ok || user.FkClanId == "" || user.FkClanId == storage.DefaultClanID {
+			continue
+		}
+
+		var progress int
+		switch challenge.Goal {
+		case storage.GoalPoints:
+			progress = workout.Points
+		case storage.GoalWorkouts:
+			progress = 1
+		case storage.GoalTonnage:
+			for _, record := range workout.Records {
+				if record.FkExerciseId == challenge.FkExerciseId && record.Counts() {
+					progress += record.Reps * record.Weight
+				}
+			}
+		}
+
+		result, ok := byClan[user.FkClanId]
+		if !ok {
+			result = &storage.ChallengeResult{FkChallengeId: challenge.ChallengeID, FkClanId: user.FkClanId}
+			byClan[user.FkClanId] = result
+		}
+		result.Progress += progress
+	}
+
+	for _, result := range byClan {
+		if result.Progress > 0 {
+			results = append(results, result)
+		}
+	}
+	storage.RankResults(challenge, results)
+	return results, nil
+}
+
+// SettleChallenge stores the final progress of the clans and awards the reward.
+func (s *Storage) SettleChallenge(_ context.Context, challengeID *string, settledAt time.Time) ([]*storage.ChallengeResult, error) {
+	const op = "storage.memory.SettleChallenge"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	challenge, ok := s.challenges[*challengeID]
+	if !ok {
+		return nil, storage.ErrChallengeNotFound
+	}
+	if challenge.SettledAt != nil {
+		return nil, storage.ErrChallengeSettled
+	}
+
+	results, err := s.challengeProgress(challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, result := range results {
+		if !result.Completed {
+			continue
+		}
+		result.Points = challenge.Reward
+		clan, ok := s.clans[result.FkClanId]
+		if !ok {
+			clan = &storage.Clan{ClanId: result.FkClanId}
+			s.clans[result.FkClanId] = clan
+		}
+		clan.Points += result.Points
+		clan.SeasonPoints += result.Points
+	}
+
+	settledAt = settledAt.UTC()
+	challenge.SettledAt = &settledAt
+	s.challengeResults[challenge.ChallengeID] = copyResults(results)
+	return results, nil
+}
+
+// GetChallengeResults retrieves the stored results of a settled challenge, ranked.
+func (s *Storage) GetChallengeResults(_ context.Context, challengeID *string) ([]*storage.ChallengeResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyResults(s.challengeResults[*challengeID]), nil
+}
+
+// GetClanResults retrieves the results of the clan in settled challenges, the last ended first.
+func (s *Storage) GetClanResults(_ context.Context, clanID *string) ([]*storage.ChallengeResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var results []*storage.ChallengeResult
+	for _, challengeResults := range s.challengeResults {
+		for _, result := range challengeResults {
+			if result.FkClanId == *clanID {
+				c := *result
+				results = append(results, &c)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := s.challenges[results[i].FkChallengeId], s.challenges[results[j].FkChallengeId]
+		if !a.EndTime.Equal(b.EndTime) {
+			return a.EndTime.After(b.EndTime)
+		}
+		return a.ChallengeID < b.ChallengeID
+	})
+	return results, nil
+}
+
+// GetClan retrieves a clan with its points. Only the default clan and clans that got points exist.
+func (s *Storage) GetClan(_ context.Context, clanID *string) (*storage.Clan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	clan, ok := s.clans[*clanID]
+	if !ok {
+		return nil, storage.ErrClanNotFound
+	}
+	c := *clan
+	return &c, nil
+}
+
+func copyChallenge(challenge *storage.Challenge) *storage.Challenge {
+	c := *challenge
+	if challenge.SettledAt != nil {
+		settledAt := *challenge.SettledAt
+		c.SettledAt = &settledAt
+	}
+	return &c
+}
+
+func copyResults(results []*storage.ChallengeResult) []*storage.ChallengeResult {
+	copies := make([]*storage.ChallengeResult, 0, len(results))
+	for _, result := range results {
+		c := *result
+		copies = append(copies, &c)
+	}
+	return copies
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+	"time"
+)
+
+// AddEvents stores copies of the events, all or none of them
+func (s *Storage) AddEvents(_ context.Context, events []*storage.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		if _, ok := s.users[event.UserID]; !ok {
+			return storage.ErrUserNotFound
+		}
+	}
+	for _, event := range events {
+		stored := *event
+		s.events = append(s.events, &stored)
+	}
+	return nil
+}
+
+// GetFeed returns events of the users the user follows, newest first. The page starts after the cursor.
+func (s *Storage) GetFeed(_ context.Context, userID *string, cursor *storage.FeedCursor, limit int) ([]*storage.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var feed []*storage.Event
+	for _, event := range s.events {
+		follow, ok := s.follows[followKey{follower: *userID, followee: event.UserID}]
+		if !ok || follow.Status != storage.FollowAccepted {
+			continue
+		}
+		if cursor != nil && !newer(cursor.CreatedAt, cursor.EventID, event) {
+			continue
+		}
+		copied := *event
+		feed = append(feed, &copied)
+	}
+	sort.Slice(feed, func(i, j int) bool {
+		return newer(feed[i].CreatedAt, feed[i].EventID, feed[j])
+	})
+	if len(feed) > limit {
+		feed = feed[:limit]
+	}
+	return feed, nil
+}
+
+// newer reports whether the position (createdAt, eventID) comes before the event in the feed order.
+func newer(createdAt time.Time, eventID string, event *storage.Event) bool {
+	if !createdAt.Equal(event.CreatedAt) {
+		return createdAt.After(event.CreatedAt)
+	}
+	return eventID > event.EventID
+}
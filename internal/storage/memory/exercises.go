@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+)
+
+// GetExercises retrieves all exercises ordered by ID
+func (s *Storage) GetExercises(_ context.Context) ([]*storage.Exercise, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	exercises := make([]*storage.Exercise, 0, len(s.exercises))
+	for _, exercise := range s.exercises {
+		exercises = append(exercises, copyExercise(exercise))
+	}
+	sort.Slice(exercises, func(i, j int) bool {
+		return exercises[i].ExerciseId < exercises[j].ExerciseId
+	})
+	return exercises, nil
+}
+
+// GetExercise retrieves an exercise by its ID
+func (s *Storage) GetExercise(_ context.Context, exerciseID int) (*storage.Exercise, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	exercise, ok := s.exercises[exerciseID]
+	if !ok {
+		return nil, storage.ErrExerciseNotFound
+	}
+	return copyExercise(exercise), nil
+}
+
+// CreateExercise creates an exercise and returns its ID, the name must be unique and muscle groups must exist
+func (s *Storage) CreateExercise(_ context.Context, exercise *storage.Exercise) (*int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkExercise(exercise); err != nil {
+		return nil, err
+	}
+	s.nextExerciseID++
+	created := copyExercise(exercise)
+	created.ExerciseId = s.nextExerciseID
+	s.exercises[created.ExerciseId] = created
+	id := created.ExerciseId
+	return &id, nil
+}
+
+// UpdateExercise updates an exercise and replaces its muscle groups
+func (s *Storage) UpdateExercise(_ context.Context, exercise *storage.Exercise) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.exercises[exercise.ExerciseId]; !ok {
+		return storage.ErrExerciseNotFound
+	}
+	if err := s.checkExercise(exercise); err != nil {
+		return err
+	}
+	s.exercises[exercise.ExerciseId] = copyExercise(exercise)
+	return nil
+}
+
+// DeleteExercise deletes an exercise unless records, maxes or challenges reference it
+func (s *Storage) DeleteExercise(_ context.Context, exerciseID *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.exercises[*exerciseID]; !ok {
+		return storage.ErrExerciseNotFound
+	}
+	for _, maxes := range s.maxes {
+		if _, ok := maxes[*exerciseID]; ok {
+			return storage.ErrExerciseInUse
+		}
+	}
+	for _, workout := range s.workouts {
+		for _, record := range workout.Records {
+			if record.FkExerciseId == *exerciseID {
+				return storage.ErrExerciseInUse
+			}
+		}
+	}
+	for _, challenge := range s.challenges {
+		if challenge.FkExerciseId == *exerciseID {
+			return storage.ErrExerciseInUse
+		}
+	}
+	delete(s.exercises, *exerciseID)
+	return nil
+}
+
+// checkExercise checks that the name is not taken by another exercise and all muscle groups exist
+func (s *Storage) checkExercise(exercise *storage.Exercise) error {
+	for _, existing := range s.exercises {
+		if existing.Name == exercise.Name && existing.ExerciseId != exercise.ExerciseId {
+			return storage.ErrExerciseExists
+		}
+	}
+	for _, groupID := range exercise.MuscleGroupIds {
+		if _, ok := s.muscleGroups[groupID]; !ok {
+			return storage.ErrMuscleGroupNotFound
+		}
+	}
+	return nil
+}
+
+// GetMuscleGroups retrieves all muscle groups ordered by ID
+func (s *Storage) GetMuscleGroups(_ context.Context) ([]*storage.MuscleGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	groups := make([]*storage.MuscleGroup, 0, len(s.muscleGroups))
+	for _, group := range s.muscleGroups {
+		c := *group
+		groups = append(groups, &c)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].MuscleGroupId < groups[j].MuscleGroupId
+	})
+	return groups, nil
+}
+
+// CreateMuscleGroup creates a muscle group and returns its ID
+func (s *Storage) CreateMuscleGroup(_ context.Context, group *storage.MuscleGroup) (*int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.muscleGroupNameTaken(group.Name, 0) {
+		return nil, storage.ErrMuscleGroupExists
+	}
+	s.nextMuscleGroupID++
+	id := s.nextMuscleGroupID
+	s.muscleGroups[id] = &storage.MuscleGroup{MuscleGroupId: id, Name: group.Name}
+	return &id, nil
+}
+
+// UpdateMuscleGroup renames a muscle group
+func (s *Storage) UpdateMuscleGroup(_ context.Context, group *storage.MuscleGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.muscleGroups[group.MuscleGroupId]
+	if !ok {
+		return storage.ErrMuscleGroupNotFound
+	}
+	if s.muscleGroupNameTaken(group.Name, group.MuscleGroupId) {
+		return storage.ErrMuscleGroupExists
+	}
+	existing.Name = group.Name
+	return nil
+}
+
+// DeleteMuscleGroup deletes a muscle group and unlinks it from exercises
+func (s *Storage) DeleteMuscleGroup(_ context.Context, groupID *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.muscleGroups[*groupID]; !ok {
+		return storage.ErrMuscleGroupNotFound
+	}
+	delete(s.muscleGroups, *groupID)
+	for _, exercise := range s.exercises {
+		groups := exercise.MuscleGroupIds[:0]
+		for _, id := range exercise.MuscleGroupIds {
+			if id != *groupID {
+				groups = append(groups, id)
+			}
+		}
+		exercise.MuscleGroupIds = groups
+	}
+	return nil
+}
+
+func (s *Storage) muscleGroupNameTaken(name string, exceptID int) bool {
+	for _, group := range s.muscleGroups {
+		if group.Name == name && group.MuscleGroupId != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyExercise copies the exercise, dropping duplicated muscle groups and keeping them sorted like Postgres returns them
+func copyExercise(exercise *storage.Exercise) *storage.Exercise {
+	c := *exercise
+	c.MuscleGroupIds = make([]int, 0, len(exercise.MuscleGroupIds))
+	seen := make(map[int]bool, len(exercise.MuscleGroupIds))
+	for _, id := range exercise.MuscleGroupIds {
+		if !seen[id] {
+			seen[id] = true
+			c.MuscleGroupIds = append(c.MuscleGroupIds, id)
+		}
+	}
+	sort.Ints(c.MuscleGroupIds)
+	return &c
+}
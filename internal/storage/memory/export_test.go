@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+)
+
+// JoinNewClan puts the user in a new clan, the storage has no way to create clans.
+func (s *Storage) JoinNewClan(_ context.Context, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[userID]
+	if !ok {
+		return "", storage.ErrUserNotFound
+	}
+	clanID := storage.GenerateUID()
+	s.clans[clanID] = &storage.Clan{ClanId: clanID, Name: "clan-" + clanID}
+	user.FkClanId = clanID
+	return clanID, nil
+}
+
+// AwardClan adds points to the clan the way a settled challenge does.
+func (s *Storage) AwardClan(_ context.Context, clanID string, points int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clan, ok := s.clans[clanID]
+	if !ok {
+		return storage.ErrClanNotFound
+	}
+	clan.Points += points
+	clan.SeasonPoints += points
+	return nil
+}
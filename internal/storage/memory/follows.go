@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+)
+
+// Follow stores a follow request with its status
+func (s *Storage) Follow(_ context.Context, follow *storage.Follow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[follow.FollowerID]; !ok {
+		return storage.ErrUserNotFound
+	}
+	if _, ok := s.users[follow.FolloweeID]; !ok {
+		return storage.ErrUserNotFound
+	}
+	key := followKey{follower: follow.FollowerID, followee: follow.FolloweeID}
+	if _, ok := s.follows[key]; ok {
+		return storage.ErrFollowExists
+	}
+	stored := *follow
+	s.follows[key] = &stored
+	return nil
+}
+
+// Unfollow deletes the follow, pending or accepted
+func (s *Storage) Unfollow(_ context.Context, followerID *string, followeeID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := followKey{follower: *followerID, followee: *followeeID}
+	if _, ok := s.follows[key]; !ok {
+		return storage.ErrFollowNotFound
+	}
+	delete(s.follows, key)
+	return nil
+}
+
+// AcceptFollow accepts a pending follow request
+func (s *Storage) AcceptFollow(_ context.Context, followerID *string, followeeID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	follow, ok := s.follows[followKey{follower: *followerID, followee: *followeeID}]
+	if !ok || follow.Status != storage.FollowPending {
+		return storage.ErrFollowNotFound
+	}
+	follow.Status = storage.FollowAccepted
+	return nil
+}
+
+// GetFollowers returns follows to the user, newest first
+func (s *Storage) GetFollowers(_ context.Context, userID *string) ([]*storage.Follow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getFollows(func(key followKey) bool { return key.followee == *userID }), nil
+}
+
+// GetFollowing returns follows of the user, newest first
+func (s *Storage) GetFollowing(_ context.Context, userID *string) ([]*storage.Follow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.getFollows(func(key followKey) bool { return key.follower == *userID }), nil
+}
+
+// getFollows copies the matching follows, s.mu must be held.
+func (s *Storage) getFollows(match func(followKey) bool) []*storage.Follow {
+	var follows []*storage.Follow
+	for key, follow := range s.follows {
+		if match(key) {
+			copied := *follow
+			follows = append(follows, &copied)
+		}
+	}
+	sort.Slice(follows, func(i, j int) bool {
+		if !follows[i].CreatedAt.Equal(follows[j].CreatedAt) {
+			return follows[i].CreatedAt.After(follows[j].CreatedAt)
+		}
+		if follows[i].FollowerID != follows[j].FollowerID {
+			return follows[i].FollowerID < follows[j].FollowerID
+		}
+		return follows[i].FolloweeID < follows[j].FolloweeID
+	})
+	return follows
+}
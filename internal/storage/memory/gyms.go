@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"time"
+)
+
+// CreateSubscription stores a subscription of the user to the gym. There are no gyms, any gym ID is accepted.
+func (s *Storage) CreateSubscription(_ context.Context, subscription *storage.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[subscription.FkUserId]; !ok {
+		return storage.ErrUserNotFound
+	}
+	stored := *subscription
+	stored.StartDate, stored.EndDate = day(subscription.StartDate), day(subscription.EndDate)
+	s.subscriptions = append(s.subscriptions, &stored)
+	return nil
+}
+
+// GetActiveSubscription retrieves the subscription of the user to the gym covering the day of at, the one ending last
+func (s *Storage) GetActiveSubscription(_ context.Context, userID *string, gymID int, at time.Time) (*storage.Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	today := day(at)
+	var active *storage.Subscription
+	for _, subscription := range s.subscriptions {
+		if subscription.FkUserId != *userID || subscription.FkGymId != gymID ||
+			subscription.StartDate.After(today) || subscription.EndDate.Before(today) {
+			continue
+		}
+		if active == nil || subscription.EndDate.After(active.EndDate) ||
+			(subscription.EndDate.Equal(active.EndDate) && subscription.SubscriptionId < active.SubscriptionId) {
+			active = subscription
+		}
+	}
+	if active == nil {
+		return nil, storage.ErrNoSubscription
+	}
+	c := *active
+	return &c, nil
+}
+
+// CreateCheckIn stores a check-in
+func (s *Storage) CreateCheckIn(_ context.Context, checkIn *storage.CheckIn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *checkIn
+	s.checkIns = append(s.checkIns, &stored)
+	return nil
+}
+
+// GetLastCheckIn retrieves the latest check-in of the user made at or after since
+func (s *Storage) GetLastCheckIn(_ context.Context, userID *string, since time.Time) (*storage.CheckIn, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var last *storage.CheckIn
+	for _, checkIn := range s.checkIns {
+		if checkIn.FkUserId != *userID || checkIn.CreatedAt.Before(since) {
+			continue
+		}
+		if last == nil || !checkIn.CreatedAt.Before(last.CreatedAt) {
+			last = checkIn
+		}
+	}
+	if last == nil {
+		return nil, storage.ErrCheckInNotFound
+	}
+	c := *last
+	return &c, nil
+}
+
+// day returns the midnight in UTC of the day of t.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
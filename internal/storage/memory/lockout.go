@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// lockLevelTTL is how long previous locks are remembered for the backoff, the same as in Redis.
+const lockLevelTTL = 24 * time.Hour
+
+type lockout struct {
+	failures       int
+	failuresExpire time.Time
+	level          int
+	levelExpire    time.Time
+	lockedUntil    time.Time
+}
+
+// lockoutFor returns the lockout state of the email with expired parts reset.
+func (s *Storage) lockoutFor(email *string, now time.Time) *lockout {
+	key := strings.ToLower(*email)
+	l, ok := s.lockouts[key]
+	if !ok {
+		l = &lockout{}
+		s.lockouts[key] = l
+	}
+	if !now.Before(l.failuresExpire) {
+		l.failures = 0
+	}
+	if !now.Before(l.levelExpire) {
+		l.level = 0
+	}
+	return l
+}
+
+// GetLockout returns the time the account is locked until, or zero time if it is not locked.
+func (s *Storage) GetLockout(_ context.Context, email *string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	l, ok := s.lockouts[strings.ToLower(*email)]
+	if !ok || !time.Now().Before(l.lockedUntil) {
+		return time.Time{}, nil
+	}
+	return l.lockedUntil, nil
+}
+
+// RegisterFailedLogin counts a failed login and locks the account with exponential backoff.
+func (s *Storage) RegisterFailedLogin(_ context.Context, email *string, maxAttempts int, window, baseDuration, maxDuration time.Duration) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	l := s.lockoutFor(email, now)
+
+	l.failures++
+	if l.failures == 1 {
+		l.failuresExpire = now.Add(window)
+	}
+	if l.failures < maxAttempts {
+		return time.Time{}, nil
+	}
+
+	l.level++
+	l.levelExpire = now.Add(lockLevelTTL)
+	duration := baseDuration << (l.level - 1)
+	if duration > maxDuration || duration <= 0 {
+		duration = maxDuration
+	}
+
+	l.failures = 0
+	l.lockedUntil = now.Add(duration)
+	return l.lockedUntil, nil
+}
+
+// ResetFailedLogins forgets failed logins and previous locks.
+func (s *Storage) ResetFailedLogins(_ context.Context, email *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.lockoutFor(email, time.Now())
+	l.failures = 0
+	l.level = 0
+	return nil
+}
+
+// Unlock removes the lock together with failed logins and previous locks.
+func (s *Storage) Unlock(_ context.Context, email *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lockouts, strings.ToLower(*email))
+	return nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"GYMBRO/cmd/migrate/migrations"
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/pubsub"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Storage keeps everything in process memory. It implements the same repositories as Postgres and Redis together,
+// so the server and HTTP tests can run without containers. Data is lost when the process stops.
+// All the data is guarded by one mutex, so operations touching several "tables" (e.g. MergeUsers) stay atomic.
+// Values are copied on the way in and out, callers never share memory with the storage.
+type Storage struct {
+	mu sync.RWMutex
+
+	users      map[string]*storage.User
+	identities map[identityKey]*storage.Identity
+	maxes      map[string]map[int]storage.Max
+	workouts   map[string]*storage.WorkoutWithRecords
+	sessions   map[string]*storage.WorkoutSession
+	// sessionEvents are published while mu is held, so subscribers get them in the order of the changes
+	sessionEvents *pubsub.Hub
+	// achievements are kept by user and code
+	achievements map[string]map[string]time.Time
+	follows      map[followKey]*storage.Follow
+	events       []*storage.Event
+	// subscriptions and check-ins are kept in the order they were made
+	subscriptions []*storage.Subscription
+	checkIns      []*storage.CheckIn
+	clans         map[string]*storage.Clan
+	challenges    map[string]*storage.Challenge
+	// challengeResults are kept by challenge, ranked
+	challengeResults map[string][]*storage.ChallengeResult
+	seasons          map[int]*storage.Season
+	// standings are the archived ones, kept by season
+	standings map[int][]*storage.Standing
+	// webhooks and deliveries are kept in the order they were made
+	webhooks   []*storage.Webhook
+	deliveries []*storage.WebhookDelivery
+
+	exercises         map[int]*storage.Exercise
+	muscleGroups      map[int]*storage.MuscleGroup
+	nextExerciseID    int
+	nextMuscleGroupID int
+
+	lockouts   map[string]*lockout
+	rateLimits map[string][]time.Time
+	// locks are the names of the locks held, see TryLock
+	locks map[string]bool
+}
+
+type followKey struct {
+	follower string
+	followee string
+}
+
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+// New creates an empty storage with the same exercises and muscle groups the fill migration adds.
+func New() *Storage {
+	s := &Storage{
+		users:            make(map[string]*storage.User),
+		identities:       make(map[identityKey]*storage.Identity),
+		maxes:            make(map[string]map[int]storage.Max),
+		workouts:         make(map[string]*storage.WorkoutWithRecords),
+		sessions:         make(map[string]*storage.WorkoutSession),
+		sessionEvents:    pubsub.New(),
+		achievements:     make(map[string]map[string]time.Time),
+		follows:          make(map[followKey]*storage.Follow),
+		clans:            map[string]*storage.Clan{storage.DefaultClanID: {ClanId: storage.DefaultClanID, Name: "Default"}},
+		challenges:       make(map[string]*storage.Challenge),
+		challengeResults: make(map[string][]*storage.ChallengeResult),
+		seasons:          make(map[int]*storage.Season),
+		standings:        make(map[int][]*storage.Standing),
+		exercises:        make(map[int]*storage.Exercise),
+		muscleGroups:     make(map[int]*storage.MuscleGroup),
+		lockouts:         make(map[string]*lockout),
+		rateLimits:       make(map[string][]time.Time),
+		locks:            make(map[string]bool),
+	}
+	s.seed()
+	return s
+}
+
+func (s *Storage) seed() {
+	groups := map[string]int{}
+	for _, name := range []string{"Chest", "Back", "Legs", "Shoulders", "Arms", "Abs"} {
+		s.nextMuscleGroupID++
+		s.muscleGroups[s.nextMuscleGroupID] = &storage.MuscleGroup{MuscleGroupId: s.nextMuscleGroupID, Name: name}
+		groups[name] = s.nextMuscleGroupID
+	}
+
+	exercises := []struct {
+		name, description, picture string
+		groups                     []string
+	}{
+		{"Bench Press", "A basic chest exercise performed with a barbell or dumbbells.", "bench_press.png", []string{"Chest"}},
+		{"Deadlift", "A fundamental compound exercise targeting the entire posterior chain.", "deadlift.png", []string{"Back", "Legs"}},
+		{"Squat", "A primary leg exercise that targets the quadriceps and glutes.", "squat.png", []string{"Legs"}},
+		{"Shoulder Press", "An overhead pressing movement that targets the deltoid muscles.", "shoulder_press.png", []string{"Shoulders"}},
+		{"Bicep Curl", "An isolated exercise that targets the biceps.", "bicep_curl.png", []string{"Arms"}},
+		{"Crunch", "An abdominal exercise focusing on the rectus abdominis.", "crunch.png", []string{"Abs"}},
+	}
+	for _, e := range exercises {
+		s.nextExerciseID++
+		exercise := &storage.Exercise{ExerciseId: s.nextExerciseID, Name: e.name, Description: e.description, Picture: e.picture, MuscleGroupIds: []int{}}
+		for _, group := range e.groups {
+			exercise.MuscleGroupIds = append(exercise.MuscleGroupIds, groups[group])
+		}
+		s.exercises[exercise.ExerciseId] = exercise
+	}
+}
+
+// Ping always succeeds, the storage is in the same process.
+func (s *Storage) Ping(_ context.Context) error {
+	return nil
+}
+
+// MigrationVersion reports the latest migration, there is no schema to fall behind.
+func (s *Storage) MigrationVersion(_ context.Context) (uint, bool, error) {
+	const op = "storage.memory.MigrationVersion"
+	version, err := migrations.Latest()
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return version, false, nil
+}
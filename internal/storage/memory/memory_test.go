@@ -0,0 +1,158 @@
+package memory_test
+
+import (
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/memory"
+	"GYMBRO/internal/storage/storagetest"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUsers(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	user := &storage.User{UserId: "user1", Username: "bro", Email: "bro@gym.com", Password: "hash"}
+	_, err := s.RegisterNewUser(ctx, user)
+	require.NoError(t, err)
+
+	_, err = s.RegisterNewUser(ctx, &storage.User{UserId: "user2", Username: "other", Email: "bro@gym.com"})
+	require.ErrorIs(t, err, storage.ErrUserExists)
+
+	got, err := s.GetUserByEmail(ctx, &user.Email)
+	require.NoError(t, err)
+	require.Equal(t, storage.RoleUser, got.Role)
+
+	// returned values are copies, changing them does not change the storage
+	got.Role = storage.RoleAdmin
+	got, err = s.GetUserByID(ctx, &user.UserId)
+	require.NoError(t, err)
+	require.Equal(t, storage.RoleUser, got.Role)
+
+	missing := "missing"
+	_, err = s.GetUserByID(ctx, &missing)
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestMergeUsers(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	target, source := "target", "source"
+	_, err := s.RegisterNewUser(ctx, &storage.User{UserId: target, Username: target, Email: "target@gym.com"})
+	require.NoError(t, err)
+	_, err = s.RegisterOAuthUser(ctx, &storage.User{UserId: source, Username: source, Email: "source@gym.com"},
+		&storage.Identity{Provider: "google", Subject: "123"})
+	require.NoError(t, err)
+
+	require.NoError(t, s.SetUserMax(ctx, &target, &storage.Max{ExerciseId: 1, MaxWeight: 100, Reps: 5}))
+	require.NoError(t, s.SetUserMax(ctx, &source, &storage.Max{ExerciseId: 1, MaxWeight: 120, Reps: 1}))
+	require.NoError(t, s.SaveWorkout(ctx, &storage.WorkoutSession{UserID: source, SessionID: "workout", Points: 10,
+		Records: []storage.Record{{RecordId: "record", FkWorkoutId: "workout", FkExerciseId: 1, Reps: 1, Weight: 120, Points: 10}}}))
+
+	require.NoError(t, s.MergeUsers(ctx, &target, &source))
+
+	_, err = s.GetUserByID(ctx, &source)
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	user, err := s.GetUserByIdentity(ctx, ptr("google"), ptr("123"))
+	require.NoError(t, err)
+	require.Equal(t, target, user.UserId)
+	require.Equal(t, 10, user.Points)
+
+	max, err := s.GetUserMax(ctx, &target, ptr(1))
+	require.NoError(t, err)
+	require.Equal(t, 120, max.MaxWeight)
+
+	workout, err := s.GetWorkout(ctx, ptr("workout"))
+	require.NoError(t, err)
+	require.Equal(t, target, workout.UserID)
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	email := "Bro@Gym.com"
+
+	for i := 0; i < 2; i++ {
+		lockedUntil, err := s.RegisterFailedLogin(ctx, &email, 3, time.Minute, time.Minute, time.Hour)
+		require.NoError(t, err)
+		require.True(t, lockedUntil.IsZero())
+	}
+	lockedUntil, err := s.RegisterFailedLogin(ctx, &email, 3, time.Minute, time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), lockedUntil, time.Second)
+
+	lower := "bro@gym.com"
+	got, err := s.GetLockout(ctx, &lower)
+	require.NoError(t, err)
+	require.Equal(t, lockedUntil, got)
+
+	require.NoError(t, s.Unlock(ctx, &email))
+	got, err = s.GetLockout(ctx, &email)
+	require.NoError(t, err)
+	require.True(t, got.IsZero())
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := s.Allow(ctx, "ip:1", 2, time.Minute)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, retryAfter, err := s.Allow(ctx, "ip:1", 2, time.Minute)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Greater(t, retryAfter, 59*time.Second)
+
+	allowed, _, err = s.Allow(ctx, "ip:2", 2, time.Minute)
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+// TestConcurrentSessions is meant to be run with -race.
+func TestConcurrentSessions(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := fmt.Sprintf("user%d", i%5)
+			session, err := s.GetSession(ctx, &userID)
+			if err != nil {
+				session = &storage.WorkoutSession{UserID: userID, SessionID: storage.GenerateUID()}
+				require.NoError(t, s.CreateSession(ctx, session))
+			}
+			session.Records = append(session.Records, storage.Record{RecordId: storage.GenerateUID()})
+			require.NoError(t, s.UpdateSession(ctx, &userID, session))
+			_, err = s.GetAllSessions(ctx)
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	count, err := s.CountSessions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestContract(t *testing.T) {
+	s := memory.New()
+	storagetest.Run(t, func(t *testing.T) storagetest.Repositories {
+		return storagetest.Repositories{Users: s, Workouts: s, Sessions: s, SessionEvents: s, Exercises: s, Stats: s, Achievements: s, Follows: s, Events: s, Gyms: s, Challenges: s, Seasons: s, Moderation: s, Webhooks: s, Locker: s, JoinClan: s.JoinNewClan, AwardClan: s.AwardClan}
+	})
+}
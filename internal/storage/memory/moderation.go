@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+)
+
+// GetFlaggedRecords retrieves saved records that are held or excluded, the oldest workout first.
+func (s *Storage) GetFlaggedRecords(_ context.Context, limit int) ([]*storage.FlaggedRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var workouts []*storage.WorkoutWithRecords
+	for _, workout := range s.workouts {
+		workouts = append(workouts, workout)
+	}
+	sort.Slice(workouts, func(i, j int) bool {
+		if !workouts[i].StartTime.Equal(workouts[j].StartTime) {
+			return workouts[i].StartTime.Before(workouts[j].StartTime)
+		}
+		return workouts[i].WorkoutID < workouts[j].WorkoutID
+	})
+
+	records := make([]*storage.FlaggedRecord, 0)
+	for _, workout := range workouts {
+		for _, record := range workout.Records {
+			if len(records) == limit {
+				return records, nil
+			}
+			if record.Status == storage.RecordHeld || record.Status == storage.RecordExcluded {
+				records = append(records, flagged(workout, record))
+			}
+		}
+	}
+	return records, nil
+}
+
+// ApproveRecord makes a held record count and adds its points to the workout and the user,
+// with prBonus on top if it becomes the max of the user.
+func (s *Storage) ApproveRecord(_ context.Context, recordID *string, prBonus int) (*storage.FlaggedRecord, *storage.Max, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	workout, record := s.findRecord(*recordID)
+	if record == nil {
+		return nil, nil, storage.ErrRecordNotFound
+	}
+	if record.Status != storage.RecordHeld {
+		return nil, nil, storage.ErrRecordNotHeld
+	}
+
+	record.Status = storage.RecordOK
+	var newMax *storage.Max
+	max := storage.Max{UserID: workout.UserID, ExerciseId: record.FkExerciseId, MaxWeight: record.Weight, Reps: record.Reps}
+	if current, ok := s.maxes[workout.UserID][record.FkExerciseId]; !ok || better(max, current) {
+		s.setMax(workout.UserID, max)
+		record.Points += prBonus
+		newMax = &max
+	}
+	workout.Points += record.Points
+	if user, ok := s.users[workout.UserID]; ok {
+		user.Points += record.Points
+		if s.inCurrentSeason(workout) {
+			user.SeasonPoints += record.Points
+		}
+	}
+	return flagged(workout, *record), newMax, nil
+}
+
+// VoidRecord takes a record back with its points and rebuilds the max of its exercise.
+func (s *Storage) VoidRecord(_ context.Context, recordID *string) (*storage.FlaggedRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	workout, record := s.findRecord(*recordID)
+	if record == nil {
+		return nil, storage.ErrRecordNotFound
+	}
+	if record.Status == storage.RecordVoided {
+		return nil, storage.ErrRecordVoided
+	}
+
+	counted := record.Counts()
+	record.Status = storage.RecordVoided
+	if !counted {
+		return flagged(workout, *record), nil
+	}
+
+	workout.Points -= record.Points
+	if user, ok := s.users[workout.UserID]; ok {
+		user.Points -= record.Points
+		if s.inCurrentSeason(workout) {
+			user.SeasonPoints = max(user.SeasonPoints-record.Points, 0)
+		}
+	}
+
+	delete(s.maxes[workout.UserID], record.FkExerciseId)
+	for _, w := range s.workouts {
+		if w.UserID != workout.UserID {
+			continue
+		}
+		for _, r := range w.Records {
+			if r.FkExerciseId != record.FkExerciseId || !r.Counts() {
+				continue
+			}
+			m := storage.Max{UserID: w.UserID, ExerciseId: r.FkExerciseId, MaxWeight: r.Weight, Reps: r.Reps}
+			if current, ok := s.maxes[w.UserID][r.FkExerciseId]; !ok || better(m, current) {
+				s.setMax(w.UserID, m)
+			}
+		}
+	}
+	return flagged(workout, *record), nil
+}
+
+// findRecord returns the record and its workout or nil, mu has to be held.
+func (s *Storage) findRecord(recordID string) (*storage.WorkoutWithRecords, *storage.Record) {
+	for _, workout := range s.workouts {
+		for i := range workout.Records {
+			if workout.Records[i].RecordId == recordID {
+				return workout, &workout.Records[i]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// inCurrentSeason tells if the workout earned seasonal points, mu has to be held.
+// Without seasons every workout does.
+func (s *Storage) inCurrentSeason(workout *storage.WorkoutWithRecords) bool {
+	season := s.currentSeason()
+	return season == nil || !workout.StartTime.Before(season.StartTime)
+}
+
+func flagged(workout *storage.WorkoutWithRecords, record storage.Record) *storage.FlaggedRecord {
+	return &storage.FlaggedRecord{Record: record, UserID: workout.UserID, StartTime: workout.StartTime}
+}
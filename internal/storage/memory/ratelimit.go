@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// Allow registers a hit for the key in a sliding window of the given size.
+// Rejected hits are not counted, so they do not extend the window.
+func (s *Storage) Allow(_ context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+
+	hits := s.rateLimits[key]
+	start := 0
+	for start < len(hits) && !hits[start].After(now.Add(-window)) {
+		start++
+	}
+	hits = hits[start:]
+
+	if len(hits) < limit {
+		s.rateLimits[key] = append(hits, now)
+		return true, 0, nil
+	}
+	s.rateLimits[key] = hits
+	return false, hits[0].Add(window).Sub(now), nil
+}
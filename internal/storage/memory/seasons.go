@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StartSeason stores the first season.
+func (s *Storage) StartSeason(_ context.Context, season *storage.Season) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.seasons) > 0 {
+		return storage.ErrSeasonExists
+	}
+	s.seasons[season.SeasonID] = copySeason(season)
+	return nil
+}
+
+// GetCurrentSeason retrieves the season that is not ended.
+func (s *Storage) GetCurrentSeason(_ context.Context) (*storage.Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if season := s.currentSeason(); season != nil {
+		return copySeason(season), nil
+	}
+	return nil, storage.ErrSeasonNotFound
+}
+
+// currentSeason returns the season that is not ended or nil, mu has to be held.
+func (s *Storage) currentSeason() *storage.Season {
+	for _, season := range s.seasons {
+		if season.EndedAt == nil {
+			return season
+		}
+	}
+	return nil
+}
+
+// GetSeason retrieves a season by its ID.
+func (s *Storage) GetSeason(_ context.Context, seasonID int) (*storage.Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	season, ok := s.seasons[seasonID]
+	if !ok {
+		return nil, storage.ErrSeasonNotFound
+	}
+	return copySeason(season), nil
+}
+
+// GetSeasons retrieves all seasons, the newest first.
+func (s *Storage) GetSeasons(_ context.Context) ([]*storage.Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seasons := make([]*storage.Season, 0, len(s.seasons))
+	for _, season := range s.seasons {
+		seasons = append(seasons, copySeason(season))
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		return seasons[i].SeasonID > seasons[j].SeasonID
+	})
+	return seasons, nil
+}
+
+// RolloverSeason archives the standings, resets the seasonal points and starts the next season under one lock.
+func (s *Storage) RolloverSeason(_ context.Context, seasonID int, endedAt time.Time, next *storage.Season) error {
+	const op = "storage.memory.RolloverSeason"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	season, ok := s.seasons[seasonID]
+	if !ok {
+		return storage.ErrSeasonNotFound
+	}
+	if season.EndedAt != nil {
+		return storage.ErrSeasonEnded
+	}
+	if _, ok := s.seasons[next.SeasonID]; ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrSeasonExists)
+	}
+
+	var archived []*storage.Standing
+	for _, kind := range []string{storage.StandingUsers, storage.StandingClans, storage.StandingGyms} {
+		standings := s.liveStandings(kind)
+		for _, standing := range standings {
+			standing.FkSeasonId = seasonID
+		}
+		archived = append(archived, standings...)
+	}
+	s.standings[seasonID] = archived
+
+	for _, user := range s.users {
+		user.SeasonPoints = 0
+	}
+	for _, clan := range s.clans {
+		clan.SeasonPoints = 0
+	}
+
+	endedAt = endedAt.UTC()
+	season.EndedAt = &endedAt
+	s.seasons[next.SeasonID] = copySeason(next)
+	return nil
+}
+
+// GetStandings retrieves the archived standings of the kind in a season, ranked.
+func (s *Storage) GetStandings(_ context.Context, seasonID int, kind string, limit int) ([]*storage.Standing, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	standings := make([]*storage.Standing, 0)
+	for _, standing := range s.standings[seasonID] {
+		if standing.Kind != kind {
+			continue
+		}
+		if len(standings) == limit {
+			break
+		}
+		c := *standing
+		standings = append(standings, &c)
+	}
+	return standings, nil
+}
+
+// GetLiveStandings computes the standings of the kind in the current season, ranked.
+func (s *Storage) GetLiveStandings(_ context.Context, kind string, limit int) ([]*storage.Standing, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	standings := s.liveStandings(kind)
+	if len(standings) > limit {
+		standings = standings[:limit]
+	}
+	return standings, nil
+}
+
+// liveStandings ranks the users, clans or gyms by their seasonal points, mu has to be held.
+// The default clan and gym are left out.
+// There are no gyms in the memory storage, gym standings have no names.
+func (s *Storage) liveStandings(kind string) []*storage.Standing {
+	standings := make([]*storage.Standing, 0)
+	switch kind {
+	case storage.StandingUsers:
+		for _, user := range s.users {
+			if user.SeasonPoints > 0 && !user.IsPrivate {
+				standings = append(standings, &storage.Standing{Kind: kind, SubjectID: user.UserId, Name: user.Username, Points: user.SeasonPoints})
+			}
+		}
+	case storage.StandingClans:
+		for _, clan := range s.clans {
+			if clan.SeasonPoints > 0 && clan.ClanId != storage.DefaultClanID {
+				standings = append(standings, &storage.Standing{Kind: kind, SubjectID: clan.ClanId, Name: clan.Name, Points: clan.SeasonPoints})
+			}
+		}
+	case storage.StandingGyms:
+		byGym := make(map[int]*storage.Standing)
+		for _, user := range s.users {
+			if user.SeasonPoints <= 0 || user.FkGymId == storage.DefaultGymID {
+				continue
+			}
+			standing, ok := byGym[user.FkGymId]
+			if !ok {
+				standing = &storage.Standing{Kind: kind, SubjectID: strconv.Itoa(user.FkGymId)}
+				byGym[user.FkGymId] = standing
+				standings = append(standings, standing)
+			}
+			standing.Points += user.SeasonPoints
+		}
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].SubjectID < standings[j].SubjectID
+	})
+	for i, standing := range standings {
+		standing.Rank = i + 1
+		if i > 0 && standing.Points == standings[i-1].Points {
+			standing.Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
+
+// TryLock takes the lock in the process, there are no other replicas to share it with.
+func (s *Storage) TryLock(_ context.Context, name string) (func(), bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks[name] {
+		return nil, false, nil
+	}
+	s.locks[name] = true
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.locks, name)
+	}, true, nil
+}
+
+func copySeason(season *storage.Season) *storage.Season {
+	c := *season
+	if season.EndedAt != nil {
+		endedAt := *season.EndedAt
+		c.EndedAt = &endedAt
+	}
+	return &c
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"time"
+)
+
+// CreateSession stores a new workout session for a user, replacing the previous one if any.
+func (s *Storage) CreateSession(_ context.Context, session *storage.WorkoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.UserID] = copySession(session)
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionCreated, UserID: session.UserID, Session: copySession(session)})
+	return nil
+}
+
+// GetSession retrieves the workout session of a user.
+func (s *Storage) GetSession(_ context.Context, userID *string) (*storage.WorkoutSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	session, ok := s.sessions[*userID]
+	if !ok {
+		return nil, storage.ErrNoSession
+	}
+	return copySession(session), nil
+}
+
+// UpdateSession replaces the session of a user and bumps its last update time.
+func (s *Storage) UpdateSession(_ context.Context, userID *string, updatedSession *storage.WorkoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updatedSession.LastUpdated = time.Now()
+	s.sessions[*userID] = copySession(updatedSession)
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionUpdated, UserID: *userID, Session: copySession(updatedSession)})
+	return nil
+}
+
+// DeleteSession removes the session of a user.
+func (s *Storage) DeleteSession(_ context.Context, userID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, *userID)
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionDeleted, UserID: *userID})
+	return nil
+}
+
+// GetAllSessions retrieves all workout sessions.
+func (s *Storage) GetAllSessions(_ context.Context) ([]*storage.WorkoutSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sessions := make([]*storage.WorkoutSession, 0, len(s.sessions))
+	for _, session := range s.sessions {
+		sessions = append(sessions, copySession(session))
+	}
+	return sessions, nil
+}
+
+// GetSessions returns the sessions of the users that have one.
+func (s *Storage) GetSessions(_ context.Context, userIDs []string) ([]*storage.WorkoutSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var sessions []*storage.WorkoutSession
+	for _, userID := range userIDs {
+		if session, ok := s.sessions[userID]; ok {
+			sessions = append(sessions, copySession(session))
+		}
+	}
+	return sessions, nil
+}
+
+// CountSessions returns the number of workout sessions.
+func (s *Storage) CountSessions(_ context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions), nil
+}
+
+// SubscribeSessions streams the changes of the user's session until the context is done.
+func (s *Storage) SubscribeSessions(ctx context.Context, userID *string) (<-chan *storage.SessionEvent, error) {
+	return s.sessionEvents.Subscribe(ctx, *userID), nil
+}
+
+func copySession(session *storage.WorkoutSession) *storage.WorkoutSession {
+	c := *session
+	c.Records = append([]storage.Record(nil), session.Records...)
+	return &c
+}
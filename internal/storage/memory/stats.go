@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+	"time"
+)
+
+// GetMuscleGroupVolume sums the records that count of user's workouts started in [from, to) per workout and muscle group.
+func (s *Storage) GetMuscleGroupVolume(_ context.Context, userID *string, from time.Time, to time.Time) ([]*storage.MuscleGroupVolume, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var volumes []*storage.MuscleGroupVolume
+	for _, workout := range s.workouts {
+		if workout.UserID != *userID || workout.StartTime.Before(from) || !workout.StartTime.Before(to) {
+			continue
+		}
+		byGroup := make(map[int]*storage.MuscleGroupVolume)
+		for _, record := range workout.Records {
+			if !record.Counts() {
+				continue
+			}
+			exercise, ok := s.exercises[record.FkExerciseId]
+			if !ok {
+				continue
+			}
+			for _, groupID := range exercise.MuscleGroupIds {
+				group, ok := s.muscleGroups[groupID]
+				if !ok {
+					continue
+				}
+				volume, ok := byGroup[groupID]
+				if !ok {
+					volume = &storage.MuscleGroupVolume{
+						WorkoutID:       workout.WorkoutID,
+						StartTime:       workout.StartTime,
+						MuscleGroupId:   groupID,
+						MuscleGroupName: group.Name,
+					}
+					byGroup[groupID] = volume
+					volumes = append(volumes, volume)
+				}
+				volume.Sets++
+				volume.Reps += record.Reps
+				volume.Tonnage += record.Reps * record.Weight
+			}
+		}
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		a, b := volumes[i], volumes[j]
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		if a.WorkoutID != b.WorkoutID {
+			return a.WorkoutID < b.WorkoutID
+		}
+		return a.MuscleGroupId < b.MuscleGroupId
+	})
+	return volumes, nil
+}
+
+// GetExerciseSets returns the records that count of the exercise in user's workouts started in [from, to).
+func (s *Storage) GetExerciseSets(_ context.Context, userID *string, exerciseID int, from time.Time, to time.Time) ([]*storage.ExerciseSet, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.exercises[exerciseID]; !ok {
+		return nil, storage.ErrExerciseNotFound
+	}
+
+	var workouts []*storage.WorkoutWithRecords
+	for _, workout := range s.workouts {
+		if workout.UserID == *userID && !workout.StartTime.Before(from) && workout.StartTime.Before(to) {
+			workouts = append(workouts, workout)
+		}
+	}
+	sort.Slice(workouts, func(i, j int) bool {
+		if !workouts[i].StartTime.Equal(workouts[j].StartTime) {
+			return workouts[i].StartTime.Before(workouts[j].StartTime)
+		}
+		return workouts[i].WorkoutID < workouts[j].WorkoutID
+	})
+
+	var sets []*storage.ExerciseSet
+	for _, workout := range workouts {
+		for _, record := range workout.Records {
+			if record.FkExerciseId == exerciseID && record.Counts() {
+				sets = append(sets, &storage.ExerciseSet{WorkoutID: workout.WorkoutID, StartTime: workout.StartTime, Reps: record.Reps, Weight: record.Weight})
+			}
+		}
+	}
+	return sets, nil
+}
@@ -0,0 +1,415 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+	"time"
+)
+
+// RegisterNewUser stores a new user, usernames and emails are unique.
+func (s *Storage) RegisterNewUser(_ context.Context, user *storage.User) (*string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.insertUser(user); err != nil {
+		return nil, err
+	}
+	id := user.UserId
+	return &id, nil
+}
+
+// RegisterOAuthUser stores a new user together with the provider identity they signed up with.
+func (s *Storage) RegisterOAuthUser(_ context.Context, user *storage.User, identity *storage.Identity) (*string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := identityKey{provider: identity.Provider, subject: identity.Subject}
+	if _, ok := s.identities[key]; ok {
+		return nil, storage.ErrIdentityExists
+	}
+	if err := s.insertUser(user); err != nil {
+		return nil, err
+	}
+	s.identities[key] = &storage.Identity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		FkUserId:  user.UserId,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}
+	id := user.UserId
+	return &id, nil
+}
+
+func (s *Storage) insertUser(user *storage.User) error {
+	if _, ok := s.users[user.UserId]; ok {
+		return storage.ErrUserExists
+	}
+	for _, existing := range s.users {
+		if existing.Email == user.Email || existing.Username == user.Username {
+			return storage.ErrUserExists
+		}
+	}
+	now := time.Now()
+	s.users[user.UserId] = &storage.User{
+		UserId:      user.UserId,
+		Username:    user.Username,
+		Email:       user.Email,
+		Password:    user.Password,
+		DateOfBirth: user.DateOfBirth,
+		FkClanId:    "0",
+		LastActive:  now,
+		CreatedAt:   now,
+		Role:        storage.RoleUser,
+	}
+	return nil
+}
+
+// GetUserByID retrieves a user by their ID
+func (s *Storage) GetUserByID(_ context.Context, id *string) (*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[*id]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+// GetUserByEmail retrieves a user by their email
+func (s *Storage) GetUserByEmail(_ context.Context, email *string) (*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, user := range s.users {
+		if user.Email == *email {
+			return copyUser(user), nil
+		}
+	}
+	return nil, storage.ErrUserNotFound
+}
+
+// GetUserByIdentity retrieves a user by the subject they have at the OAuth provider
+func (s *Storage) GetUserByIdentity(_ context.Context, provider *string, subject *string) (*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	identity, ok := s.identities[identityKey{provider: *provider, subject: *subject}]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+	user, ok := s.users[identity.FkUserId]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+// GetUserIdentities retrieves all OAuth identities linked to a user, oldest first
+func (s *Storage) GetUserIdentities(_ context.Context, userID *string) ([]*storage.Identity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var identities []*storage.Identity
+	for _, identity := range s.identities {
+		if identity.FkUserId == *userID {
+			c := *identity
+			identities = append(identities, &c)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		return identities[i].CreatedAt.Before(identities[j].CreatedAt)
+	})
+	return identities, nil
+}
+
+// AddUserIdentity links an OAuth identity to an existing user
+func (s *Storage) AddUserIdentity(_ context.Context, identity *storage.Identity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := identityKey{provider: identity.Provider, subject: identity.Subject}
+	if _, ok := s.identities[key]; ok {
+		return storage.ErrIdentityExists
+	}
+	if _, ok := s.users[identity.FkUserId]; !ok {
+		return storage.ErrUserNotFound
+	}
+	if s.hasProvider(identity.FkUserId, identity.Provider) {
+		return storage.ErrIdentityExists
+	}
+	s.identities[key] = &storage.Identity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		FkUserId:  identity.FkUserId,
+		Email:     identity.Email,
+		CreatedAt: time.Now(),
+	}
+	return nil
+}
+
+// hasProvider reports whether the user already has an identity of the provider, s.mu must be held.
+func (s *Storage) hasProvider(userID string, provider string) bool {
+	for _, identity := range s.identities {
+		if identity.FkUserId == userID && identity.Provider == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteUserIdentity unlinks the identity of the given provider from a user
+func (s *Storage) DeleteUserIdentity(_ context.Context, userID *string, provider *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := false
+	for key, identity := range s.identities {
+		if identity.FkUserId == *userID && identity.Provider == *provider {
+			delete(s.identities, key)
+			deleted = true
+		}
+	}
+	if !deleted {
+		return storage.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// MergeUsers moves identities, workouts, maxes, achievements, follows, events, subscriptions, check-ins and points of the source user
+// to the target user and deletes the source user. It returns ErrIdentityExists if both users have an identity of the same provider.
+func (s *Storage) MergeUsers(_ context.Context, targetID *string, sourceID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	source, ok := s.users[*sourceID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	target, ok := s.users[*targetID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+
+	for _, identity := range s.identities {
+		if identity.FkUserId == *sourceID && s.hasProvider(*targetID, identity.Provider) {
+			return storage.ErrIdentityExists
+		}
+	}
+
+	target.Points += source.Points
+	target.SeasonPoints += source.SeasonPoints
+	target.IsFlagged = target.IsFlagged || source.IsFlagged
+	for _, identity := range s.identities {
+		if identity.FkUserId == *sourceID {
+			identity.FkUserId = *targetID
+		}
+	}
+	for _, workout := range s.workouts {
+		if workout.UserID == *sourceID {
+			workout.UserID = *targetID
+		}
+	}
+	for exerciseID, max := range s.maxes[*sourceID] {
+		if current, ok := s.maxes[*targetID][exerciseID]; ok && !better(max, current) {
+			continue
+		}
+		max.UserID = *targetID
+		s.setMax(*targetID, max)
+	}
+
+	for code, awardedAt := range s.achievements[*sourceID] {
+		s.award(*targetID, code, awardedAt)
+	}
+
+	for key, follow := range s.follows {
+		if key.follower != *sourceID && key.followee != *sourceID {
+			continue
+		}
+		delete(s.follows, key)
+		if key.follower == *sourceID {
+			key.follower = *targetID
+		}
+		if key.followee == *sourceID {
+			key.followee = *targetID
+		}
+		if key.follower == key.followee {
+			continue
+		}
+		// a follow both users had stays accepted if any of them was
+		if current, ok := s.follows[key]; ok && (current.Status == storage.FollowAccepted || follow.Status != storage.FollowAccepted) {
+			continue
+		}
+		follow.FollowerID, follow.FolloweeID = key.follower, key.followee
+		s.follows[key] = follow
+	}
+	for _, event := range s.events {
+		if event.UserID == *sourceID {
+			event.UserID = *targetID
+		}
+	}
+	for _, subscription := range s.subscriptions {
+		if subscription.FkUserId == *sourceID {
+			subscription.FkUserId = *targetID
+		}
+	}
+	for _, checkIn := range s.checkIns {
+		if checkIn.FkUserId == *sourceID {
+			checkIn.FkUserId = *targetID
+		}
+	}
+	for _, webhook := range s.webhooks {
+		if webhook.OwnerID == *sourceID {
+			webhook.OwnerID = *targetID
+		}
+		if webhook.Kind == storage.WebhookUser && webhook.SubjectID == *sourceID {
+			webhook.SubjectID = *targetID
+		}
+	}
+
+	delete(s.maxes, *sourceID)
+	delete(s.achievements, *sourceID)
+	delete(s.users, *sourceID)
+	return nil
+}
+
+// ChangeStatus updates the active status and last active timestamp for a user.
+func (s *Storage) ChangeStatus(_ context.Context, userID *string, status bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.users[*userID]; ok {
+		user.IsActive = status
+		user.LastActive = time.Now()
+	}
+	return nil
+}
+
+// SetUserRole changes the role of a user
+func (s *Storage) SetUserRole(_ context.Context, userID *string, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.Role = role
+	return nil
+}
+
+// SetUserDisabled disables or enables a user account
+func (s *Storage) SetUserDisabled(_ context.Context, userID *string, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.IsDisabled = disabled
+	return nil
+}
+
+// SetUserPrivate sets whether following the user needs an approval
+func (s *Storage) SetUserPrivate(_ context.Context, userID *string, private bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.IsPrivate = private
+	return nil
+}
+
+// SetUserFlagged sets whether the user logged records that failed the plausibility checks
+func (s *Storage) SetUserFlagged(_ context.Context, userID *string, flagged bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.IsFlagged = flagged
+	return nil
+}
+
+// SetUserBodyWeight sets the body weight of a user
+func (s *Storage) SetUserBodyWeight(_ context.Context, userID *string, weight int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+	user.BodyWeight = weight
+	return nil
+}
+
+// GetClanMembers returns the users of the clan.
+func (s *Storage) GetClanMembers(_ context.Context, clanID *string) ([]*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var members []*storage.User
+	for _, user := range s.users {
+		if user.FkClanId == *clanID {
+			members = append(members, copyUser(user))
+		}
+	}
+	return members, nil
+}
+
+// GetGymMembers returns the users whose gym is gymID.
+func (s *Storage) GetGymMembers(_ context.Context, gymID int) ([]*storage.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var members []*storage.User
+	for _, user := range s.users {
+		if user.FkGymId == gymID {
+			members = append(members, copyUser(user))
+		}
+	}
+	return members, nil
+}
+
+// GetUserMax retrieves the maximum weight and reps for a specific exercise.
+func (s *Storage) GetUserMax(_ context.Context, userID *string, exercise *int) (*storage.Max, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	max, ok := s.maxes[*userID][*exercise]
+	if !ok {
+		return nil, storage.ErrNoMaxes
+	}
+	return &max, nil
+}
+
+// GetUserMaxes retrieves all maximum weight records for a user, ordered by exercise.
+func (s *Storage) GetUserMaxes(_ context.Context, userID *string) ([]*storage.Max, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var maxes []*storage.Max
+	for _, max := range s.maxes[*userID] {
+		max := max
+		maxes = append(maxes, &max)
+	}
+	sort.Slice(maxes, func(i, j int) bool {
+		return maxes[i].ExerciseId < maxes[j].ExerciseId
+	})
+	return maxes, nil
+}
+
+// SetUserMax inserts or replaces the maximum weight and reps for a user's exercise.
+func (s *Storage) SetUserMax(_ context.Context, userID *string, max *storage.Max) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setMax(*userID, storage.Max{UserID: *userID, ExerciseId: max.ExerciseId, MaxWeight: max.MaxWeight, Reps: max.Reps})
+	return nil
+}
+
+func (s *Storage) setMax(userID string, max storage.Max) {
+	if s.maxes[userID] == nil {
+		s.maxes[userID] = make(map[int]storage.Max)
+	}
+	s.maxes[userID][max.ExerciseId] = max
+}
+
+// better tells if a is a heavier max than b, or the same weight for more reps.
+func better(a, b storage.Max) bool {
+	return a.MaxWeight > b.MaxWeight || (a.MaxWeight == b.MaxWeight && a.Reps > b.Reps)
+}
+
+func copyUser(user *storage.User) *storage.User {
+	c := *user
+	return &c
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CreateWebhook stores a webhook unless the owner has max of them, there are no gyms in memory so any gym ID is accepted.
+func (s *Storage) CreateWebhook(_ context.Context, webhook *storage.Webhook, max int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, existing := range s.webhooks {
+		if existing.OwnerID == webhook.OwnerID {
+			count++
+		}
+	}
+	if count >= max {
+		return storage.ErrWebhookLimit
+	}
+	s.webhooks = append(s.webhooks, copyWebhook(webhook))
+	return nil
+}
+
+// GetWebhooks returns the webhooks the user manages, the oldest first.
+func (s *Storage) GetWebhooks(_ context.Context, ownerID *string) ([]*storage.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	webhooks := make([]*storage.Webhook, 0)
+	for _, webhook := range s.webhooks {
+		if webhook.OwnerID == *ownerID {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the webhook of the owner with its deliveries.
+func (s *Storage) DeleteWebhook(_ context.Context, ownerID *string, webhookID *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findWebhook(*ownerID, *webhookID) == nil {
+		return storage.ErrWebhookNotFound
+	}
+	s.webhooks = slices.DeleteFunc(s.webhooks, func(webhook *storage.Webhook) bool {
+		return webhook.WebhookID == *webhookID
+	})
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(delivery *storage.WebhookDelivery) bool {
+		return delivery.WebhookID == *webhookID
+	})
+	return nil
+}
+
+// AddDeliveries queues a copy of the delivery for the webhooks of the user and of their gym subscribed to its event.
+func (s *Storage) AddDeliveries(_ context.Context, userID *string, delivery *storage.WebhookDelivery) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[*userID]
+	if !ok {
+		return 0, nil
+	}
+
+	added := 0
+	for _, webhook := range s.webhooks {
+		if !slices.Contains(webhook.Events, delivery.Event) {
+			continue
+		}
+		forUser := webhook.Kind == storage.WebhookUser && webhook.SubjectID == user.UserId
+		forGym := webhook.Kind == storage.WebhookGym && webhook.SubjectID == strconv.Itoa(user.FkGymId) && !user.IsPrivate
+		if !forUser && !forGym {
+			continue
+		}
+		queued := *delivery
+		queued.DeliveryID = storage.GenerateUID()
+		queued.WebhookID = webhook.WebhookID
+		queued.Status = storage.DeliveryPending
+		s.deliveries = append(s.deliveries, &queued)
+		added++
+	}
+	return added, nil
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at now, the longest waiting first.
+func (s *Storage) GetDueDeliveries(_ context.Context, now time.Time, limit int) ([]*storage.PendingDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var due []*storage.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == storage.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	pending := make([]*storage.PendingDelivery, 0, len(due))
+	for _, delivery := range due {
+		for _, webhook := range s.webhooks {
+			if webhook.WebhookID == delivery.WebhookID {
+				pending = append(pending, &storage.PendingDelivery{WebhookDelivery: *copyDelivery(delivery), URL: webhook.URL, Secret: webhook.Secret})
+				break
+			}
+		}
+	}
+	return pending, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt. Deliveries of deleted webhooks are gone, updating them does nothing.
+func (s *Storage) UpdateDelivery(_ context.Context, delivery *storage.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, stored := range s.deliveries {
+		if stored.DeliveryID == delivery.DeliveryID {
+			s.deliveries[i] = copyDelivery(delivery)
+			return nil
+		}
+	}
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries of the owner's webhooks, the newest first.
+func (s *Storage) GetDeliveries(_ context.Context, ownerID *string, webhookID string, status string, limit int) ([]*storage.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if webhookID != "" && s.findWebhook(*ownerID, webhookID) == nil {
+		return nil, storage.ErrWebhookNotFound
+	}
+
+	deliveries := make([]*storage.WebhookDelivery, 0)
+	// deliveries are kept in the order they were queued
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		delivery := s.deliveries[i]
+		if webhookID != "" && delivery.WebhookID != webhookID {
+			continue
+		}
+		if status != "" && delivery.Status != status {
+			continue
+		}
+		if s.findWebhook(*ownerID, delivery.WebhookID) == nil {
+			continue
+		}
+		deliveries = append(deliveries, copyDelivery(delivery))
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery queues a dead delivery of the owner's webhook again.
+func (s *Storage) RetryDelivery(_ context.Context, ownerID *string, deliveryID *string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range s.deliveries {
+		if delivery.DeliveryID != *deliveryID || s.findWebhook(*ownerID, delivery.WebhookID) == nil {
+			continue
+		}
+		if delivery.Status != storage.DeliveryDead {
+			return storage.ErrDeliveryNotDead
+		}
+		delivery.Status = storage.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = at
+		return nil
+	}
+	return storage.ErrDeliveryNotFound
+}
+
+// findWebhook returns the webhook of the owner or nil, mu has to be held.
+func (s *Storage) findWebhook(ownerID string, webhookID string) *storage.Webhook {
+	for _, webhook := range s.webhooks {
+		if webhook.WebhookID == webhookID && webhook.OwnerID == ownerID {
+			return webhook
+		}
+	}
+	return nil
+}
+
+func copyWebhook(webhook *storage.Webhook) *storage.Webhook {
+	c := *webhook
+	c.Events = slices.Clone(webhook.Events)
+	return &c
+}
+
+func copyDelivery(delivery *storage.WebhookDelivery) *storage.WebhookDelivery {
+	c := *delivery
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		c.DeliveredAt = &deliveredAt
+	}
+	return &c
+}
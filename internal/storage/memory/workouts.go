@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sort"
+	"time"
+)
+
+// GetWorkout retrieves a workout with its records by the workout ID.
+func (s *Storage) GetWorkout(_ context.Context, workoutID *string) (*storage.WorkoutWithRecords, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	workout, ok := s.workouts[*workoutID]
+	if !ok {
+		return nil, storage.ErrWorkoutNotFound
+	}
+	return copyWorkout(workout), nil
+}
+
+// StreamWorkouts passes user's workouts started in [from, to) to fn ordered by start time.
+// The workouts are copied first, so fn runs without holding the lock.
+func (s *Storage) StreamWorkouts(_ context.Context, userID *string, from time.Time, to time.Time, fn func(*storage.WorkoutWithRecords) error) error {
+	s.mu.RLock()
+	var workouts []*storage.WorkoutWithRecords
+	for _, workout := range s.workouts {
+		if workout.UserID == *userID && !workout.StartTime.Before(from) && workout.StartTime.Before(to) {
+			workouts = append(workouts, copyWorkout(workout))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(workouts, func(i, j int) bool {
+		if !workouts[i].StartTime.Equal(workouts[j].StartTime) {
+			return workouts[i].StartTime.Before(workouts[j].StartTime)
+		}
+		return workouts[i].WorkoutID < workouts[j].WorkoutID
+	})
+	for _, workout := range workouts {
+		if err := fn(workout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveWorkout stores the finished session as a workout and adds its points to the user.
+// Sessions without records are not saved, records without a status are saved as ok.
+func (s *Storage) SaveWorkout(_ context.Context, session *storage.WorkoutSession) error {
+	if len(session.Records) < 1 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.users[session.UserID]; ok {
+		user.Points += session.Points
+		user.SeasonPoints += session.Points
+	}
+	records := append([]storage.Record(nil), session.Records...)
+	for i := range records {
+		if records[i].Status == "" {
+			records[i].Status = storage.RecordOK
+		}
+	}
+	s.workouts[session.SessionID] = &storage.WorkoutWithRecords{
+		UserID:    session.UserID,
+		WorkoutID: session.SessionID,
+		StartTime: session.StartTime,
+		EndTime:   session.LastUpdated,
+		Records:   records,
+		Points:    session.Points,
+		GymID:     session.GymID,
+	}
+	return nil
+}
+
+func copyWorkout(workout *storage.WorkoutWithRecords) *storage.WorkoutWithRecords {
+	c := *workout
+	c.Records = append([]storage.Record(nil), workout.Records...)
+	return &c
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	storage "GYMBRO/internal/storage"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,17 +14,45 @@ type SessionRepository struct {
 	mock.Mock
 }
 
-// CreateSession provides a mock function with given fields: _a0
-func (_m *SessionRepository) CreateSession(_a0 *storage.WorkoutSession) error {
+// CountSessions provides a mock function with given fields: _a0
+func (_m *SessionRepository) CountSessions(_a0 context.Context) (int, error) {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for CountSessions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSession provides a mock function with given fields: _a0, _a1
+func (_m *SessionRepository) CreateSession(_a0 context.Context, _a1 *storage.WorkoutSession) error {
+	ret := _m.Called(_a0, _a1)
+
 	if len(ret) == 0 {
 		panic("no return value specified for CreateSession")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*storage.WorkoutSession) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.WorkoutSession) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,17 +60,17 @@ func (_m *SessionRepository) CreateSession(_a0 *storage.WorkoutSession) error {
 	return r0
 }
 
-// DeleteSession provides a mock function with given fields: _a0
-func (_m *SessionRepository) DeleteSession(_a0 *string) error {
-	ret := _m.Called(_a0)
+// DeleteSession provides a mock function with given fields: _a0, _a1
+func (_m *SessionRepository) DeleteSession(_a0 context.Context, _a1 *string) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteSession")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*string) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,9 +78,9 @@ func (_m *SessionRepository) DeleteSession(_a0 *string) error {
 	return r0
 }
 
-// GetAllSessions provides a mock function with given fields:
-func (_m *SessionRepository) GetAllSessions() ([]*storage.WorkoutSession, error) {
-	ret := _m.Called()
+// GetAllSessions provides a mock function with given fields: _a0
+func (_m *SessionRepository) GetAllSessions(_a0 context.Context) ([]*storage.WorkoutSession, error) {
+	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllSessions")
@@ -59,19 +88,19 @@ func (_m *SessionRepository) GetAllSessions() ([]*storage.WorkoutSession, error)
 
 	var r0 []*storage.WorkoutSession
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]*storage.WorkoutSession, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*storage.WorkoutSession, error)); ok {
+		return rf(_a0)
 	}
-	if rf, ok := ret.Get(0).(func() []*storage.WorkoutSession); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*storage.WorkoutSession); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*storage.WorkoutSession)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,9 +108,9 @@ func (_m *SessionRepository) GetAllSessions() ([]*storage.WorkoutSession, error)
 	return r0, r1
 }
 
-// GetSession provides a mock function with given fields: _a0
-func (_m *SessionRepository) GetSession(_a0 *string) (*storage.WorkoutSession, error) {
-	ret := _m.Called(_a0)
+// GetSession provides a mock function with given fields: _a0, _a1
+func (_m *SessionRepository) GetSession(_a0 context.Context, _a1 *string) (*storage.WorkoutSession, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetSession")
@@ -89,19 +118,19 @@ func (_m *SessionRepository) GetSession(_a0 *string) (*storage.WorkoutSession, e
 
 	var r0 *storage.WorkoutSession
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string) (*storage.WorkoutSession, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (*storage.WorkoutSession, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*string) *storage.WorkoutSession); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) *storage.WorkoutSession); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*storage.WorkoutSession)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,17 +138,47 @@ func (_m *SessionRepository) GetSession(_a0 *string) (*storage.WorkoutSession, e
 	return r0, r1
 }
 
-// UpdateSession provides a mock function with given fields: _a0, _a1
-func (_m *SessionRepository) UpdateSession(_a0 *string, _a1 *storage.WorkoutSession) error {
-	ret := _m.Called(_a0, _a1)
+// GetSessions provides a mock function with given fields: ctx, userIDs
+func (_m *SessionRepository) GetSessions(ctx context.Context, userIDs []string) ([]*storage.WorkoutSession, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessions")
+	}
+
+	var r0 []*storage.WorkoutSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*storage.WorkoutSession, error)); ok {
+		return rf(ctx, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*storage.WorkoutSession); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.WorkoutSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSession provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SessionRepository) UpdateSession(_a0 context.Context, _a1 *string, _a2 *storage.WorkoutSession) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateSession")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*string, *storage.WorkoutSession) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *storage.WorkoutSession) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
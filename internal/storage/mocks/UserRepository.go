@@ -4,6 +4,7 @@ package mocks
 
 import (
 	storage "GYMBRO/internal/storage"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,16 +14,16 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// ChangeStatus provides a mock function with given fields: _a0, _a1
-func (_m *UserRepository) ChangeStatus(_a0 *string, _a1 bool) error {
+// AddUserIdentity provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) AddUserIdentity(_a0 context.Context, _a1 *storage.Identity) error {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ChangeStatus")
+		panic("no return value specified for AddUserIdentity")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*string, bool) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.Identity) error); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
@@ -31,9 +32,105 @@ func (_m *UserRepository) ChangeStatus(_a0 *string, _a1 bool) error {
 	return r0
 }
 
-// GetUserByEmail provides a mock function with given fields: _a0
-func (_m *UserRepository) GetUserByEmail(_a0 *string) (*storage.User, error) {
-	ret := _m.Called(_a0)
+// ChangeStatus provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) ChangeStatus(_a0 context.Context, _a1 *string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserIdentity provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) DeleteUserIdentity(_a0 context.Context, _a1 *string, _a2 *string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetClanMembers provides a mock function with given fields: ctx, clanID
+func (_m *UserRepository) GetClanMembers(ctx context.Context, clanID *string) ([]*storage.User, error) {
+	ret := _m.Called(ctx, clanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClanMembers")
+	}
+
+	var r0 []*storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) ([]*storage.User, error)); ok {
+		return rf(ctx, clanID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) []*storage.User); ok {
+		r0 = rf(ctx, clanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(ctx, clanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGymMembers provides a mock function with given fields: ctx, gymID
+func (_m *UserRepository) GetGymMembers(ctx context.Context, gymID int) ([]*storage.User, error) {
+	ret := _m.Called(ctx, gymID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGymMembers")
+	}
+
+	var r0 []*storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*storage.User, error)); ok {
+		return rf(ctx, gymID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*storage.User); ok {
+		r0 = rf(ctx, gymID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, gymID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByEmail provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserByEmail(_a0 context.Context, _a1 *string) (*storage.User, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByEmail")
@@ -41,19 +138,19 @@ func (_m *UserRepository) GetUserByEmail(_a0 *string) (*storage.User, error) {
 
 	var r0 *storage.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string) (*storage.User, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (*storage.User, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*string) *storage.User); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) *storage.User); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*storage.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,9 +158,9 @@ func (_m *UserRepository) GetUserByEmail(_a0 *string) (*storage.User, error) {
 	return r0, r1
 }
 
-// GetUserByID provides a mock function with given fields: _a0
-func (_m *UserRepository) GetUserByID(_a0 *string) (*storage.User, error) {
-	ret := _m.Called(_a0)
+// GetUserByID provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserByID(_a0 context.Context, _a1 *string) (*storage.User, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByID")
@@ -71,19 +168,49 @@ func (_m *UserRepository) GetUserByID(_a0 *string) (*storage.User, error) {
 
 	var r0 *storage.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string) (*storage.User, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (*storage.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) *storage.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByIdentity provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetUserByIdentity(_a0 context.Context, _a1 *string, _a2 *string) (*storage.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByIdentity")
+	}
+
+	var r0 *storage.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string) (*storage.User, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(*string) *storage.User); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string) *storage.User); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*storage.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *string, *string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -91,28 +218,28 @@ func (_m *UserRepository) GetUserByID(_a0 *string) (*storage.User, error) {
 	return r0, r1
 }
 
-// GetUserMax provides a mock function with given fields: _a0, _a1
-func (_m *UserRepository) GetUserMax(_a0 *string, _a1 *int) (*storage.Max, error) {
+// GetUserIdentities provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserIdentities(_a0 context.Context, _a1 *string) ([]*storage.Identity, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserMax")
+		panic("no return value specified for GetUserIdentities")
 	}
 
-	var r0 *storage.Max
+	var r0 []*storage.Identity
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string, *int) (*storage.Max, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *string) ([]*storage.Identity, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*string, *int) *storage.Max); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *string) []*storage.Identity); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*storage.Max)
+			r0 = ret.Get(0).([]*storage.Identity)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string, *int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -121,9 +248,39 @@ func (_m *UserRepository) GetUserMax(_a0 *string, _a1 *int) (*storage.Max, error
 	return r0, r1
 }
 
-// GetUserMaxes provides a mock function with given fields: _a0
-func (_m *UserRepository) GetUserMaxes(_a0 *string) ([]*storage.Max, error) {
-	ret := _m.Called(_a0)
+// GetUserMax provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) GetUserMax(_a0 context.Context, _a1 *string, _a2 *int) (*storage.Max, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserMax")
+	}
+
+	var r0 *storage.Max
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *int) (*storage.Max, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *int) *storage.Max); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Max)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string, *int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserMaxes provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) GetUserMaxes(_a0 context.Context, _a1 *string) ([]*storage.Max, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserMaxes")
@@ -131,19 +288,19 @@ func (_m *UserRepository) GetUserMaxes(_a0 *string) ([]*storage.Max, error) {
 
 	var r0 []*storage.Max
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string) ([]*storage.Max, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) ([]*storage.Max, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*string) []*storage.Max); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) []*storage.Max); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*storage.Max)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -151,9 +308,27 @@ func (_m *UserRepository) GetUserMaxes(_a0 *string) ([]*storage.Max, error) {
 	return r0, r1
 }
 
-// RegisterNewUser provides a mock function with given fields: _a0
-func (_m *UserRepository) RegisterNewUser(_a0 *storage.User) (*string, error) {
-	ret := _m.Called(_a0)
+// MergeUsers provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) MergeUsers(_a0 context.Context, _a1 *string, _a2 *string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeUsers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RegisterNewUser provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) RegisterNewUser(_a0 context.Context, _a1 *storage.User) (*string, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RegisterNewUser")
@@ -161,19 +336,19 @@ func (_m *UserRepository) RegisterNewUser(_a0 *storage.User) (*string, error) {
 
 	var r0 *string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*storage.User) (*string, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.User) (*string, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*storage.User) *string); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.User) *string); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*storage.User) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *storage.User) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -181,17 +356,137 @@ func (_m *UserRepository) RegisterNewUser(_a0 *storage.User) (*string, error) {
 	return r0, r1
 }
 
-// SetUserMax provides a mock function with given fields: _a0, _a1
-func (_m *UserRepository) SetUserMax(_a0 *string, _a1 *storage.Max) error {
-	ret := _m.Called(_a0, _a1)
+// RegisterOAuthUser provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) RegisterOAuthUser(_a0 context.Context, _a1 *storage.User, _a2 *storage.Identity) (*string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterOAuthUser")
+	}
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.User, *storage.Identity) (*string, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.User, *storage.Identity) *string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *storage.User, *storage.Identity) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetUserBodyWeight provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserBodyWeight(_a0 context.Context, _a1 *string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserBodyWeight")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserDisabled provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserDisabled(_a0 context.Context, _a1 *string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserDisabled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserFlagged provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserFlagged(_a0 context.Context, _a1 *string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserFlagged")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserMax provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserMax(_a0 context.Context, _a1 *string, _a2 *storage.Max) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetUserMax")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*string, *storage.Max) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *storage.Max) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserPrivate provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserPrivate(_a0 context.Context, _a1 *string, _a2 bool) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserPrivate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, bool) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserRole provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) SetUserRole(_a0 context.Context, _a1 *string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
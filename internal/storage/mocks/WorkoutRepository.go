@@ -4,8 +4,11 @@ package mocks
 
 import (
 	storage "GYMBRO/internal/storage"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // WorkoutRepository is an autogenerated mock type for the WorkoutRepository type
@@ -13,9 +16,9 @@ type WorkoutRepository struct {
 	mock.Mock
 }
 
-// GetWorkout provides a mock function with given fields: _a0
-func (_m *WorkoutRepository) GetWorkout(_a0 *string) (*storage.WorkoutWithRecords, error) {
-	ret := _m.Called(_a0)
+// GetWorkout provides a mock function with given fields: _a0, _a1
+func (_m *WorkoutRepository) GetWorkout(_a0 context.Context, _a1 *string) (*storage.WorkoutWithRecords, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetWorkout")
@@ -23,19 +26,19 @@ func (_m *WorkoutRepository) GetWorkout(_a0 *string) (*storage.WorkoutWithRecord
 
 	var r0 *storage.WorkoutWithRecords
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*string) (*storage.WorkoutWithRecords, error)); ok {
-		return rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (*storage.WorkoutWithRecords, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(*string) *storage.WorkoutWithRecords); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *string) *storage.WorkoutWithRecords); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*storage.WorkoutWithRecords)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,17 +46,35 @@ func (_m *WorkoutRepository) GetWorkout(_a0 *string) (*storage.WorkoutWithRecord
 	return r0, r1
 }
 
-// SaveWorkout provides a mock function with given fields: _a0
-func (_m *WorkoutRepository) SaveWorkout(_a0 *storage.WorkoutSession) error {
-	ret := _m.Called(_a0)
+// SaveWorkout provides a mock function with given fields: _a0, _a1
+func (_m *WorkoutRepository) SaveWorkout(_a0 context.Context, _a1 *storage.WorkoutSession) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveWorkout")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*storage.WorkoutSession) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *storage.WorkoutSession) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamWorkouts provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *WorkoutRepository) StreamWorkouts(_a0 context.Context, _a1 *string, _a2 time.Time, _a3 time.Time, _a4 func(*storage.WorkoutWithRecords) error) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamWorkouts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, time.Time, time.Time, func(*storage.WorkoutWithRecords) error) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// AwardAchievement stores the achievement unless the user already has it
+func (s *Storage) AwardAchievement(ctx context.Context, achievement *storage.Achievement) (bool, error) {
+	const op = "storage.postgresql.AwardAchievement"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `INSERT INTO achievements (fk_user_id, code, awarded_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		achievement.UserID, achievement.Code, achievement.AwardedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return false, storage.ErrUserNotFound
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetAchievements retrieves user's achievements ordered by award time
+func (s *Storage) GetAchievements(ctx context.Context, userID *string) ([]*storage.Achievement, error) {
+	const op = "storage.postgresql.GetAchievements"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT fk_user_id, code, awarded_at FROM achievements WHERE fk_user_id = $1 ORDER BY awarded_at, code`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var achievements []*storage.Achievement
+	for rows.Next() {
+		var achievement storage.Achievement
+		if err := rows.Scan(&achievement.UserID, &achievement.Code, &achievement.AwardedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		achievements = append(achievements, &achievement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return achievements, nil
+}
@@ -0,0 +1,285 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+	"time"
+)
+
+const challengeColumns = `challenge_id, name, goal, COALESCE(fk_exercise_id, 0), target, reward, start_time, end_time, settled_at, created_at`
+
+const resultColumns = `fk_challenge_id, fk_clan_id, progress, rank, completed, points`
+
+// querier is a pool or a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func scanChallenge(row pgx.Row) (*storage.Challenge, error) {
+	var challenge storage.Challenge
+	err := row.Scan(&challenge.ChallengeID, &challenge.Name, &challenge.Goal, &challenge.FkExerciseId, &challenge.Target, &challenge.Reward,
+		&challenge.StartTime, &challenge.EndTime, &challenge.SettledAt, &challenge.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// CreateChallenge stores a challenge
+func (s *Storage) CreateChallenge(ctx context.Context, challenge *storage.Challenge) error {
+	const op = "storage.postgresql.CreateChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO challenges (challenge_id, name, goal, fk_exercise_id, target, reward, start_time, end_time, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6, $7, $8, $9)`,
+		challenge.ChallengeID, challenge.Name, challenge.Goal, challenge.FkExerciseId, challenge.Target, challenge.Reward,
+		challenge.StartTime, challenge.EndTime, challenge.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return storage.ErrExerciseNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetChallenge retrieves a challenge by its ID
+func (s *Storage) GetChallenge(ctx context.Context, challengeID *string) (*storage.Challenge, error) {
+	const op = "storage.postgresql.GetChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	challenge, err := scanChallenge(s.db.QueryRow(ctx, `SELECT `+challengeColumns+` FROM challenges WHERE challenge_id = $1`, challengeID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return challenge, nil
+}
+
+// GetChallenges retrieves the challenges not settled yet ordered by end time, or the settled ones, the last ended first
+func (s *Storage) GetChallenges(ctx context.Context, settled bool) ([]*storage.Challenge, error) {
+	const op = "storage.postgresql.GetChallenges"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query := `SELECT ` + challengeColumns + ` FROM challenges WHERE settled_at IS NULL ORDER BY end_time, challenge_id`
+	if settled {
+		query = `SELECT ` + challengeColumns + ` FROM challenges WHERE settled_at IS NOT NULL ORDER BY end_time DESC, challenge_id`
+	}
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var challenges []*storage.Challenge
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenges, nil
+}
+
+// challengeProgress sums the workouts started in the challenge window per clan of their users,
+// users in the default clan are not in a clan and are left out
+func challengeProgress(ctx context.Context, db querier, challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	args := []any{challenge.StartTime, challenge.EndTime}
+	var progress, records string
+	switch challenge.Goal {
+	case storage.GoalPoints:
+		progress = `SUM(w.points)`
+	case storage.GoalWorkouts:
+		progress = `COUNT(*)`
+	case storage.GoalTonnage:
+		progress = `SUM(r.reps * r.weight)`
+		records = `JOIN records r ON r.fk_workout_id = w.workout_id AND r.fk_exercise_id = $3 AND r.status = 'ok'`
+		args = append(args, challenge.FkExerciseId)
+	default:
+		return nil, fmt.Errorf("unknown goal %q", challenge.Goal)
+	}
+
+	rows, err := db.Query(ctx, fmt.Sprintf(`SELECT u.fk_clan_id, %[1]s
+		FROM workouts w
+		JOIN users u ON u.user_id = w.fk_user_id
+		%[2]s
+		WHERE w.start_time >= $1 AND w.start_time < $2 AND u.fk_clan_id IS NOT NULL AND u.fk_clan_id <> '0'
+		GROUP BY u.fk_clan_id
+		HAVING %[1]s > 0`, progress, records), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*storage.ChallengeResult
+	for rows.Next() {
+		result := &storage.ChallengeResult{FkChallengeId: challenge.ChallengeID}
+		if err := rows.Scan(&result.FkClanId, &result.Progress); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	storage.RankResults(challenge, results)
+	return results, nil
+}
+
+// GetChallengeProgress computes the live progress of the clans in the challenge
+func (s *Storage) GetChallengeProgress(ctx context.Context, challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	const op = "storage.postgresql.GetChallengeProgress"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	results, err := challengeProgress(ctx, s.db, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+// SettleChallenge stores the final progress of the clans and awards the reward in one transaction.
+// Marking the challenge settled first locks its row, so a concurrent settlement waits and then finds it settled.
+func (s *Storage) SettleChallenge(ctx context.Context, challengeID *string, settledAt time.Time) ([]*storage.ChallengeResult, error) {
+	const op = "storage.postgresql.SettleChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	challenge, err := scanChallenge(tx.QueryRow(ctx, `UPDATE challenges SET settled_at = $2 WHERE challenge_id = $1 AND settled_at IS NULL
+		RETURNING `+challengeColumns, challengeID, settledAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM challenges WHERE challenge_id = $1)`, challengeID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("%s, existsQuery: %w", op, err)
+		}
+		if !exists {
+			return nil, storage.ErrChallengeNotFound
+		}
+		return nil, storage.ErrChallengeSettled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s, settleQuery: %w", op, err)
+	}
+
+	results, err := challengeProgress(ctx, tx, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s, progressQuery: %w", op, err)
+	}
+	if len(results) == 0 {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return results, nil
+	}
+
+	inParams := make([]string, 0, len(results))
+	args := make([]interface{}, 0, len(results)*6)
+	var completed []string
+	for i, result := range results {
+		if result.Completed {
+			result.Points = challenge.Reward
+			completed = append(completed, result.FkClanId)
+		}
+		inParams = append(inParams, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6))
+		args = append(args, result.FkChallengeId, result.FkClanId, result.Progress, result.Rank, result.Completed, result.Points)
+	}
+
+	resultsQuery := fmt.Sprintf(`INSERT INTO challengeresults (%s) VALUES %s`, resultColumns, strings.Join(inParams, ", "))
+	if _, err := tx.Exec(ctx, resultsQuery, args...); err != nil {
+		return nil, fmt.Errorf("%s, resultsQuery: %w", op, err)
+	}
+
+	if len(completed) > 0 && challenge.Reward > 0 {
+		if _, err := tx.Exec(ctx, `UPDATE clans SET points = points + $1, season_points = season_points + $1 WHERE clan_id = ANY($2)`, challenge.Reward, completed); err != nil {
+			return nil, fmt.Errorf("%s, pointsQuery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+func (s *Storage) getResults(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.ChallengeResult, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var results []*storage.ChallengeResult
+	for rows.Next() {
+		result := &storage.ChallengeResult{}
+		if err := rows.Scan(&result.FkChallengeId, &result.FkClanId, &result.Progress, &result.Rank, &result.Completed, &result.Points); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// GetChallengeResults retrieves the stored results of a settled challenge, ranked
+func (s *Storage) GetChallengeResults(ctx context.Context, challengeID *string) ([]*storage.ChallengeResult, error) {
+	const op = "storage.postgresql.GetChallengeResults"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getResults(ctx, op, `SELECT `+resultColumns+` FROM challengeresults WHERE fk_challenge_id = $1 ORDER BY rank, fk_clan_id`, challengeID)
+}
+
+// GetClanResults retrieves the results of the clan in settled challenges, the last ended first
+func (s *Storage) GetClanResults(ctx context.Context, clanID *string) ([]*storage.ChallengeResult, error) {
+	const op = "storage.postgresql.GetClanResults"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getResults(ctx, op, `SELECT r.fk_challenge_id, r.fk_clan_id, r.progress, r.rank, r.completed, r.points
+		FROM challengeresults r
+		JOIN challenges c ON c.challenge_id = r.fk_challenge_id
+		WHERE r.fk_clan_id = $1
+		ORDER BY c.end_time DESC, c.challenge_id`, clanID)
+}
+
+// GetClan retrieves a clan with its points
+func (s *Storage) GetClan(ctx context.Context, clanID *string) (*storage.Clan, error) {
+	const op = "storage.postgresql.GetClan"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var clan storage.Clan
+	err := s.db.QueryRow(ctx, `SELECT clan_id, COALESCE(fk_owner_id, ''), name, COALESCE(description, ''), points, season_points, COALESCE(created_at, 'epoch'::timestamp)
+		FROM clans WHERE clan_id = $1`, clanID).
+		Scan(&clan.ClanId, &clan.FkOwnerId, &clan.Name, &clan.Description, &clan.Points, &clan.SeasonPoints, &clan.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrClanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &clan, nil
+}
@@ -0,0 +1,76 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// AddEvents stores the events in one transaction
+func (s *Storage) AddEvents(ctx context.Context, events []*storage.Event) error {
+	const op = "storage.postgresql.AddEvents"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, event := range events {
+		_, err := tx.Exec(ctx, `INSERT INTO events (event_id, fk_user_id, type, workout_id, points, exercise_id, weight, reps, code, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			event.EventID, event.UserID, event.Type, event.WorkoutID, event.Points, event.ExerciseID, event.Weight, event.Reps, event.Code, event.CreatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return storage.ErrUserNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetFeed retrieves events of the users the user follows, newest first. The page starts after the cursor.
+func (s *Storage) GetFeed(ctx context.Context, userID *string, cursor *storage.FeedCursor, limit int) ([]*storage.Event, error) {
+	const op = "storage.postgresql.GetFeed"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query := `SELECT e.event_id, e.fk_user_id, e.type, e.workout_id, e.points, e.exercise_id, e.weight, e.reps, e.code, e.created_at
+		FROM events e JOIN follows f ON f.followee_id = e.fk_user_id
+		WHERE f.follower_id = $1 AND f.status = $2`
+	args := []interface{}{userID, storage.FollowAccepted}
+	if cursor != nil {
+		query += ` AND (e.created_at, e.event_id) < ($3, $4)`
+		args = append(args, cursor.CreatedAt, cursor.EventID)
+	}
+	query += fmt.Sprintf(` ORDER BY e.created_at DESC, e.event_id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []*storage.Event
+	for rows.Next() {
+		var event storage.Event
+		err := rows.Scan(&event.EventID, &event.UserID, &event.Type, &event.WorkoutID, &event.Points, &event.ExerciseID, &event.Weight, &event.Reps, &event.Code, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,268 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// exerciseQuery selects exercises with IDs of the muscle groups they target, %s is the filter, see scanExercise
+const exerciseQuery = `SELECT e.exercise_id, e.name, COALESCE(e.description, ''), COALESCE(e.picture, ''), e.weight_limit,
+		COALESCE(array_agg(emg.muscle_group_id ORDER BY emg.muscle_group_id) FILTER (WHERE emg.muscle_group_id IS NOT NULL), '{}')
+		FROM exercises e
+		LEFT JOIN exercisemusclegroups emg ON emg.exercise_id = e.exercise_id
+		%s
+		GROUP BY e.exercise_id
+		ORDER BY e.exercise_id`
+
+func scanExercise(row pgx.Row) (*storage.Exercise, error) {
+	exercise := &storage.Exercise{}
+	var muscleGroups []int32
+	if err := row.Scan(&exercise.ExerciseId, &exercise.Name, &exercise.Description, &exercise.Picture, &exercise.WeightLimit, &muscleGroups); err != nil {
+		return nil, err
+	}
+	exercise.MuscleGroupIds = make([]int, 0, len(muscleGroups))
+	for _, id := range muscleGroups {
+		exercise.MuscleGroupIds = append(exercise.MuscleGroupIds, int(id))
+	}
+	return exercise, nil
+}
+
+// GetExercises retrieves all exercises with IDs of the muscle groups they target
+func (s *Storage) GetExercises(ctx context.Context) ([]*storage.Exercise, error) {
+	const op = "storage.postgresql.GetExercises"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, fmt.Sprintf(exerciseQuery, ""))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var exercises []*storage.Exercise
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exercises, nil
+}
+
+// GetExercise retrieves an exercise with IDs of the muscle groups it targets
+func (s *Storage) GetExercise(ctx context.Context, exerciseID int) (*storage.Exercise, error) {
+	const op = "storage.postgresql.GetExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	exercise, err := scanExercise(s.db.QueryRow(ctx, fmt.Sprintf(exerciseQuery, "WHERE e.exercise_id = $1"), exerciseID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrExerciseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return exercise, nil
+}
+
+// CreateExercise creates an exercise with its muscle groups and returns its ID
+func (s *Storage) CreateExercise(ctx context.Context, exercise *storage.Exercise) (*int, error) {
+	const op = "storage.postgresql.CreateExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	err = tx.QueryRow(ctx, `INSERT INTO exercises (name, description, picture, weight_limit) VALUES ($1, $2, $3, $4) RETURNING exercise_id`,
+		exercise.Name, exercise.Description, exercise.Picture, exercise.WeightLimit).Scan(&id)
+	if err != nil {
+		return nil, exerciseError(op, err)
+	}
+
+	if err := setExerciseMuscleGroups(ctx, tx, id, exercise.MuscleGroupIds); err != nil {
+		return nil, exerciseError(op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &id, nil
+}
+
+// UpdateExercise updates an exercise and replaces its muscle groups
+func (s *Storage) UpdateExercise(ctx context.Context, exercise *storage.Exercise) error {
+	const op = "storage.postgresql.UpdateExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE exercises SET name = $1, description = $2, picture = $3, weight_limit = $4 WHERE exercise_id = $5`,
+		exercise.Name, exercise.Description, exercise.Picture, exercise.WeightLimit, exercise.ExerciseId)
+	if err != nil {
+		return exerciseError(op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrExerciseNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM exercisemusclegroups WHERE exercise_id = $1`, exercise.ExerciseId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := setExerciseMuscleGroups(ctx, tx, exercise.ExerciseId, exercise.MuscleGroupIds); err != nil {
+		return exerciseError(op, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteExercise deletes an exercise unless records, maxes or challenges reference it. The exercise row is locked first,
+// so a record inserted meanwhile waits for the delete and fails on the foreign key instead of being deleted by the cascade.
+func (s *Storage) DeleteExercise(ctx context.Context, exerciseID *int) error {
+	const op = "storage.postgresql.DeleteExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var inUse bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM records WHERE fk_exercise_id = e.exercise_id)
+		OR EXISTS (SELECT 1 FROM userexercisemaxweights WHERE exercise_id = e.exercise_id)
+		OR EXISTS (SELECT 1 FROM challenges WHERE fk_exercise_id = e.exercise_id)
+		FROM exercises e WHERE e.exercise_id = $1 FOR UPDATE`, exerciseID).Scan(&inUse)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrExerciseNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s, usageQuery: %w", op, err)
+	}
+	if inUse {
+		return storage.ErrExerciseInUse
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM exercises WHERE exercise_id = $1`, exerciseID); err != nil {
+		return fmt.Errorf("%s, deleteQuery: %w", op, err)
+	}
+	return tx.Commit(ctx)
+}
+
+// GetMuscleGroups retrieves all muscle groups
+func (s *Storage) GetMuscleGroups(ctx context.Context) ([]*storage.MuscleGroup, error) {
+	const op = "storage.postgresql.GetMuscleGroups"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT muscle_group_id, name FROM musclegroups ORDER BY muscle_group_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var groups []*storage.MuscleGroup
+	for rows.Next() {
+		group := &storage.MuscleGroup{}
+		if err := rows.Scan(&group.MuscleGroupId, &group.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// CreateMuscleGroup creates a muscle group and returns its ID
+func (s *Storage) CreateMuscleGroup(ctx context.Context, group *storage.MuscleGroup) (*int, error) {
+	const op = "storage.postgresql.CreateMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var id int
+	err := s.db.QueryRow(ctx, `INSERT INTO musclegroups (name) VALUES ($1) RETURNING muscle_group_id`, group.Name).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, storage.ErrMuscleGroupExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &id, nil
+}
+
+// UpdateMuscleGroup renames a muscle group
+func (s *Storage) UpdateMuscleGroup(ctx context.Context, group *storage.MuscleGroup) error {
+	const op = "storage.postgresql.UpdateMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE musclegroups SET name = $1 WHERE muscle_group_id = $2`, group.Name, group.MuscleGroupId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storage.ErrMuscleGroupExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrMuscleGroupNotFound
+	}
+	return nil
+}
+
+// DeleteMuscleGroup deletes a muscle group (links to exercises are deleted by cascade)
+func (s *Storage) DeleteMuscleGroup(ctx context.Context, groupID *int) error {
+	const op = "storage.postgresql.DeleteMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `DELETE FROM musclegroups WHERE muscle_group_id = $1`, groupID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrMuscleGroupNotFound
+	}
+	return nil
+}
+
+func setExerciseMuscleGroups(ctx context.Context, tx pgx.Tx, exerciseID int, groupIDs []int) error {
+	for _, groupID := range groupIDs {
+		_, err := tx.Exec(ctx, `INSERT INTO exercisemusclegroups (exercise_id, muscle_group_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, exerciseID, groupID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exerciseError maps unique (name) and foreign key (muscle group) violations to storage errors
+func exerciseError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return storage.ErrExerciseExists
+		case "23503":
+			return storage.ErrMuscleGroupNotFound
+		}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
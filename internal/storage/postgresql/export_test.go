@@ -0,0 +1,24 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+)
+
+// JoinNewClan puts the user in a new clan, the storage has no way to create clans.
+func (s *Storage) JoinNewClan(ctx context.Context, userID string) (string, error) {
+	clanID := storage.GenerateUID()
+	if _, err := s.db.Exec(ctx, `INSERT INTO clans (clan_id, name) VALUES ($1, $2)`, clanID, "clan-"+clanID); err != nil {
+		return "", err
+	}
+	if _, err := s.db.Exec(ctx, `UPDATE users SET fk_clan_id = $1 WHERE user_id = $2`, clanID, userID); err != nil {
+		return "", err
+	}
+	return clanID, nil
+}
+
+// AwardClan adds points to the clan the way a settled challenge does.
+func (s *Storage) AwardClan(ctx context.Context, clanID string, points int) error {
+	_, err := s.db.Exec(ctx, `UPDATE clans SET points = points + $2, season_points = season_points + $2 WHERE clan_id = $1`, clanID, points)
+	return err
+}
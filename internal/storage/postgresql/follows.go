@@ -0,0 +1,102 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Follow stores a follow request with its status
+func (s *Storage) Follow(ctx context.Context, follow *storage.Follow) error {
+	const op = "storage.postgresql.Follow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO follows (follower_id, followee_id, status, created_at) VALUES ($1, $2, $3, $4)`,
+		follow.FollowerID, follow.FolloweeID, follow.Status, follow.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return storage.ErrFollowExists
+			case "23503":
+				return storage.ErrUserNotFound
+			}
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Unfollow deletes the follow, pending or accepted
+func (s *Storage) Unfollow(ctx context.Context, followerID *string, followeeID *string) error {
+	const op = "storage.postgresql.Unfollow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrFollowNotFound
+	}
+	return nil
+}
+
+// AcceptFollow accepts a pending follow request
+func (s *Storage) AcceptFollow(ctx context.Context, followerID *string, followeeID *string) error {
+	const op = "storage.postgresql.AcceptFollow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE follows SET status = $1 WHERE follower_id = $2 AND followee_id = $3 AND status = $4`,
+		storage.FollowAccepted, followerID, followeeID, storage.FollowPending)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrFollowNotFound
+	}
+	return nil
+}
+
+// GetFollowers retrieves follows to the user, newest first
+func (s *Storage) GetFollowers(ctx context.Context, userID *string) ([]*storage.Follow, error) {
+	const op = "storage.postgresql.GetFollowers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getFollows(ctx, op, `SELECT follower_id, followee_id, status, created_at FROM follows WHERE followee_id = $1 ORDER BY created_at DESC, follower_id`, userID)
+}
+
+// GetFollowing retrieves follows of the user, newest first
+func (s *Storage) GetFollowing(ctx context.Context, userID *string) ([]*storage.Follow, error) {
+	const op = "storage.postgresql.GetFollowing"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getFollows(ctx, op, `SELECT follower_id, followee_id, status, created_at FROM follows WHERE follower_id = $1 ORDER BY created_at DESC, followee_id`, userID)
+}
+
+func (s *Storage) getFollows(ctx context.Context, op string, query string, userID *string) ([]*storage.Follow, error) {
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var follows []*storage.Follow
+	for rows.Next() {
+		var follow storage.Follow
+		if err := rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.Status, &follow.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		follows = append(follows, &follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strings"
+	"time"
+)
+
+// CreateSubscription stores a subscription of the user to the gym
+func (s *Storage) CreateSubscription(ctx context.Context, subscription *storage.Subscription) error {
+	const op = "storage.postgresql.CreateSubscription"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO subscriptions (subscription_id, fk_user_id, fk_gym_id, start_date, end_date, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		subscription.SubscriptionId, subscription.FkUserId, subscription.FkGymId, subscription.StartDate, subscription.EndDate, subscription.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			if strings.Contains(pgErr.ConstraintName, "fk_gym_id") {
+				return storage.ErrGymNotFound
+			}
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetActiveSubscription retrieves the subscription of the user to the gym covering the day of at, the one ending last
+func (s *Storage) GetActiveSubscription(ctx context.Context, userID *string, gymID int, at time.Time) (*storage.Subscription, error) {
+	const op = "storage.postgresql.GetActiveSubscription"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var subscription storage.Subscription
+	day := at.UTC().Format(time.DateOnly)
+	err := s.db.QueryRow(ctx, `SELECT subscription_id, fk_user_id, fk_gym_id, start_date, end_date, created_at FROM subscriptions
+		WHERE fk_user_id = $1 AND fk_gym_id = $2 AND start_date <= $3::date AND end_date >= $3::date
+		ORDER BY end_date DESC, subscription_id LIMIT 1`, userID, gymID, day).
+		Scan(&subscription.SubscriptionId, &subscription.FkUserId, &subscription.FkGymId, &subscription.StartDate, &subscription.EndDate, &subscription.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrNoSubscription
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &subscription, nil
+}
+
+// CreateCheckIn stores a check-in
+func (s *Storage) CreateCheckIn(ctx context.Context, checkIn *storage.CheckIn) error {
+	const op = "storage.postgresql.CreateCheckIn"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO checkins (check_in_id, fk_user_id, fk_gym_id, fk_subscription_id, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		checkIn.CheckInID, checkIn.FkUserId, checkIn.FkGymId, checkIn.FkSubscriptionId, checkIn.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetLastCheckIn retrieves the latest check-in of the user made at or after since
+func (s *Storage) GetLastCheckIn(ctx context.Context, userID *string, since time.Time) (*storage.CheckIn, error) {
+	const op = "storage.postgresql.GetLastCheckIn"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var checkIn storage.CheckIn
+	err := s.db.QueryRow(ctx, `SELECT check_in_id, fk_user_id, fk_gym_id, fk_subscription_id, created_at FROM checkins
+		WHERE fk_user_id = $1 AND created_at >= $2 ORDER BY created_at DESC, check_in_id DESC LIMIT 1`, userID, since).
+		Scan(&checkIn.CheckInID, &checkIn.FkUserId, &checkIn.FkGymId, &checkIn.FkSubscriptionId, &checkIn.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrCheckInNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &checkIn, nil
+}
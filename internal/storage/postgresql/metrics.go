@@ -0,0 +1,56 @@
+package postgresql
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector exposes pgxpool statistics on every scrape.
+type poolCollector struct {
+	storage *Storage
+
+	acquiredConns *prometheus.Desc
+	idleConns     *prometheus.Desc
+	totalConns    *prometheus.Desc
+	maxConns      *prometheus.Desc
+	acquireCount  *prometheus.Desc
+	acquireTime   *prometheus.Desc
+	emptyAcquire  *prometheus.Desc
+}
+
+// NewPoolCollector returns a Prometheus collector with connection pool statistics of the storage.
+func NewPoolCollector(s *Storage) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("gymbro", "pgx_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		storage:       s,
+		acquiredConns: desc("acquired_connections", "Number of currently acquired connections."),
+		idleConns:     desc("idle_connections", "Number of currently idle connections."),
+		totalConns:    desc("total_connections", "Total number of connections in the pool."),
+		maxConns:      desc("max_connections", "Maximum size of the pool."),
+		acquireCount:  desc("acquire_total", "Number of successful acquires from the pool."),
+		acquireTime:   desc("acquire_duration_seconds_total", "Total time spent waiting for connections."),
+		emptyAcquire:  desc("empty_acquire_total", "Number of acquires that had to wait for a connection."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireTime
+	ch <- c.emptyAcquire
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.storage.db.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireTime, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+}
@@ -0,0 +1,173 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+const flaggedRecordQuery = `SELECT r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points, r.status, r.reason, w.fk_user_id, w.start_time
+	FROM records r
+	JOIN workouts w ON w.workout_id = r.fk_workout_id`
+
+// inSeason is true when the workout started at $2 earned seasonal points, without seasons every workout does
+const inSeason = `NOT EXISTS (SELECT 1 FROM seasons WHERE ended_at IS NULL AND start_time > $2)`
+
+func scanFlaggedRecord(row pgx.Row) (*storage.FlaggedRecord, error) {
+	var record storage.FlaggedRecord
+	err := row.Scan(&record.RecordId, &record.FkWorkoutId, &record.FkExerciseId, &record.Reps, &record.Weight, &record.Points,
+		&record.Status, &record.Reason, &record.UserID, &record.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetFlaggedRecords retrieves saved records that are held or excluded, the oldest workout first.
+func (s *Storage) GetFlaggedRecords(ctx context.Context, limit int) ([]*storage.FlaggedRecord, error) {
+	const op = "storage.postgresql.GetFlaggedRecords"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, flaggedRecordQuery+` WHERE r.status IN ('held', 'excluded')
+		ORDER BY w.start_time, w.workout_id, r.record_id LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.FlaggedRecord, 0)
+	for rows.Next() {
+		record, err := scanFlaggedRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return records, nil
+}
+
+// ApproveRecord makes a held record count and makes it the max of the user if it beats it, with prBonus added to its points then.
+// Its points are added to the workout and the user. The record is locked until the transaction ends.
+func (s *Storage) ApproveRecord(ctx context.Context, recordID *string, prBonus int) (*storage.FlaggedRecord, *storage.Max, error) {
+	const op = "storage.postgresql.ApproveRecord"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := scanFlaggedRecord(tx.QueryRow(ctx, flaggedRecordQuery+` WHERE r.record_id = $1 FOR UPDATE OF r`, recordID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, storage.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, recordQuery: %w", op, err)
+	}
+	if record.Status != storage.RecordHeld {
+		return nil, nil, storage.ErrRecordNotHeld
+	}
+
+	// nothing is inserted or updated when the max of the user is at least as good
+	tag, err := tx.Exec(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = EXCLUDED.max_weight, reps = EXCLUDED.reps
+		WHERE EXCLUDED.max_weight > userexercisemaxweights.max_weight
+			OR (EXCLUDED.max_weight = userexercisemaxweights.max_weight AND EXCLUDED.reps > userexercisemaxweights.reps)`,
+		record.UserID, record.FkExerciseId, record.Weight, record.Reps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+	}
+	var newMax *storage.Max
+	if tag.RowsAffected() > 0 {
+		newMax = &storage.Max{UserID: record.UserID, ExerciseId: record.FkExerciseId, MaxWeight: record.Weight, Reps: record.Reps}
+		record.Points += prBonus
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE records SET status = 'ok', points = $1 WHERE record_id = $2`, record.Points, record.RecordId); err != nil {
+		return nil, nil, fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	record.Status = storage.RecordOK
+
+	if _, err := tx.Exec(ctx, `UPDATE workouts SET points = points + $1 WHERE workout_id = $2`, record.Points, record.FkWorkoutId); err != nil {
+		return nil, nil, fmt.Errorf("%s, workoutQuery: %w", op, err)
+	}
+	_, err = tx.Exec(ctx, `UPDATE users SET points = points + $1, season_points = season_points + CASE WHEN `+inSeason+` THEN $1 ELSE 0 END
+		WHERE user_id = $3`, record.Points, record.StartTime, record.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, userQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return record, newMax, nil
+}
+
+// VoidRecord takes a record back. If it counted, its points are subtracted from the workout and the user
+// and the max of its exercise is rebuilt from the records that still count. The record is locked until the transaction ends.
+func (s *Storage) VoidRecord(ctx context.Context, recordID *string) (*storage.FlaggedRecord, error) {
+	const op = "storage.postgresql.VoidRecord"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	record, err := scanFlaggedRecord(tx.QueryRow(ctx, flaggedRecordQuery+` WHERE r.record_id = $1 FOR UPDATE OF r`, recordID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s, recordQuery: %w", op, err)
+	}
+	if record.Status == storage.RecordVoided {
+		return nil, storage.ErrRecordVoided
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE records SET status = 'voided' WHERE record_id = $1`, record.RecordId); err != nil {
+		return nil, fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	counted := record.Counts()
+	record.Status = storage.RecordVoided
+
+	if counted {
+		if _, err := tx.Exec(ctx, `UPDATE workouts SET points = points - $1 WHERE workout_id = $2`, record.Points, record.FkWorkoutId); err != nil {
+			return nil, fmt.Errorf("%s, workoutQuery: %w", op, err)
+		}
+		_, err = tx.Exec(ctx, `UPDATE users SET points = points - $1,
+			season_points = CASE WHEN `+inSeason+` THEN GREATEST(season_points - $1, 0) ELSE season_points END
+			WHERE user_id = $3`, record.Points, record.StartTime, record.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%s, userQuery: %w", op, err)
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM userexercisemaxweights WHERE user_id = $1 AND exercise_id = $2`, record.UserID, record.FkExerciseId)
+		if err != nil {
+			return nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps)
+			SELECT w.fk_user_id, r.fk_exercise_id, r.weight, r.reps
+			FROM records r
+			JOIN workouts w ON w.workout_id = r.fk_workout_id
+			WHERE w.fk_user_id = $1 AND r.fk_exercise_id = $2 AND r.status = 'ok'
+			ORDER BY r.weight DESC, r.reps DESC
+			LIMIT 1`, record.UserID, record.FkExerciseId)
+		if err != nil {
+			return nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return record, nil
+}
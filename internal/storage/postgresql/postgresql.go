@@ -1,6 +1,7 @@
 package postgresql
 
 import (
+	"GYMBRO/internal/lib/tracing"
 	"GYMBRO/internal/storage"
 	"context"
 	"errors"
@@ -18,7 +19,13 @@ type Storage struct {
 
 func New(storagePath string) (*Storage, error) {
 	const op = "storage.postgresql.New"
-	dbpool, err := pgxpool.New(context.Background(), storagePath)
+	poolCfg, err := pgxpool.ParseConfig(storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	poolCfg.ConnConfig.Tracer = queryTracer{}
+
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -29,13 +36,45 @@ func (s *Storage) Close() {
 	s.db.Close()
 }
 
+// Ping checks the connection to the database.
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.postgresql.Ping"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if err := s.db.Ping(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MigrationVersion returns the version from the golang-migrate schema_migrations table. Zero means no migrations.
+func (s *Storage) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	const op = "storage.postgresql.MigrationVersion"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var (
+		version int64
+		dirty   bool
+	)
+	err := s.db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return uint(version), dirty, nil
+}
+
 // RegisterNewUser registers a new user in the database and returns the user ID or an error
-func (s *Storage) RegisterNewUser(user *storage.User) (*string, error) {
+func (s *Storage) RegisterNewUser(ctx context.Context, user *storage.User) (*string, error) {
 	const op = "storage.postgresql.RegisterNewUser"
-	_, err := s.db.Exec(context.Background(),
-		`INSERT INTO users (user_id, username, email, password_hash, date_of_birth, google_id, fk_clan_id, fk_gym_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-		user.UserId, user.Username, user.Email, user.Password, user.DateOfBirth, user.GoogleId, "0", 0)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO users (user_id, username, email, password_hash, date_of_birth, fk_clan_id, fk_gym_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		user.UserId, user.Username, user.Email, user.Password, user.DateOfBirth, "0", 0)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation error code
@@ -46,12 +85,215 @@ func (s *Storage) RegisterNewUser(user *storage.User) (*string, error) {
 	return &user.UserId, nil
 }
 
+// RegisterOAuthUser registers a new user together with the provider identity they signed up with.
+func (s *Storage) RegisterOAuthUser(ctx context.Context, user *storage.User, identity *storage.Identity) (*string, error) {
+	const op = "storage.postgresql.RegisterOAuthUser"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO users (user_id, username, email, password_hash, date_of_birth, fk_clan_id, fk_gym_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		user.UserId, user.Username, user.Email, user.Password, user.DateOfBirth, "0", 0)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, storage.ErrUserExists
+		}
+		return nil, fmt.Errorf("%s, userQuery: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO useridentities (provider, subject, fk_user_id, email) VALUES ($1, $2, $3, $4)`,
+		identity.Provider, identity.Subject, user.UserId, identity.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, storage.ErrIdentityExists
+		}
+		return nil, fmt.Errorf("%s, identityQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &user.UserId, nil
+}
+
+// GetUserByIdentity retrieves a user by the subject (user id) they have at the OAuth provider
+func (s *Storage) GetUserByIdentity(ctx context.Context, provider *string, subject *string) (*storage.User, error) {
+	const op = "storage.postgresql.GetUserByIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var user storage.User
+	row := s.db.QueryRow(ctx, `SELECT u.user_id, u.username, u.email, u.password_hash, u.points, u.season_points, u.date_of_birth, u.fk_clan_id, u.fk_gym_id, u.created_at, u.role, u.is_disabled, COALESCE(u.body_weight, 0), u.is_private, u.is_flagged
+		FROM users u JOIN useridentities i ON i.fk_user_id = u.user_id WHERE i.provider = $1 AND i.subject = $2`, provider, subject)
+	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.Points, &user.SeasonPoints, &user.DateOfBirth, &user.FkClanId, &user.FkGymId, &user.CreatedAt, &user.Role, &user.IsDisabled, &user.BodyWeight, &user.IsPrivate, &user.IsFlagged)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &user, nil
+}
+
+// GetUserIdentities retrieves all OAuth identities linked to a user
+func (s *Storage) GetUserIdentities(ctx context.Context, userID *string) ([]*storage.Identity, error) {
+	const op = "storage.postgresql.GetUserIdentities"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT provider, subject, fk_user_id, COALESCE(email, ''), created_at FROM useridentities WHERE fk_user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var identities []*storage.Identity
+	for rows.Next() {
+		identity := &storage.Identity{}
+		if err := rows.Scan(&identity.Provider, &identity.Subject, &identity.FkUserId, &identity.Email, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identities, nil
+}
+
+// AddUserIdentity links an OAuth identity to an existing user
+func (s *Storage) AddUserIdentity(ctx context.Context, identity *storage.Identity) error {
+	const op = "storage.postgresql.AddUserIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO useridentities (provider, subject, fk_user_id, email) VALUES ($1, $2, $3, $4)`,
+		identity.Provider, identity.Subject, identity.FkUserId, identity.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storage.ErrIdentityExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteUserIdentity unlinks the identity of the given provider from a user
+func (s *Storage) DeleteUserIdentity(ctx context.Context, userID *string, provider *string) error {
+	const op = "storage.postgresql.DeleteUserIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `DELETE FROM useridentities WHERE fk_user_id = $1 AND provider = $2`, userID, provider)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// MergeUsers moves everything owned by the source user (identities, workouts, maxes, achievements, follows, events, points, subscriptions, check-ins, clans)
+// to the target user and deletes the source user. Everything happens in one transaction.
+// It returns ErrIdentityExists if both users have an identity of the same provider.
+func (s *Storage) MergeUsers(ctx context.Context, targetID *string, sourceID *string) error {
+	const op = "storage.postgresql.MergeUsers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		sourcePoints, sourceSeasonPoints int
+		sourceFlagged                    bool
+	)
+	err = tx.QueryRow(ctx, `SELECT points, season_points, is_flagged FROM users WHERE user_id = $1 FOR UPDATE`, sourceID).Scan(&sourcePoints, &sourceSeasonPoints, &sourceFlagged)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s, sourceQuery: %w", op, err)
+	}
+
+	var sharedProvider bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM useridentities s JOIN useridentities t ON t.provider = s.provider
+		WHERE s.fk_user_id = $1 AND t.fk_user_id = $2)`, sourceID, targetID).Scan(&sharedProvider)
+	if err != nil {
+		return fmt.Errorf("%s, providersQuery: %w", op, err)
+	}
+	if sharedProvider {
+		return storage.ErrIdentityExists
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET points = points + $1, season_points = season_points + $2, is_flagged = is_flagged OR $3 WHERE user_id = $4`,
+		sourcePoints, sourceSeasonPoints, sourceFlagged, targetID)
+	if err != nil {
+		return fmt.Errorf("%s, pointsQuery: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	moves := []struct {
+		name  string
+		query string
+	}{
+		{"identitiesQuery", `UPDATE useridentities SET fk_user_id = $1 WHERE fk_user_id = $2`},
+		{"workoutsQuery", `UPDATE workouts SET fk_user_id = $1 WHERE fk_user_id = $2`},
+		{"subscriptionsQuery", `UPDATE subscriptions SET fk_user_id = $1 WHERE fk_user_id = $2`},
+		{"clansQuery", `UPDATE clans SET fk_owner_id = $1 WHERE fk_owner_id = $2`},
+		{"maxesQuery", `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps)
+		SELECT $1, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = $2
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = EXCLUDED.max_weight, reps = EXCLUDED.reps
+		WHERE EXCLUDED.max_weight > userexercisemaxweights.max_weight
+		OR (EXCLUDED.max_weight = userexercisemaxweights.max_weight AND EXCLUDED.reps > userexercisemaxweights.reps)`},
+		{"achievementsQuery", `INSERT INTO achievements (fk_user_id, code, awarded_at)
+		SELECT $1, code, awarded_at FROM achievements WHERE fk_user_id = $2
+		ON CONFLICT (fk_user_id, code) DO UPDATE SET awarded_at = LEAST(achievements.awarded_at, EXCLUDED.awarded_at)`},
+		{"followingQuery", `INSERT INTO follows (follower_id, followee_id, status, created_at)
+		SELECT $1, followee_id, status, created_at FROM follows WHERE follower_id = $2 AND followee_id <> $1
+		ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = EXCLUDED.status WHERE EXCLUDED.status = 'accepted'`},
+		{"followersQuery", `INSERT INTO follows (follower_id, followee_id, status, created_at)
+		SELECT follower_id, $1, status, created_at FROM follows WHERE followee_id = $2 AND follower_id <> $1
+		ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = EXCLUDED.status WHERE EXCLUDED.status = 'accepted'`},
+		{"eventsQuery", `UPDATE events SET fk_user_id = $1 WHERE fk_user_id = $2`},
+		{"checkInsQuery", `UPDATE checkins SET fk_user_id = $1 WHERE fk_user_id = $2`},
+		{"webhookOwnersQuery", `UPDATE webhooks SET fk_owner_id = $1 WHERE fk_owner_id = $2`},
+		{"webhookSubjectsQuery", `UPDATE webhooks SET subject_id = $1 WHERE kind = 'user' AND subject_id = $2`},
+	}
+	for _, move := range moves {
+		if _, err := tx.Exec(ctx, move.query, targetID, sourceID); err != nil {
+			return fmt.Errorf("%s, %s: %w", op, move.name, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE user_id = $1`, sourceID); err != nil {
+		return fmt.Errorf("%s, deleteQuery: %w", op, err)
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetUserByID retrieves a user's data by their ID
-func (s *Storage) GetUserByID(id *string) (*storage.User, error) {
+func (s *Storage) GetUserByID(ctx context.Context, id *string) (*storage.User, error) {
 	const op = "storage.postgresql.GetUserByID"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var user storage.User
-	row := s.db.QueryRow(context.Background(), `SELECT user_id, username, email, password_hash, date_of_birth, google_id, fk_clan_id, fk_gym_id, created_at FROM users WHERE user_id = $1`, id)
-	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.DateOfBirth, &user.GoogleId, &user.FkClanId, &user.FkGymId, &user.CreatedAt)
+	row := s.db.QueryRow(ctx, `SELECT user_id, username, email, password_hash, points, season_points, date_of_birth, fk_clan_id, fk_gym_id, created_at, role, is_disabled, COALESCE(body_weight, 0), is_private, is_flagged FROM users WHERE user_id = $1`, id)
+	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.Points, &user.SeasonPoints, &user.DateOfBirth, &user.FkClanId, &user.FkGymId, &user.CreatedAt, &user.Role, &user.IsDisabled, &user.BodyWeight, &user.IsPrivate, &user.IsFlagged)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, storage.ErrUserNotFound
 	}
@@ -62,11 +304,13 @@ func (s *Storage) GetUserByID(id *string) (*storage.User, error) {
 }
 
 // GetUserByEmail retrieves a user's data by their email
-func (s *Storage) GetUserByEmail(email *string) (*storage.User, error) {
+func (s *Storage) GetUserByEmail(ctx context.Context, email *string) (*storage.User, error) {
 	const op = "storage.postgresql.GetUserByEmail"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var user storage.User
-	row := s.db.QueryRow(context.Background(), `SELECT user_id, username, email, password_hash, date_of_birth, google_id, fk_clan_id, fk_gym_id, created_at FROM users WHERE email = $1`, email)
-	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.DateOfBirth, &user.GoogleId, &user.FkClanId, &user.FkGymId, &user.CreatedAt)
+	row := s.db.QueryRow(ctx, `SELECT user_id, username, email, password_hash, points, season_points, date_of_birth, fk_clan_id, fk_gym_id, created_at, role, is_disabled, COALESCE(body_weight, 0), is_private, is_flagged FROM users WHERE email = $1`, email)
+	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.Points, &user.SeasonPoints, &user.DateOfBirth, &user.FkClanId, &user.FkGymId, &user.CreatedAt, &user.Role, &user.IsDisabled, &user.BodyWeight, &user.IsPrivate, &user.IsFlagged)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, storage.ErrUserNotFound
 	}
@@ -77,20 +321,138 @@ func (s *Storage) GetUserByEmail(email *string) (*storage.User, error) {
 }
 
 // ChangeStatus updates the active status and last active timestamp for a user.
-func (s *Storage) ChangeStatus(userID *string, status bool) error {
+func (s *Storage) ChangeStatus(ctx context.Context, userID *string, status bool) error {
 	const op = "storage.postgresql.ChangeStatus"
-	_, err := s.db.Exec(context.Background(), `UPDATE users SET is_active = $1, last_active = $2 WHERE user_id = $3`, status, time.Now(), userID)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `UPDATE users SET is_active = $1, last_active = $2 WHERE user_id = $3`, status, time.Now(), userID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
+// SetUserRole changes the role of a user
+func (s *Storage) SetUserRole(ctx context.Context, userID *string, role string) error {
+	const op = "storage.postgresql.SetUserRole"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE users SET role = $1 WHERE user_id = $2`, role, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// SetUserDisabled disables or enables a user account
+func (s *Storage) SetUserDisabled(ctx context.Context, userID *string, disabled bool) error {
+	const op = "storage.postgresql.SetUserDisabled"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE users SET is_disabled = $1 WHERE user_id = $2`, disabled, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// SetUserBodyWeight sets the body weight of a user
+func (s *Storage) SetUserBodyWeight(ctx context.Context, userID *string, weight int) error {
+	const op = "storage.postgresql.SetUserBodyWeight"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE users SET body_weight = $1 WHERE user_id = $2`, weight, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// SetUserPrivate sets whether following the user needs an approval
+func (s *Storage) SetUserPrivate(ctx context.Context, userID *string, private bool) error {
+	const op = "storage.postgresql.SetUserPrivate"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE users SET is_private = $1 WHERE user_id = $2`, private, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// SetUserFlagged sets whether the user logged records that failed the plausibility checks
+func (s *Storage) SetUserFlagged(ctx context.Context, userID *string, flagged bool) error {
+	const op = "storage.postgresql.SetUserFlagged"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `UPDATE users SET is_flagged = $1 WHERE user_id = $2`, flagged, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// GetClanMembers retrieves the users of the clan
+func (s *Storage) GetClanMembers(ctx context.Context, clanID *string) ([]*storage.User, error) {
+	const op = "storage.postgresql.GetClanMembers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUsers(ctx, op, `SELECT user_id, username, email, password_hash, points, season_points, date_of_birth, fk_clan_id, fk_gym_id, created_at, role, is_disabled, COALESCE(body_weight, 0), is_private, is_flagged FROM users WHERE fk_clan_id = $1`, clanID)
+}
+
+// GetGymMembers retrieves the users whose gym is gymID
+func (s *Storage) GetGymMembers(ctx context.Context, gymID int) ([]*storage.User, error) {
+	const op = "storage.postgresql.GetGymMembers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUsers(ctx, op, `SELECT user_id, username, email, password_hash, points, season_points, date_of_birth, fk_clan_id, fk_gym_id, created_at, role, is_disabled, COALESCE(body_weight, 0), is_private, is_flagged FROM users WHERE fk_gym_id = $1`, gymID)
+}
+
+func (s *Storage) getUsers(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.User, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []*storage.User
+	for rows.Next() {
+		var user storage.User
+		if err := rows.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.Points, &user.SeasonPoints, &user.DateOfBirth, &user.FkClanId, &user.FkGymId, &user.CreatedAt, &user.Role, &user.IsDisabled, &user.BodyWeight, &user.IsPrivate, &user.IsFlagged); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
 // GetUserMax retrieves the maximum weight and reps for a specific exercise.
-func (s *Storage) GetUserMax(userID *string, exercise *int) (*storage.Max, error) {
+func (s *Storage) GetUserMax(ctx context.Context, userID *string, exercise *int) (*storage.Max, error) {
 	const op = "storage.postgresql.GetUserMax"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var userMax storage.Max
-	row := s.db.QueryRow(context.Background(), `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = $1 AND exercise_id = $2`, userID, exercise)
+	row := s.db.QueryRow(ctx, `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = $1 AND exercise_id = $2`, userID, exercise)
 	err := row.Scan(&userMax.UserID, &userMax.ExerciseId, &userMax.MaxWeight, &userMax.Reps)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -102,10 +464,12 @@ func (s *Storage) GetUserMax(userID *string, exercise *int) (*storage.Max, error
 }
 
 // GetUserMaxes retrieves all maximum weight records for a user.
-func (s *Storage) GetUserMaxes(userID *string) ([]*storage.Max, error) {
+func (s *Storage) GetUserMaxes(ctx context.Context, userID *string) ([]*storage.Max, error) {
 	const op = "storage.postgresql.GetUserMaxes"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var userMaxes []*storage.Max
-	rows, err := s.db.Query(context.Background(), `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = $1`, userID)
+	rows, err := s.db.Query(ctx, `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = $1`, userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return userMaxes, storage.ErrNoMaxes
@@ -133,9 +497,11 @@ func (s *Storage) GetUserMaxes(userID *string) ([]*storage.Max, error) {
 }
 
 // SetUserMax inserts or updates the maximum weight and reps for a user's exercise.
-func (s *Storage) SetUserMax(userID *string, max *storage.Max) error {
+func (s *Storage) SetUserMax(ctx context.Context, userID *string, max *storage.Max) error {
 	const op = "storage.postgresql.SetUserMax"
-	_, err := s.db.Exec(context.Background(), `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = EXCLUDED.max_weight, reps = EXCLUDED.reps`, userID, max.ExerciseId, max.MaxWeight, max.Reps)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = EXCLUDED.max_weight, reps = EXCLUDED.reps`, userID, max.ExerciseId, max.MaxWeight, max.Reps)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -143,15 +509,17 @@ func (s *Storage) SetUserMax(userID *string, max *storage.Max) error {
 }
 
 // GetWorkout retrieves a workout record by its ID.
-func (s *Storage) GetWorkout(workoutID *string) (*storage.WorkoutWithRecords, error) {
+func (s *Storage) GetWorkout(ctx context.Context, workoutID *string) (*storage.WorkoutWithRecords, error) {
 	const op = "storage.postgresql.GetWorkout"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	query := `SELECT w.workout_id, w.fk_user_id, w.start_time, w.end_time, w.points, r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points
+	query := `SELECT w.workout_id, w.fk_user_id, w.start_time, w.end_time, w.points, COALESCE(w.fk_gym_id, 0), r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points, r.status, r.reason
 	FROM workouts w
 	LEFT JOIN records r ON w.workout_id = r.fk_workout_id
 	WHERE w.workout_id = $1`
 
-	rows, err := s.db.Query(context.Background(), query, workoutID)
+	rows, err := s.db.Query(ctx, query, workoutID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, storage.ErrWorkoutNotFound
@@ -164,7 +532,9 @@ func (s *Storage) GetWorkout(workoutID *string) (*storage.WorkoutWithRecords, er
 		WorkoutID: *workoutID,
 	}
 
+	found := false
 	for rows.Next() {
+		found = true
 		var record storage.Record
 		err := rows.Scan(
 			&workoutWithRecords.WorkoutID,
@@ -172,12 +542,15 @@ func (s *Storage) GetWorkout(workoutID *string) (*storage.WorkoutWithRecords, er
 			&workoutWithRecords.StartTime,
 			&workoutWithRecords.EndTime,
 			&workoutWithRecords.Points,
+			&workoutWithRecords.GymID,
 			&record.RecordId,
 			&record.FkWorkoutId,
 			&record.FkExerciseId,
 			&record.Reps,
 			&record.Weight,
 			&record.Points,
+			&record.Status,
+			&record.Reason,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -192,12 +565,104 @@ func (s *Storage) GetWorkout(workoutID *string) (*storage.WorkoutWithRecords, er
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	if !found {
+		return nil, storage.ErrWorkoutNotFound
+	}
+
 	return workoutWithRecords, nil
 }
 
-func (s *Storage) SaveWorkout(workout *storage.WorkoutSession) error {
+// StreamWorkouts walks over all user's workouts started in [from, to) with their records
+// and passes them one by one to fn, so the whole history is never held in memory.
+// Iteration stops at the first error returned by fn.
+func (s *Storage) StreamWorkouts(ctx context.Context, userID *string, from time.Time, to time.Time, fn func(*storage.WorkoutWithRecords) error) error {
+	const op = "storage.postgresql.StreamWorkouts"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	query := `SELECT w.workout_id, w.fk_user_id, w.start_time, w.end_time, w.points, COALESCE(w.fk_gym_id, 0), r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points, r.status, r.reason
+	FROM workouts w
+	LEFT JOIN records r ON w.workout_id = r.fk_workout_id
+	WHERE w.fk_user_id = $1 AND w.start_time >= $2 AND w.start_time < $3
+	ORDER BY w.start_time, w.workout_id`
+
+	rows, err := s.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var current *storage.WorkoutWithRecords
+
+	for rows.Next() {
+		var (
+			workout  storage.WorkoutWithRecords
+			recordID *string
+			record   storage.Record
+			fkWoID   *string
+			exercise *int
+			reps     *int
+			weight   *int
+			points   *int
+			status   *string
+			reason   *string
+		)
+		err := rows.Scan(
+			&workout.WorkoutID,
+			&workout.UserID,
+			&workout.StartTime,
+			&workout.EndTime,
+			&workout.Points,
+			&workout.GymID,
+			&recordID,
+			&fkWoID,
+			&exercise,
+			&reps,
+			&weight,
+			&points,
+			&status,
+			&reason,
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if current == nil || current.WorkoutID != workout.WorkoutID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			current = &workout
+		}
+
+		if recordID != nil {
+			record.RecordId = *recordID
+			record.FkWorkoutId = *fkWoID
+			record.FkExerciseId = *exercise
+			record.Reps = *reps
+			record.Weight = *weight
+			record.Points = *points
+			record.Status = *status
+			record.Reason = *reason
+			current.Records = append(current.Records, record)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+func (s *Storage) SaveWorkout(ctx context.Context, workout *storage.WorkoutSession) error {
 	const op = "storage.postgresql.SaveWorkout"
-	ctx := context.Background()
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -219,14 +684,14 @@ func (s *Storage) SaveWorkout(workout *storage.WorkoutSession) error {
 		return nil
 	}
 
-	userQuery := `UPDATE users SET points = points + $1 WHERE user_id = $2`
+	userQuery := `UPDATE users SET points = points + $1, season_points = season_points + $1 WHERE user_id = $2`
 
 	_, err = tx.Exec(ctx, userQuery, workout.Points, workout.UserID)
 	if err != nil {
 		return fmt.Errorf("%s, userQuery: %w", op, err)
 	}
 
-	workoutQuery := `INSERT INTO workouts (workout_id, fk_user_id, start_time, end_time, points) VALUES ($1, $2, $3, $4, $5)`
+	workoutQuery := `INSERT INTO workouts (workout_id, fk_user_id, start_time, end_time, points, fk_gym_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0))`
 
 	_, err = tx.Exec(ctx, workoutQuery,
 		workout.SessionID,
@@ -234,20 +699,22 @@ func (s *Storage) SaveWorkout(workout *storage.WorkoutSession) error {
 		workout.StartTime,
 		workout.LastUpdated,
 		workout.Points,
+		workout.GymID,
 	)
 	if err != nil {
 		return fmt.Errorf("%s, workoutQuery: %w", op, err)
 	}
 
 	inParams := make([]string, 0, len(workout.Records))
-	args := make([]interface{}, 0, len(workout.Records)*6)
+	args := make([]interface{}, 0, len(workout.Records)*8)
 
+	// records without a status are saved as ok
 	for i, record := range workout.Records {
-		inParams = append(inParams, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", i*6+1, i*6+2, i*6+3, i*6+4, i*6+5, i*6+6))
-		args = append(args, record.RecordId, record.FkWorkoutId, record.FkExerciseId, record.Reps, record.Weight, record.Points)
+		inParams = append(inParams, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, COALESCE(NULLIF($%d, ''), 'ok'), $%d)", i*8+1, i*8+2, i*8+3, i*8+4, i*8+5, i*8+6, i*8+7, i*8+8))
+		args = append(args, record.RecordId, record.FkWorkoutId, record.FkExerciseId, record.Reps, record.Weight, record.Points, record.Status, record.Reason)
 	}
 
-	recordQuery := fmt.Sprintf(`INSERT INTO records (record_id, fk_workout_id, fk_exercise_id, reps, weight, points) VALUES %s`, strings.Join(inParams, ", "))
+	recordQuery := fmt.Sprintf(`INSERT INTO records (record_id, fk_workout_id, fk_exercise_id, reps, weight, points, status, reason) VALUES %s`, strings.Join(inParams, ", "))
 
 	_, err = tx.Exec(ctx, recordQuery, args...)
 	if err != nil {
@@ -0,0 +1,263 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const seasonColumns = `season_id, start_time, end_time, ended_at`
+
+// standingsQuery selects subject_id, name, points and rank of the users, clans or gyms in the current season,
+// the default clan and gym are left out
+func standingsQuery(kind string) (string, error) {
+	switch kind {
+	case storage.StandingUsers:
+		return `SELECT user_id AS subject_id, username AS name, season_points AS points, RANK() OVER (ORDER BY season_points DESC) AS rank
+			FROM users WHERE season_points > 0 AND NOT is_private`, nil
+	case storage.StandingClans:
+		return `SELECT clan_id AS subject_id, name, season_points AS points, RANK() OVER (ORDER BY season_points DESC) AS rank
+			FROM clans WHERE season_points > 0 AND clan_id <> '0'`, nil
+	case storage.StandingGyms:
+		return `SELECT u.fk_gym_id::text AS subject_id, COALESCE(g.name, '') AS name, SUM(u.season_points)::int AS points,
+			RANK() OVER (ORDER BY SUM(u.season_points) DESC) AS rank
+			FROM users u LEFT JOIN gyms g ON g.gym_id = u.fk_gym_id
+			WHERE u.season_points > 0 AND u.fk_gym_id <> 0
+			GROUP BY u.fk_gym_id, g.name`, nil
+	}
+	return "", fmt.Errorf("unknown standing kind %q", kind)
+}
+
+func scanSeason(row pgx.Row) (*storage.Season, error) {
+	var season storage.Season
+	if err := row.Scan(&season.SeasonID, &season.StartTime, &season.EndTime, &season.EndedAt); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// StartSeason stores the first season
+func (s *Storage) StartSeason(ctx context.Context, season *storage.Season) error {
+	const op = "storage.postgresql.StartSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `INSERT INTO seasons (season_id, start_time, end_time)
+		SELECT $1, $2, $3 WHERE NOT EXISTS (SELECT 1 FROM seasons)`, season.SeasonID, season.StartTime, season.EndTime)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storage.ErrSeasonExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrSeasonExists
+	}
+	return nil
+}
+
+// GetCurrentSeason retrieves the season that is not ended
+func (s *Storage) GetCurrentSeason(ctx context.Context) (*storage.Season, error) {
+	const op = "storage.postgresql.GetCurrentSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	season, err := scanSeason(s.db.QueryRow(ctx, `SELECT `+seasonColumns+` FROM seasons WHERE ended_at IS NULL`))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return season, nil
+}
+
+// GetSeason retrieves a season by its ID
+func (s *Storage) GetSeason(ctx context.Context, seasonID int) (*storage.Season, error) {
+	const op = "storage.postgresql.GetSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	season, err := scanSeason(s.db.QueryRow(ctx, `SELECT `+seasonColumns+` FROM seasons WHERE season_id = $1`, seasonID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, storage.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return season, nil
+}
+
+// GetSeasons retrieves all seasons, the newest first
+func (s *Storage) GetSeasons(ctx context.Context) ([]*storage.Season, error) {
+	const op = "storage.postgresql.GetSeasons"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT `+seasonColumns+` FROM seasons ORDER BY season_id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var seasons []*storage.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		seasons = append(seasons, season)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return seasons, nil
+}
+
+// RolloverSeason archives the standings, resets the seasonal points and starts the next season in one transaction.
+// Users and clans are locked against writes while it runs, so points of a workout saved meanwhile are not reset unarchived.
+func (s *Storage) RolloverSeason(ctx context.Context, seasonID int, endedAt time.Time, next *storage.Season) error {
+	const op = "storage.postgresql.RolloverSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE seasons SET ended_at = $2 WHERE season_id = $1 AND ended_at IS NULL`, seasonID, endedAt)
+	if err != nil {
+		return fmt.Errorf("%s, endQuery: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM seasons WHERE season_id = $1)`, seasonID).Scan(&exists); err != nil {
+			return fmt.Errorf("%s, existsQuery: %w", op, err)
+		}
+		if !exists {
+			return storage.ErrSeasonNotFound
+		}
+		return storage.ErrSeasonEnded
+	}
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE users, clans IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("%s, lockQuery: %w", op, err)
+	}
+
+	for _, kind := range []string{storage.StandingUsers, storage.StandingClans, storage.StandingGyms} {
+		query, err := standingsQuery(kind)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO seasonstandings (fk_season_id, kind, subject_id, name, points, rank)
+			SELECT $1, $2, subject_id, name, points, rank FROM (`+query+`) s`, seasonID, kind)
+		if err != nil {
+			return fmt.Errorf("%s, archiveQuery: %w", op, err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET season_points = 0 WHERE season_points <> 0`); err != nil {
+		return fmt.Errorf("%s, usersQuery: %w", op, err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE clans SET season_points = 0 WHERE season_points <> 0`); err != nil {
+		return fmt.Errorf("%s, clansQuery: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO seasons (season_id, start_time, end_time) VALUES ($1, $2, $3)`, next.SeasonID, next.StartTime, next.EndTime)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%s: %w", op, storage.ErrSeasonExists)
+		}
+		return fmt.Errorf("%s, nextQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) getStandings(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.Standing, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	standings := make([]*storage.Standing, 0)
+	for rows.Next() {
+		standing := &storage.Standing{}
+		if err := rows.Scan(&standing.FkSeasonId, &standing.Kind, &standing.SubjectID, &standing.Name, &standing.Points, &standing.Rank); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		standings = append(standings, standing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return standings, nil
+}
+
+// GetStandings retrieves the archived standings of the kind in a season, ranked
+func (s *Storage) GetStandings(ctx context.Context, seasonID int, kind string, limit int) ([]*storage.Standing, error) {
+	const op = "storage.postgresql.GetStandings"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getStandings(ctx, op, `SELECT fk_season_id, kind, subject_id, name, points, rank FROM seasonstandings
+		WHERE fk_season_id = $1 AND kind = $2
+		ORDER BY rank, subject_id
+		LIMIT $3`, seasonID, kind, limit)
+}
+
+// GetLiveStandings computes the standings of the kind in the current season, ranked
+func (s *Storage) GetLiveStandings(ctx context.Context, kind string, limit int) ([]*storage.Standing, error) {
+	const op = "storage.postgresql.GetLiveStandings"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query, err := standingsQuery(kind)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.getStandings(ctx, op, `SELECT 0, $1::text, subject_id, name, points, rank FROM (`+query+`) s
+		ORDER BY rank, subject_id
+		LIMIT $2`, kind, limit)
+}
+
+// TryLock takes a session level advisory lock. It is held by a connection taken from the pool,
+// so the lock is released by unlock or when the connection is closed, e.g. when the replica dies.
+func (s *Storage) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	const op = "storage.postgresql.TryLock"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	return func() {
+		// the context of the job may be cancelled by now
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+			// closing the connection ends the session and its locks
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}, true, nil
+}
@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetMuscleGroupVolume sums the records that count of user's workouts started in [from, to) per workout and muscle group
+func (s *Storage) GetMuscleGroupVolume(ctx context.Context, userID *string, from time.Time, to time.Time) ([]*storage.MuscleGroupVolume, error) {
+	const op = "storage.postgresql.GetMuscleGroupVolume"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	rows, err := s.db.Query(ctx, `SELECT w.workout_id, w.start_time, mg.muscle_group_id, mg.name,
+		COUNT(*), SUM(r.reps), SUM(r.reps * r.weight)
+		FROM workouts w
+		JOIN records r ON r.fk_workout_id = w.workout_id
+		JOIN exercisemusclegroups emg ON emg.exercise_id = r.fk_exercise_id
+		JOIN musclegroups mg ON mg.muscle_group_id = emg.muscle_group_id
+		WHERE w.fk_user_id = $1 AND w.start_time >= $2 AND w.start_time < $3 AND r.status = 'ok'
+		GROUP BY w.workout_id, w.start_time, mg.muscle_group_id, mg.name
+		ORDER BY w.start_time, w.workout_id, mg.muscle_group_id`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var volumes []*storage.MuscleGroupVolume
+	for rows.Next() {
+		volume := &storage.MuscleGroupVolume{}
+		err := rows.Scan(&volume.WorkoutID, &volume.StartTime, &volume.MuscleGroupId, &volume.MuscleGroupName,
+			&volume.Sets, &volume.Reps, &volume.Tonnage)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return volumes, nil
+}
+
+// GetExerciseSets retrieves the records that count of the exercise in user's workouts started in [from, to)
+func (s *Storage) GetExerciseSets(ctx context.Context, userID *string, exerciseID int, from time.Time, to time.Time) ([]*storage.ExerciseSet, error) {
+	const op = "storage.postgresql.GetExerciseSets"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var exists bool
+	if err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM exercises WHERE exercise_id = $1)`, exerciseID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return nil, storage.ErrExerciseNotFound
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT w.workout_id, w.start_time, r.reps, r.weight
+		FROM workouts w
+		JOIN records r ON r.fk_workout_id = w.workout_id
+		WHERE w.fk_user_id = $1 AND r.fk_exercise_id = $2 AND w.start_time >= $3 AND w.start_time < $4 AND r.status = 'ok'
+		ORDER BY w.start_time, w.workout_id`, userID, exerciseID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sets []*storage.ExerciseSet
+	for rows.Next() {
+		set := &storage.ExerciseSet{}
+		if err := rows.Scan(&set.WorkoutID, &set.StartTime, &set.Reps, &set.Weight); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		sets = append(sets, set)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sets, nil
+}
@@ -0,0 +1,292 @@
+package postgresql
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const webhookColumns = `webhook_id, fk_owner_id, kind, subject_id, url, secret, events, created_at`
+
+const deliveryColumns = `d.delivery_id, d.fk_webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func scanWebhook(row pgx.Row) (*storage.Webhook, error) {
+	var webhook storage.Webhook
+	err := row.Scan(&webhook.WebhookID, &webhook.OwnerID, &webhook.Kind, &webhook.SubjectID, &webhook.URL, &webhook.Secret,
+		&webhook.Events, &webhook.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// scanDelivery scans deliveryColumns followed by dest
+func scanDelivery(row pgx.Row, dest ...any) (*storage.WebhookDelivery, error) {
+	var delivery storage.WebhookDelivery
+	err := row.Scan(append([]any{&delivery.DeliveryID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// CreateWebhook stores a webhook unless the owner has max of them, gym webhooks are checked against the gyms
+func (s *Storage) CreateWebhook(ctx context.Context, webhook *storage.Webhook, max int) error {
+	const op = "storage.postgresql.CreateWebhook"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the owner is locked, so concurrent requests can't both get under the limit
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT 1 FROM users WHERE user_id = $1 FOR UPDATE`, webhook.OwnerID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM webhooks WHERE fk_owner_id = $1`, webhook.OwnerID).Scan(&count); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if count >= max {
+		return storage.ErrWebhookLimit
+	}
+
+	tag, err := tx.Exec(ctx, `INSERT INTO webhooks (`+webhookColumns+`)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8
+		WHERE $3 <> 'gym' OR EXISTS (SELECT 1 FROM gyms WHERE gym_id::text = $4)`,
+		webhook.WebhookID, webhook.OwnerID, webhook.Kind, webhook.SubjectID, webhook.URL, webhook.Secret, webhook.Events, webhook.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrGymNotFound
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetWebhooks retrieves the webhooks the user manages, the oldest first
+func (s *Storage) GetWebhooks(ctx context.Context, ownerID *string) ([]*storage.Webhook, error) {
+	const op = "storage.postgresql.GetWebhooks"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE fk_owner_id = $1 ORDER BY created_at, webhook_id`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*storage.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the webhook of the owner, its deliveries are deleted by the cascade
+func (s *Storage) DeleteWebhook(ctx context.Context, ownerID *string, webhookID *string) error {
+	const op = "storage.postgresql.DeleteWebhook"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tag, err := s.db.Exec(ctx, `DELETE FROM webhooks WHERE webhook_id = $1 AND fk_owner_id = $2`, webhookID, ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return storage.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// AddDeliveries queues a copy of the delivery for the webhooks of the user and of their gym subscribed to its event
+func (s *Storage) AddDeliveries(ctx context.Context, userID *string, delivery *storage.WebhookDelivery) (int, error) {
+	const op = "storage.postgresql.AddDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT h.webhook_id
+		FROM webhooks h
+		JOIN users u ON u.user_id = $1
+		WHERE $2 = ANY(h.events)
+			AND ((h.kind = 'user' AND h.subject_id = u.user_id)
+				OR (h.kind = 'gym' AND h.subject_id = u.fk_gym_id::text AND NOT u.is_private))`, userID, delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("%s, webhooksQuery: %w", op, err)
+	}
+	webhookIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("%s, webhooksQuery: %w", op, err)
+	}
+
+	for _, webhookID := range webhookIDs {
+		_, err := tx.Exec(ctx, `INSERT INTO webhookdeliveries (delivery_id, fk_webhook_id, event, payload, status, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, 'pending', $5, $6)`,
+			storage.GenerateUID(), webhookID, delivery.Event, delivery.Payload, delivery.NextAttemptAt, delivery.CreatedAt)
+		if err != nil {
+			return 0, fmt.Errorf("%s, insertQuery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return len(webhookIDs), nil
+}
+
+// GetDueDeliveries retrieves up to limit pending deliveries due at now with the URL and secret of their webhook, the longest waiting first
+func (s *Storage) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*storage.PendingDelivery, error) {
+	const op = "storage.postgresql.GetDueDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.Query(ctx, `SELECT `+deliveryColumns+`, h.url, h.secret
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+		ORDER BY d.next_attempt_at, d.delivery_id
+		LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*storage.PendingDelivery, 0)
+	for rows.Next() {
+		var pending storage.PendingDelivery
+		delivery, err := scanDelivery(rows, &pending.URL, &pending.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pending.WebhookDelivery = *delivery
+		deliveries = append(deliveries, &pending)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt
+func (s *Storage) UpdateDelivery(ctx context.Context, delivery *storage.WebhookDelivery) error {
+	const op = "storage.postgresql.UpdateDelivery"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.Exec(ctx, `UPDATE webhookdeliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6
+		WHERE delivery_id = $7`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.DeliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetDeliveries retrieves up to limit deliveries of the owner's webhooks, the newest first
+func (s *Storage) GetDeliveries(ctx context.Context, ownerID *string, webhookID string, status string, limit int) ([]*storage.WebhookDelivery, error) {
+	const op = "storage.postgresql.GetDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if webhookID != "" {
+		var exists bool
+		err := s.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE webhook_id = $1 AND fk_owner_id = $2)`, webhookID, ownerID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("%s, webhookQuery: %w", op, err)
+		}
+		if !exists {
+			return nil, storage.ErrWebhookNotFound
+		}
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT `+deliveryColumns+`
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE h.fk_owner_id = $1 AND ($2 = '' OR d.fk_webhook_id = $2) AND ($3 = '' OR d.status = $3)
+		ORDER BY d.created_at DESC, d.delivery_id DESC
+		LIMIT $4`, ownerID, webhookID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*storage.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery queues a dead delivery of the owner's webhook again
+func (s *Storage) RetryDelivery(ctx context.Context, ownerID *string, deliveryID *string, at time.Time) error {
+	const op = "storage.postgresql.RetryDelivery"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT d.status
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE d.delivery_id = $1 AND h.fk_owner_id = $2
+		FOR UPDATE OF d`, deliveryID, ownerID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storage.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	if status != storage.DeliveryDead {
+		return storage.ErrDeliveryNotDead
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE webhookdeliveries SET status = 'pending', attempts = 0, next_attempt_at = $1 WHERE delivery_id = $2`, at, deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s, retryQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Package pubsub fans session events out to the subscribers of the same process. Storages without Redis
+// use it, they serve a single instance, so there is nobody else to tell.
+package pubsub
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"sync"
+)
+
+// buffer is the number of events a subscriber can fall behind before new events are dropped for it.
+// Every event carries the whole session, so the next one delivered brings the subscriber up to date.
+const buffer = 16
+
+type Hub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan *storage.SessionEvent]struct{}
+}
+
+func New() *Hub {
+	return &Hub{subs: make(map[string]map[chan *storage.SessionEvent]struct{})}
+}
+
+// Publish sends the event to the subscribers of its user without waiting for them.
+func (h *Hub) Publish(event *storage.SessionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[event.UserID] {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the events of the user published until the context is done, the channel is closed then.
+func (h *Hub) Subscribe(ctx context.Context, userID string) <-chan *storage.SessionEvent {
+	sub := make(chan *storage.SessionEvent, buffer)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan *storage.SessionEvent]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[userID], sub)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+		close(sub)
+	}()
+
+	return sub
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strings"
+	"time"
+)
+
+const (
+	lockoutPrefix = "lockout:"
+
+	// lockLevelTTL is how long previous locks are remembered for the backoff.
+	lockLevelTTL = 24 * time.Hour
+)
+
+func lockoutKeys(email *string) (failures, level, until string) {
+	base := lockoutPrefix + strings.ToLower(*email)
+	return base + ":failures", base + ":level", base + ":until"
+}
+
+// registerFailure increments the failure counter and locks the account once it reaches the limit.
+// Returns the lock duration in milliseconds, or 0 if the account was not locked.
+var registerFailure = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if failures < tonumber(ARGV[1]) then
+	return 0
+end
+
+local level = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+local duration = tonumber(ARGV[3]) * 2 ^ (level - 1)
+if duration > tonumber(ARGV[4]) then
+	duration = tonumber(ARGV[4])
+end
+
+redis.call('DEL', KEYS[1])
+redis.call('SET', KEYS[3], '1', 'PX', duration)
+return duration
+`)
+
+// GetLockout returns the time the account is locked until, or zero time if it is not locked.
+func (rs *RedisStorage) GetLockout(ctx context.Context, email *string) (time.Time, error) {
+	const op = "storage.redis.GetLockout"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, _, untilKey := lockoutKeys(email)
+	ttl, err := rs.Client.PTTL(ctx, untilKey).Result()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	// PTTL returns negative values if the key does not exist or has no expiration
+	if ttl <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(ttl), nil
+}
+
+// RegisterFailedLogin counts a failed login and locks the account with exponential backoff.
+func (rs *RedisStorage) RegisterFailedLogin(ctx context.Context, email *string, maxAttempts int, window, baseDuration, maxDuration time.Duration) (time.Time, error) {
+	const op = "storage.redis.RegisterFailedLogin"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	failuresKey, levelKey, untilKey := lockoutKeys(email)
+	duration, err := registerFailure.Run(ctx, rs.Client, []string{failuresKey, levelKey, untilKey},
+		maxAttempts, window.Milliseconds(), baseDuration.Milliseconds(), maxDuration.Milliseconds(), lockLevelTTL.Milliseconds()).Int64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if duration == 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(time.Duration(duration) * time.Millisecond), nil
+}
+
+// ResetFailedLogins forgets failed logins and previous locks.
+func (rs *RedisStorage) ResetFailedLogins(ctx context.Context, email *string) error {
+	const op = "storage.redis.ResetFailedLogins"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	failuresKey, levelKey, _ := lockoutKeys(email)
+	if err := rs.Client.Del(ctx, failuresKey, levelKey).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Unlock removes the lock together with failed logins and previous locks.
+func (rs *RedisStorage) Unlock(ctx context.Context, email *string) error {
+	const op = "storage.redis.Unlock"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	failuresKey, levelKey, untilKey := lockoutKeys(email)
+	if err := rs.Client.Del(ctx, failuresKey, levelKey, untilKey).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
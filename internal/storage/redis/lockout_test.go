@@ -0,0 +1,106 @@
+package redis_test
+
+import (
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/redis"
+	"context"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRegisterFailedLogin runs the lockout script against Redis at TEST_REDIS_PATH (with TEST_REDIS_PASSWORD).
+func TestRegisterFailedLogin(t *testing.T) {
+	redisPath := os.Getenv("TEST_REDIS_PATH")
+	if redisPath == "" {
+		t.Skip("TEST_REDIS_PATH is not set")
+	}
+	rs, err := redis.New(redisPath, os.Getenv("TEST_REDIS_PASSWORD"), 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	const maxAttempts = 3
+	window, base, maxDuration := time.Minute, 10*time.Second, 25*time.Second
+	// fail registers failed logins until the last one locks the account and returns how long for
+	fail := func(t *testing.T, email *string) time.Duration {
+		t.Helper()
+		for i := 1; i < maxAttempts; i++ {
+			lockedUntil, err := rs.RegisterFailedLogin(ctx, email, maxAttempts, window, base, maxDuration)
+			require.NoError(t, err)
+			require.True(t, lockedUntil.IsZero(), "locked after %d failures", i)
+		}
+		lockedUntil, err := rs.RegisterFailedLogin(ctx, email, maxAttempts, window, base, maxDuration)
+		require.NoError(t, err)
+		require.False(t, lockedUntil.IsZero())
+
+		got, err := rs.GetLockout(ctx, email)
+		require.NoError(t, err)
+		require.WithinDuration(t, lockedUntil, got, time.Second)
+		return time.Until(lockedUntil)
+	}
+	newEmail := func(t *testing.T) *string {
+		email := storage.GenerateUID() + "@Gym.com"
+		t.Cleanup(func() { _ = rs.Unlock(context.Background(), &email) })
+		return &email
+	}
+
+	t.Run("Backoff", func(t *testing.T) {
+		email := newEmail(t)
+
+		// every lock lasts twice as long as the one before, up to the max
+		require.InDelta(t, base.Seconds(), fail(t, email).Seconds(), 1)
+		require.InDelta(t, 2*base.Seconds(), fail(t, email).Seconds(), 1)
+		require.InDelta(t, maxDuration.Seconds(), fail(t, email).Seconds(), 1)
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		email := newEmail(t)
+		fail(t, email)
+
+		lower := strings.ToLower(*email)
+		lockedUntil, err := rs.GetLockout(ctx, &lower)
+		require.NoError(t, err)
+		require.False(t, lockedUntil.IsZero())
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		email := newEmail(t)
+		for i := 1; i < maxAttempts; i++ {
+			_, err := rs.RegisterFailedLogin(ctx, email, maxAttempts, window, base, maxDuration)
+			require.NoError(t, err)
+		}
+
+		// a successful login forgets the failures, it takes all the attempts again to lock the account
+		require.NoError(t, rs.ResetFailedLogins(ctx, email))
+		require.InDelta(t, base.Seconds(), fail(t, email).Seconds(), 1)
+
+		// the lock itself stays until it expires or is removed
+		require.NoError(t, rs.ResetFailedLogins(ctx, email))
+		lockedUntil, err := rs.GetLockout(ctx, email)
+		require.NoError(t, err)
+		require.False(t, lockedUntil.IsZero())
+
+		require.NoError(t, rs.Unlock(ctx, email))
+		lockedUntil, err = rs.GetLockout(ctx, email)
+		require.NoError(t, err)
+		require.True(t, lockedUntil.IsZero())
+		// without previous locks the next one starts from the base duration
+		require.InDelta(t, base.Seconds(), fail(t, email).Seconds(), 1)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		email := newEmail(t)
+		for i := 1; i < maxAttempts; i++ {
+			_, err := rs.RegisterFailedLogin(ctx, email, maxAttempts, 100*time.Millisecond, base, maxDuration)
+			require.NoError(t, err)
+		}
+
+		// the failures expire with the window counted from the first one
+		time.Sleep(200 * time.Millisecond)
+		lockedUntil, err := rs.RegisterFailedLogin(ctx, email, maxAttempts, 100*time.Millisecond, base, maxDuration)
+		require.NoError(t, err)
+		require.True(t, lockedUntil.IsZero())
+	})
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"GYMBRO/internal/lib/metrics"
+	"GYMBRO/internal/lib/tracing"
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"net"
+	"strings"
+	"time"
+)
+
+// metricsHook measures the latency of every Redis command and traces it (pipelines are measured as "pipeline").
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startCommandSpan(ctx, cmd.Name())
+		start := time.Now()
+		err := next(ctx, cmd)
+		metrics.RedisCommandDuration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+		endCommandSpan(span, err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startCommandSpan(ctx, "pipeline")
+		start := time.Now()
+		err := next(ctx, cmds)
+		metrics.RedisCommandDuration.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+		endCommandSpan(span, err)
+		return err
+	}
+}
+
+func startCommandSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "redis "+strings.ToUpper(command),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.String("db.operation.name", command)),
+	)
+}
+
+func endCommandSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+)
+
+// sessionEventsPrefix names the pub/sub channel of a user's session. Channels are not keys,
+// so they do not show up in the SCAN of sessions.
+const sessionEventsPrefix = "session-events:"
+
+// changeSession runs the change and publishes the event in one MULTI, so the subscribers on every
+// replica get the events in the order the changes were made and never miss a change that was stored.
+func (rs *RedisStorage) changeSession(ctx context.Context, event *storage.SessionEvent, change func(pipe redis.Pipeliner)) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = rs.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		change(pipe)
+		pipe.Publish(ctx, sessionEventsPrefix+event.UserID, payload)
+		return nil
+	})
+	return err
+}
+
+// SubscribeSessions subscribes to the channel of the user's session. It returns once Redis confirmed
+// the subscription, so no change made afterwards is missed.
+func (rs *RedisStorage) SubscribeSessions(ctx context.Context, userID *string) (<-chan *storage.SessionEvent, error) {
+	const op = "storage.redis.SubscribeSessions"
+	spanCtx, span := tracing.Start(ctx, op)
+	defer span.End()
+	sub := rs.Client.Subscribe(spanCtx, sessionEventsPrefix+*userID)
+	if _, err := sub.Receive(spanCtx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events := make(chan *storage.SessionEvent)
+	go func() {
+		defer close(events)
+		defer sub.Close()
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event storage.SessionEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					continue
+				}
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+const rateLimitPrefix = "ratelimit:"
+
+// slidingWindow keeps the timestamps of hits in a sorted set. Old hits are trimmed,
+// a new one is added only if there is room, so rejected requests do not extend the window.
+// Returns {1, 0} if allowed or {0, milliseconds until the oldest hit leaves the window}.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// Allow registers a hit for the key in a sliding window of the given size.
+func (rs *RedisStorage) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	const op = "storage.redis.Allow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	now := time.Now().UnixMilli()
+	member := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	res, err := slidingWindow.Run(ctx, rs.Client, []string{rateLimitPrefix + key}, now, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("%s: unexpected script result %v", op, res)
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -1,18 +1,22 @@
 package redis
 
 import (
+	"GYMBRO/internal/lib/tracing"
 	"GYMBRO/internal/storage"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
+	"strings"
 	"time"
 )
 
+// sessionPrefix separates workout sessions from the other keys (rate limits, etc.) kept in the same database.
+const sessionPrefix = "session:"
+
 type RedisStorage struct {
 	Client *redis.Client
-	ctx    context.Context
 }
 
 func New(redisAddr string, password string, db int) (*RedisStorage, error) {
@@ -22,30 +26,109 @@ func New(redisAddr string, password string, db int) (*RedisStorage, error) {
 		Password: password,
 		DB:       db,
 	})
+	rdb.AddHook(metricsHook{})
 	_, err := rdb.Ping(context.Background()).Result()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return &RedisStorage{
 		Client: rdb,
-		ctx:    context.Background(),
 	}, nil
 }
 
+// MigrateSessionKeys moves the sessions stored before sessionPrefix was added, under the user ID alone, to their
+// prefixed keys, so workouts in progress during the upgrade are not lost. Every other key has a prefix, so only string keys
+// without a colon holding a session of that user are moved. A prefixed session already started by the user wins
+// and the old one is deleted. It is safe to run more than once.
+func (rs *RedisStorage) MigrateSessionKeys(ctx context.Context) error {
+	const op = "storage.redis.MigrateSessionKeys"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var cursor uint64
+	for {
+		keys, newCursor, err := rs.Client.Scan(ctx, cursor, "*", 100).Result()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, key := range keys {
+			if strings.Contains(key, ":") {
+				continue
+			}
+			// GET fails with WRONGTYPE on hashes, lists, etc., none of them is a session
+			keyType, err := rs.Client.Type(ctx, key).Result()
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			if keyType != "string" {
+				continue
+			}
+			data, err := rs.Client.Get(ctx, key).Result()
+			if err != nil {
+				if errors.Is(err, redis.Nil) {
+					continue
+				}
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			var session storage.WorkoutSession
+			if err := json.Unmarshal([]byte(data), &session); err != nil || session.UserID != key {
+				continue
+			}
+			moved, err := rs.Client.RenameNX(ctx, key, sessionPrefix+key).Result()
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			if !moved {
+				if err := rs.Client.Del(ctx, key).Err(); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+			}
+		}
+
+		if newCursor == 0 {
+			break
+		}
+		cursor = newCursor
+	}
+	return nil
+}
+
+// Ping checks the connection to Redis.
+func (rs *RedisStorage) Ping(ctx context.Context) error {
+	const op = "storage.redis.Ping"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if err := rs.Client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
 // CreateSession initializes a new workout session for a user and stores it in Redis.
-func (rs *RedisStorage) CreateSession(session *storage.WorkoutSession) error {
+func (rs *RedisStorage) CreateSession(ctx context.Context, session *storage.WorkoutSession) error {
 	const op = "storage.redis.CreateSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	data, err := json.Marshal(session)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	return rs.Client.Set(rs.ctx, session.UserID, data, 0).Err()
+	event := &storage.SessionEvent{Type: storage.SessionCreated, UserID: session.UserID, Session: session}
+	err = rs.changeSession(ctx, event, func(pipe redis.Pipeliner) {
+		pipe.Set(ctx, sessionPrefix+session.UserID, data, 0)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
 }
 
 // GetSession retrieves the workout session for a specific sessionID from Redis.
-func (rs *RedisStorage) GetSession(userID *string) (*storage.WorkoutSession, error) {
+func (rs *RedisStorage) GetSession(ctx context.Context, userID *string) (*storage.WorkoutSession, error) {
 	const op = "storage.redis.GetSession"
-	data, err := rs.Client.Get(rs.ctx, *userID).Result()
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	data, err := rs.Client.Get(ctx, sessionPrefix+*userID).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, storage.ErrNoSession
@@ -61,14 +144,19 @@ func (rs *RedisStorage) GetSession(userID *string) (*storage.WorkoutSession, err
 }
 
 // UpdateSession updates a session's details (e.g., points, records) and stores it in Redis.
-func (rs *RedisStorage) UpdateSession(userID *string, updatedSession *storage.WorkoutSession) error {
+func (rs *RedisStorage) UpdateSession(ctx context.Context, userID *string, updatedSession *storage.WorkoutSession) error {
 	const op = "storage.redis.UpdateSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	updatedSession.LastUpdated = time.Now()
 	data, err := json.Marshal(updatedSession)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	err = rs.Client.Set(rs.ctx, *userID, data, 0).Err()
+	event := &storage.SessionEvent{Type: storage.SessionUpdated, UserID: *userID, Session: updatedSession}
+	err = rs.changeSession(ctx, event, func(pipe redis.Pipeliner) {
+		pipe.Set(ctx, sessionPrefix+*userID, data, 0)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -76,9 +164,14 @@ func (rs *RedisStorage) UpdateSession(userID *string, updatedSession *storage.Wo
 }
 
 // DeleteSession removes a session completely from Redis.
-func (rs *RedisStorage) DeleteSession(userID *string) error {
+func (rs *RedisStorage) DeleteSession(ctx context.Context, userID *string) error {
 	const op = "storage.redis.DeleteSession"
-	err := rs.Client.Del(rs.ctx, *userID).Err()
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	event := &storage.SessionEvent{Type: storage.SessionDeleted, UserID: *userID}
+	err := rs.changeSession(ctx, event, func(pipe redis.Pipeliner) {
+		pipe.Del(ctx, sessionPrefix+*userID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -86,20 +179,22 @@ func (rs *RedisStorage) DeleteSession(userID *string) error {
 }
 
 // GetAllSessions retrieves all workout sessions stored in Redis.
-func (rs *RedisStorage) GetAllSessions() ([]*storage.WorkoutSession, error) {
+func (rs *RedisStorage) GetAllSessions(ctx context.Context) ([]*storage.WorkoutSession, error) {
 	const op = "storage.redis.GetAllSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var (
 		cursor   uint64
 		sessions []*storage.WorkoutSession
 	)
 	for {
-		keys, newCursor, err := rs.Client.Scan(rs.ctx, cursor, "", 10).Result()
+		keys, newCursor, err := rs.Client.Scan(ctx, cursor, sessionPrefix+"*", 10).Result()
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
 		for _, key := range keys {
-			data, err := rs.Client.Get(rs.ctx, key).Result()
+			data, err := rs.Client.Get(ctx, key).Result()
 			if err != nil {
 				if errors.Is(err, redis.Nil) {
 					continue
@@ -122,3 +217,61 @@ func (rs *RedisStorage) GetAllSessions() ([]*storage.WorkoutSession, error) {
 
 	return sessions, nil
 }
+
+// GetSessions retrieves the sessions of the users that have one with a single MGET.
+func (rs *RedisStorage) GetSessions(ctx context.Context, userIDs []string) ([]*storage.WorkoutSession, error) {
+	const op = "storage.redis.GetSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = sessionPrefix + userID
+	}
+	values, err := rs.Client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sessions []*storage.WorkoutSession
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// the user has no session
+			continue
+		}
+		var session storage.WorkoutSession
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+// CountSessions returns the number of workout sessions stored in Redis without reading them.
+// SCAN can return a key more than once while keys are being changed, so it is an estimate for metrics only.
+func (rs *RedisStorage) CountSessions(ctx context.Context) (int, error) {
+	const op = "storage.redis.CountSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var (
+		cursor uint64
+		count  int
+	)
+	for {
+		keys, newCursor, err := rs.Client.Scan(ctx, cursor, sessionPrefix+"*", 100).Result()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		count += len(keys)
+
+		if newCursor == 0 {
+			break
+		}
+		cursor = newCursor
+	}
+	return count, nil
+}
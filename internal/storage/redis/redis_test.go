@@ -0,0 +1,65 @@
+package redis_test
+
+import (
+	"GYMBRO/internal/storage"
+	"GYMBRO/internal/storage/redis"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+// TestMigrateSessionKeys runs against Redis at TEST_REDIS_PATH (with TEST_REDIS_PASSWORD).
+func TestMigrateSessionKeys(t *testing.T) {
+	redisPath := os.Getenv("TEST_REDIS_PATH")
+	if redisPath == "" {
+		t.Skip("TEST_REDIS_PATH is not set")
+	}
+	rs, err := redis.New(redisPath, os.Getenv("TEST_REDIS_PASSWORD"), 0)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	old := &storage.WorkoutSession{SessionID: storage.GenerateUID(), UserID: storage.GenerateUID()}
+	data, err := json.Marshal(old)
+	require.NoError(t, err)
+	require.NoError(t, rs.Client.Set(ctx, old.UserID, data, 0).Err())
+	t.Cleanup(func() { rs.Client.Del(context.Background(), old.UserID) })
+	t.Cleanup(func() { _ = rs.DeleteSession(context.Background(), &old.UserID) })
+
+	// the user started a new workout after the upgrade, it is kept
+	started := &storage.WorkoutSession{SessionID: storage.GenerateUID(), UserID: storage.GenerateUID()}
+	data, err = json.Marshal(started)
+	require.NoError(t, err)
+	require.NoError(t, rs.Client.Set(ctx, started.UserID, data, 0).Err())
+	t.Cleanup(func() { rs.Client.Del(context.Background(), started.UserID) })
+	require.NoError(t, rs.CreateSession(ctx, &storage.WorkoutSession{SessionID: "new", UserID: started.UserID}))
+	t.Cleanup(func() { _ = rs.DeleteSession(context.Background(), &started.UserID) })
+
+	// not a session
+	other := storage.GenerateUID()
+	require.NoError(t, rs.Client.Set(ctx, other, "value", 0).Err())
+	t.Cleanup(func() { rs.Client.Del(context.Background(), other) })
+
+	// not a string, GET would fail with WRONGTYPE
+	hash := storage.GenerateUID()
+	require.NoError(t, rs.Client.HSet(ctx, hash, "field", "value").Err())
+	t.Cleanup(func() { rs.Client.Del(context.Background(), hash) })
+
+	require.NoError(t, rs.MigrateSessionKeys(ctx))
+	require.NoError(t, rs.MigrateSessionKeys(ctx))
+
+	session, err := rs.GetSession(ctx, &old.UserID)
+	require.NoError(t, err)
+	require.Equal(t, old.SessionID, session.SessionID)
+	require.Zero(t, rs.Client.Exists(ctx, old.UserID).Val())
+
+	// the old session lost to the new one and is gone
+	session, err = rs.GetSession(ctx, &started.UserID)
+	require.NoError(t, err)
+	require.Equal(t, "new", session.SessionID)
+	require.Zero(t, rs.Client.Exists(ctx, started.UserID).Val())
+
+	require.Equal(t, "value", rs.Client.Get(ctx, other).Val())
+	require.Equal(t, "value", rs.Client.HGet(ctx, hash, "field").Val())
+}
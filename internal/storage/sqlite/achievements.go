@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+)
+
+// AwardAchievement stores the achievement unless the user already has it
+func (s *Storage) AwardAchievement(ctx context.Context, achievement *storage.Achievement) (bool, error) {
+	const op = "storage.sqlite.AwardAchievement"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `INSERT INTO achievements (fk_user_id, code, awarded_at) VALUES (?, ?, ?) ON CONFLICT DO NOTHING`,
+		achievement.UserID, achievement.Code, formatTime(achievement.AwardedAt))
+	if err != nil {
+		if isForeignKey(err) {
+			return false, storage.ErrUserNotFound
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return n > 0, nil
+}
+
+// GetAchievements retrieves user's achievements ordered by award time
+func (s *Storage) GetAchievements(ctx context.Context, userID *string) ([]*storage.Achievement, error) {
+	const op = "storage.sqlite.GetAchievements"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT fk_user_id, code, awarded_at FROM achievements WHERE fk_user_id = ? ORDER BY awarded_at, code`, *userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var achievements []*storage.Achievement
+	for rows.Next() {
+		var (
+			achievement storage.Achievement
+			awardedAt   string
+		)
+		if err := rows.Scan(&achievement.UserID, &achievement.Code, &awardedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if achievement.AwardedAt, err = parseTime(awardedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		achievements = append(achievements, &achievement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return achievements, nil
+}
@@ -0,0 +1,292 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const challengeColumns = `challenge_id, name, goal, COALESCE(fk_exercise_id, 0), target, reward, start_time, end_time, settled_at, created_at`
+
+const resultColumns = `fk_challenge_id, fk_clan_id, progress, rank, completed, points`
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func scanChallenge(row scanner) (*storage.Challenge, error) {
+	var (
+		challenge                     storage.Challenge
+		startTime, endTime, createdAt string
+		settledAt                     sql.NullString
+	)
+	err := row.Scan(&challenge.ChallengeID, &challenge.Name, &challenge.Goal, &challenge.FkExerciseId, &challenge.Target, &challenge.Reward,
+		&startTime, &endTime, &settledAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.StartTime, err = parseTime(startTime); err != nil {
+		return nil, err
+	}
+	if challenge.EndTime, err = parseTime(endTime); err != nil {
+		return nil, err
+	}
+	if challenge.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if settledAt.Valid {
+		t, err := parseTime(settledAt.String)
+		if err != nil {
+			return nil, err
+		}
+		challenge.SettledAt = &t
+	}
+	return &challenge, nil
+}
+
+// CreateChallenge stores a challenge
+func (s *Storage) CreateChallenge(ctx context.Context, challenge *storage.Challenge) error {
+	const op = "storage.sqlite.CreateChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO challenges (challenge_id, name, goal, fk_exercise_id, target, reward, start_time, end_time, created_at)
+		VALUES (?, ?, ?, NULLIF(?, 0), ?, ?, ?, ?, ?)`,
+		challenge.ChallengeID, challenge.Name, challenge.Goal, challenge.FkExerciseId, challenge.Target, challenge.Reward,
+		formatTime(challenge.StartTime), formatTime(challenge.EndTime), formatTime(challenge.CreatedAt))
+	switch {
+	case err == nil:
+		return nil
+	case isForeignKey(err):
+		return storage.ErrExerciseNotFound
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// GetChallenge retrieves a challenge by its ID
+func (s *Storage) GetChallenge(ctx context.Context, challengeID *string) (*storage.Challenge, error) {
+	const op = "storage.sqlite.GetChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	challenge, err := scanChallenge(s.db.QueryRowContext(ctx, `SELECT `+challengeColumns+` FROM challenges WHERE challenge_id = ?`, *challengeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return challenge, nil
+}
+
+// GetChallenges retrieves the challenges not settled yet ordered by end time, or the settled ones, the last ended first
+func (s *Storage) GetChallenges(ctx context.Context, settled bool) ([]*storage.Challenge, error) {
+	const op = "storage.sqlite.GetChallenges"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query := `SELECT ` + challengeColumns + ` FROM challenges WHERE settled_at IS NULL ORDER BY end_time, challenge_id`
+	if settled {
+		query = `SELECT ` + challengeColumns + ` FROM challenges WHERE settled_at IS NOT NULL ORDER BY end_time DESC, challenge_id`
+	}
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var challenges []*storage.Challenge
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenges, nil
+}
+
+// challengeProgress sums the workouts started in the challenge window per clan of their users,
+// users in the default clan are not in a clan and are left out
+func challengeProgress(ctx context.Context, db queryer, challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	args := []interface{}{formatTime(challenge.StartTime), formatTime(challenge.EndTime)}
+	var progress, records string
+	switch challenge.Goal {
+	case storage.GoalPoints:
+		progress = `SUM(w.points)`
+	case storage.GoalWorkouts:
+		progress = `COUNT(*)`
+	case storage.GoalTonnage:
+		progress = `SUM(r.reps * r.weight)`
+		records = `JOIN records r ON r.fk_workout_id = w.workout_id AND r.fk_exercise_id = ? AND r.status = 'ok'`
+		args = []interface{}{challenge.FkExerciseId, formatTime(challenge.StartTime), formatTime(challenge.EndTime)}
+	default:
+		return nil, fmt.Errorf("unknown goal %q", challenge.Goal)
+	}
+	args = append(args, storage.DefaultClanID)
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`SELECT u.fk_clan_id, %[1]s
+		FROM workouts w
+		JOIN users u ON u.user_id = w.fk_user_id
+		%[2]s
+		WHERE w.start_time >= ? AND w.start_time < ? AND u.fk_clan_id <> ?
+		GROUP BY u.fk_clan_id
+		HAVING %[1]s > 0`, progress, records), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*storage.ChallengeResult
+	for rows.Next() {
+		result := &storage.ChallengeResult{FkChallengeId: challenge.ChallengeID}
+		if err := rows.Scan(&result.FkClanId, &result.Progress); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	storage.RankResults(challenge, results)
+	return results, nil
+}
+
+// GetChallengeProgress computes the live progress of the clans in the challenge
+func (s *Storage) GetChallengeProgress(ctx context.Context, challenge *storage.Challenge) ([]*storage.ChallengeResult, error) {
+	const op = "storage.sqlite.GetChallengeProgress"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	results, err := challengeProgress(ctx, s.db, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+// SettleChallenge stores the final progress of the clans and awards the reward in one transaction.
+// Clans get a row of points with their first award, users refer to clans without a foreign key.
+func (s *Storage) SettleChallenge(ctx context.Context, challengeID *string, settledAt time.Time) ([]*storage.ChallengeResult, error) {
+	const op = "storage.sqlite.SettleChallenge"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	challenge, err := scanChallenge(tx.QueryRowContext(ctx, `UPDATE challenges SET settled_at = ? WHERE challenge_id = ? AND settled_at IS NULL
+		RETURNING `+challengeColumns, formatTime(settledAt), *challengeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM challenges WHERE challenge_id = ?)`, *challengeID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("%s, existsQuery: %w", op, err)
+		}
+		if !exists {
+			return nil, storage.ErrChallengeNotFound
+		}
+		return nil, storage.ErrChallengeSettled
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s, settleQuery: %w", op, err)
+	}
+
+	results, err := challengeProgress(ctx, tx, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%s, progressQuery: %w", op, err)
+	}
+
+	for _, result := range results {
+		if result.Completed {
+			result.Points = challenge.Reward
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO challengeresults (`+resultColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+			result.FkChallengeId, result.FkClanId, result.Progress, result.Rank, result.Completed, result.Points)
+		if err != nil {
+			return nil, fmt.Errorf("%s, resultsQuery: %w", op, err)
+		}
+		if result.Points > 0 {
+			_, err := tx.ExecContext(ctx, `INSERT INTO clans (clan_id, points, season_points) VALUES (?, ?, ?)
+				ON CONFLICT (clan_id) DO UPDATE SET points = points + excluded.points, season_points = season_points + excluded.season_points`,
+				result.FkClanId, result.Points, result.Points)
+			if err != nil {
+				return nil, fmt.Errorf("%s, pointsQuery: %w", op, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return results, nil
+}
+
+func (s *Storage) getResults(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.ChallengeResult, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var results []*storage.ChallengeResult
+	for rows.Next() {
+		result := &storage.ChallengeResult{}
+		if err := rows.Scan(&result.FkChallengeId, &result.FkClanId, &result.Progress, &result.Rank, &result.Completed, &result.Points); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return results, nil
+}
+
+// GetChallengeResults retrieves the stored results of a settled challenge, ranked
+func (s *Storage) GetChallengeResults(ctx context.Context, challengeID *string) ([]*storage.ChallengeResult, error) {
+	const op = "storage.sqlite.GetChallengeResults"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getResults(ctx, op, `SELECT `+resultColumns+` FROM challengeresults WHERE fk_challenge_id = ? ORDER BY rank, fk_clan_id`, *challengeID)
+}
+
+// GetClanResults retrieves the results of the clan in settled challenges, the last ended first
+func (s *Storage) GetClanResults(ctx context.Context, clanID *string) ([]*storage.ChallengeResult, error) {
+	const op = "storage.sqlite.GetClanResults"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getResults(ctx, op, `SELECT r.fk_challenge_id, r.fk_clan_id, r.progress, r.rank, r.completed, r.points
+		FROM challengeresults r
+		JOIN challenges c ON c.challenge_id = r.fk_challenge_id
+		WHERE r.fk_clan_id = ?
+		ORDER BY c.end_time DESC, c.challenge_id`, *clanID)
+}
+
+// GetClan retrieves a clan with its points, clans of users that never got points have no row
+func (s *Storage) GetClan(ctx context.Context, clanID *string) (*storage.Clan, error) {
+	const op = "storage.sqlite.GetClan"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var clan storage.Clan
+	err := s.db.QueryRowContext(ctx, `SELECT clan_id, name, points, season_points FROM clans WHERE clan_id = ?`, *clanID).
+		Scan(&clan.ClanId, &clan.Name, &clan.Points, &clan.SeasonPoints)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrClanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &clan, nil
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+)
+
+// AddEvents stores the events in one transaction
+func (s *Storage) AddEvents(ctx context.Context, events []*storage.Event) error {
+	const op = "storage.sqlite.AddEvents"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		_, err := tx.ExecContext(ctx, `INSERT INTO events (event_id, fk_user_id, type, workout_id, points, exercise_id, weight, reps, code, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			event.EventID, event.UserID, event.Type, event.WorkoutID, event.Points, event.ExerciseID, event.Weight, event.Reps, event.Code, formatTime(event.CreatedAt))
+		if err != nil {
+			if isForeignKey(err) {
+				return storage.ErrUserNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetFeed retrieves events of the users the user follows, newest first. The page starts after the cursor.
+func (s *Storage) GetFeed(ctx context.Context, userID *string, cursor *storage.FeedCursor, limit int) ([]*storage.Event, error) {
+	const op = "storage.sqlite.GetFeed"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query := `SELECT e.event_id, e.fk_user_id, e.type, e.workout_id, e.points, e.exercise_id, e.weight, e.reps, e.code, e.created_at
+		FROM events e JOIN follows f ON f.followee_id = e.fk_user_id
+		WHERE f.follower_id = ? AND f.status = ?`
+	args := []interface{}{*userID, storage.FollowAccepted}
+	if cursor != nil {
+		// times are stored in a fixed width layout, so they compare as strings
+		query += ` AND (e.created_at, e.event_id) < (?, ?)`
+		args = append(args, formatTime(cursor.CreatedAt), cursor.EventID)
+	}
+	query += ` ORDER BY e.created_at DESC, e.event_id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var events []*storage.Event
+	for rows.Next() {
+		var (
+			event     storage.Event
+			createdAt string
+		)
+		err := rows.Scan(&event.EventID, &event.UserID, &event.Type, &event.WorkoutID, &event.Points, &event.ExerciseID, &event.Weight, &event.Reps, &event.Code, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if event.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,265 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selectExercises selects exercises with IDs of the muscle groups they target, see scanExercise
+const selectExercises = `SELECT e.exercise_id, e.name, COALESCE(e.description, ''), COALESCE(e.picture, ''), e.weight_limit,
+		COALESCE((SELECT group_concat(muscle_group_id) FROM (
+			SELECT muscle_group_id FROM exercisemusclegroups WHERE exercise_id = e.exercise_id ORDER BY muscle_group_id
+		)), '')
+		FROM exercises e`
+
+func scanExercise(row scanner) (*storage.Exercise, error) {
+	var (
+		exercise     = &storage.Exercise{}
+		muscleGroups string
+	)
+	if err := row.Scan(&exercise.ExerciseId, &exercise.Name, &exercise.Description, &exercise.Picture, &exercise.WeightLimit, &muscleGroups); err != nil {
+		return nil, err
+	}
+	exercise.MuscleGroupIds = []int{}
+	for _, id := range strings.Split(muscleGroups, ",") {
+		if id == "" {
+			continue
+		}
+		groupID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, err
+		}
+		exercise.MuscleGroupIds = append(exercise.MuscleGroupIds, groupID)
+	}
+	return exercise, nil
+}
+
+// GetExercises retrieves all exercises with IDs of the muscle groups they target
+func (s *Storage) GetExercises(ctx context.Context) ([]*storage.Exercise, error) {
+	const op = "storage.sqlite.GetExercises"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, selectExercises+` ORDER BY e.exercise_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var exercises []*storage.Exercise
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exercises, nil
+}
+
+// GetExercise retrieves an exercise with IDs of the muscle groups it targets
+func (s *Storage) GetExercise(ctx context.Context, exerciseID int) (*storage.Exercise, error) {
+	const op = "storage.sqlite.GetExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	exercise, err := scanExercise(s.db.QueryRowContext(ctx, selectExercises+` WHERE e.exercise_id = ?`, exerciseID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrExerciseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return exercise, nil
+}
+
+// CreateExercise creates an exercise with its muscle groups and returns its ID
+func (s *Storage) CreateExercise(ctx context.Context, exercise *storage.Exercise) (*int, error) {
+	const op = "storage.sqlite.CreateExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO exercises (name, description, picture, weight_limit) VALUES (?, ?, ?, ?) RETURNING exercise_id`,
+		exercise.Name, exercise.Description, exercise.Picture, exercise.WeightLimit).Scan(&id)
+	if err != nil {
+		return nil, exerciseError(op, err)
+	}
+
+	if err := setExerciseMuscleGroups(ctx, tx, id, exercise.MuscleGroupIds); err != nil {
+		return nil, exerciseError(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &id, nil
+}
+
+// UpdateExercise updates an exercise and replaces its muscle groups
+func (s *Storage) UpdateExercise(ctx context.Context, exercise *storage.Exercise) error {
+	const op = "storage.sqlite.UpdateExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE exercises SET name = ?, description = ?, picture = ?, weight_limit = ? WHERE exercise_id = ?`,
+		exercise.Name, exercise.Description, exercise.Picture, exercise.WeightLimit, exercise.ExerciseId)
+	if err != nil {
+		return exerciseError(op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrExerciseNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM exercisemusclegroups WHERE exercise_id = ?`, exercise.ExerciseId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := setExerciseMuscleGroups(ctx, tx, exercise.ExerciseId, exercise.MuscleGroupIds); err != nil {
+		return exerciseError(op, err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteExercise deletes an exercise unless records, maxes or challenges reference it. The check is a condition of the delete,
+// so nothing can reference the exercise in between.
+func (s *Storage) DeleteExercise(ctx context.Context, exerciseID *int) error {
+	const op = "storage.sqlite.DeleteExercise"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `DELETE FROM exercises WHERE exercise_id = ?1
+		AND NOT EXISTS (SELECT 1 FROM records WHERE fk_exercise_id = ?1)
+		AND NOT EXISTS (SELECT 1 FROM userexercisemaxweights WHERE exercise_id = ?1)
+		AND NOT EXISTS (SELECT 1 FROM challenges WHERE fk_exercise_id = ?1)`, *exerciseID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM exercises WHERE exercise_id = ?)`, *exerciseID).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if exists {
+		return storage.ErrExerciseInUse
+	}
+	return storage.ErrExerciseNotFound
+}
+
+// GetMuscleGroups retrieves all muscle groups
+func (s *Storage) GetMuscleGroups(ctx context.Context) ([]*storage.MuscleGroup, error) {
+	const op = "storage.sqlite.GetMuscleGroups"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT muscle_group_id, name FROM musclegroups ORDER BY muscle_group_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var groups []*storage.MuscleGroup
+	for rows.Next() {
+		group := &storage.MuscleGroup{}
+		if err := rows.Scan(&group.MuscleGroupId, &group.Name); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return groups, nil
+}
+
+// CreateMuscleGroup creates a muscle group and returns its ID
+func (s *Storage) CreateMuscleGroup(ctx context.Context, group *storage.MuscleGroup) (*int, error) {
+	const op = "storage.sqlite.CreateMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var id int
+	err := s.db.QueryRowContext(ctx, `INSERT INTO musclegroups (name) VALUES (?) RETURNING muscle_group_id`, group.Name).Scan(&id)
+	if err != nil {
+		if isUnique(err) {
+			return nil, storage.ErrMuscleGroupExists
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &id, nil
+}
+
+// UpdateMuscleGroup renames a muscle group
+func (s *Storage) UpdateMuscleGroup(ctx context.Context, group *storage.MuscleGroup) error {
+	const op = "storage.sqlite.UpdateMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `UPDATE musclegroups SET name = ? WHERE muscle_group_id = ?`, group.Name, group.MuscleGroupId)
+	if err != nil {
+		if isUnique(err) {
+			return storage.ErrMuscleGroupExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrMuscleGroupNotFound
+	}
+	return nil
+}
+
+// DeleteMuscleGroup deletes a muscle group (links to exercises are deleted by cascade)
+func (s *Storage) DeleteMuscleGroup(ctx context.Context, groupID *int) error {
+	const op = "storage.sqlite.DeleteMuscleGroup"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `DELETE FROM musclegroups WHERE muscle_group_id = ?`, *groupID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrMuscleGroupNotFound
+	}
+	return nil
+}
+
+func setExerciseMuscleGroups(ctx context.Context, tx *sql.Tx, exerciseID int, groupIDs []int) error {
+	for _, groupID := range groupIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO exercisemusclegroups (exercise_id, muscle_group_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, exerciseID, groupID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exerciseError maps unique (name) and foreign key (muscle group) violations to storage errors
+func exerciseError(op string, err error) error {
+	switch {
+	case isUnique(err):
+		return storage.ErrExerciseExists
+	case isForeignKey(err):
+		return storage.ErrMuscleGroupNotFound
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"fmt"
+)
+
+// Follow stores a follow request with its status
+func (s *Storage) Follow(ctx context.Context, follow *storage.Follow) error {
+	const op = "storage.sqlite.Follow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO follows (follower_id, followee_id, status, created_at) VALUES (?, ?, ?, ?)`,
+		follow.FollowerID, follow.FolloweeID, follow.Status, formatTime(follow.CreatedAt))
+	switch {
+	case err == nil:
+		return nil
+	case isUnique(err):
+		return storage.ErrFollowExists
+	case isForeignKey(err):
+		return storage.ErrUserNotFound
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// Unfollow deletes the follow, pending or accepted
+func (s *Storage) Unfollow(ctx context.Context, followerID *string, followeeID *string) error {
+	const op = "storage.sqlite.Unfollow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateFollow(ctx, op, `DELETE FROM follows WHERE follower_id = ? AND followee_id = ?`, *followerID, *followeeID)
+}
+
+// AcceptFollow accepts a pending follow request
+func (s *Storage) AcceptFollow(ctx context.Context, followerID *string, followeeID *string) error {
+	const op = "storage.sqlite.AcceptFollow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateFollow(ctx, op, `UPDATE follows SET status = ? WHERE follower_id = ? AND followee_id = ? AND status = ?`,
+		storage.FollowAccepted, *followerID, *followeeID, storage.FollowPending)
+}
+
+// updateFollow runs a change of one follow and returns storage.ErrFollowNotFound if nothing was changed.
+func (s *Storage) updateFollow(ctx context.Context, op string, query string, args ...interface{}) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrFollowNotFound
+	}
+	return nil
+}
+
+// GetFollowers retrieves follows to the user, newest first
+func (s *Storage) GetFollowers(ctx context.Context, userID *string) ([]*storage.Follow, error) {
+	const op = "storage.sqlite.GetFollowers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getFollows(ctx, op, `SELECT follower_id, followee_id, status, created_at FROM follows WHERE followee_id = ? ORDER BY created_at DESC, follower_id`, *userID)
+}
+
+// GetFollowing retrieves follows of the user, newest first
+func (s *Storage) GetFollowing(ctx context.Context, userID *string) ([]*storage.Follow, error) {
+	const op = "storage.sqlite.GetFollowing"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getFollows(ctx, op, `SELECT follower_id, followee_id, status, created_at FROM follows WHERE follower_id = ? ORDER BY created_at DESC, followee_id`, *userID)
+}
+
+func (s *Storage) getFollows(ctx context.Context, op string, query string, userID string) ([]*storage.Follow, error) {
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var follows []*storage.Follow
+	for rows.Next() {
+		var (
+			follow    storage.Follow
+			createdAt string
+		)
+		if err := rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.Status, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if follow.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		follows = append(follows, &follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return follows, nil
+}
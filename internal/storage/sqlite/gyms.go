@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Subscription dates are stored as midnights in UTC, so a day compares with them as a string.
+
+// CreateSubscription stores a subscription of the user to the gym
+func (s *Storage) CreateSubscription(ctx context.Context, subscription *storage.Subscription) error {
+	const op = "storage.sqlite.CreateSubscription"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO subscriptions (subscription_id, fk_user_id, fk_gym_id, start_date, end_date, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		subscription.SubscriptionId, subscription.FkUserId, subscription.FkGymId,
+		formatTime(day(subscription.StartDate)), formatTime(day(subscription.EndDate)), formatTime(subscription.CreatedAt))
+	switch {
+	case err == nil:
+		return nil
+	case isForeignKey(err):
+		return storage.ErrUserNotFound
+	default:
+		return fmt.Errorf("%s: %w", op, err)
+	}
+}
+
+// GetActiveSubscription retrieves the subscription of the user to the gym covering the day of at, the one ending last
+func (s *Storage) GetActiveSubscription(ctx context.Context, userID *string, gymID int, at time.Time) (*storage.Subscription, error) {
+	const op = "storage.sqlite.GetActiveSubscription"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var (
+		subscription                  storage.Subscription
+		startDate, endDate, createdAt string
+	)
+	today := formatTime(day(at))
+	err := s.db.QueryRowContext(ctx, `SELECT subscription_id, fk_user_id, fk_gym_id, start_date, end_date, created_at FROM subscriptions
+		WHERE fk_user_id = ? AND fk_gym_id = ? AND start_date <= ? AND end_date >= ?
+		ORDER BY end_date DESC, subscription_id LIMIT 1`, *userID, gymID, today, today).
+		Scan(&subscription.SubscriptionId, &subscription.FkUserId, &subscription.FkGymId, &startDate, &endDate, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrNoSubscription
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if subscription.StartDate, err = parseTime(startDate); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if subscription.EndDate, err = parseTime(endDate); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if subscription.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &subscription, nil
+}
+
+// CreateCheckIn stores a check-in
+func (s *Storage) CreateCheckIn(ctx context.Context, checkIn *storage.CheckIn) error {
+	const op = "storage.sqlite.CreateCheckIn"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO checkins (check_in_id, fk_user_id, fk_gym_id, fk_subscription_id, created_at) VALUES (?, ?, ?, ?, ?)`,
+		checkIn.CheckInID, checkIn.FkUserId, checkIn.FkGymId, checkIn.FkSubscriptionId, formatTime(checkIn.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetLastCheckIn retrieves the latest check-in of the user made at or after since
+func (s *Storage) GetLastCheckIn(ctx context.Context, userID *string, since time.Time) (*storage.CheckIn, error) {
+	const op = "storage.sqlite.GetLastCheckIn"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var (
+		checkIn   storage.CheckIn
+		createdAt string
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT check_in_id, fk_user_id, fk_gym_id, fk_subscription_id, created_at FROM checkins
+		WHERE fk_user_id = ? AND created_at >= ? ORDER BY created_at DESC, check_in_id DESC LIMIT 1`, *userID, formatTime(since)).
+		Scan(&checkIn.CheckInID, &checkIn.FkUserId, &checkIn.FkGymId, &checkIn.FkSubscriptionId, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrCheckInNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if checkIn.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &checkIn, nil
+}
+
+// day returns the midnight in UTC of the day of t.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,186 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const flaggedRecordQuery = `SELECT r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points, r.status, r.reason, w.fk_user_id, w.start_time
+	FROM records r
+	JOIN workouts w ON w.workout_id = r.fk_workout_id`
+
+// inSeason is true when the workout started at ? earned seasonal points, without seasons every workout does
+const inSeason = `NOT EXISTS (SELECT 1 FROM seasons WHERE ended_at IS NULL AND start_time > ?)`
+
+func scanFlaggedRecord(row scanner) (*storage.FlaggedRecord, error) {
+	var (
+		record    storage.FlaggedRecord
+		startTime string
+	)
+	err := row.Scan(&record.RecordId, &record.FkWorkoutId, &record.FkExerciseId, &record.Reps, &record.Weight, &record.Points,
+		&record.Status, &record.Reason, &record.UserID, &startTime)
+	if err != nil {
+		return nil, err
+	}
+	if record.StartTime, err = parseTime(startTime); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetFlaggedRecords retrieves saved records that are held or excluded, the oldest workout first.
+func (s *Storage) GetFlaggedRecords(ctx context.Context, limit int) ([]*storage.FlaggedRecord, error) {
+	const op = "storage.sqlite.GetFlaggedRecords"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, flaggedRecordQuery+` WHERE r.status IN ('held', 'excluded')
+		ORDER BY w.start_time, w.workout_id, r.rowid LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	records := make([]*storage.FlaggedRecord, 0)
+	for rows.Next() {
+		record, err := scanFlaggedRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return records, nil
+}
+
+// ApproveRecord makes a held record count and makes it the max of the user if it beats it, with prBonus added to its points then.
+// Its points are added to the workout and the user. Everything happens in one transaction.
+func (s *Storage) ApproveRecord(ctx context.Context, recordID *string, prBonus int) (*storage.FlaggedRecord, *storage.Max, error) {
+	const op = "storage.sqlite.ApproveRecord"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	record, err := scanFlaggedRecord(tx.QueryRowContext(ctx, flaggedRecordQuery+` WHERE r.record_id = ?`, *recordID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, storage.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, recordQuery: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE records SET status = 'ok' WHERE record_id = ? AND status = 'held'`, record.RecordId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, nil, storage.ErrRecordNotHeld
+	}
+	record.Status = storage.RecordOK
+
+	// nothing is inserted or updated when the max of the user is at least as good
+	res, err = tx.ExecContext(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = excluded.max_weight, reps = excluded.reps
+		WHERE excluded.max_weight > max_weight OR (excluded.max_weight = max_weight AND excluded.reps > reps)`,
+		record.UserID, record.FkExerciseId, record.Weight, record.Reps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+	}
+	var newMax *storage.Max
+	if n, _ := res.RowsAffected(); n > 0 {
+		newMax = &storage.Max{UserID: record.UserID, ExerciseId: record.FkExerciseId, MaxWeight: record.Weight, Reps: record.Reps}
+		record.Points += prBonus
+		if _, err := tx.ExecContext(ctx, `UPDATE records SET points = ? WHERE record_id = ?`, record.Points, record.RecordId); err != nil {
+			return nil, nil, fmt.Errorf("%s, bonusQuery: %w", op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE workouts SET points = points + ? WHERE workout_id = ?`, record.Points, record.FkWorkoutId); err != nil {
+		return nil, nil, fmt.Errorf("%s, workoutQuery: %w", op, err)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE users SET points = points + ?, season_points = season_points + CASE WHEN `+inSeason+` THEN ? ELSE 0 END
+		WHERE user_id = ?`, record.Points, formatTime(record.StartTime), record.Points, record.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, userQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return record, newMax, nil
+}
+
+// VoidRecord takes a record back. If it counted, its points are subtracted from the workout and the user
+// and the max of its exercise is rebuilt from the records that still count. Everything happens in one transaction.
+func (s *Storage) VoidRecord(ctx context.Context, recordID *string) (*storage.FlaggedRecord, error) {
+	const op = "storage.sqlite.VoidRecord"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	record, err := scanFlaggedRecord(tx.QueryRowContext(ctx, flaggedRecordQuery+` WHERE r.record_id = ?`, *recordID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s, recordQuery: %w", op, err)
+	}
+	if record.Status == storage.RecordVoided {
+		return nil, storage.ErrRecordVoided
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE records SET status = 'voided' WHERE record_id = ? AND status = ?`, record.RecordId, record.Status)
+	if err != nil {
+		return nil, fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, storage.ErrRecordVoided
+	}
+	counted := record.Counts()
+	record.Status = storage.RecordVoided
+
+	if counted {
+		if _, err := tx.ExecContext(ctx, `UPDATE workouts SET points = points - ? WHERE workout_id = ?`, record.Points, record.FkWorkoutId); err != nil {
+			return nil, fmt.Errorf("%s, workoutQuery: %w", op, err)
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE users SET points = points - ?,
+			season_points = CASE WHEN `+inSeason+` THEN MAX(season_points - ?, 0) ELSE season_points END
+			WHERE user_id = ?`, record.Points, formatTime(record.StartTime), record.Points, record.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%s, userQuery: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM userexercisemaxweights WHERE user_id = ? AND exercise_id = ?`, record.UserID, record.FkExerciseId)
+		if err != nil {
+			return nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps)
+			SELECT w.fk_user_id, r.fk_exercise_id, r.weight, r.reps
+			FROM records r
+			JOIN workouts w ON w.workout_id = r.fk_workout_id
+			WHERE w.fk_user_id = ? AND r.fk_exercise_id = ? AND r.status = 'ok'
+			ORDER BY r.weight DESC, r.reps DESC
+			LIMIT 1`, record.UserID, record.FkExerciseId)
+		if err != nil {
+			return nil, fmt.Errorf("%s, maxQuery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return record, nil
+}
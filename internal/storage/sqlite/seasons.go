@@ -0,0 +1,251 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const seasonColumns = `season_id, start_time, end_time, ended_at`
+
+// standingsQuery selects subject_id, name, points and rank of the users, clans or gyms in the current season,
+// the default clan and gym are left out.
+// There are no gyms in the SQLite storage, gym standings have no names.
+func standingsQuery(kind string) (string, error) {
+	switch kind {
+	case storage.StandingUsers:
+		return `SELECT user_id AS subject_id, username AS name, season_points AS points, RANK() OVER (ORDER BY season_points DESC) AS rank
+			FROM users WHERE season_points > 0 AND NOT is_private`, nil
+	case storage.StandingClans:
+		return `SELECT clan_id AS subject_id, name, season_points AS points, RANK() OVER (ORDER BY season_points DESC) AS rank
+			FROM clans WHERE season_points > 0 AND clan_id <> '` + storage.DefaultClanID + `'`, nil
+	case storage.StandingGyms:
+		return `SELECT CAST(fk_gym_id AS TEXT) AS subject_id, '' AS name, SUM(season_points) AS points,
+			RANK() OVER (ORDER BY SUM(season_points) DESC) AS rank
+			FROM users
+			WHERE season_points > 0 AND fk_gym_id <> 0
+			GROUP BY fk_gym_id`, nil
+	}
+	return "", fmt.Errorf("unknown standing kind %q", kind)
+}
+
+func scanSeason(row scanner) (*storage.Season, error) {
+	var (
+		season             storage.Season
+		startTime, endTime string
+		endedAt            sql.NullString
+	)
+	err := row.Scan(&season.SeasonID, &startTime, &endTime, &endedAt)
+	if err != nil {
+		return nil, err
+	}
+	if season.StartTime, err = parseTime(startTime); err != nil {
+		return nil, err
+	}
+	if season.EndTime, err = parseTime(endTime); err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		t, err := parseTime(endedAt.String)
+		if err != nil {
+			return nil, err
+		}
+		season.EndedAt = &t
+	}
+	return &season, nil
+}
+
+// StartSeason stores the first season
+func (s *Storage) StartSeason(ctx context.Context, season *storage.Season) error {
+	const op = "storage.sqlite.StartSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `INSERT INTO seasons (season_id, start_time, end_time)
+		SELECT ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM seasons)`, season.SeasonID, formatTime(season.StartTime), formatTime(season.EndTime))
+	if err != nil {
+		if isUnique(err) {
+			return storage.ErrSeasonExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	} else if n == 0 {
+		return storage.ErrSeasonExists
+	}
+	return nil
+}
+
+// GetCurrentSeason retrieves the season that is not ended
+func (s *Storage) GetCurrentSeason(ctx context.Context) (*storage.Season, error) {
+	const op = "storage.sqlite.GetCurrentSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	season, err := scanSeason(s.db.QueryRowContext(ctx, `SELECT `+seasonColumns+` FROM seasons WHERE ended_at IS NULL`))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return season, nil
+}
+
+// GetSeason retrieves a season by its ID
+func (s *Storage) GetSeason(ctx context.Context, seasonID int) (*storage.Season, error) {
+	const op = "storage.sqlite.GetSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	season, err := scanSeason(s.db.QueryRowContext(ctx, `SELECT `+seasonColumns+` FROM seasons WHERE season_id = ?`, seasonID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrSeasonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return season, nil
+}
+
+// GetSeasons retrieves all seasons, the newest first
+func (s *Storage) GetSeasons(ctx context.Context) ([]*storage.Season, error) {
+	const op = "storage.sqlite.GetSeasons"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+seasonColumns+` FROM seasons ORDER BY season_id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var seasons []*storage.Season
+	for rows.Next() {
+		season, err := scanSeason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		seasons = append(seasons, season)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return seasons, nil
+}
+
+// RolloverSeason archives the standings, resets the seasonal points and starts the next season in one transaction.
+// Ending the season comes first, it takes the write lock of the database for the rest of the transaction.
+func (s *Storage) RolloverSeason(ctx context.Context, seasonID int, endedAt time.Time, next *storage.Season) error {
+	const op = "storage.sqlite.RolloverSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE seasons SET ended_at = ? WHERE season_id = ? AND ended_at IS NULL`, formatTime(endedAt), seasonID)
+	if err != nil {
+		return fmt.Errorf("%s, endQuery: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, endQuery: %w", op, err)
+	}
+	if n == 0 {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM seasons WHERE season_id = ?)`, seasonID).Scan(&exists); err != nil {
+			return fmt.Errorf("%s, existsQuery: %w", op, err)
+		}
+		if !exists {
+			return storage.ErrSeasonNotFound
+		}
+		return storage.ErrSeasonEnded
+	}
+
+	for _, kind := range []string{storage.StandingUsers, storage.StandingClans, storage.StandingGyms} {
+		query, err := standingsQuery(kind)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		_, err = tx.ExecContext(ctx, `INSERT INTO seasonstandings (fk_season_id, kind, subject_id, name, points, rank)
+			SELECT ?, ?, subject_id, name, points, rank FROM (`+query+`)`, seasonID, kind)
+		if err != nil {
+			return fmt.Errorf("%s, archiveQuery: %w", op, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET season_points = 0 WHERE season_points <> 0`); err != nil {
+		return fmt.Errorf("%s, usersQuery: %w", op, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE clans SET season_points = 0 WHERE season_points <> 0`); err != nil {
+		return fmt.Errorf("%s, clansQuery: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO seasons (season_id, start_time, end_time) VALUES (?, ?, ?)`,
+		next.SeasonID, formatTime(next.StartTime), formatTime(next.EndTime))
+	if err != nil {
+		if isUnique(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrSeasonExists)
+		}
+		return fmt.Errorf("%s, nextQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) getStandings(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.Standing, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	standings := make([]*storage.Standing, 0)
+	for rows.Next() {
+		standing := &storage.Standing{}
+		if err := rows.Scan(&standing.FkSeasonId, &standing.Kind, &standing.SubjectID, &standing.Name, &standing.Points, &standing.Rank); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		standings = append(standings, standing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return standings, nil
+}
+
+// GetStandings retrieves the archived standings of the kind in a season, ranked
+func (s *Storage) GetStandings(ctx context.Context, seasonID int, kind string, limit int) ([]*storage.Standing, error) {
+	const op = "storage.sqlite.GetStandings"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getStandings(ctx, op, `SELECT fk_season_id, kind, subject_id, name, points, rank FROM seasonstandings
+		WHERE fk_season_id = ? AND kind = ?
+		ORDER BY rank, subject_id
+		LIMIT ?`, seasonID, kind, limit)
+}
+
+// GetLiveStandings computes the standings of the kind in the current season, ranked
+func (s *Storage) GetLiveStandings(ctx context.Context, kind string, limit int) ([]*storage.Standing, error) {
+	const op = "storage.sqlite.GetLiveStandings"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	query, err := standingsQuery(kind)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return s.getStandings(ctx, op, `SELECT 0, ?, subject_id, name, points, rank FROM (`+query+`)
+		ORDER BY rank, subject_id
+		LIMIT ?`, kind, limit)
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Active workout sessions are kept in the sessions table as JSON, the same way Redis keeps them,
+// so a SQLite deployment does not need Redis.
+
+// sessionBatch is the number of users GetSessions looks up in one query.
+const sessionBatch = 500
+
+// CreateSession initializes a new workout session for a user, replacing the previous one if any.
+func (s *Storage) CreateSession(ctx context.Context, session *storage.WorkoutSession) error {
+	const op = "storage.sqlite.CreateSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if err := s.putSession(ctx, session.UserID, session); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionCreated, UserID: session.UserID, Session: copySession(session)})
+	return nil
+}
+
+// GetSession retrieves the workout session of a user.
+func (s *Storage) GetSession(ctx context.Context, userID *string) (*storage.WorkoutSession, error) {
+	const op = "storage.sqlite.GetSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM sessions WHERE user_id = ?`, *userID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNoSession
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var session storage.WorkoutSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &session, nil
+}
+
+// UpdateSession updates a session's details (e.g., points, records) and bumps its last update time.
+func (s *Storage) UpdateSession(ctx context.Context, userID *string, updatedSession *storage.WorkoutSession) error {
+	const op = "storage.sqlite.UpdateSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	updatedSession.LastUpdated = time.Now()
+	if err := s.putSession(ctx, *userID, updatedSession); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionUpdated, UserID: *userID, Session: copySession(updatedSession)})
+	return nil
+}
+
+// DeleteSession removes a session completely.
+func (s *Storage) DeleteSession(ctx context.Context, userID *string) error {
+	const op = "storage.sqlite.DeleteSession"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, *userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.sessionEvents.Publish(&storage.SessionEvent{Type: storage.SessionDeleted, UserID: *userID})
+	return nil
+}
+
+// GetAllSessions retrieves all workout sessions.
+func (s *Storage) GetAllSessions(ctx context.Context) ([]*storage.WorkoutSession, error) {
+	const op = "storage.sqlite.GetAllSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM sessions`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sessions []*storage.WorkoutSession
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		var session storage.WorkoutSession
+		if err := json.Unmarshal([]byte(data), &session); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// GetSessions returns the sessions of the users that have one.
+func (s *Storage) GetSessions(ctx context.Context, userIDs []string) ([]*storage.WorkoutSession, error) {
+	const op = "storage.sqlite.GetSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var sessions []*storage.WorkoutSession
+	// keeps every query well below the limit of bound parameters
+	for len(userIDs) > 0 {
+		batch := userIDs[:min(len(userIDs), sessionBatch)]
+		userIDs = userIDs[len(batch):]
+
+		args := make([]interface{}, len(batch))
+		for i, userID := range batch {
+			args[i] = userID
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+		rows, err := s.db.QueryContext(ctx, `SELECT data FROM sessions WHERE user_id IN (`+placeholders+`)`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for rows.Next() {
+			var data string
+			if err := rows.Scan(&data); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			var session storage.WorkoutSession
+			if err := json.Unmarshal([]byte(data), &session); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			sessions = append(sessions, &session)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return sessions, nil
+}
+
+// CountSessions returns the number of workout sessions.
+func (s *Storage) CountSessions(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.CountSessions"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sessions`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+func (s *Storage) putSession(ctx context.Context, userID string, session *storage.WorkoutSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO sessions (user_id, data) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET data = excluded.data`, userID, string(data))
+	return err
+}
+
+// SubscribeSessions streams the changes of the user's session until the context is done.
+func (s *Storage) SubscribeSessions(ctx context.Context, userID *string) (<-chan *storage.SessionEvent, error) {
+	return s.sessionEvents.Subscribe(ctx, *userID), nil
+}
+
+// copySession keeps published sessions from changing when the caller reuses the one it saved.
+func copySession(session *storage.WorkoutSession) *storage.WorkoutSession {
+	c := *session
+	c.Records = append([]storage.Record(nil), session.Records...)
+	return &c
+}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"GYMBRO/cmd/migrate/migrations"
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage/pubsub"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"strings"
+	"time"
+)
+
+// timeLayout is fixed width and always UTC, so stored times compare correctly as strings.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// connParams enable foreign keys (off by default in SQLite), wait for locks instead of failing
+// and take the write lock at the start of a transaction, so concurrent transactions do not deadlock.
+const connParams = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+type Storage struct {
+	db *sql.DB
+	// sessionEvents are fanned out in process, a SQLite deployment is a single instance
+	sessionEvents *pubsub.Hub
+}
+
+// New opens the database file at storagePath, creating it if needed.
+func New(storagePath string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+	separator := "?"
+	if strings.Contains(storagePath, "?") {
+		separator = "&"
+	}
+	db, err := sql.Open("sqlite", storagePath+separator+connParams)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Storage{db: db, sessionEvents: pubsub.New()}, nil
+}
+
+func (s *Storage) Close() {
+	s.db.Close()
+}
+
+// Migrate applies the embedded SQLite migrations that are not applied yet.
+func (s *Storage) Migrate() error {
+	const op = "storage.sqlite.Migrate"
+	source, err := iofs.New(migrations.SQLiteFS, "sqlite")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	driver, err := migratesqlite.WithInstance(s.db, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// m.Close would close the database as well, so only the source is closed
+	defer source.Close()
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Ping checks that the database file is still usable.
+func (s *Storage) Ping(ctx context.Context) error {
+	const op = "storage.sqlite.Ping"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MigrationVersion returns the version from the golang-migrate schema_migrations table. Zero means no migrations.
+func (s *Storage) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	const op = "storage.sqlite.MigrationVersion"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var (
+		version int64
+		dirty   bool
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "no such table") {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+	return uint(version), dirty, nil
+}
+
+// isConstraint tells if err is a violation of the given constraint, e.g. sqlite3.SQLITE_CONSTRAINT_UNIQUE.
+func isConstraint(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+func isUnique(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE) || isConstraint(err, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+func isForeignKey(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+// parseTime parses times written by formatTime, empty strings are zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(timeLayout, s)
+}
@@ -0,0 +1,40 @@
+package sqlite_test
+
+import (
+	"GYMBRO/cmd/migrate/migrations"
+	"GYMBRO/internal/storage/sqlite"
+	"GYMBRO/internal/storage/storagetest"
+	"context"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func newStorage(t *testing.T) *sqlite.Storage {
+	t.Helper()
+	s, err := sqlite.New(filepath.Join(t.TempDir(), "gymbro.db"))
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+	require.NoError(t, s.Migrate())
+	return s
+}
+
+func TestMigrate(t *testing.T) {
+	s := newStorage(t)
+	// applying again is a no-op
+	require.NoError(t, s.Migrate())
+
+	version, dirty, err := s.MigrationVersion(context.Background())
+	require.NoError(t, err)
+	require.False(t, dirty)
+	latest, err := migrations.LatestSQLite()
+	require.NoError(t, err)
+	require.Equal(t, latest, version)
+}
+
+func TestContract(t *testing.T) {
+	s := newStorage(t)
+	storagetest.Run(t, func(t *testing.T) storagetest.Repositories {
+		return storagetest.Repositories{Users: s, Workouts: s, Sessions: s, SessionEvents: s, Exercises: s, Stats: s, Achievements: s, Follows: s, Events: s, Gyms: s, Challenges: s, Seasons: s, Moderation: s, Webhooks: s, JoinClan: s.JoinNewClan, AwardClan: s.AwardClan}
+	})
+}
@@ -0,0 +1,446 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const userColumns = `u.user_id, u.username, u.email, u.password_hash, u.points, u.season_points, u.date_of_birth, u.fk_clan_id, u.fk_gym_id, u.created_at, u.role, u.is_disabled, u.body_weight, u.is_private, u.is_flagged`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row scanner) (*storage.User, error) {
+	var (
+		user                   storage.User
+		dateOfBirth, createdAt string
+	)
+	err := row.Scan(&user.UserId, &user.Username, &user.Email, &user.Password, &user.Points, &user.SeasonPoints, &dateOfBirth, &user.FkClanId, &user.FkGymId, &createdAt, &user.Role, &user.IsDisabled, &user.BodyWeight, &user.IsPrivate, &user.IsFlagged)
+	if err != nil {
+		return nil, err
+	}
+	if user.DateOfBirth, err = parseTime(dateOfBirth); err != nil {
+		return nil, err
+	}
+	if user.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getUser runs a query selecting userColumns and maps no rows to storage.ErrUserNotFound.
+func (s *Storage) getUser(ctx context.Context, op string, query string, args ...interface{}) (*storage.User, error) {
+	user, err := scanUser(s.db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertUser(ctx context.Context, db execer, user *storage.User) error {
+	now := formatTime(time.Now())
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO users (user_id, username, email, password_hash, date_of_birth, fk_clan_id, fk_gym_id, last_active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.UserId, user.Username, user.Email, user.Password, formatTime(user.DateOfBirth), "0", 0, now, now)
+	if isUnique(err) {
+		return storage.ErrUserExists
+	}
+	return err
+}
+
+// RegisterNewUser registers a new user in the database and returns the user ID or an error
+func (s *Storage) RegisterNewUser(ctx context.Context, user *storage.User) (*string, error) {
+	const op = "storage.sqlite.RegisterNewUser"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if err := insertUser(ctx, s.db, user); err != nil {
+		if errors.Is(err, storage.ErrUserExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &user.UserId, nil
+}
+
+// RegisterOAuthUser registers a new user together with the provider identity they signed up with.
+func (s *Storage) RegisterOAuthUser(ctx context.Context, user *storage.User, identity *storage.Identity) (*string, error) {
+	const op = "storage.sqlite.RegisterOAuthUser"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(ctx, tx, user); err != nil {
+		if errors.Is(err, storage.ErrUserExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s, userQuery: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO useridentities (provider, subject, fk_user_id, email, created_at) VALUES (?, ?, ?, ?, ?)`,
+		identity.Provider, identity.Subject, user.UserId, identity.Email, formatTime(time.Now()))
+	if err != nil {
+		if isUnique(err) {
+			return nil, storage.ErrIdentityExists
+		}
+		return nil, fmt.Errorf("%s, identityQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &user.UserId, nil
+}
+
+// GetUserByIdentity retrieves a user by the subject (user id) they have at the OAuth provider
+func (s *Storage) GetUserByIdentity(ctx context.Context, provider *string, subject *string) (*storage.User, error) {
+	const op = "storage.sqlite.GetUserByIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUser(ctx, op, `SELECT `+userColumns+` FROM users u JOIN useridentities i ON i.fk_user_id = u.user_id
+		WHERE i.provider = ? AND i.subject = ?`, *provider, *subject)
+}
+
+// GetUserIdentities retrieves all OAuth identities linked to a user
+func (s *Storage) GetUserIdentities(ctx context.Context, userID *string) ([]*storage.Identity, error) {
+	const op = "storage.sqlite.GetUserIdentities"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT provider, subject, fk_user_id, email, created_at FROM useridentities WHERE fk_user_id = ? ORDER BY created_at`, *userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var identities []*storage.Identity
+	for rows.Next() {
+		var (
+			identity  = &storage.Identity{}
+			createdAt string
+		)
+		if err := rows.Scan(&identity.Provider, &identity.Subject, &identity.FkUserId, &identity.Email, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if identity.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return identities, nil
+}
+
+// AddUserIdentity links an OAuth identity to an existing user
+func (s *Storage) AddUserIdentity(ctx context.Context, identity *storage.Identity) error {
+	const op = "storage.sqlite.AddUserIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO useridentities (provider, subject, fk_user_id, email, created_at) VALUES (?, ?, ?, ?, ?)`,
+		identity.Provider, identity.Subject, identity.FkUserId, identity.Email, formatTime(time.Now()))
+	if err != nil {
+		if isUnique(err) {
+			return storage.ErrIdentityExists
+		}
+		if isForeignKey(err) {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteUserIdentity unlinks the identity of the given provider from a user
+func (s *Storage) DeleteUserIdentity(ctx context.Context, userID *string, provider *string) error {
+	const op = "storage.sqlite.DeleteUserIdentity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `DELETE FROM useridentities WHERE fk_user_id = ? AND provider = ?`, *userID, *provider)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// MergeUsers moves everything owned by the source user (identities, workouts, maxes, achievements, follows, events,
+// subscriptions, check-ins, points) to the target user and deletes the source user. Everything happens in one transaction.
+// It returns ErrIdentityExists if both users have an identity of the same provider.
+func (s *Storage) MergeUsers(ctx context.Context, targetID *string, sourceID *string) error {
+	const op = "storage.sqlite.MergeUsers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var (
+		sourcePoints, sourceSeasonPoints int
+		sourceFlagged                    bool
+	)
+	err = tx.QueryRowContext(ctx, `SELECT points, season_points, is_flagged FROM users WHERE user_id = ?`, *sourceID).Scan(&sourcePoints, &sourceSeasonPoints, &sourceFlagged)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s, sourceQuery: %w", op, err)
+	}
+
+	var sharedProvider bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM useridentities s JOIN useridentities t ON t.provider = s.provider
+		WHERE s.fk_user_id = ? AND t.fk_user_id = ?)`, *sourceID, *targetID).Scan(&sharedProvider)
+	if err != nil {
+		return fmt.Errorf("%s, providersQuery: %w", op, err)
+	}
+	if sharedProvider {
+		return storage.ErrIdentityExists
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE users SET points = points + ?, season_points = season_points + ?, is_flagged = is_flagged OR ? WHERE user_id = ?`,
+		sourcePoints, sourceSeasonPoints, sourceFlagged, *targetID)
+	if err != nil {
+		return fmt.Errorf("%s, pointsQuery: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	moves := []struct {
+		name  string
+		query string
+	}{
+		{"identitiesQuery", `UPDATE useridentities SET fk_user_id = ?1 WHERE fk_user_id = ?2`},
+		{"workoutsQuery", `UPDATE workouts SET fk_user_id = ?1 WHERE fk_user_id = ?2`},
+		{"maxesQuery", `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps)
+		SELECT ?1, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = ?2
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = excluded.max_weight, reps = excluded.reps
+		WHERE excluded.max_weight > userexercisemaxweights.max_weight
+		OR (excluded.max_weight = userexercisemaxweights.max_weight AND excluded.reps > userexercisemaxweights.reps)`},
+		{"achievementsQuery", `INSERT INTO achievements (fk_user_id, code, awarded_at)
+		SELECT ?1, code, awarded_at FROM achievements WHERE fk_user_id = ?2
+		ON CONFLICT (fk_user_id, code) DO UPDATE SET awarded_at = min(awarded_at, excluded.awarded_at)`},
+		{"followingQuery", `INSERT INTO follows (follower_id, followee_id, status, created_at)
+		SELECT ?1, followee_id, status, created_at FROM follows WHERE follower_id = ?2 AND followee_id <> ?1
+		ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = excluded.status WHERE excluded.status = 'accepted'`},
+		{"followersQuery", `INSERT INTO follows (follower_id, followee_id, status, created_at)
+		SELECT follower_id, ?1, status, created_at FROM follows WHERE followee_id = ?2 AND follower_id <> ?1
+		ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = excluded.status WHERE excluded.status = 'accepted'`},
+		{"eventsQuery", `UPDATE events SET fk_user_id = ?1 WHERE fk_user_id = ?2`},
+		{"subscriptionsQuery", `UPDATE subscriptions SET fk_user_id = ?1 WHERE fk_user_id = ?2`},
+		{"checkInsQuery", `UPDATE checkins SET fk_user_id = ?1 WHERE fk_user_id = ?2`},
+		{"webhookOwnersQuery", `UPDATE webhooks SET fk_owner_id = ?1 WHERE fk_owner_id = ?2`},
+		{"webhookSubjectsQuery", `UPDATE webhooks SET subject_id = ?1 WHERE kind = 'user' AND subject_id = ?2`},
+	}
+	for _, move := range moves {
+		if _, err := tx.ExecContext(ctx, move.query, *targetID, *sourceID); err != nil {
+			return fmt.Errorf("%s, %s: %w", op, move.name, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE user_id = ?`, *sourceID); err != nil {
+		return fmt.Errorf("%s, deleteQuery: %w", op, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetUserByID retrieves a user's data by their ID
+func (s *Storage) GetUserByID(ctx context.Context, id *string) (*storage.User, error) {
+	const op = "storage.sqlite.GetUserByID"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUser(ctx, op, `SELECT `+userColumns+` FROM users u WHERE u.user_id = ?`, *id)
+}
+
+// GetUserByEmail retrieves a user's data by their email
+func (s *Storage) GetUserByEmail(ctx context.Context, email *string) (*storage.User, error) {
+	const op = "storage.sqlite.GetUserByEmail"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUser(ctx, op, `SELECT `+userColumns+` FROM users u WHERE u.email = ?`, *email)
+}
+
+// ChangeStatus updates the active status and last active timestamp for a user.
+func (s *Storage) ChangeStatus(ctx context.Context, userID *string, status bool) error {
+	const op = "storage.sqlite.ChangeStatus"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET is_active = ?, last_active = ? WHERE user_id = ?`, status, formatTime(time.Now()), *userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetUserRole changes the role of a user
+func (s *Storage) SetUserRole(ctx context.Context, userID *string, role string) error {
+	const op = "storage.sqlite.SetUserRole"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateUser(ctx, op, `UPDATE users SET role = ? WHERE user_id = ?`, role, *userID)
+}
+
+// SetUserDisabled disables or enables a user account
+func (s *Storage) SetUserDisabled(ctx context.Context, userID *string, disabled bool) error {
+	const op = "storage.sqlite.SetUserDisabled"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateUser(ctx, op, `UPDATE users SET is_disabled = ? WHERE user_id = ?`, disabled, *userID)
+}
+
+// SetUserBodyWeight sets the body weight of a user
+func (s *Storage) SetUserBodyWeight(ctx context.Context, userID *string, weight int) error {
+	const op = "storage.sqlite.SetUserBodyWeight"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateUser(ctx, op, `UPDATE users SET body_weight = ? WHERE user_id = ?`, weight, *userID)
+}
+
+// SetUserPrivate sets whether following the user needs an approval
+func (s *Storage) SetUserPrivate(ctx context.Context, userID *string, private bool) error {
+	const op = "storage.sqlite.SetUserPrivate"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateUser(ctx, op, `UPDATE users SET is_private = ? WHERE user_id = ?`, private, *userID)
+}
+
+// SetUserFlagged sets whether the user logged records that failed the plausibility checks
+func (s *Storage) SetUserFlagged(ctx context.Context, userID *string, flagged bool) error {
+	const op = "storage.sqlite.SetUserFlagged"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.updateUser(ctx, op, `UPDATE users SET is_flagged = ? WHERE user_id = ?`, flagged, *userID)
+}
+
+// GetClanMembers returns the users of the clan.
+func (s *Storage) GetClanMembers(ctx context.Context, clanID *string) ([]*storage.User, error) {
+	const op = "storage.sqlite.GetClanMembers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUsers(ctx, op, `SELECT `+userColumns+` FROM users u WHERE u.fk_clan_id = ?`, *clanID)
+}
+
+// GetGymMembers returns the users whose gym is gymID.
+func (s *Storage) GetGymMembers(ctx context.Context, gymID int) ([]*storage.User, error) {
+	const op = "storage.sqlite.GetGymMembers"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	return s.getUsers(ctx, op, `SELECT `+userColumns+` FROM users u WHERE u.fk_gym_id = ?`, gymID)
+}
+
+// getUsers runs a query selecting userColumns.
+func (s *Storage) getUsers(ctx context.Context, op string, query string, args ...interface{}) ([]*storage.User, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []*storage.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// updateUser runs an update of one user and returns storage.ErrUserNotFound if nothing was updated.
+func (s *Storage) updateUser(ctx context.Context, op string, query string, args ...interface{}) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+// GetUserMax retrieves the maximum weight and reps for a specific exercise.
+func (s *Storage) GetUserMax(ctx context.Context, userID *string, exercise *int) (*storage.Max, error) {
+	const op = "storage.sqlite.GetUserMax"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var userMax storage.Max
+	row := s.db.QueryRowContext(ctx, `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = ? AND exercise_id = ?`, *userID, *exercise)
+	err := row.Scan(&userMax.UserID, &userMax.ExerciseId, &userMax.MaxWeight, &userMax.Reps)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNoMaxes
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &userMax, nil
+}
+
+// GetUserMaxes retrieves all maximum weight records for a user.
+func (s *Storage) GetUserMaxes(ctx context.Context, userID *string) ([]*storage.Max, error) {
+	const op = "storage.sqlite.GetUserMaxes"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT user_id, exercise_id, max_weight, reps FROM userexercisemaxweights WHERE user_id = ? ORDER BY exercise_id`, *userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var userMaxes []*storage.Max
+	for rows.Next() {
+		userMax := &storage.Max{}
+		if err := rows.Scan(&userMax.UserID, &userMax.ExerciseId, &userMax.MaxWeight, &userMax.Reps); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		userMaxes = append(userMaxes, userMax)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userMaxes, nil
+}
+
+// SetUserMax inserts or updates the maximum weight and reps for a user's exercise.
+func (s *Storage) SetUserMax(ctx context.Context, userID *string, max *storage.Max) error {
+	const op = "storage.sqlite.SetUserMax"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO userexercisemaxweights (user_id, exercise_id, max_weight, reps) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET max_weight = excluded.max_weight, reps = excluded.reps`, *userID, max.ExerciseId, max.MaxWeight, max.Reps)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
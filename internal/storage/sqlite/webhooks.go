@@ -0,0 +1,308 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const webhookColumns = `webhook_id, fk_owner_id, kind, subject_id, url, secret, events, created_at`
+
+const deliveryColumns = `d.delivery_id, d.fk_webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+func scanWebhook(row scanner) (*storage.Webhook, error) {
+	var (
+		webhook           storage.Webhook
+		events, createdAt string
+	)
+	err := row.Scan(&webhook.WebhookID, &webhook.OwnerID, &webhook.Kind, &webhook.SubjectID, &webhook.URL, &webhook.Secret, &events, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	// events are stored comma separated
+	webhook.Events = strings.Split(events, ",")
+	if webhook.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// scanDelivery scans deliveryColumns followed by dest.
+func scanDelivery(row scanner, dest ...interface{}) (*storage.WebhookDelivery, error) {
+	var (
+		delivery                 storage.WebhookDelivery
+		nextAttemptAt, createdAt string
+		deliveredAt              sql.NullString
+	)
+	err := row.Scan(append([]interface{}{&delivery.DeliveryID, &delivery.WebhookID, &delivery.Event, &delivery.Payload, &delivery.Status,
+		&delivery.Attempts, &nextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &createdAt, &deliveredAt}, dest...)...)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.NextAttemptAt, err = parseTime(nextAttemptAt); err != nil {
+		return nil, err
+	}
+	if delivery.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		t, err := parseTime(deliveredAt.String)
+		if err != nil {
+			return nil, err
+		}
+		delivery.DeliveredAt = &t
+	}
+	return &delivery, nil
+}
+
+// CreateWebhook stores a webhook unless the owner has max of them, there are no gyms in SQLite so any gym ID is accepted.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook *storage.Webhook, max int) error {
+	const op = "storage.sqlite.CreateWebhook"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	// writes are serialized, the count can't change between the check and the insert
+	res, err := s.db.ExecContext(ctx, `INSERT INTO webhooks (`+webhookColumns+`)
+		SELECT ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8
+		WHERE (SELECT COUNT(*) FROM webhooks WHERE fk_owner_id = ?2) < ?9`,
+		webhook.WebhookID, webhook.OwnerID, webhook.Kind, webhook.SubjectID, webhook.URL, webhook.Secret,
+		strings.Join(webhook.Events, ","), formatTime(webhook.CreatedAt), max)
+	if err != nil {
+		if isForeignKey(err) {
+			return storage.ErrUserNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if affected == 0 {
+		return storage.ErrWebhookLimit
+	}
+	return nil
+}
+
+// GetWebhooks returns the webhooks the user manages, the oldest first.
+func (s *Storage) GetWebhooks(ctx context.Context, ownerID *string) ([]*storage.Webhook, error) {
+	const op = "storage.sqlite.GetWebhooks"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE fk_owner_id = ? ORDER BY created_at, webhook_id`, *ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*storage.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the webhook of the owner, its deliveries are deleted by the cascade.
+func (s *Storage) DeleteWebhook(ctx context.Context, ownerID *string, webhookID *string) error {
+	const op = "storage.sqlite.DeleteWebhook"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE webhook_id = ? AND fk_owner_id = ?`, *webhookID, *ownerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// AddDeliveries queues a copy of the delivery for the webhooks of the user and of their gym subscribed to its event.
+func (s *Storage) AddDeliveries(ctx context.Context, userID *string, delivery *storage.WebhookDelivery) (int, error) {
+	const op = "storage.sqlite.AddDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT h.webhook_id
+		FROM webhooks h
+		JOIN users u ON u.user_id = ?
+		WHERE instr(',' || h.events || ',', ',' || ? || ',') > 0
+			AND ((h.kind = 'user' AND h.subject_id = u.user_id)
+				OR (h.kind = 'gym' AND h.subject_id = CAST(u.fk_gym_id AS TEXT) AND NOT u.is_private))`, *userID, delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("%s, webhooksQuery: %w", op, err)
+	}
+	var webhookIDs []string
+	for rows.Next() {
+		var webhookID string
+		if err := rows.Scan(&webhookID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s, webhooksQuery: %w", op, err)
+		}
+		webhookIDs = append(webhookIDs, webhookID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s, webhooksQuery: %w", op, err)
+	}
+
+	for _, webhookID := range webhookIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO webhookdeliveries (delivery_id, fk_webhook_id, event, payload, status, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, 'pending', ?, ?)`,
+			storage.GenerateUID(), webhookID, delivery.Event, delivery.Payload, formatTime(delivery.NextAttemptAt), formatTime(delivery.CreatedAt))
+		if err != nil {
+			return 0, fmt.Errorf("%s, insertQuery: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return len(webhookIDs), nil
+}
+
+// GetDueDeliveries returns up to limit pending deliveries due at now with the URL and secret of their webhook, the longest waiting first.
+func (s *Storage) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*storage.PendingDelivery, error) {
+	const op = "storage.sqlite.GetDueDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	rows, err := s.db.QueryContext(ctx, `SELECT `+deliveryColumns+`, h.url, h.secret
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.rowid
+		LIMIT ?`, formatTime(now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*storage.PendingDelivery, 0)
+	for rows.Next() {
+		var pending storage.PendingDelivery
+		delivery, err := scanDelivery(rows, &pending.URL, &pending.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		pending.WebhookDelivery = *delivery
+		deliveries = append(deliveries, &pending)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt.
+func (s *Storage) UpdateDelivery(ctx context.Context, delivery *storage.WebhookDelivery) error {
+	const op = "storage.sqlite.UpdateDelivery"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	var deliveredAt interface{}
+	if delivery.DeliveredAt != nil {
+		deliveredAt = formatTime(*delivery.DeliveredAt)
+	}
+	_, err := s.db.ExecContext(ctx, `UPDATE webhookdeliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?
+		WHERE delivery_id = ?`,
+		delivery.Status, delivery.Attempts, formatTime(delivery.NextAttemptAt), delivery.LastStatusCode, delivery.LastError, deliveredAt, delivery.DeliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries of the owner's webhooks, the newest first.
+func (s *Storage) GetDeliveries(ctx context.Context, ownerID *string, webhookID string, status string, limit int) ([]*storage.WebhookDelivery, error) {
+	const op = "storage.sqlite.GetDeliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	if webhookID != "" {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE webhook_id = ? AND fk_owner_id = ?)`, webhookID, *ownerID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("%s, webhookQuery: %w", op, err)
+		}
+		if !exists {
+			return nil, storage.ErrWebhookNotFound
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+deliveryColumns+`
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE h.fk_owner_id = ? AND (? = '' OR d.fk_webhook_id = ?) AND (? = '' OR d.status = ?)
+		ORDER BY d.created_at DESC, d.rowid DESC
+		LIMIT ?`, *ownerID, webhookID, webhookID, status, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*storage.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery queues a dead delivery of the owner's webhook again.
+func (s *Storage) RetryDelivery(ctx context.Context, ownerID *string, deliveryID *string, at time.Time) error {
+	const op = "storage.sqlite.RetryDelivery"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT d.status
+		FROM webhookdeliveries d
+		JOIN webhooks h ON h.webhook_id = d.fk_webhook_id
+		WHERE d.delivery_id = ? AND h.fk_owner_id = ?`, *deliveryID, *ownerID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%s, statusQuery: %w", op, err)
+	}
+	if status != storage.DeliveryDead {
+		return storage.ErrDeliveryNotDead
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE webhookdeliveries SET status = 'pending', attempts = 0, next_attempt_at = ?
+		WHERE delivery_id = ? AND status = 'dead'`, formatTime(at), *deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s, retryQuery: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"GYMBRO/internal/lib/tracing"
+	"GYMBRO/internal/storage"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const workoutQuery = `SELECT w.workout_id, w.fk_user_id, w.start_time, w.end_time, w.points, COALESCE(w.fk_gym_id, 0), r.record_id, r.fk_workout_id, r.fk_exercise_id, r.reps, r.weight, r.points, r.status, r.reason
+	FROM workouts w
+	LEFT JOIN records r ON w.workout_id = r.fk_workout_id`
+
+// scanWorkouts groups the rows of workoutQuery (ordered by workout) into workouts and passes them to fn one by one.
+func scanWorkouts(rows *sql.Rows, fn func(*storage.WorkoutWithRecords) error) error {
+	var current *storage.WorkoutWithRecords
+	for rows.Next() {
+		var (
+			workout            storage.WorkoutWithRecords
+			startTime, endTime string
+			recordID, fkWoID   sql.NullString
+			exercise, reps     sql.NullInt64
+			weight, points     sql.NullInt64
+			status, reason     sql.NullString
+		)
+		err := rows.Scan(&workout.WorkoutID, &workout.UserID, &startTime, &endTime, &workout.Points, &workout.GymID,
+			&recordID, &fkWoID, &exercise, &reps, &weight, &points, &status, &reason)
+		if err != nil {
+			return err
+		}
+
+		if current == nil || current.WorkoutID != workout.WorkoutID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			if workout.StartTime, err = parseTime(startTime); err != nil {
+				return err
+			}
+			if workout.EndTime, err = parseTime(endTime); err != nil {
+				return err
+			}
+			current = &workout
+		}
+
+		if recordID.Valid {
+			current.Records = append(current.Records, storage.Record{
+				RecordId:     recordID.String,
+				FkWorkoutId:  fkWoID.String,
+				FkExerciseId: int(exercise.Int64),
+				Reps:         int(reps.Int64),
+				Weight:       int(weight.Int64),
+				Points:       int(points.Int64),
+				Status:       status.String,
+				Reason:       reason.String,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+// GetWorkout retrieves a workout record by its ID.
+func (s *Storage) GetWorkout(ctx context.Context, workoutID *string) (*storage.WorkoutWithRecords, error) {
+	const op = "storage.sqlite.GetWorkout"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, workoutQuery+` WHERE w.workout_id = ? ORDER BY r.rowid`, *workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var found *storage.WorkoutWithRecords
+	err = scanWorkouts(rows, func(workout *storage.WorkoutWithRecords) error {
+		found = workout
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if found == nil {
+		return nil, storage.ErrWorkoutNotFound
+	}
+	return found, nil
+}
+
+// StreamWorkouts walks over all user's workouts started in [from, to) with their records
+// and passes them one by one to fn, so the whole history is never held in memory.
+// Iteration stops at the first error returned by fn.
+func (s *Storage) StreamWorkouts(ctx context.Context, userID *string, from time.Time, to time.Time, fn func(*storage.WorkoutWithRecords) error) error {
+	const op = "storage.sqlite.StreamWorkouts"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, workoutQuery+` WHERE w.fk_user_id = ? AND w.start_time >= ? AND w.start_time < ?
+	ORDER BY w.start_time, w.workout_id, r.rowid`, *userID, formatTime(from), formatTime(to))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	// errors of fn are returned as is, like in the other storages
+	var fnErr error
+	err = scanWorkouts(rows, func(workout *storage.WorkoutWithRecords) error {
+		fnErr = fn(workout)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SaveWorkout stores the finished session as a workout with its records and adds its points to the user.
+// Sessions without records are not saved, records without a status are saved as ok.
+func (s *Storage) SaveWorkout(ctx context.Context, workout *storage.WorkoutSession) error {
+	const op = "storage.sqlite.SaveWorkout"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(workout.Records) < 1 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET points = points + ?, season_points = season_points + ? WHERE user_id = ?`, workout.Points, workout.Points, workout.UserID); err != nil {
+		return fmt.Errorf("%s, userQuery: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO workouts (workout_id, fk_user_id, start_time, end_time, points, fk_gym_id) VALUES (?, ?, ?, ?, ?, NULLIF(?, 0))`,
+		workout.SessionID, workout.UserID, formatTime(workout.StartTime), formatTime(workout.LastUpdated), workout.Points, workout.GymID)
+	if err != nil {
+		return fmt.Errorf("%s, workoutQuery: %w", op, err)
+	}
+
+	for _, record := range workout.Records {
+		_, err := tx.ExecContext(ctx, `INSERT INTO records (record_id, fk_workout_id, fk_exercise_id, reps, weight, points, status, reason)
+			VALUES (?, ?, ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'ok'), ?)`,
+			record.RecordId, record.FkWorkoutId, record.FkExerciseId, record.Reps, record.Weight, record.Points, record.Status, record.Reason)
+		if err != nil {
+			return fmt.Errorf("%s, recordQuery: %w", op, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -1,92 +1,330 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"github.com/google/uuid"
+	"sort"
 	"time"
 )
 
 var (
-	ErrUserNotFound    = errors.New("user not found")
-	ErrUserExists      = errors.New("users already exists")
-	ErrWorkoutNotFound = errors.New("workout not found")
-	ErrNoSession       = errors.New("no session")
-	ErrNoMaxes         = errors.New("no maxes")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserExists          = errors.New("users already exists")
+	ErrWorkoutNotFound     = errors.New("workout not found")
+	ErrNoSession           = errors.New("no session")
+	ErrNoMaxes             = errors.New("no maxes")
+	ErrIdentityExists      = errors.New("identity already exists")
+	ErrIdentityNotFound    = errors.New("identity not found")
+	ErrExerciseNotFound    = errors.New("exercise not found")
+	ErrExerciseExists      = errors.New("exercise already exists")
+	ErrExerciseInUse       = errors.New("exercise in use")
+	ErrMuscleGroupNotFound = errors.New("muscle group not found")
+	ErrMuscleGroupExists   = errors.New("muscle group already exists")
+	ErrFollowExists        = errors.New("follow already exists")
+	ErrFollowNotFound      = errors.New("follow not found")
+	ErrGymNotFound         = errors.New("gym not found")
+	ErrNoSubscription      = errors.New("no active subscription")
+	ErrCheckInNotFound     = errors.New("check-in not found")
+	ErrClanNotFound        = errors.New("clan not found")
+	ErrChallengeNotFound   = errors.New("challenge not found")
+	ErrChallengeSettled    = errors.New("challenge already settled")
+	ErrSeasonNotFound      = errors.New("season not found")
+	ErrSeasonExists        = errors.New("season already exists")
+	ErrSeasonEnded         = errors.New("season already ended")
+	ErrRecordNotFound      = errors.New("record not found")
+	ErrRecordNotHeld       = errors.New("record not held")
+	ErrRecordVoided        = errors.New("record already voided")
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrWebhookLimit        = errors.New("webhook limit reached")
+	ErrDeliveryNotFound    = errors.New("delivery not found")
+	ErrDeliveryNotDead     = errors.New("delivery not dead")
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	FollowPending  = "pending"
+	FollowAccepted = "accepted"
+)
+
+const (
+	EventWorkoutFinished = "workout_finished"
+	EventPersonalRecord  = "personal_record"
+	EventAchievement     = "achievement"
+)
+
+// Statuses of records set by the plausibility checks. Held records wait for a moderator and excluded ones never count,
+// neither of them earns points or sets maxes. Voided records are taken back by a moderator with their points.
+const (
+	RecordOK       = "ok"
+	RecordHeld     = "held"
+	RecordExcluded = "excluded"
+	RecordVoided   = "voided"
+)
+
+// Events sent to webhooks. Workouts ended by the user are workout.ended, the ones the scheduler ended after inactivity
+// are session.auto_ended.
+const (
+	WebhookWorkoutEnded     = "workout.ended"
+	WebhookPRAchieved       = "pr.achieved"
+	WebhookSessionAutoEnded = "session.auto_ended"
+)
+
+// Webhooks send the events of one user or of the members of one gym.
+const (
+	WebhookUser = "user"
+	WebhookGym  = "gym"
+)
+
+// Statuses of webhook deliveries. Pending deliveries are retried until they are delivered
+// or run out of attempts and become dead.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Users who did not join a clan or pick a gym belong to the default ones.
+const (
+	DefaultClanID = "0"
+	DefaultGymID  = 0
+)
+
+// Goals of challenges: the sum of workout points, the sum of reps * weight of one exercise, the number of workouts.
+const (
+	GoalPoints   = "points"
+	GoalTonnage  = "tonnage"
+	GoalWorkouts = "workouts"
+)
+
+// Kinds of season standings.
+const (
+	StandingUsers = "users"
+	StandingClans = "clans"
+	StandingGyms  = "gyms"
+)
+
+const (
+	SessionCreated = "created"
+	SessionUpdated = "updated"
+	SessionDeleted = "deleted"
 )
 
 type WorkoutWithRecords struct {
-	UserID    string    `json:"user_id"`
-	WorkoutID string    `json:"session_id"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	Records   []Record  `json:"records"`
-	Points    int       `json:"points"`
+	UserID    string
+	WorkoutID string
+	StartTime time.Time
+	EndTime   time.Time
+	Records   []Record
+	Points    int
+	// GymID is the gym the user checked in at before the workout, 0 if they did not
+	GymID int
 }
 
 type Record struct {
 	RecordId     string `json:"record_id"`
 	FkWorkoutId  string `json:"fk_workout_id"`
-	FkExerciseId int    `json:"fk_exercise_id" validate:"required"`
-	Reps         int    `json:"reps" validate:"required,gte=1"`
-	Weight       int    `json:"weight" validate:"required,gte=1"`
-	Points       int    `json:"points"`
+	FkExerciseId int    `json:"fk_exercise_id"`
+	Reps         int    `json:"reps"`
+	Weight       int    `json:"weight"`
+	// Points of a held record are only added to the workout when it is approved
+	Points int    `json:"points"`
+	Status string `json:"status"`
+	// Reason tells why the record is held or excluded
+	Reason string `json:"reason"`
+}
+
+// Counts tells if the record earns points and can set a max.
+// Records of sessions started before the plausibility checks have no status and count.
+func (r *Record) Counts() bool {
+	return r.Status == "" || r.Status == RecordOK
+}
+
+// FlaggedRecord is a saved record with the user and workout it belongs to, as moderators review it.
+type FlaggedRecord struct {
+	Record
+	UserID    string
+	StartTime time.Time
 }
 
 type Subscription struct {
-	SubscriptionId string    `json:"subscription_id"`
-	FkUserId       string    `json:"fk_user_id"`
-	FkGymId        int       `json:"fk_gym_id"`
-	StartDate      time.Time `json:"start_date"`
-	EndDate        time.Time `json:"end_date"`
-	CreatedAt      time.Time `json:"created_at"`
+	SubscriptionId string
+	FkUserId       string
+	FkGymId        int
+	StartDate      time.Time
+	EndDate        time.Time
+	CreatedAt      time.Time
+}
+
+// CheckIn is a visit of a user to a gym, it is only made with an active subscription to the gym.
+type CheckIn struct {
+	CheckInID        string
+	FkUserId         string
+	FkGymId          int
+	FkSubscriptionId string
+	CreatedAt        time.Time
 }
 
 type Workout struct {
-	WorkoutId string    `json:"workout_id"`
-	FkUserId  string    `json:"fk_user_id"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	Points    int       `json:"points"`
+	WorkoutId string
+	FkUserId  string
+	StartTime time.Time
+	EndTime   time.Time
+	Points    int
 }
 
 type Exercise struct {
-	ExerciseId  int    `json:"exercise_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Picture     string `json:"picture"`
+	ExerciseId     int
+	Name           string
+	Description    string
+	Picture        string
+	MuscleGroupIds []int
+	// WeightLimit is the heaviest plausible weight, 0 falls back to the default limit of the config
+	WeightLimit int
+}
+
+type MuscleGroup struct {
+	MuscleGroupId int
+	Name          string
 }
 
 type User struct {
-	UserId      string    `json:"user_id"`
-	Username    string    `json:"username" validate:"required"`
-	Email       string    `json:"email" validate:"required,email"`
-	Password    string    `json:"password" validate:"required"`
-	Points      int       `json:"points"`
-	DateOfBirth time.Time `json:"date_of_birth"`
-	GoogleId    string    `json:"google_id"`
-	FkClanId    string    `json:"fk_clan_id"`
-	FkGymId     int       `json:"fk_gym_id"`
-	IsActive    bool      `json:"is_active"`
-	LastActive  time.Time `json:"last_active"`
-	CreatedAt   time.Time `json:"created_at"`
+	UserId   string
+	Username string
+	Email    string
+	Password string
+	Points   int
+	// SeasonPoints are the points of the current season, Points are never reset
+	SeasonPoints int
+	DateOfBirth  time.Time
+	FkClanId     string
+	FkGymId      int
+	IsActive     bool
+	LastActive   time.Time
+	CreatedAt    time.Time
+	Role         string
+	IsDisabled   bool
+	BodyWeight   int
+	IsPrivate    bool
+	// IsFlagged is set when the user logs a record that fails the plausibility checks
+	IsFlagged bool
+}
+
+type Identity struct {
+	Provider  string
+	Subject   string
+	FkUserId  string
+	Email     string
+	CreatedAt time.Time
 }
 
 type Gym struct {
-	GymId       int    `json:"gym_id"`
-	Name        string `json:"name"`
-	Address     string `json:"address"`
-	Description string `json:"description"`
+	GymId       int
+	Name        string
+	Address     string
+	Description string
 }
 
 type Clan struct {
-	ClanId      string    `json:"clan_id"`
-	FkOwnerId   string    `json:"fk_owner_id"`
-	Name        string    `json:"name" validate:"required"`
-	Description string    `json:"description"`
-	Points      int       `json:"points" validate:"gte=0"`
-	CreatedAt   time.Time `json:"created_at"`
+	ClanId      string
+	FkOwnerId   string
+	Name        string
+	Description string
+	Points      int
+	// SeasonPoints are the points of the current season, Points are never reset
+	SeasonPoints int
+	CreatedAt    time.Time
+}
+
+// Challenge is a goal for clans. The workouts members start in [StartTime, EndTime) count towards Target,
+// and every clan that reaches it gets Reward clan points when the challenge is settled. FkExerciseId is only
+// set for tonnage goals, only records that count add to them. SettledAt is nil until the challenge is settled.
+type Challenge struct {
+	ChallengeID  string
+	Name         string
+	Goal         string
+	FkExerciseId int
+	Target       int
+	Reward       int
+	StartTime    time.Time
+	EndTime      time.Time
+	SettledAt    *time.Time
+	CreatedAt    time.Time
+}
+
+// ChallengeResult is the progress of a clan in a challenge. Points are the clan points awarded,
+// they stay 0 until the challenge is settled.
+type ChallengeResult struct {
+	FkChallengeId string
+	FkClanId      string
+	Progress      int
+	Rank          int
+	Completed     bool
+	Points        int
+}
+
+// Season is a period of seasonal points. The next season starts when it is rolled over, EndedAt is nil until then.
+type Season struct {
+	SeasonID  int
+	StartTime time.Time
+	EndTime   time.Time
+	EndedAt   *time.Time
+}
+
+// Standing is the place of a user, clan or gym in a season. SubjectID is the user, clan or gym ID
+// and Name the username or the clan or gym name (empty in storages without gyms).
+type Standing struct {
+	FkSeasonId int
+	Kind       string
+	SubjectID  string
+	Name       string
+	Points     int
+	Rank       int
+}
+
+// Webhook sends the events it subscribes to to URL, signed with Secret. Kind is WebhookUser or WebhookGym
+// and SubjectID the user or the gym ID. OwnerID is the user who manages it.
+type Webhook struct {
+	WebhookID string
+	OwnerID   string
+	Kind      string
+	SubjectID string
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is one event queued for one webhook. Payload is the JSON body, it is signed and sent as is on every attempt.
+// LastStatusCode and LastError describe the last failed attempt, DeliveredAt is nil until it succeeds.
+type WebhookDelivery struct {
+	DeliveryID     string
+	WebhookID      string
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
 }
 
+// PendingDelivery is a delivery that is due with where to send it, as the dispatcher sends it.
+type PendingDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WorkoutSession is the active workout of a user. Sessions are stored as JSON in Redis and SQLite and sent as JSON
+// in session events, the json tags of it and of Record are that encoding: renaming them loses the sessions in progress.
 type WorkoutSession struct {
 	UserID      string    `json:"user_id"`
 	SessionID   string    `json:"session_id"`
@@ -94,39 +332,344 @@ type WorkoutSession struct {
 	LastUpdated time.Time `json:"last_updated"`
 	Records     []Record  `json:"records"`
 	Points      int       `json:"points"`
+	GymID       int       `json:"gym_id,omitempty"`
+}
+
+// SessionEvent is a change of the active workout session of a user. Session is nil when it was deleted.
+type SessionEvent struct {
+	Type    string          `json:"type"`
+	UserID  string          `json:"user_id"`
+	Session *WorkoutSession `json:"session,omitempty"`
 }
 
 type Max struct {
-	UserID     string `json:"user_id"`
-	ExerciseId int    `json:"exercise_id"`
-	MaxWeight  int    `json:"max_weight"`
-	Reps       int    `json:"reps"`
+	UserID     string
+	ExerciseId int
+	MaxWeight  int
+	Reps       int
+}
+
+// Achievement is a badge awarded to a user, Code refers to a rule of the achievements package.
+type Achievement struct {
+	UserID    string
+	Code      string
+	AwardedAt time.Time
+}
+
+// Follow is a follow request of FollowerID to FolloweeID. Following a private profile stays pending
+// until the followee accepts it.
+type Follow struct {
+	FollowerID string
+	FolloweeID string
+	Status     string
+	CreatedAt  time.Time
+}
+
+// Event is something that happened to a user and is shown in the feed of their followers.
+// Only the fields of the event type are set: Points for finished workouts, ExerciseID, Weight and Reps for
+// personal records, Code for achievements.
+type Event struct {
+	EventID    string
+	UserID     string
+	Type       string
+	WorkoutID  string
+	Points     int
+	ExerciseID int
+	Weight     int
+	Reps       int
+	Code       string
+	CreatedAt  time.Time
+}
+
+// FeedCursor points at the last event of a feed page, the next page starts after it.
+type FeedCursor struct {
+	CreatedAt time.Time
+	EventID   string
+}
+
+// MuscleGroupVolume is the training volume of one muscle group in one workout.
+// Every record is a set, tonnage is the sum of reps * weight.
+type MuscleGroupVolume struct {
+	WorkoutID       string
+	StartTime       time.Time
+	MuscleGroupId   int
+	MuscleGroupName string
+	Sets            int
+	Reps            int
+	Tonnage         int
+}
+
+// ExerciseSet is one record of an exercise with the workout it was done in.
+type ExerciseSet struct {
+	WorkoutID string
+	StartTime time.Time
+	Reps      int
+	Weight    int
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=WorkoutRepository --output=./mocks
 type WorkoutRepository interface {
-	GetWorkout(*string) (*WorkoutWithRecords, error)
-	SaveWorkout(*WorkoutSession) error
+	GetWorkout(context.Context, *string) (*WorkoutWithRecords, error)
+	SaveWorkout(context.Context, *WorkoutSession) error
+	StreamWorkouts(context.Context, *string, time.Time, time.Time, func(*WorkoutWithRecords) error) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=StatsRepository --output=./mocks
+type StatsRepository interface {
+	// GetMuscleGroupVolume returns the volume per muscle group of user's workouts started in [from, to),
+	// ordered by start time. A record counts for every muscle group its exercise targets.
+	GetMuscleGroupVolume(ctx context.Context, userID *string, from time.Time, to time.Time) ([]*MuscleGroupVolume, error)
+	// GetExerciseSets returns the records of the exercise in user's workouts started in [from, to),
+	// ordered by start time. ErrExerciseNotFound is returned if there is no such exercise.
+	GetExerciseSets(ctx context.Context, userID *string, exerciseID int, from time.Time, to time.Time) ([]*ExerciseSet, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=SessionRepository --output=./mocks
 type SessionRepository interface {
-	CreateSession(*WorkoutSession) error
-	UpdateSession(*string, *WorkoutSession) error
-	DeleteSession(*string) error
-	GetSession(*string) (*WorkoutSession, error)
-	GetAllSessions() ([]*WorkoutSession, error)
+	CreateSession(context.Context, *WorkoutSession) error
+	UpdateSession(context.Context, *string, *WorkoutSession) error
+	DeleteSession(context.Context, *string) error
+	GetSession(context.Context, *string) (*WorkoutSession, error)
+	GetAllSessions(context.Context) ([]*WorkoutSession, error)
+	// GetSessions returns the sessions of the users that have one, in no particular order.
+	GetSessions(ctx context.Context, userIDs []string) ([]*WorkoutSession, error)
+	CountSessions(context.Context) (int, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=SessionSubscriber --output=./mocks
+type SessionSubscriber interface {
+	// SubscribeSessions streams the changes of the user's session: the session storage publishes an event on
+	// every CreateSession, UpdateSession and DeleteSession made after it returns. The channel is closed when
+	// the context is done or the subscription is lost.
+	SubscribeSessions(ctx context.Context, userID *string) (<-chan *SessionEvent, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=RateLimitRepository --output=./mocks
+type RateLimitRepository interface {
+	// Allow registers a hit for the key in a sliding window and reports whether it fits into the limit.
+	// If it does not, the returned duration says when the next hit will be allowed.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=LockoutRepository --output=./mocks
+type LockoutRepository interface {
+	// GetLockout returns the time the account is locked until, or zero time if it is not locked.
+	GetLockout(context.Context, *string) (time.Time, error)
+	// RegisterFailedLogin counts a failed login within the window. When the count reaches maxAttempts the account
+	// gets locked for baseDuration doubled for every previous lock (capped by maxDuration), and the time
+	// it is locked until is returned. Otherwise, the returned time is zero.
+	RegisterFailedLogin(ctx context.Context, email *string, maxAttempts int, window, baseDuration, maxDuration time.Duration) (time.Time, error)
+	// ResetFailedLogins forgets failed logins and previous locks, it is called after successful login.
+	ResetFailedLogins(context.Context, *string) error
+	// Unlock removes the lock together with failed logins and previous locks.
+	Unlock(context.Context, *string) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Pinger --output=./mocks
+type Pinger interface {
+	// Ping checks that the storage is reachable.
+	Ping(context.Context) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=MigrationRepository --output=./mocks
+type MigrationRepository interface {
+	// MigrationVersion returns the applied migration version and whether the last migration failed halfway.
+	MigrationVersion(context.Context) (uint, bool, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=UserRepository --output=./mocks
 type UserRepository interface {
-	GetUserByID(*string) (*User, error)
-	GetUserByEmail(*string) (*User, error)
-	RegisterNewUser(*User) (*string, error)
-	ChangeStatus(*string, bool) error
-	GetUserMax(*string, *int) (*Max, error)
-	GetUserMaxes(*string) ([]*Max, error)
-	SetUserMax(*string, *Max) error
+	GetUserByID(context.Context, *string) (*User, error)
+	GetUserByEmail(context.Context, *string) (*User, error)
+	RegisterNewUser(context.Context, *User) (*string, error)
+	RegisterOAuthUser(context.Context, *User, *Identity) (*string, error)
+	GetUserByIdentity(context.Context, *string, *string) (*User, error)
+	GetUserIdentities(context.Context, *string) ([]*Identity, error)
+	AddUserIdentity(context.Context, *Identity) error
+	DeleteUserIdentity(context.Context, *string, *string) error
+	MergeUsers(context.Context, *string, *string) error
+	ChangeStatus(context.Context, *string, bool) error
+	GetUserMax(context.Context, *string, *int) (*Max, error)
+	GetUserMaxes(context.Context, *string) ([]*Max, error)
+	SetUserMax(context.Context, *string, *Max) error
+	SetUserRole(context.Context, *string, string) error
+	SetUserDisabled(context.Context, *string, bool) error
+	SetUserBodyWeight(context.Context, *string, int) error
+	SetUserPrivate(context.Context, *string, bool) error
+	SetUserFlagged(context.Context, *string, bool) error
+	// GetClanMembers returns the users of the clan.
+	GetClanMembers(ctx context.Context, clanID *string) ([]*User, error)
+	// GetGymMembers returns the users whose gym is gymID.
+	GetGymMembers(ctx context.Context, gymID int) ([]*User, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=GymRepository --output=./mocks
+type GymRepository interface {
+	// CreateSubscription stores a subscription of the user to the gym. ErrUserNotFound or ErrGymNotFound
+	// is returned if either does not exist (storages without gyms accept any gym ID).
+	CreateSubscription(context.Context, *Subscription) error
+	// GetActiveSubscription returns the subscription of the user to the gym that covers the day of at (UTC),
+	// the one ending last if there are several. ErrNoSubscription is returned if there is none.
+	GetActiveSubscription(ctx context.Context, userID *string, gymID int, at time.Time) (*Subscription, error)
+	// CreateCheckIn stores a check-in.
+	CreateCheckIn(context.Context, *CheckIn) error
+	// GetLastCheckIn returns the latest check-in of the user made at or after since.
+	// ErrCheckInNotFound is returned if there is none.
+	GetLastCheckIn(ctx context.Context, userID *string, since time.Time) (*CheckIn, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=ChallengeRepository --output=./mocks
+type ChallengeRepository interface {
+	// CreateChallenge stores a challenge. ErrExerciseNotFound is returned if the exercise of a tonnage goal does not exist.
+	CreateChallenge(context.Context, *Challenge) error
+	// GetChallenge returns the challenge or ErrChallengeNotFound.
+	GetChallenge(context.Context, *string) (*Challenge, error)
+	// GetChallenges returns the challenges that are not settled yet ordered by end time,
+	// or the settled ones, the last ended first.
+	GetChallenges(ctx context.Context, settled bool) ([]*Challenge, error)
+	// GetChallengeProgress returns the live progress of the clans with any progress in the challenge, ranked.
+	// Clans are the ones members are in now, not when they trained. The default clan is no clan, it never competes.
+	GetChallengeProgress(context.Context, *Challenge) ([]*ChallengeResult, error)
+	// SettleChallenge stores the final progress of the clans, awards the reward to the clans that reached
+	// the target and marks the challenge settled, all at once. A challenge is settled only once,
+	// ErrChallengeSettled is returned after that.
+	SettleChallenge(ctx context.Context, challengeID *string, settledAt time.Time) ([]*ChallengeResult, error)
+	// GetChallengeResults returns the stored results of a settled challenge, ranked.
+	GetChallengeResults(context.Context, *string) ([]*ChallengeResult, error)
+	// GetClanResults returns the results of the clan in settled challenges, the last ended first.
+	GetClanResults(context.Context, *string) ([]*ChallengeResult, error)
+	// GetClan returns the clan with its points or ErrClanNotFound.
+	GetClan(context.Context, *string) (*Clan, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=SeasonRepository --output=./mocks
+type SeasonRepository interface {
+	// StartSeason stores the first season, ErrSeasonExists is returned if there is one already.
+	StartSeason(context.Context, *Season) error
+	// GetCurrentSeason returns the season that is not ended, ErrSeasonNotFound is returned before the first one is started.
+	GetCurrentSeason(context.Context) (*Season, error)
+	// GetSeason returns the season or ErrSeasonNotFound.
+	GetSeason(context.Context, int) (*Season, error)
+	// GetSeasons returns all seasons, the newest first.
+	GetSeasons(context.Context) ([]*Season, error)
+	// RolloverSeason archives the standings of the season, ends it at endedAt, resets the seasonal points of users and clans
+	// and starts next, all at once. ErrSeasonEnded is returned if the season is ended already.
+	RolloverSeason(ctx context.Context, seasonID int, endedAt time.Time, next *Season) error
+	// GetStandings returns up to limit archived standings of the kind in an ended season, ranked.
+	GetStandings(ctx context.Context, seasonID int, kind string, limit int) ([]*Standing, error)
+	// GetLiveStandings returns up to limit standings of the kind in the current season, ranked. Users and clans
+	// without seasonal points, private users, the default clan and the default gym are left out, gyms have the points of their members.
+	GetLiveStandings(ctx context.Context, kind string, limit int) ([]*Standing, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=ModerationRepository --output=./mocks
+type ModerationRepository interface {
+	// GetFlaggedRecords returns up to limit saved records that are held or excluded, the oldest workout first.
+	GetFlaggedRecords(ctx context.Context, limit int) ([]*FlaggedRecord, error)
+	// ApproveRecord makes a held record count: its points are added to the workout and the user. If it beats the max
+	// of the user (heavier, or as heavy with more reps) it becomes the max and prBonus is added to its points first,
+	// the new max is returned then and nil otherwise. ErrRecordNotHeld is returned for records that are not held.
+	ApproveRecord(ctx context.Context, recordID *string, prBonus int) (*FlaggedRecord, *Max, error)
+	// VoidRecord takes a record back. Points it earned are subtracted from the workout and the user
+	// (seasonal points do not drop below 0) and the max of the exercise is rebuilt from the records that still count.
+	// ErrRecordVoided is returned for records voided before.
+	VoidRecord(ctx context.Context, recordID *string) (*FlaggedRecord, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=WebhookRepository --output=./mocks
+type WebhookRepository interface {
+	// CreateWebhook stores a webhook unless its owner already manages max webhooks, ErrWebhookLimit is returned then.
+	// ErrGymNotFound is returned for gym webhooks of gyms that do not exist (storages without gyms accept any gym ID).
+	CreateWebhook(ctx context.Context, webhook *Webhook, max int) error
+	// GetWebhooks returns the webhooks the user manages, the oldest first.
+	GetWebhooks(ctx context.Context, ownerID *string) ([]*Webhook, error)
+	// DeleteWebhook deletes the webhook with its deliveries. ErrWebhookNotFound is returned if the owner has no such webhook.
+	DeleteWebhook(ctx context.Context, ownerID *string, webhookID *string) error
+	// AddDeliveries queues a copy of the delivery for every webhook subscribed to its event that sends the events of the user:
+	// the user's webhooks and, unless the user is private, the webhooks of the user's gym. It returns the number of queued deliveries.
+	AddDeliveries(ctx context.Context, userID *string, delivery *WebhookDelivery) (int, error)
+	// GetDueDeliveries returns up to limit pending deliveries whose next attempt is at or before now, the longest waiting first.
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*PendingDelivery, error)
+	// UpdateDelivery stores the outcome of an attempt: the status, attempts, next attempt, last failure and delivery time.
+	UpdateDelivery(context.Context, *WebhookDelivery) error
+	// GetDeliveries returns up to limit deliveries of the webhooks the user manages, the newest first. An empty webhookID
+	// takes all of them and an empty status any status. ErrWebhookNotFound is returned if the owner has no such webhook.
+	GetDeliveries(ctx context.Context, ownerID *string, webhookID string, status string, limit int) ([]*WebhookDelivery, error)
+	// RetryDelivery queues a dead delivery again with no attempts, its next attempt is at. ErrDeliveryNotFound is returned
+	// if the owner has no such delivery and ErrDeliveryNotDead if it is not dead.
+	RetryDelivery(ctx context.Context, ownerID *string, deliveryID *string, at time.Time) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=Locker --output=./mocks
+type Locker interface {
+	// TryLock takes the named lock if nobody holds it, so a background job runs on one replica at a time.
+	// ok is false if the lock is held, otherwise unlock has to be called when the job is done.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=FollowRepository --output=./mocks
+type FollowRepository interface {
+	// Follow stores a follow request, it returns ErrFollowExists if the follower already follows or asked to follow.
+	Follow(context.Context, *Follow) error
+	// Unfollow deletes the follow of follower to followee, pending or not.
+	Unfollow(ctx context.Context, followerID *string, followeeID *string) error
+	// AcceptFollow accepts a pending follow request.
+	AcceptFollow(ctx context.Context, followerID *string, followeeID *string) error
+	// GetFollowers returns follows to the user, newest first.
+	GetFollowers(context.Context, *string) ([]*Follow, error)
+	// GetFollowing returns follows of the user, newest first.
+	GetFollowing(context.Context, *string) ([]*Follow, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=EventRepository --output=./mocks
+type EventRepository interface {
+	AddEvents(context.Context, []*Event) error
+	// GetFeed returns up to limit events of the users the user follows (accepted follows only), newest first.
+	// A nil cursor starts with the newest event.
+	GetFeed(ctx context.Context, userID *string, cursor *FeedCursor, limit int) ([]*Event, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=AchievementRepository --output=./mocks
+type AchievementRepository interface {
+	// AwardAchievement stores the achievement and reports whether it is new. Awarding an achievement
+	// the user already has is not an error and keeps the first award time.
+	AwardAchievement(context.Context, *Achievement) (bool, error)
+	// GetAchievements returns user's achievements ordered by award time.
+	GetAchievements(context.Context, *string) ([]*Achievement, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.43.2 --name=ExerciseRepository --output=./mocks
+type ExerciseRepository interface {
+	GetExercises(context.Context) ([]*Exercise, error)
+	// GetExercise returns the exercise or ErrExerciseNotFound.
+	GetExercise(context.Context, int) (*Exercise, error)
+	CreateExercise(context.Context, *Exercise) (*int, error)
+	UpdateExercise(context.Context, *Exercise) error
+	// DeleteExercise deletes an exercise nobody used. ErrExerciseInUse is returned if records, maxes or challenges reference it,
+	// so deleting an exercise never takes the history of users with it.
+	DeleteExercise(context.Context, *int) error
+	GetMuscleGroups(context.Context) ([]*MuscleGroup, error)
+	CreateMuscleGroup(context.Context, *MuscleGroup) (*int, error)
+	UpdateMuscleGroup(context.Context, *MuscleGroup) error
+	DeleteMuscleGroup(context.Context, *int) error
+}
+
+// RankResults orders the results by progress, the clan ID breaking ties, ranks them (equal progress is an equal rank)
+// and marks the clans that reached the target of the challenge.
+func RankResults(challenge *Challenge, results []*ChallengeResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Progress != results[j].Progress {
+			return results[i].Progress > results[j].Progress
+		}
+		return results[i].FkClanId < results[j].FkClanId
+	})
+	for i, result := range results {
+		result.Rank = i + 1
+		if i > 0 && result.Progress == results[i-1].Progress {
+			result.Rank = results[i-1].Rank
+		}
+		result.Completed = result.Progress >= challenge.Target
+	}
 }
 
 func GenerateUID() string {
@@ -0,0 +1,1782 @@
+// Package storagetest is the contract every storage implementation has to pass, so handlers
+// behave the same on Postgres, SQLite and in memory. It only uses the repository interfaces
+// and unique names and IDs, so it can also run against a shared database that already has data.
+package storagetest
+
+import (
+	"GYMBRO/internal/storage"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Repositories are the implementations under test. The suites of nil repositories are skipped.
+// Workouts need Users and Exercises as well, records refer to both. Stats and challenges are checked on saved workouts,
+// so they need all three, so do seasons and moderation. Achievements, follows, events, gym subscriptions and webhooks belong to users. SessionEvents
+// are checked on the changes made through Sessions, so they have to come from the same storage.
+// JoinClan puts a user in a new clan and AwardClan adds points to a clan the way a settled challenge does, the repositories
+// have no way to create clans and the default clan never earns points. The clan standings are skipped without them.
+type Repositories struct {
+	Users         storage.UserRepository
+	Workouts      storage.WorkoutRepository
+	Sessions      storage.SessionRepository
+	SessionEvents storage.SessionSubscriber
+	Exercises     storage.ExerciseRepository
+	Stats         storage.StatsRepository
+	Achievements  storage.AchievementRepository
+	Follows       storage.FollowRepository
+	Events        storage.EventRepository
+	Gyms          storage.GymRepository
+	Challenges    storage.ChallengeRepository
+	Seasons       storage.SeasonRepository
+	Moderation    storage.ModerationRepository
+	Webhooks      storage.WebhookRepository
+	Locker        storage.Locker
+	JoinClan      func(ctx context.Context, userID string) (string, error)
+	AwardClan     func(ctx context.Context, clanID string, points int) error
+}
+
+// Run runs the contract suites. newRepos is called for every test, it can return fresh
+// or shared repositories.
+func Run(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	t.Run("Users", func(t *testing.T) {
+		testUsers(t, newRepos)
+	})
+	t.Run("Workouts", func(t *testing.T) {
+		testWorkouts(t, newRepos)
+	})
+	t.Run("Sessions", func(t *testing.T) {
+		testSessions(t, newRepos)
+	})
+	t.Run("Exercises", func(t *testing.T) {
+		testExercises(t, newRepos)
+	})
+	t.Run("Stats", func(t *testing.T) {
+		testStats(t, newRepos)
+	})
+	t.Run("Achievements", func(t *testing.T) {
+		testAchievements(t, newRepos)
+	})
+	t.Run("Follows", func(t *testing.T) {
+		testFollows(t, newRepos)
+	})
+	t.Run("Gyms", func(t *testing.T) {
+		testGyms(t, newRepos)
+	})
+	t.Run("Challenges", func(t *testing.T) {
+		testChallenges(t, newRepos)
+	})
+	t.Run("Seasons", func(t *testing.T) {
+		testSeasons(t, newRepos)
+	})
+	t.Run("Moderation", func(t *testing.T) {
+		testModeration(t, newRepos)
+	})
+	t.Run("Webhooks", func(t *testing.T) {
+		testWebhooks(t, newRepos)
+	})
+	t.Run("Locker", func(t *testing.T) {
+		testLocker(t, newRepos)
+	})
+}
+
+func testUsers(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if newRepos(t).Users == nil {
+		t.Skip("no UserRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("RegisterAndGet", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := NewUser()
+
+		id, err := users.RegisterNewUser(ctx, user)
+		require.NoError(t, err)
+		require.Equal(t, user.UserId, *id)
+
+		byID, err := users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, user.Username, byID.Username)
+		require.Equal(t, user.Email, byID.Email)
+		require.Equal(t, user.Password, byID.Password)
+		require.Equal(t, storage.RoleUser, byID.Role)
+		require.False(t, byID.IsDisabled)
+
+		byEmail, err := users.GetUserByEmail(ctx, &user.Email)
+		require.NoError(t, err)
+		require.Equal(t, user.UserId, byEmail.UserId)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		users := newRepos(t).Users
+		missing := storage.GenerateUID()
+
+		_, err := users.GetUserByID(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+		_, err = users.GetUserByEmail(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+		_, err = users.GetUserByIdentity(ctx, &missing, &missing)
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+		require.ErrorIs(t, users.SetUserRole(ctx, &missing, storage.RoleAdmin), storage.ErrUserNotFound)
+		require.ErrorIs(t, users.SetUserDisabled(ctx, &missing, true), storage.ErrUserNotFound)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := NewUser()
+		_, err := users.RegisterNewUser(ctx, user)
+		require.NoError(t, err)
+
+		sameEmail := NewUser()
+		sameEmail.Email = user.Email
+		_, err = users.RegisterNewUser(ctx, sameEmail)
+		require.ErrorIs(t, err, storage.ErrUserExists)
+
+		sameUsername := NewUser()
+		sameUsername.Username = user.Username
+		_, err = users.RegisterNewUser(ctx, sameUsername)
+		require.ErrorIs(t, err, storage.ErrUserExists)
+	})
+
+	t.Run("RoleAndDisabled", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := RegisterUser(t, users)
+
+		require.NoError(t, users.SetUserRole(ctx, &user.UserId, storage.RoleModerator))
+		require.NoError(t, users.SetUserDisabled(ctx, &user.UserId, true))
+		require.NoError(t, users.ChangeStatus(ctx, &user.UserId, true))
+
+		got, err := users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, storage.RoleModerator, got.Role)
+		require.True(t, got.IsDisabled)
+	})
+
+	t.Run("Identities", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := NewUser()
+		google := &storage.Identity{Provider: "google", Subject: storage.GenerateUID(), Email: user.Email}
+		_, err := users.RegisterOAuthUser(ctx, user, google)
+		require.NoError(t, err)
+
+		got, err := users.GetUserByIdentity(ctx, &google.Provider, &google.Subject)
+		require.NoError(t, err)
+		require.Equal(t, user.UserId, got.UserId)
+
+		github := &storage.Identity{Provider: "github", Subject: storage.GenerateUID(), FkUserId: user.UserId}
+		require.NoError(t, users.AddUserIdentity(ctx, github))
+
+		other := RegisterUser(t, users)
+		taken := &storage.Identity{Provider: github.Provider, Subject: github.Subject, FkUserId: other.UserId}
+		require.ErrorIs(t, users.AddUserIdentity(ctx, taken), storage.ErrIdentityExists)
+		_, err = users.RegisterOAuthUser(ctx, NewUser(), google)
+		require.ErrorIs(t, err, storage.ErrIdentityExists)
+		secondGoogle := &storage.Identity{Provider: google.Provider, Subject: storage.GenerateUID(), FkUserId: user.UserId}
+		require.ErrorIs(t, users.AddUserIdentity(ctx, secondGoogle), storage.ErrIdentityExists, "one account got two identities of a provider")
+
+		identities, err := users.GetUserIdentities(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Len(t, identities, 2)
+		require.Equal(t, google.Subject, identities[0].Subject)
+		require.Equal(t, user.Email, identities[0].Email)
+		require.Equal(t, user.UserId, identities[1].FkUserId)
+
+		require.NoError(t, users.DeleteUserIdentity(ctx, &user.UserId, &google.Provider))
+		require.ErrorIs(t, users.DeleteUserIdentity(ctx, &user.UserId, &google.Provider), storage.ErrIdentityNotFound)
+		_, err = users.GetUserByIdentity(ctx, &google.Provider, &google.Subject)
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+	})
+
+	t.Run("BodyWeight", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := RegisterUser(t, users)
+
+		got, err := users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Zero(t, got.BodyWeight)
+
+		require.NoError(t, users.SetUserBodyWeight(ctx, &user.UserId, 82))
+		got, err = users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, 82, got.BodyWeight)
+
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, users.SetUserBodyWeight(ctx, &missing, 82), storage.ErrUserNotFound)
+	})
+
+	t.Run("Private", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := RegisterUser(t, users)
+
+		require.NoError(t, users.SetUserPrivate(ctx, &user.UserId, true))
+		got, err := users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.True(t, got.IsPrivate)
+
+		require.NoError(t, users.SetUserPrivate(ctx, &user.UserId, false))
+		got, err = users.GetUserByEmail(ctx, &user.Email)
+		require.NoError(t, err)
+		require.False(t, got.IsPrivate)
+
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, users.SetUserPrivate(ctx, &missing, true), storage.ErrUserNotFound)
+	})
+
+	t.Run("Flagged", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := RegisterUser(t, users)
+
+		require.NoError(t, users.SetUserFlagged(ctx, &user.UserId, true))
+		got, err := users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.True(t, got.IsFlagged)
+
+		require.NoError(t, users.SetUserFlagged(ctx, &user.UserId, false))
+		got, err = users.GetUserByEmail(ctx, &user.Email)
+		require.NoError(t, err)
+		require.False(t, got.IsFlagged)
+
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, users.SetUserFlagged(ctx, &missing, true), storage.ErrUserNotFound)
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		users := newRepos(t).Users
+		user := RegisterUser(t, users)
+
+		// new users are in the default clan and gym, which may have other members in a shared database
+		clan := storage.DefaultClanID
+		members, err := users.GetClanMembers(ctx, &clan)
+		require.NoError(t, err)
+		require.True(t, hasUser(members, user.UserId), "GetClanMembers does not return the user")
+
+		members, err = users.GetGymMembers(ctx, storage.DefaultGymID)
+		require.NoError(t, err)
+		require.True(t, hasUser(members, user.UserId), "GetGymMembers does not return the user")
+
+		missing := storage.GenerateUID()
+		members, err = users.GetClanMembers(ctx, &missing)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+
+	t.Run("Maxes", func(t *testing.T) {
+		repos := newRepos(t)
+		if repos.Exercises == nil {
+			t.Skip("maxes refer to exercises, no ExerciseRepository")
+		}
+		users := repos.Users
+		user := RegisterUser(t, users)
+		first, second := CreateExercise(t, repos.Exercises), CreateExercise(t, repos.Exercises)
+
+		_, err := users.GetUserMax(ctx, &user.UserId, &first)
+		require.ErrorIs(t, err, storage.ErrNoMaxes)
+		maxes, err := users.GetUserMaxes(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Empty(t, maxes)
+
+		require.NoError(t, users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: second, MaxWeight: 100, Reps: 5}))
+		require.NoError(t, users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: first, MaxWeight: 80, Reps: 5}))
+		require.NoError(t, users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: first, MaxWeight: 90, Reps: 3}))
+
+		max, err := users.GetUserMax(ctx, &user.UserId, &first)
+		require.NoError(t, err)
+		require.Equal(t, storage.Max{UserID: user.UserId, ExerciseId: first, MaxWeight: 90, Reps: 3}, *max)
+
+		maxes, err = users.GetUserMaxes(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*storage.Max{
+			{UserID: user.UserId, ExerciseId: first, MaxWeight: 90, Reps: 3},
+			{UserID: user.UserId, ExerciseId: second, MaxWeight: 100, Reps: 5},
+		}, maxes)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		repos := newRepos(t)
+		if repos.Exercises == nil || repos.Workouts == nil {
+			t.Skip("merge moves maxes and workouts, no ExerciseRepository or WorkoutRepository")
+		}
+		users := repos.Users
+		target, source := RegisterUser(t, users), NewUser()
+		identity := &storage.Identity{Provider: "google", Subject: storage.GenerateUID()}
+		_, err := users.RegisterOAuthUser(ctx, source, identity)
+		require.NoError(t, err)
+
+		heavier, lighter := CreateExercise(t, repos.Exercises), CreateExercise(t, repos.Exercises)
+		require.NoError(t, users.SetUserMax(ctx, &target.UserId, &storage.Max{ExerciseId: heavier, MaxWeight: 100, Reps: 5}))
+		require.NoError(t, users.SetUserMax(ctx, &source.UserId, &storage.Max{ExerciseId: heavier, MaxWeight: 120, Reps: 1}))
+		require.NoError(t, users.SetUserMax(ctx, &target.UserId, &storage.Max{ExerciseId: lighter, MaxWeight: 50, Reps: 5}))
+		require.NoError(t, users.SetUserMax(ctx, &source.UserId, &storage.Max{ExerciseId: lighter, MaxWeight: 40, Reps: 10}))
+		workout := NewSession(source.UserId, time.Now().Add(-time.Hour), heavier)
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, workout))
+		require.NoError(t, users.SetUserFlagged(ctx, &source.UserId, true))
+
+		require.NoError(t, users.MergeUsers(ctx, &target.UserId, &source.UserId))
+
+		_, err = users.GetUserByID(ctx, &source.UserId)
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+		got, err := users.GetUserByIdentity(ctx, &identity.Provider, &identity.Subject)
+		require.NoError(t, err)
+		require.Equal(t, target.UserId, got.UserId)
+		require.True(t, got.IsFlagged, "the flag of the source user is lost")
+
+		max, err := users.GetUserMax(ctx, &target.UserId, &heavier)
+		require.NoError(t, err)
+		require.Equal(t, 120, max.MaxWeight)
+		max, err = users.GetUserMax(ctx, &target.UserId, &lighter)
+		require.NoError(t, err)
+		require.Equal(t, 50, max.MaxWeight)
+
+		saved, err := repos.Workouts.GetWorkout(ctx, &workout.SessionID)
+		require.NoError(t, err)
+		require.Equal(t, target.UserId, saved.UserID)
+
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, users.MergeUsers(ctx, &target.UserId, &missing), storage.ErrUserNotFound)
+	})
+
+	t.Run("MergeSameProvider", func(t *testing.T) {
+		users := newRepos(t).Users
+		target, source := NewUser(), NewUser()
+		targetIdentity := &storage.Identity{Provider: "google", Subject: storage.GenerateUID()}
+		sourceIdentity := &storage.Identity{Provider: "google", Subject: storage.GenerateUID()}
+		_, err := users.RegisterOAuthUser(ctx, target, targetIdentity)
+		require.NoError(t, err)
+		_, err = users.RegisterOAuthUser(ctx, source, sourceIdentity)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, users.MergeUsers(ctx, &target.UserId, &source.UserId), storage.ErrIdentityExists)
+
+		got, err := users.GetUserByIdentity(ctx, &sourceIdentity.Provider, &sourceIdentity.Subject)
+		require.NoError(t, err)
+		require.Equal(t, source.UserId, got.UserId, "the refused merge moved the identity")
+		identities, err := users.GetUserIdentities(ctx, &target.UserId)
+		require.NoError(t, err)
+		require.Len(t, identities, 1)
+	})
+}
+
+func testAchievements(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Achievements == nil || repos.Users == nil {
+		t.Skip("no AchievementRepository or UserRepository")
+	}
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("AwardAndGet", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+
+		achievements, err := repos.Achievements.GetAchievements(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Empty(t, achievements)
+
+		awarded, err := repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: user.UserId, Code: "workouts_10", AwardedAt: now})
+		require.NoError(t, err)
+		require.True(t, awarded)
+		awarded, err = repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: user.UserId, Code: "first_workout", AwardedAt: now.Add(-time.Hour)})
+		require.NoError(t, err)
+		require.True(t, awarded)
+		// awarding again keeps the first award
+		awarded, err = repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: user.UserId, Code: "workouts_10", AwardedAt: now.Add(time.Hour)})
+		require.NoError(t, err)
+		require.False(t, awarded)
+
+		achievements, err = repos.Achievements.GetAchievements(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Len(t, achievements, 2)
+		require.Equal(t, "first_workout", achievements[0].Code)
+		require.True(t, now.Add(-time.Hour).Equal(achievements[0].AwardedAt))
+		require.Equal(t, "workouts_10", achievements[1].Code)
+		require.True(t, now.Equal(achievements[1].AwardedAt))
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		repos := newRepos(t)
+		target, source := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		_, err := repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: target.UserId, Code: "first_workout", AwardedAt: now})
+		require.NoError(t, err)
+		_, err = repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: source.UserId, Code: "first_workout", AwardedAt: now.Add(-time.Hour)})
+		require.NoError(t, err)
+		_, err = repos.Achievements.AwardAchievement(ctx, &storage.Achievement{UserID: source.UserId, Code: "workouts_10", AwardedAt: now})
+		require.NoError(t, err)
+
+		require.NoError(t, repos.Users.MergeUsers(ctx, &target.UserId, &source.UserId))
+
+		achievements, err := repos.Achievements.GetAchievements(ctx, &target.UserId)
+		require.NoError(t, err)
+		require.Len(t, achievements, 2)
+		require.Equal(t, "first_workout", achievements[0].Code)
+		require.True(t, now.Add(-time.Hour).Equal(achievements[0].AwardedAt))
+		require.Equal(t, "workouts_10", achievements[1].Code)
+	})
+}
+
+func testWorkouts(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Workouts == nil || repos.Users == nil || repos.Exercises == nil {
+		t.Skip("no WorkoutRepository, UserRepository or ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		exercise := CreateExercise(t, repos.Exercises)
+		session := NewSession(user.UserId, time.Now().Add(-time.Hour), exercise, exercise)
+
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+
+		workout, err := repos.Workouts.GetWorkout(ctx, &session.SessionID)
+		require.NoError(t, err)
+		require.Equal(t, session.SessionID, workout.WorkoutID)
+		require.Equal(t, user.UserId, workout.UserID)
+		require.Equal(t, session.Points, workout.Points)
+		require.True(t, session.StartTime.Equal(workout.StartTime), "start time %s, expected %s", workout.StartTime, session.StartTime)
+		require.True(t, session.LastUpdated.Equal(workout.EndTime), "end time %s, expected %s", workout.EndTime, session.LastUpdated)
+		require.ElementsMatch(t, session.Records, workout.Records)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		missing := storage.GenerateUID()
+		_, err := newRepos(t).Workouts.GetWorkout(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrWorkoutNotFound)
+	})
+
+	t.Run("EmptySessionIsNotSaved", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		session := NewSession(user.UserId, time.Now())
+
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+
+		_, err := repos.Workouts.GetWorkout(ctx, &session.SessionID)
+		require.ErrorIs(t, err, storage.ErrWorkoutNotFound)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		repos := newRepos(t)
+		user, other := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		exercise := CreateExercise(t, repos.Exercises)
+		day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		late := NewSession(user.UserId, day.Add(2*time.Hour), exercise)
+		early := NewSession(user.UserId, day, exercise, exercise)
+		before := NewSession(user.UserId, day.Add(-48*time.Hour), exercise)
+		foreign := NewSession(other.UserId, day, exercise)
+		for _, session := range []*storage.WorkoutSession{late, early, before, foreign} {
+			require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		}
+
+		var streamed []*storage.WorkoutWithRecords
+		err := repos.Workouts.StreamWorkouts(ctx, &user.UserId, day.Add(-time.Hour), day.Add(24*time.Hour), func(workout *storage.WorkoutWithRecords) error {
+			streamed = append(streamed, workout)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, streamed, 2)
+		require.Equal(t, early.SessionID, streamed[0].WorkoutID)
+		require.Len(t, streamed[0].Records, 2)
+		require.Equal(t, late.SessionID, streamed[1].WorkoutID)
+
+		stop := errors.New("stop")
+		calls := 0
+		err = repos.Workouts.StreamWorkouts(ctx, &user.UserId, time.Time{}, day.Add(24*time.Hour), func(*storage.WorkoutWithRecords) error {
+			calls++
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func testSessions(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if newRepos(t).Sessions == nil {
+		t.Skip("no SessionRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		sessions := newRepos(t).Sessions
+		userID := storage.GenerateUID()
+
+		_, err := sessions.GetSession(ctx, &userID)
+		require.ErrorIs(t, err, storage.ErrNoSession)
+
+		before, err := sessions.CountSessions(ctx)
+		require.NoError(t, err)
+
+		session := NewSession(userID, time.Now())
+		require.NoError(t, sessions.CreateSession(ctx, session))
+
+		got, err := sessions.GetSession(ctx, &userID)
+		require.NoError(t, err)
+		require.Equal(t, session.SessionID, got.SessionID)
+		require.Empty(t, got.Records)
+
+		count, err := sessions.CountSessions(ctx)
+		require.NoError(t, err)
+		require.Equal(t, before+1, count)
+
+		updatedBefore := time.Now()
+		got.Records = append(got.Records, storage.Record{RecordId: storage.GenerateUID(), FkWorkoutId: got.SessionID, FkExerciseId: 1, Reps: 5, Weight: 100, Points: 10})
+		got.Points = 10
+		require.NoError(t, sessions.UpdateSession(ctx, &userID, got))
+
+		updated, err := sessions.GetSession(ctx, &userID)
+		require.NoError(t, err)
+		require.Equal(t, got.Records, updated.Records)
+		require.Equal(t, 10, updated.Points)
+		require.False(t, updated.LastUpdated.Before(updatedBefore), "UpdateSession has to bump LastUpdated")
+
+		all, err := sessions.GetAllSessions(ctx)
+		require.NoError(t, err)
+		found := false
+		for _, s := range all {
+			found = found || s.SessionID == session.SessionID
+		}
+		require.True(t, found, "GetAllSessions does not return the session")
+
+		require.NoError(t, sessions.DeleteSession(ctx, &userID))
+		_, err = sessions.GetSession(ctx, &userID)
+		require.ErrorIs(t, err, storage.ErrNoSession)
+		require.NoError(t, sessions.DeleteSession(ctx, &userID), "deleting a missing session is not an error")
+	})
+
+	t.Run("GetSessions", func(t *testing.T) {
+		sessions := newRepos(t).Sessions
+		first, second, without := storage.GenerateUID(), storage.GenerateUID(), storage.GenerateUID()
+		require.NoError(t, sessions.CreateSession(ctx, NewSession(first, time.Now())))
+		require.NoError(t, sessions.CreateSession(ctx, NewSession(second, time.Now())))
+		t.Cleanup(func() {
+			_ = sessions.DeleteSession(ctx, &first)
+			_ = sessions.DeleteSession(ctx, &second)
+		})
+
+		got, err := sessions.GetSessions(ctx, []string{first, without, second})
+		require.NoError(t, err)
+		userIDs := make([]string, 0, len(got))
+		for _, session := range got {
+			userIDs = append(userIDs, session.UserID)
+		}
+		require.ElementsMatch(t, []string{first, second}, userIDs)
+
+		got, err = sessions.GetSessions(ctx, nil)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		repos := newRepos(t)
+		if repos.SessionEvents == nil {
+			t.Skip("no SessionSubscriber")
+		}
+		userID := storage.GenerateUID()
+		otherID := storage.GenerateUID()
+
+		subCtx, cancel := context.WithCancel(ctx)
+		events, err := repos.SessionEvents.SubscribeSessions(subCtx, &userID)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.Sessions.CreateSession(ctx, NewSession(otherID, time.Now())), "changes of other users are not streamed")
+		t.Cleanup(func() { repos.Sessions.DeleteSession(ctx, &otherID) })
+
+		session := NewSession(userID, time.Now())
+		require.NoError(t, repos.Sessions.CreateSession(ctx, session))
+		session.Points = 10
+		require.NoError(t, repos.Sessions.UpdateSession(ctx, &userID, session))
+		require.NoError(t, repos.Sessions.DeleteSession(ctx, &userID))
+
+		next := func() *storage.SessionEvent {
+			select {
+			case event, ok := <-events:
+				require.True(t, ok, "the channel is closed before the context is done")
+				return event
+			case <-time.After(5 * time.Second):
+				t.Fatal("no session event")
+				return nil
+			}
+		}
+
+		created := next()
+		require.Equal(t, storage.SessionCreated, created.Type)
+		require.Equal(t, userID, created.UserID)
+		require.Equal(t, session.SessionID, created.Session.SessionID)
+
+		updated := next()
+		require.Equal(t, storage.SessionUpdated, updated.Type)
+		require.Equal(t, 10, updated.Session.Points)
+
+		deleted := next()
+		require.Equal(t, storage.SessionDeleted, deleted.Type)
+		require.Nil(t, deleted.Session)
+
+		cancel()
+		for range events {
+		}
+	})
+}
+
+func testExercises(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if newRepos(t).Exercises == nil {
+		t.Skip("no ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("Exercises", func(t *testing.T) {
+		exercises := newRepos(t).Exercises
+		chest, err := exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+		arms, err := exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+
+		exercise := &storage.Exercise{Name: uniqueName("exercise"), Description: "Push", MuscleGroupIds: []int{*chest}, WeightLimit: 300}
+		id, err := exercises.CreateExercise(ctx, exercise)
+		require.NoError(t, err)
+		exercise.ExerciseId = *id
+
+		_, err = exercises.CreateExercise(ctx, &storage.Exercise{Name: exercise.Name})
+		require.ErrorIs(t, err, storage.ErrExerciseExists)
+		_, err = exercises.CreateExercise(ctx, &storage.Exercise{Name: uniqueName("exercise"), MuscleGroupIds: []int{-1}})
+		require.ErrorIs(t, err, storage.ErrMuscleGroupNotFound)
+
+		exercise.Description = "Push harder"
+		exercise.MuscleGroupIds = []int{*arms, *chest}
+		require.NoError(t, exercises.UpdateExercise(ctx, exercise))
+		require.ErrorIs(t, exercises.UpdateExercise(ctx, &storage.Exercise{ExerciseId: -1, Name: uniqueName("exercise")}), storage.ErrExerciseNotFound)
+
+		got := findExercise(t, exercises, exercise.ExerciseId)
+		require.NotNil(t, got)
+		require.Equal(t, "Push harder", got.Description)
+		require.Equal(t, 300, got.WeightLimit)
+		require.ElementsMatch(t, []int{*chest, *arms}, got.MuscleGroupIds)
+
+		got, err = exercises.GetExercise(ctx, exercise.ExerciseId)
+		require.NoError(t, err)
+		require.Equal(t, exercise.Name, got.Name)
+		require.Equal(t, 300, got.WeightLimit)
+		require.ElementsMatch(t, []int{*chest, *arms}, got.MuscleGroupIds)
+		_, err = exercises.GetExercise(ctx, -1)
+		require.ErrorIs(t, err, storage.ErrExerciseNotFound)
+
+		require.NoError(t, exercises.DeleteMuscleGroup(ctx, arms))
+		require.Equal(t, []int{*chest}, findExercise(t, exercises, exercise.ExerciseId).MuscleGroupIds)
+
+		require.NoError(t, exercises.DeleteExercise(ctx, &exercise.ExerciseId))
+		require.ErrorIs(t, exercises.DeleteExercise(ctx, &exercise.ExerciseId), storage.ErrExerciseNotFound)
+		require.Nil(t, findExercise(t, exercises, exercise.ExerciseId))
+	})
+
+	t.Run("DeleteInUse", func(t *testing.T) {
+		repos := newRepos(t)
+		if repos.Users == nil || repos.Workouts == nil {
+			t.Skip("no UserRepository or WorkoutRepository")
+		}
+		user := RegisterUser(t, repos.Users)
+		logged, maxed := CreateExercise(t, repos.Exercises), CreateExercise(t, repos.Exercises)
+		workout := NewSession(user.UserId, time.Now().Add(-time.Hour), logged)
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, workout))
+		require.NoError(t, repos.Users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: maxed, MaxWeight: 100, Reps: 1}))
+
+		require.ErrorIs(t, repos.Exercises.DeleteExercise(ctx, &logged), storage.ErrExerciseInUse)
+		require.ErrorIs(t, repos.Exercises.DeleteExercise(ctx, &maxed), storage.ErrExerciseInUse)
+
+		saved, err := repos.Workouts.GetWorkout(ctx, &workout.SessionID)
+		require.NoError(t, err)
+		require.Len(t, saved.Records, len(workout.Records), "the refused delete took records with it")
+		require.NotNil(t, findExercise(t, repos.Exercises, logged))
+	})
+
+	t.Run("MuscleGroups", func(t *testing.T) {
+		exercises := newRepos(t).Exercises
+		name := uniqueName("group")
+		id, err := exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: name})
+		require.NoError(t, err)
+
+		_, err = exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: name})
+		require.ErrorIs(t, err, storage.ErrMuscleGroupExists)
+
+		other, err := exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+		require.ErrorIs(t, exercises.UpdateMuscleGroup(ctx, &storage.MuscleGroup{MuscleGroupId: *other, Name: name}), storage.ErrMuscleGroupExists)
+
+		renamed := uniqueName("group")
+		require.NoError(t, exercises.UpdateMuscleGroup(ctx, &storage.MuscleGroup{MuscleGroupId: *id, Name: renamed}))
+		require.ErrorIs(t, exercises.UpdateMuscleGroup(ctx, &storage.MuscleGroup{MuscleGroupId: -1, Name: uniqueName("group")}), storage.ErrMuscleGroupNotFound)
+
+		groups, err := exercises.GetMuscleGroups(ctx)
+		require.NoError(t, err)
+		require.Contains(t, groups, &storage.MuscleGroup{MuscleGroupId: *id, Name: renamed})
+
+		require.NoError(t, exercises.DeleteMuscleGroup(ctx, id))
+		require.ErrorIs(t, exercises.DeleteMuscleGroup(ctx, id), storage.ErrMuscleGroupNotFound)
+	})
+}
+
+func testStats(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Stats == nil || repos.Workouts == nil || repos.Users == nil || repos.Exercises == nil {
+		t.Skip("no StatsRepository, WorkoutRepository, UserRepository or ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("MuscleGroupVolume", func(t *testing.T) {
+		repos := newRepos(t)
+		user, other := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		chest, err := repos.Exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+		arms, err := repos.Exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+		bench, err := repos.Exercises.CreateExercise(ctx, &storage.Exercise{Name: uniqueName("exercise"), MuscleGroupIds: []int{*chest, *arms}})
+		require.NoError(t, err)
+		curl, err := repos.Exercises.CreateExercise(ctx, &storage.Exercise{Name: uniqueName("exercise"), MuscleGroupIds: []int{*arms}})
+		require.NoError(t, err)
+		untargeted := CreateExercise(t, repos.Exercises)
+		day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		// reps are 5, 6, 7 and weight is 100: bench hits both groups, curl only arms
+		first := NewSession(user.UserId, day, *bench, *curl, untargeted)
+		second := NewSession(user.UserId, day.Add(24*time.Hour), *curl)
+		outside := NewSession(user.UserId, day.Add(-48*time.Hour), *bench)
+		foreign := NewSession(other.UserId, day, *bench)
+		for _, session := range []*storage.WorkoutSession{first, second, outside, foreign} {
+			require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		}
+
+		volumes, err := repos.Stats.GetMuscleGroupVolume(ctx, &user.UserId, day.Add(-time.Hour), day.Add(48*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, volumes, 3)
+		for _, volume := range volumes {
+			volume.StartTime = volume.StartTime.UTC()
+		}
+		expected := []*storage.MuscleGroupVolume{
+			{WorkoutID: first.SessionID, StartTime: first.StartTime, MuscleGroupId: *chest, Sets: 1, Reps: 5, Tonnage: 500},
+			{WorkoutID: first.SessionID, StartTime: first.StartTime, MuscleGroupId: *arms, Sets: 2, Reps: 11, Tonnage: 1100},
+			{WorkoutID: second.SessionID, StartTime: second.StartTime, MuscleGroupId: *arms, Sets: 1, Reps: 5, Tonnage: 500},
+		}
+		if *arms < *chest {
+			expected[0], expected[1] = expected[1], expected[0]
+		}
+		for i := range expected {
+			require.NotEmpty(t, volumes[i].MuscleGroupName)
+			expected[i].MuscleGroupName = volumes[i].MuscleGroupName
+			require.Equal(t, expected[i], volumes[i])
+		}
+
+		missing := storage.GenerateUID()
+		volumes, err = repos.Stats.GetMuscleGroupVolume(ctx, &missing, time.Time{}, day)
+		require.NoError(t, err)
+		require.Empty(t, volumes)
+	})
+
+	t.Run("ExerciseSets", func(t *testing.T) {
+		repos := newRepos(t)
+		user, other := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		squat, bench := CreateExercise(t, repos.Exercises), CreateExercise(t, repos.Exercises)
+		day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		// reps are 5, 6, 7 in the order of the exercises
+		later := NewSession(user.UserId, day.Add(24*time.Hour), squat)
+		earlier := NewSession(user.UserId, day, squat, bench, squat)
+		outside := NewSession(user.UserId, day.Add(-48*time.Hour), squat)
+		foreign := NewSession(other.UserId, day, squat)
+		for _, session := range []*storage.WorkoutSession{later, earlier, outside, foreign} {
+			require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		}
+
+		sets, err := repos.Stats.GetExerciseSets(ctx, &user.UserId, squat, day.Add(-time.Hour), day.Add(48*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, sets, 3)
+		for _, set := range sets {
+			set.StartTime = set.StartTime.UTC()
+		}
+		require.ElementsMatch(t, []*storage.ExerciseSet{
+			{WorkoutID: earlier.SessionID, StartTime: earlier.StartTime, Reps: 5, Weight: 100},
+			{WorkoutID: earlier.SessionID, StartTime: earlier.StartTime, Reps: 7, Weight: 100},
+		}, sets[:2])
+		require.Equal(t, &storage.ExerciseSet{WorkoutID: later.SessionID, StartTime: later.StartTime, Reps: 5, Weight: 100}, sets[2])
+
+		sets, err = repos.Stats.GetExerciseSets(ctx, &other.UserId, bench, time.Time{}, day.Add(48*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, sets)
+
+		_, err = repos.Stats.GetExerciseSets(ctx, &user.UserId, -1, time.Time{}, day)
+		require.ErrorIs(t, err, storage.ErrExerciseNotFound)
+	})
+
+	t.Run("CountingRecordsOnly", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		group, err := repos.Exercises.CreateMuscleGroup(ctx, &storage.MuscleGroup{Name: uniqueName("group")})
+		require.NoError(t, err)
+		squat, err := repos.Exercises.CreateExercise(ctx, &storage.Exercise{Name: uniqueName("exercise"), MuscleGroupIds: []int{*group}})
+		require.NoError(t, err)
+		day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+		// reps are 5, 6, 7: the held and the voided set are left out of volume and progress
+		session := NewSession(user.UserId, day, *squat, *squat, *squat)
+		session.Records[1].Status = storage.RecordHeld
+		session.Records[2].Status = storage.RecordVoided
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+
+		volumes, err := repos.Stats.GetMuscleGroupVolume(ctx, &user.UserId, day.Add(-time.Hour), day.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, volumes, 1)
+		require.Equal(t, 1, volumes[0].Sets)
+		require.Equal(t, 5, volumes[0].Reps)
+		require.Equal(t, 500, volumes[0].Tonnage)
+
+		sets, err := repos.Stats.GetExerciseSets(ctx, &user.UserId, *squat, day.Add(-time.Hour), day.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, sets, 1)
+		require.Equal(t, 5, sets[0].Reps)
+	})
+}
+
+func testFollows(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Follows == nil || repos.Events == nil || repos.Users == nil {
+		t.Skip("no FollowRepository, EventRepository or UserRepository")
+	}
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("FollowAndAccept", func(t *testing.T) {
+		repos := newRepos(t)
+		follower, public, private := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: follower.UserId, FolloweeID: public.UserId, Status: storage.FollowAccepted, CreatedAt: now.Add(-time.Minute)}))
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: follower.UserId, FolloweeID: private.UserId, Status: storage.FollowPending, CreatedAt: now}))
+		err := repos.Follows.Follow(ctx, &storage.Follow{FollowerID: follower.UserId, FolloweeID: public.UserId, Status: storage.FollowAccepted, CreatedAt: now})
+		require.ErrorIs(t, err, storage.ErrFollowExists)
+		missing := storage.GenerateUID()
+		err = repos.Follows.Follow(ctx, &storage.Follow{FollowerID: follower.UserId, FolloweeID: missing, Status: storage.FollowAccepted, CreatedAt: now})
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+		following, err := repos.Follows.GetFollowing(ctx, &follower.UserId)
+		require.NoError(t, err)
+		require.Len(t, following, 2)
+		require.Equal(t, private.UserId, following[0].FolloweeID)
+		require.Equal(t, storage.FollowPending, following[0].Status)
+		require.True(t, now.Equal(following[0].CreatedAt))
+		require.Equal(t, public.UserId, following[1].FolloweeID)
+		require.Equal(t, storage.FollowAccepted, following[1].Status)
+
+		followers, err := repos.Follows.GetFollowers(ctx, &private.UserId)
+		require.NoError(t, err)
+		require.Len(t, followers, 1)
+		require.Equal(t, follower.UserId, followers[0].FollowerID)
+
+		require.NoError(t, repos.Follows.AcceptFollow(ctx, &follower.UserId, &private.UserId))
+		require.ErrorIs(t, repos.Follows.AcceptFollow(ctx, &follower.UserId, &private.UserId), storage.ErrFollowNotFound)
+		followers, err = repos.Follows.GetFollowers(ctx, &private.UserId)
+		require.NoError(t, err)
+		require.Equal(t, storage.FollowAccepted, followers[0].Status)
+
+		require.NoError(t, repos.Follows.Unfollow(ctx, &follower.UserId, &public.UserId))
+		require.ErrorIs(t, repos.Follows.Unfollow(ctx, &follower.UserId, &public.UserId), storage.ErrFollowNotFound)
+		following, err = repos.Follows.GetFollowing(ctx, &follower.UserId)
+		require.NoError(t, err)
+		require.Len(t, following, 1)
+	})
+
+	t.Run("Feed", func(t *testing.T) {
+		repos := newRepos(t)
+		reader, followed, pending, stranger := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users), RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: reader.UserId, FolloweeID: followed.UserId, Status: storage.FollowAccepted, CreatedAt: now}))
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: reader.UserId, FolloweeID: pending.UserId, Status: storage.FollowPending, CreatedAt: now}))
+
+		workout := storage.GenerateUID()
+		// the first two events have the same time, the event ID decides their order
+		first, second := storage.GenerateUID(), storage.GenerateUID()
+		if first < second {
+			first, second = second, first
+		}
+		events := []*storage.Event{
+			{EventID: first, UserID: followed.UserId, Type: storage.EventWorkoutFinished, WorkoutID: workout, Points: 120, CreatedAt: now},
+			{EventID: second, UserID: followed.UserId, Type: storage.EventPersonalRecord, WorkoutID: workout, ExerciseID: 1, Weight: 100, Reps: 5, CreatedAt: now},
+			{EventID: storage.GenerateUID(), UserID: followed.UserId, Type: storage.EventAchievement, WorkoutID: workout, Code: "first_workout", CreatedAt: now.Add(-time.Hour)},
+			{EventID: storage.GenerateUID(), UserID: pending.UserId, Type: storage.EventWorkoutFinished, CreatedAt: now},
+			{EventID: storage.GenerateUID(), UserID: stranger.UserId, Type: storage.EventWorkoutFinished, CreatedAt: now},
+			{EventID: storage.GenerateUID(), UserID: reader.UserId, Type: storage.EventWorkoutFinished, CreatedAt: now},
+		}
+		require.NoError(t, repos.Events.AddEvents(ctx, events))
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, repos.Events.AddEvents(ctx, []*storage.Event{{EventID: storage.GenerateUID(), UserID: missing, Type: storage.EventWorkoutFinished, CreatedAt: now}}), storage.ErrUserNotFound)
+
+		page, err := repos.Events.GetFeed(ctx, &reader.UserId, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		require.Equal(t, *events[0], *page[0])
+		require.Equal(t, *events[1], *page[1])
+
+		page, err = repos.Events.GetFeed(ctx, &reader.UserId, &storage.FeedCursor{CreatedAt: page[1].CreatedAt, EventID: page[1].EventID}, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, *events[2], *page[0])
+
+		page, err = repos.Events.GetFeed(ctx, &reader.UserId, &storage.FeedCursor{CreatedAt: page[0].CreatedAt, EventID: page[0].EventID}, 2)
+		require.NoError(t, err)
+		require.Empty(t, page)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		repos := newRepos(t)
+		target, source, friend, fan := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users), RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: target.UserId, FolloweeID: friend.UserId, Status: storage.FollowPending, CreatedAt: now}))
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: source.UserId, FolloweeID: friend.UserId, Status: storage.FollowAccepted, CreatedAt: now}))
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: fan.UserId, FolloweeID: source.UserId, Status: storage.FollowAccepted, CreatedAt: now}))
+		require.NoError(t, repos.Follows.Follow(ctx, &storage.Follow{FollowerID: source.UserId, FolloweeID: target.UserId, Status: storage.FollowAccepted, CreatedAt: now}))
+		event := &storage.Event{EventID: storage.GenerateUID(), UserID: source.UserId, Type: storage.EventWorkoutFinished, CreatedAt: now}
+		require.NoError(t, repos.Events.AddEvents(ctx, []*storage.Event{event}))
+
+		require.NoError(t, repos.Users.MergeUsers(ctx, &target.UserId, &source.UserId))
+
+		following, err := repos.Follows.GetFollowing(ctx, &target.UserId)
+		require.NoError(t, err)
+		require.Len(t, following, 1)
+		require.Equal(t, friend.UserId, following[0].FolloweeID)
+		require.Equal(t, storage.FollowAccepted, following[0].Status)
+
+		followers, err := repos.Follows.GetFollowers(ctx, &target.UserId)
+		require.NoError(t, err)
+		require.Len(t, followers, 1)
+		require.Equal(t, fan.UserId, followers[0].FollowerID)
+
+		feed, err := repos.Events.GetFeed(ctx, &fan.UserId, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, feed, 1)
+		require.Equal(t, target.UserId, feed[0].UserID)
+	})
+}
+
+// testGyms uses the default gym, it is the only one a fresh Postgres database has.
+func testGyms(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Gyms == nil || repos.Users == nil {
+		t.Skip("no GymRepository or UserRepository")
+	}
+	ctx := context.Background()
+	gym := storage.DefaultGymID
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	t.Run("Subscriptions", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+
+		_, err := repos.Gyms.GetActiveSubscription(ctx, &user.UserId, gym, time.Now())
+		require.ErrorIs(t, err, storage.ErrNoSubscription)
+
+		expired := &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym,
+			StartDate: today.AddDate(0, -2, 0), EndDate: today.AddDate(0, 0, -1), CreatedAt: time.Now()}
+		short := &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym,
+			StartDate: today.AddDate(0, 0, -1), EndDate: today, CreatedAt: time.Now()}
+		long := &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym,
+			StartDate: today, EndDate: today.AddDate(1, 0, 0), CreatedAt: time.Now()}
+		for _, subscription := range []*storage.Subscription{expired, short, long} {
+			require.NoError(t, repos.Gyms.CreateSubscription(ctx, subscription))
+		}
+
+		// the last day of a subscription still counts, the one ending last wins
+		active, err := repos.Gyms.GetActiveSubscription(ctx, &user.UserId, gym, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, long.SubscriptionId, active.SubscriptionId)
+		require.True(t, long.EndDate.Equal(active.EndDate.UTC()), "EndDate %s is not %s", active.EndDate, long.EndDate)
+
+		active, err = repos.Gyms.GetActiveSubscription(ctx, &user.UserId, gym, today.AddDate(0, 0, -1))
+		require.NoError(t, err)
+		require.Equal(t, short.SubscriptionId, active.SubscriptionId)
+
+		_, err = repos.Gyms.GetActiveSubscription(ctx, &user.UserId, gym, today.AddDate(1, 0, 1))
+		require.ErrorIs(t, err, storage.ErrNoSubscription)
+
+		missing := storage.GenerateUID()
+		err = repos.Gyms.CreateSubscription(ctx, &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: missing, FkGymId: gym,
+			StartDate: today, EndDate: today, CreatedAt: time.Now()})
+		require.ErrorIs(t, err, storage.ErrUserNotFound)
+	})
+
+	t.Run("CheckIns", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		subscription := &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym,
+			StartDate: today, EndDate: today, CreatedAt: time.Now()}
+		require.NoError(t, repos.Gyms.CreateSubscription(ctx, subscription))
+
+		now := time.Now().UTC().Truncate(time.Second)
+		_, err := repos.Gyms.GetLastCheckIn(ctx, &user.UserId, now.Add(-time.Hour))
+		require.ErrorIs(t, err, storage.ErrCheckInNotFound)
+
+		earlier := &storage.CheckIn{CheckInID: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym, FkSubscriptionId: subscription.SubscriptionId, CreatedAt: now.Add(-2 * time.Hour)}
+		later := &storage.CheckIn{CheckInID: storage.GenerateUID(), FkUserId: user.UserId, FkGymId: gym, FkSubscriptionId: subscription.SubscriptionId, CreatedAt: now.Add(-time.Minute)}
+		require.NoError(t, repos.Gyms.CreateCheckIn(ctx, earlier))
+		require.NoError(t, repos.Gyms.CreateCheckIn(ctx, later))
+
+		last, err := repos.Gyms.GetLastCheckIn(ctx, &user.UserId, now.Add(-3*time.Hour))
+		require.NoError(t, err)
+		last.CreatedAt = last.CreatedAt.UTC()
+		require.Equal(t, later, last)
+
+		_, err = repos.Gyms.GetLastCheckIn(ctx, &user.UserId, now)
+		require.ErrorIs(t, err, storage.ErrCheckInNotFound)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		repos := newRepos(t)
+		target, source := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		subscription := &storage.Subscription{SubscriptionId: storage.GenerateUID(), FkUserId: source.UserId, FkGymId: gym,
+			StartDate: today, EndDate: today, CreatedAt: time.Now()}
+		require.NoError(t, repos.Gyms.CreateSubscription(ctx, subscription))
+		checkIn := &storage.CheckIn{CheckInID: storage.GenerateUID(), FkUserId: source.UserId, FkGymId: gym, FkSubscriptionId: subscription.SubscriptionId, CreatedAt: time.Now()}
+		require.NoError(t, repos.Gyms.CreateCheckIn(ctx, checkIn))
+
+		require.NoError(t, repos.Users.MergeUsers(ctx, &target.UserId, &source.UserId))
+
+		active, err := repos.Gyms.GetActiveSubscription(ctx, &target.UserId, gym, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, subscription.SubscriptionId, active.SubscriptionId)
+		last, err := repos.Gyms.GetLastCheckIn(ctx, &target.UserId, time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, checkIn.CheckInID, last.CheckInID)
+	})
+}
+
+func testChallenges(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Challenges == nil || repos.Workouts == nil || repos.Users == nil || repos.Exercises == nil {
+		t.Skip("no ChallengeRepository, WorkoutRepository, UserRepository or ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repos := newRepos(t)
+		start := uniqueWindow()
+		challenge := &storage.Challenge{ChallengeID: storage.GenerateUID(), Name: uniqueName("challenge"), Goal: storage.GoalTonnage,
+			FkExerciseId: CreateExercise(t, repos.Exercises), Target: 1000, Reward: 50,
+			StartTime: start, EndTime: start.Add(2 * time.Hour), CreatedAt: time.Now().UTC().Truncate(time.Second)}
+		require.NoError(t, repos.Challenges.CreateChallenge(ctx, challenge))
+
+		got, err := repos.Challenges.GetChallenge(ctx, &challenge.ChallengeID)
+		require.NoError(t, err)
+		got.StartTime, got.EndTime, got.CreatedAt = got.StartTime.UTC(), got.EndTime.UTC(), got.CreatedAt.UTC()
+		require.Equal(t, challenge, got)
+
+		open, err := repos.Challenges.GetChallenges(ctx, false)
+		require.NoError(t, err)
+		require.True(t, hasChallenge(open, challenge.ChallengeID), "GetChallenges does not return the open challenge")
+		settled, err := repos.Challenges.GetChallenges(ctx, true)
+		require.NoError(t, err)
+		require.False(t, hasChallenge(settled, challenge.ChallengeID), "GetChallenges returns the open challenge as settled")
+
+		missing := storage.GenerateUID()
+		_, err = repos.Challenges.GetChallenge(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrChallengeNotFound)
+
+		err = repos.Challenges.CreateChallenge(ctx, &storage.Challenge{ChallengeID: storage.GenerateUID(), Name: uniqueName("challenge"),
+			Goal: storage.GoalTonnage, FkExerciseId: -1, Target: 1, StartTime: start, EndTime: start.Add(time.Hour), CreatedAt: time.Now()})
+		require.ErrorIs(t, err, storage.ErrExerciseNotFound)
+	})
+
+	t.Run("ProgressAndSettle", func(t *testing.T) {
+		repos := newRepos(t)
+		if repos.JoinClan == nil {
+			t.Skip("no JoinClan")
+		}
+		user := RegisterUser(t, repos.Users)
+		clan, err := repos.JoinClan(ctx, user.UserId)
+		require.NoError(t, err)
+		squat, bench := CreateExercise(t, repos.Exercises), CreateExercise(t, repos.Exercises)
+		start := uniqueWindow()
+
+		// reps are 5, 6 in the order of the exercises and weight is 100, every record is 10 points
+		first := NewSession(user.UserId, start, squat)
+		second := NewSession(user.UserId, start.Add(time.Hour), squat, bench)
+		outside := NewSession(user.UserId, start.Add(2*time.Hour), squat)
+		for _, session := range []*storage.WorkoutSession{first, second, outside} {
+			require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		}
+
+		newChallenge := func(goal string, target int) *storage.Challenge {
+			challenge := &storage.Challenge{ChallengeID: storage.GenerateUID(), Name: uniqueName("challenge"), Goal: goal, Target: target, Reward: 50,
+				StartTime: start, EndTime: start.Add(2 * time.Hour), CreatedAt: time.Now()}
+			if goal == storage.GoalTonnage {
+				challenge.FkExerciseId = squat
+			}
+			require.NoError(t, repos.Challenges.CreateChallenge(ctx, challenge))
+			return challenge
+		}
+		tonnage := newChallenge(storage.GoalTonnage, 1000)
+		points := newChallenge(storage.GoalPoints, 30)
+		workouts := newChallenge(storage.GoalWorkouts, 3)
+
+		for _, tt := range []struct {
+			challenge *storage.Challenge
+			expected  *storage.ChallengeResult
+		}{
+			{tonnage, &storage.ChallengeResult{FkChallengeId: tonnage.ChallengeID, FkClanId: clan, Progress: 1000, Rank: 1, Completed: true}},
+			{points, &storage.ChallengeResult{FkChallengeId: points.ChallengeID, FkClanId: clan, Progress: 30, Rank: 1, Completed: true}},
+			{workouts, &storage.ChallengeResult{FkChallengeId: workouts.ChallengeID, FkClanId: clan, Progress: 2, Rank: 1}},
+		} {
+			progress, err := repos.Challenges.GetChallengeProgress(ctx, tt.challenge)
+			require.NoError(t, err)
+			require.Equal(t, []*storage.ChallengeResult{tt.expected}, progress, tt.challenge.Goal)
+		}
+
+		before, err := repos.Challenges.GetClan(ctx, &clan)
+		require.NoError(t, err)
+
+		results, err := repos.Challenges.SettleChallenge(ctx, &tonnage.ChallengeID, time.Now())
+		require.NoError(t, err)
+		expected := []*storage.ChallengeResult{{FkChallengeId: tonnage.ChallengeID, FkClanId: clan, Progress: 1000, Rank: 1, Completed: true, Points: 50}}
+		require.Equal(t, expected, results)
+		stored, err := repos.Challenges.GetChallengeResults(ctx, &tonnage.ChallengeID)
+		require.NoError(t, err)
+		require.Equal(t, expected, stored)
+
+		// a clan that did not reach the target keeps its result without points
+		results, err = repos.Challenges.SettleChallenge(ctx, &workouts.ChallengeID, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, []*storage.ChallengeResult{{FkChallengeId: workouts.ChallengeID, FkClanId: clan, Progress: 2, Rank: 1}}, results)
+
+		after, err := repos.Challenges.GetClan(ctx, &clan)
+		require.NoError(t, err)
+		require.Equal(t, before.Points+50, after.Points)
+
+		_, err = repos.Challenges.SettleChallenge(ctx, &tonnage.ChallengeID, time.Now())
+		require.ErrorIs(t, err, storage.ErrChallengeSettled)
+		missing := storage.GenerateUID()
+		_, err = repos.Challenges.SettleChallenge(ctx, &missing, time.Now())
+		require.ErrorIs(t, err, storage.ErrChallengeNotFound)
+
+		settled, err := repos.Challenges.GetChallenge(ctx, &tonnage.ChallengeID)
+		require.NoError(t, err)
+		require.NotNil(t, settled.SettledAt)
+		all, err := repos.Challenges.GetChallenges(ctx, true)
+		require.NoError(t, err)
+		require.True(t, hasChallenge(all, tonnage.ChallengeID), "GetChallenges does not return the settled challenge")
+		all, err = repos.Challenges.GetChallenges(ctx, false)
+		require.NoError(t, err)
+		require.False(t, hasChallenge(all, tonnage.ChallengeID), "GetChallenges returns the settled challenge as open")
+		require.True(t, hasChallenge(all, points.ChallengeID), "GetChallenges does not return the open challenge")
+
+		history, err := repos.Challenges.GetClanResults(ctx, &clan)
+		require.NoError(t, err)
+		require.Contains(t, history, expected[0])
+
+		_, err = repos.Challenges.GetClan(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrClanNotFound)
+	})
+
+	t.Run("DefaultClan", func(t *testing.T) {
+		repos := newRepos(t)
+		// users in the default clan are not in a clan, they never compete
+		user := RegisterUser(t, repos.Users)
+		start := uniqueWindow()
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, NewSession(user.UserId, start, CreateExercise(t, repos.Exercises))))
+		challenge := &storage.Challenge{ChallengeID: storage.GenerateUID(), Name: uniqueName("challenge"), Goal: storage.GoalWorkouts, Target: 1,
+			Reward: 50, StartTime: start, EndTime: start.Add(time.Hour), CreatedAt: time.Now()}
+		require.NoError(t, repos.Challenges.CreateChallenge(ctx, challenge))
+
+		progress, err := repos.Challenges.GetChallengeProgress(ctx, challenge)
+		require.NoError(t, err)
+		require.Empty(t, progress)
+
+		clan := storage.DefaultClanID
+		before, err := repos.Challenges.GetClan(ctx, &clan)
+		require.NoError(t, err)
+		results, err := repos.Challenges.SettleChallenge(ctx, &challenge.ChallengeID, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, results)
+		stored, err := repos.Challenges.GetChallengeResults(ctx, &challenge.ChallengeID)
+		require.NoError(t, err)
+		require.Empty(t, stored)
+		after, err := repos.Challenges.GetClan(ctx, &clan)
+		require.NoError(t, err)
+		require.Equal(t, before.Points, after.Points)
+	})
+}
+
+func testSeasons(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Seasons == nil || repos.Workouts == nil || repos.Users == nil || repos.Exercises == nil {
+		t.Skip("no SeasonRepository, WorkoutRepository, UserRepository or ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("Rollover", func(t *testing.T) {
+		repos := newRepos(t)
+		// a shared database may already be in a season
+		current, err := repos.Seasons.GetCurrentSeason(ctx)
+		if errors.Is(err, storage.ErrSeasonNotFound) {
+			start := time.Now().UTC().Truncate(time.Second)
+			current = &storage.Season{SeasonID: 1, StartTime: start, EndTime: start.Add(time.Hour)}
+			require.NoError(t, repos.Seasons.StartSeason(ctx, current))
+		} else {
+			require.NoError(t, err)
+		}
+		require.ErrorIs(t, repos.Seasons.StartSeason(ctx, &storage.Season{SeasonID: current.SeasonID + 100, StartTime: current.StartTime,
+			EndTime: current.EndTime}), storage.ErrSeasonExists)
+
+		user, private := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		require.NoError(t, repos.Users.SetUserPrivate(ctx, &private.UserId, true))
+		exercise := CreateExercise(t, repos.Exercises)
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, NewSession(user.UserId, time.Now(), exercise, exercise)))
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, NewSession(private.UserId, time.Now(), exercise)))
+
+		got, err := repos.Users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, 20, got.Points)
+		require.Equal(t, 20, got.SeasonPoints)
+
+		live, err := repos.Seasons.GetLiveStandings(ctx, storage.StandingUsers, 1000)
+		require.NoError(t, err)
+		entry := findStanding(live, user.UserId)
+		require.NotNil(t, entry, "GetLiveStandings does not return the user")
+		require.Equal(t, user.Username, entry.Name)
+		require.Equal(t, 20, entry.Points)
+		require.Nil(t, findStanding(live, private.UserId), "GetLiveStandings returns the private user")
+		for i := 1; i < len(live); i++ {
+			require.GreaterOrEqual(t, live[i-1].Points, live[i].Points, "GetLiveStandings is not ranked")
+		}
+		limited, err := repos.Seasons.GetLiveStandings(ctx, storage.StandingUsers, 1)
+		require.NoError(t, err)
+		require.Len(t, limited, 1)
+		_, err = repos.Seasons.GetLiveStandings(ctx, storage.StandingGyms, 10)
+		require.NoError(t, err)
+
+		// the default clan is no clan, it is never ranked even with points
+		var clan string
+		if repos.JoinClan != nil && repos.AwardClan != nil {
+			clan, err = repos.JoinClan(ctx, user.UserId)
+			require.NoError(t, err)
+			require.NoError(t, repos.AwardClan(ctx, clan, 50))
+			require.NoError(t, repos.AwardClan(ctx, storage.DefaultClanID, 50))
+			clans, err := repos.Seasons.GetLiveStandings(ctx, storage.StandingClans, 1000)
+			require.NoError(t, err)
+			require.NotNil(t, findStanding(clans, clan), "GetLiveStandings does not return the clan")
+			require.Nil(t, findStanding(clans, storage.DefaultClanID), "GetLiveStandings returns the default clan")
+		}
+
+		next := &storage.Season{SeasonID: current.SeasonID + 1, StartTime: current.EndTime, EndTime: current.EndTime.Add(time.Hour)}
+		require.NoError(t, repos.Seasons.RolloverSeason(ctx, current.SeasonID, time.Now(), next))
+		require.ErrorIs(t, repos.Seasons.RolloverSeason(ctx, current.SeasonID, time.Now(), next), storage.ErrSeasonEnded)
+		require.ErrorIs(t, repos.Seasons.RolloverSeason(ctx, -1, time.Now(), next), storage.ErrSeasonNotFound)
+
+		got, err = repos.Users.GetUserByID(ctx, &user.UserId)
+		require.NoError(t, err)
+		require.Equal(t, 20, got.Points, "lifetime points are reset")
+		require.Zero(t, got.SeasonPoints)
+
+		archived, err := repos.Seasons.GetStandings(ctx, current.SeasonID, storage.StandingUsers, 1000)
+		require.NoError(t, err)
+		entry = findStanding(archived, user.UserId)
+		require.NotNil(t, entry, "GetStandings does not return the user")
+		require.Equal(t, &storage.Standing{FkSeasonId: current.SeasonID, Kind: storage.StandingUsers, SubjectID: user.UserId,
+			Name: user.Username, Points: 20, Rank: entry.Rank}, entry)
+		require.Nil(t, findStanding(archived, private.UserId), "GetStandings returns the private user")
+		if clan != "" {
+			clans, err := repos.Seasons.GetStandings(ctx, current.SeasonID, storage.StandingClans, 1000)
+			require.NoError(t, err)
+			require.NotNil(t, findStanding(clans, clan), "GetStandings does not return the clan")
+			require.Nil(t, findStanding(clans, storage.DefaultClanID), "GetStandings returns the default clan")
+		}
+		live, err = repos.Seasons.GetLiveStandings(ctx, storage.StandingUsers, 1000)
+		require.NoError(t, err)
+		require.Nil(t, findStanding(live, user.UserId), "GetLiveStandings returns the user after the rollover")
+
+		ended, err := repos.Seasons.GetSeason(ctx, current.SeasonID)
+		require.NoError(t, err)
+		require.NotNil(t, ended.EndedAt)
+		season, err := repos.Seasons.GetCurrentSeason(ctx)
+		require.NoError(t, err)
+		require.Equal(t, next.SeasonID, season.SeasonID)
+		require.Nil(t, season.EndedAt)
+		seasons, err := repos.Seasons.GetSeasons(ctx)
+		require.NoError(t, err)
+		require.Equal(t, next.SeasonID, seasons[0].SeasonID, "GetSeasons is not the newest first")
+
+		_, err = repos.Seasons.GetSeason(ctx, -1)
+		require.ErrorIs(t, err, storage.ErrSeasonNotFound)
+	})
+}
+
+func testModeration(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Moderation == nil || repos.Workouts == nil || repos.Users == nil || repos.Exercises == nil {
+		t.Skip("no ModerationRepository, WorkoutRepository, UserRepository or ExerciseRepository")
+	}
+	ctx := context.Background()
+
+	t.Run("ApproveAndVoid", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		exercise := CreateExercise(t, repos.Exercises)
+
+		// a counted 100 x 5, a held 150 x 1 waiting for its 20 points and an excluded 900 x 1
+		session := NewSession(user.UserId, time.Now().Add(-time.Hour), exercise, exercise, exercise)
+		held, excluded := &session.Records[1], &session.Records[2]
+		held.Weight, held.Reps, held.Points, held.Status, held.Reason = 150, 1, 20, storage.RecordHeld, "jump"
+		excluded.Weight, excluded.Reps, excluded.Points, excluded.Status, excluded.Reason = 900, 1, 0, storage.RecordExcluded, "limit"
+		session.Points = 10
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		require.NoError(t, repos.Users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: exercise, MaxWeight: 100, Reps: 5}))
+
+		flagged, err := repos.Moderation.GetFlaggedRecords(ctx, 100)
+		require.NoError(t, err)
+		require.Equal(t, &storage.FlaggedRecord{Record: *held, UserID: user.UserId, StartTime: session.StartTime}, findFlagged(flagged, held.RecordId))
+		require.NotNil(t, findFlagged(flagged, excluded.RecordId))
+		require.Nil(t, findFlagged(flagged, session.Records[0].RecordId))
+
+		// the approved record beats the max, so it gets the bonus on top of its 20 points
+		approved, newMax, err := repos.Moderation.ApproveRecord(ctx, &held.RecordId, prBonus)
+		require.NoError(t, err)
+		require.Equal(t, storage.RecordOK, approved.Status)
+		require.Equal(t, 20+prBonus, approved.Points)
+		require.Equal(t, &storage.Max{UserID: user.UserId, ExerciseId: exercise, MaxWeight: 150, Reps: 1}, newMax)
+		requirePoints(t, repos, user.UserId, session.SessionID, 30+prBonus)
+		max, err := repos.Users.GetUserMax(ctx, &user.UserId, &exercise)
+		require.NoError(t, err)
+		require.Equal(t, 150, max.MaxWeight)
+		workout, err := repos.Workouts.GetWorkout(ctx, &session.SessionID)
+		require.NoError(t, err)
+		for _, record := range workout.Records {
+			if record.RecordId == held.RecordId {
+				require.Equal(t, 20+prBonus, record.Points, "the bonus is not saved with the record")
+			}
+		}
+
+		_, _, err = repos.Moderation.ApproveRecord(ctx, &held.RecordId, prBonus)
+		require.ErrorIs(t, err, storage.ErrRecordNotHeld)
+		_, _, err = repos.Moderation.ApproveRecord(ctx, &excluded.RecordId, prBonus)
+		require.ErrorIs(t, err, storage.ErrRecordNotHeld)
+
+		// voiding the approved record takes its points back with the bonus and the max falls back to the record that still counts
+		voided, err := repos.Moderation.VoidRecord(ctx, &held.RecordId)
+		require.NoError(t, err)
+		require.Equal(t, storage.RecordVoided, voided.Status)
+		requirePoints(t, repos, user.UserId, session.SessionID, 10)
+		max, err = repos.Users.GetUserMax(ctx, &user.UserId, &exercise)
+		require.NoError(t, err)
+		require.Equal(t, 100, max.MaxWeight)
+		require.Equal(t, 5, max.Reps)
+
+		// the excluded record never counted, so nothing is taken back
+		_, err = repos.Moderation.VoidRecord(ctx, &excluded.RecordId)
+		require.NoError(t, err)
+		requirePoints(t, repos, user.UserId, session.SessionID, 10)
+		_, err = repos.Moderation.VoidRecord(ctx, &excluded.RecordId)
+		require.ErrorIs(t, err, storage.ErrRecordVoided)
+
+		flagged, err = repos.Moderation.GetFlaggedRecords(ctx, 100)
+		require.NoError(t, err)
+		require.Nil(t, findFlagged(flagged, held.RecordId))
+		require.Nil(t, findFlagged(flagged, excluded.RecordId))
+
+		// without records that count there is no max left
+		_, err = repos.Moderation.VoidRecord(ctx, &session.Records[0].RecordId)
+		require.NoError(t, err)
+		requirePoints(t, repos, user.UserId, session.SessionID, 0)
+		_, err = repos.Users.GetUserMax(ctx, &user.UserId, &exercise)
+		require.ErrorIs(t, err, storage.ErrNoMaxes)
+
+		workout, err = repos.Workouts.GetWorkout(ctx, &session.SessionID)
+		require.NoError(t, err)
+		for _, record := range workout.Records {
+			require.Equal(t, storage.RecordVoided, record.Status)
+		}
+
+		missing := storage.GenerateUID()
+		_, _, err = repos.Moderation.ApproveRecord(ctx, &missing, prBonus)
+		require.ErrorIs(t, err, storage.ErrRecordNotFound)
+		_, err = repos.Moderation.VoidRecord(ctx, &missing)
+		require.ErrorIs(t, err, storage.ErrRecordNotFound)
+	})
+
+	t.Run("ApproveBelowMax", func(t *testing.T) {
+		repos := newRepos(t)
+		user := RegisterUser(t, repos.Users)
+		exercise := CreateExercise(t, repos.Exercises)
+
+		// the max is 150 x 3, the held 150 x 1 counts without beating it
+		session := NewSession(user.UserId, time.Now().Add(-time.Hour), exercise)
+		held := &session.Records[0]
+		held.Weight, held.Reps, held.Points, held.Status, held.Reason = 150, 1, 20, storage.RecordHeld, "jump"
+		session.Points = 0
+		require.NoError(t, repos.Workouts.SaveWorkout(ctx, session))
+		require.NoError(t, repos.Users.SetUserMax(ctx, &user.UserId, &storage.Max{ExerciseId: exercise, MaxWeight: 150, Reps: 3}))
+
+		approved, newMax, err := repos.Moderation.ApproveRecord(ctx, &held.RecordId, prBonus)
+		require.NoError(t, err)
+		require.Nil(t, newMax)
+		require.Equal(t, 20, approved.Points)
+		requirePoints(t, repos, user.UserId, session.SessionID, 20)
+		max, err := repos.Users.GetUserMax(ctx, &user.UserId, &exercise)
+		require.NoError(t, err)
+		require.Equal(t, 150, max.MaxWeight)
+		require.Equal(t, 3, max.Reps)
+	})
+}
+
+// prBonus is the bonus the cases approve records with.
+const prBonus = 50
+
+// webhookLimit is the max webhooks per owner the cases create them with, higher than any of them needs.
+const webhookLimit = 10
+
+func testWebhooks(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if repos := newRepos(t); repos.Webhooks == nil || repos.Users == nil {
+		t.Skip("no WebhookRepository or UserRepository")
+	}
+	ctx := context.Background()
+	gym := strconv.Itoa(storage.DefaultGymID)
+
+	t.Run("Webhooks", func(t *testing.T) {
+		repos := newRepos(t)
+		owner := RegisterUser(t, repos.Users)
+		other := RegisterUser(t, repos.Users)
+		created := time.Now().UTC().Truncate(time.Second)
+
+		webhook := newWebhook(owner.UserId, storage.WebhookUser, owner.UserId, created, storage.WebhookWorkoutEnded, storage.WebhookPRAchieved)
+		gymWebhook := newWebhook(owner.UserId, storage.WebhookGym, gym, created.Add(time.Second), storage.WebhookWorkoutEnded)
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, webhook, webhookLimit))
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, gymWebhook, webhookLimit))
+
+		webhooks, err := repos.Webhooks.GetWebhooks(ctx, &owner.UserId)
+		require.NoError(t, err)
+		require.Equal(t, []*storage.Webhook{webhook, gymWebhook}, webhooks)
+		webhooks, err = repos.Webhooks.GetWebhooks(ctx, &other.UserId)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+
+		require.ErrorIs(t, repos.Webhooks.DeleteWebhook(ctx, &other.UserId, &gymWebhook.WebhookID), storage.ErrWebhookNotFound)
+		require.NoError(t, repos.Webhooks.DeleteWebhook(ctx, &owner.UserId, &gymWebhook.WebhookID))
+		require.ErrorIs(t, repos.Webhooks.DeleteWebhook(ctx, &owner.UserId, &gymWebhook.WebhookID), storage.ErrWebhookNotFound)
+
+		webhooks, err = repos.Webhooks.GetWebhooks(ctx, &owner.UserId)
+		require.NoError(t, err)
+		require.Equal(t, []*storage.Webhook{webhook}, webhooks)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		repos := newRepos(t)
+		owner := RegisterUser(t, repos.Users)
+		other := RegisterUser(t, repos.Users)
+		created := time.Now().UTC().Truncate(time.Second)
+
+		first := newWebhook(owner.UserId, storage.WebhookUser, owner.UserId, created, storage.WebhookWorkoutEnded)
+		second := newWebhook(owner.UserId, storage.WebhookUser, owner.UserId, created, storage.WebhookPRAchieved)
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, first, 2))
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, second, 2))
+		third := newWebhook(owner.UserId, storage.WebhookUser, owner.UserId, created, storage.WebhookSessionAutoEnded)
+		require.ErrorIs(t, repos.Webhooks.CreateWebhook(ctx, third, 2), storage.ErrWebhookLimit)
+		// the limit is per owner
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, newWebhook(other.UserId, storage.WebhookUser, other.UserId, created, storage.WebhookWorkoutEnded), 2))
+
+		webhooks, err := repos.Webhooks.GetWebhooks(ctx, &owner.UserId)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+
+		require.NoError(t, repos.Webhooks.DeleteWebhook(ctx, &owner.UserId, &first.WebhookID))
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, third, 2))
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		repos := newRepos(t)
+		owner := RegisterUser(t, repos.Users)
+		member := RegisterUser(t, repos.Users)
+		private := RegisterUser(t, repos.Users)
+		require.NoError(t, repos.Users.SetUserPrivate(ctx, &private.UserId, true))
+		created := time.Now().UTC().Truncate(time.Second)
+		at := uniqueWindow()
+
+		webhook := newWebhook(owner.UserId, storage.WebhookUser, owner.UserId, created, storage.WebhookWorkoutEnded)
+		gymWebhook := newWebhook(owner.UserId, storage.WebhookGym, gym, created, storage.WebhookWorkoutEnded, storage.WebhookPRAchieved)
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, webhook, webhookLimit))
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, gymWebhook, webhookLimit))
+
+		// gym webhooks of other tests sharing the database get copies as well, so only the minimum is known
+		queue := func(userID, event string, createdAt time.Time) int {
+			added, err := repos.Webhooks.AddDeliveries(ctx, &userID, &storage.WebhookDelivery{
+				Event: event, Payload: `{"event":"` + event + `"}`, NextAttemptAt: at, CreatedAt: createdAt,
+			})
+			require.NoError(t, err)
+			return added
+		}
+		require.GreaterOrEqual(t, queue(owner.UserId, storage.WebhookWorkoutEnded, created), 2)
+		require.GreaterOrEqual(t, queue(member.UserId, storage.WebhookPRAchieved, created.Add(time.Second)), 1)
+		queue(private.UserId, storage.WebhookWorkoutEnded, created.Add(2*time.Second))
+		queue(owner.UserId, storage.WebhookSessionAutoEnded, created.Add(3*time.Second))
+
+		own, err := repos.Webhooks.GetDeliveries(ctx, &owner.UserId, webhook.WebhookID, "", 100)
+		require.NoError(t, err)
+		require.Len(t, own, 1)
+		require.Equal(t, storage.WebhookWorkoutEnded, own[0].Event)
+		require.Equal(t, storage.DeliveryPending, own[0].Status)
+		require.Equal(t, `{"event":"workout.ended"}`, own[0].Payload)
+		require.Zero(t, own[0].Attempts)
+		require.Nil(t, own[0].DeliveredAt)
+
+		// the private user is in the gym too, but their events are not sent to it
+		fromGym, err := repos.Webhooks.GetDeliveries(ctx, &owner.UserId, gymWebhook.WebhookID, "", 100)
+		require.NoError(t, err)
+		require.Len(t, fromGym, 2)
+		require.Equal(t, storage.WebhookPRAchieved, fromGym[0].Event, "GetDeliveries is not the newest first")
+		require.Equal(t, storage.WebhookWorkoutEnded, fromGym[1].Event)
+
+		all, err := repos.Webhooks.GetDeliveries(ctx, &owner.UserId, "", "", 100)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		limited, err := repos.Webhooks.GetDeliveries(ctx, &owner.UserId, "", "", 1)
+		require.NoError(t, err)
+		require.Len(t, limited, 1)
+		_, err = repos.Webhooks.GetDeliveries(ctx, &member.UserId, webhook.WebhookID, "", 100)
+		require.ErrorIs(t, err, storage.ErrWebhookNotFound)
+
+		due, err := repos.Webhooks.GetDueDeliveries(ctx, at.Add(-time.Second), 1000)
+		require.NoError(t, err)
+		require.Nil(t, findPending(due, own[0].DeliveryID), "a delivery is due before its next attempt")
+		due, err = repos.Webhooks.GetDueDeliveries(ctx, at, 1000)
+		require.NoError(t, err)
+		pending := findPending(due, own[0].DeliveryID)
+		require.NotNil(t, pending)
+		require.Equal(t, webhook.URL, pending.URL)
+		require.Equal(t, webhook.Secret, pending.Secret)
+		require.Equal(t, *own[0], pending.WebhookDelivery)
+
+		delivered := own[0]
+		deliveredAt := at.Add(time.Second)
+		delivered.Status, delivered.Attempts, delivered.LastStatusCode, delivered.DeliveredAt = storage.DeliveryDelivered, 1, 200, &deliveredAt
+		require.NoError(t, repos.Webhooks.UpdateDelivery(ctx, delivered))
+		dead := fromGym[1]
+		dead.Status, dead.Attempts, dead.LastStatusCode, dead.LastError = storage.DeliveryDead, 8, 500, "boom"
+		dead.NextAttemptAt = at.Add(time.Hour)
+		require.NoError(t, repos.Webhooks.UpdateDelivery(ctx, dead))
+
+		deliveries, err := repos.Webhooks.GetDeliveries(ctx, &owner.UserId, "", storage.DeliveryDelivered, 100)
+		require.NoError(t, err)
+		require.Equal(t, []*storage.WebhookDelivery{delivered}, deliveries)
+		deliveries, err = repos.Webhooks.GetDeliveries(ctx, &owner.UserId, "", storage.DeliveryDead, 100)
+		require.NoError(t, err)
+		require.Equal(t, []*storage.WebhookDelivery{dead}, deliveries)
+		due, err = repos.Webhooks.GetDueDeliveries(ctx, at.Add(24*time.Hour), 1000)
+		require.NoError(t, err)
+		require.Nil(t, findPending(due, delivered.DeliveryID))
+		require.Nil(t, findPending(due, dead.DeliveryID))
+		require.NotNil(t, findPending(due, fromGym[0].DeliveryID))
+
+		missing := storage.GenerateUID()
+		require.ErrorIs(t, repos.Webhooks.RetryDelivery(ctx, &member.UserId, &dead.DeliveryID, at), storage.ErrDeliveryNotFound)
+		require.ErrorIs(t, repos.Webhooks.RetryDelivery(ctx, &owner.UserId, &missing, at), storage.ErrDeliveryNotFound)
+		require.ErrorIs(t, repos.Webhooks.RetryDelivery(ctx, &owner.UserId, &delivered.DeliveryID, at), storage.ErrDeliveryNotDead)
+		require.NoError(t, repos.Webhooks.RetryDelivery(ctx, &owner.UserId, &dead.DeliveryID, at))
+		require.ErrorIs(t, repos.Webhooks.RetryDelivery(ctx, &owner.UserId, &dead.DeliveryID, at), storage.ErrDeliveryNotDead)
+		due, err = repos.Webhooks.GetDueDeliveries(ctx, at, 1000)
+		require.NoError(t, err)
+		retried := findPending(due, dead.DeliveryID)
+		require.NotNil(t, retried)
+		require.Zero(t, retried.Attempts)
+		require.Equal(t, "boom", retried.LastError)
+
+		// deleting the webhook deletes its deliveries
+		require.NoError(t, repos.Webhooks.DeleteWebhook(ctx, &owner.UserId, &gymWebhook.WebhookID))
+		all, err = repos.Webhooks.GetDeliveries(ctx, &owner.UserId, "", "", 100)
+		require.NoError(t, err)
+		require.Equal(t, []*storage.WebhookDelivery{delivered}, all)
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		repos := newRepos(t)
+		target, source := RegisterUser(t, repos.Users), RegisterUser(t, repos.Users)
+		webhook := newWebhook(source.UserId, storage.WebhookUser, source.UserId, time.Now().UTC().Truncate(time.Second), storage.WebhookWorkoutEnded)
+		require.NoError(t, repos.Webhooks.CreateWebhook(ctx, webhook, webhookLimit))
+
+		require.NoError(t, repos.Users.MergeUsers(ctx, &target.UserId, &source.UserId))
+
+		// the webhook is managed by the target and sends the events of the target
+		webhooks, err := repos.Webhooks.GetWebhooks(ctx, &target.UserId)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, target.UserId, webhooks[0].SubjectID)
+		added, err := repos.Webhooks.AddDeliveries(ctx, &target.UserId, &storage.WebhookDelivery{
+			Event: storage.WebhookWorkoutEnded, Payload: "{}", NextAttemptAt: time.Now(), CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, added, 1)
+		deliveries, err := repos.Webhooks.GetDeliveries(ctx, &target.UserId, webhook.WebhookID, "", 100)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+}
+
+func testLocker(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	if newRepos(t).Locker == nil {
+		t.Skip("no Locker")
+	}
+	ctx := context.Background()
+	locker := newRepos(t).Locker
+	name := uniqueName("lock")
+
+	unlock, ok, err := locker.TryLock(ctx, name)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = locker.TryLock(ctx, name)
+	require.NoError(t, err)
+	require.False(t, ok, "the lock is taken twice")
+
+	other, ok, err := locker.TryLock(ctx, uniqueName("lock"))
+	require.NoError(t, err)
+	require.True(t, ok, "another lock is not taken")
+	other()
+
+	unlock()
+	unlock, ok, err = locker.TryLock(ctx, name)
+	require.NoError(t, err)
+	require.True(t, ok, "the lock is not released")
+	unlock()
+}
+
+// NewUser returns a user with unique ID, username and email, it is not registered.
+func NewUser() *storage.User {
+	id := storage.GenerateUID()
+	return &storage.User{
+		UserId:   id,
+		Username: "user-" + id,
+		Email:    id + "@gymbro.test",
+		Password: "hash",
+	}
+}
+
+// RegisterUser registers a new unique user.
+func RegisterUser(t *testing.T, users storage.UserRepository) *storage.User {
+	t.Helper()
+	user := NewUser()
+	_, err := users.RegisterNewUser(context.Background(), user)
+	require.NoError(t, err)
+	return user
+}
+
+// CreateExercise creates an exercise with a unique name and returns its ID.
+func CreateExercise(t *testing.T, exercises storage.ExerciseRepository) int {
+	t.Helper()
+	id, err := exercises.CreateExercise(context.Background(), &storage.Exercise{Name: uniqueName("exercise")})
+	require.NoError(t, err)
+	return *id
+}
+
+// NewSession returns a session of the user that started at start and lasted an hour,
+// with one 10 point record that counts for every exercise given.
+func NewSession(userID string, start time.Time, exerciseIDs ...int) *storage.WorkoutSession {
+	// storages keep times with different precision, whole seconds survive all of them
+	start = start.UTC().Truncate(time.Second)
+	session := &storage.WorkoutSession{
+		UserID:      userID,
+		SessionID:   storage.GenerateUID(),
+		StartTime:   start,
+		LastUpdated: start.Add(time.Hour),
+	}
+	for i, exerciseID := range exerciseIDs {
+		session.Records = append(session.Records, storage.Record{
+			RecordId:     storage.GenerateUID(),
+			FkWorkoutId:  session.SessionID,
+			FkExerciseId: exerciseID,
+			Reps:         5 + i,
+			Weight:       100,
+			Points:       10,
+			Status:       storage.RecordOK,
+		})
+		session.Points += 10
+	}
+	return session
+}
+
+func findExercise(t *testing.T, exercises storage.ExerciseRepository, id int) *storage.Exercise {
+	t.Helper()
+	all, err := exercises.GetExercises(context.Background())
+	require.NoError(t, err)
+	for _, exercise := range all {
+		if exercise.ExerciseId == id {
+			return exercise
+		}
+	}
+	return nil
+}
+
+// newWebhook returns a webhook of the owner with a unique ID, it is not stored.
+func newWebhook(ownerID, kind, subjectID string, createdAt time.Time, events ...string) *storage.Webhook {
+	id := storage.GenerateUID()
+	return &storage.Webhook{
+		WebhookID: id,
+		OwnerID:   ownerID,
+		Kind:      kind,
+		SubjectID: subjectID,
+		URL:       "https://hooks.gymbro.test/" + id,
+		Secret:    "whsec_" + id,
+		Events:    events,
+		CreatedAt: createdAt,
+	}
+}
+
+func findPending(deliveries []*storage.PendingDelivery, deliveryID string) *storage.PendingDelivery {
+	for _, delivery := range deliveries {
+		if delivery.DeliveryID == deliveryID {
+			return delivery
+		}
+	}
+	return nil
+}
+
+func findFlagged(records []*storage.FlaggedRecord, recordID string) *storage.FlaggedRecord {
+	for _, record := range records {
+		if record.RecordId == recordID {
+			return record
+		}
+	}
+	return nil
+}
+
+// requirePoints checks the points of the workout and the user, the user trained only once.
+func requirePoints(t *testing.T, repos Repositories, userID, workoutID string, expected int) {
+	t.Helper()
+	workout, err := repos.Workouts.GetWorkout(context.Background(), &workoutID)
+	require.NoError(t, err)
+	require.Equal(t, expected, workout.Points, "workout points")
+	user, err := repos.Users.GetUserByID(context.Background(), &userID)
+	require.NoError(t, err)
+	require.Equal(t, expected, user.Points, "user points")
+}
+
+func hasUser(users []*storage.User, userID string) bool {
+	for _, user := range users {
+		if user.UserId == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChallenge(challenges []*storage.Challenge, challengeID string) bool {
+	for _, challenge := range challenges {
+		if challenge.ChallengeID == challengeID {
+			return true
+		}
+	}
+	return false
+}
+
+func findStanding(standings []*storage.Standing, subjectID string) *storage.Standing {
+	for _, standing := range standings {
+		if standing.SubjectID == subjectID {
+			return standing
+		}
+	}
+	return nil
+}
+
+// uniqueWindow returns the start of a few hours far in the future that no other test trains in,
+// so the progress of a challenge only counts the workouts of the test.
+func uniqueWindow() time.Time {
+	return time.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rand.Int63n(1_000_000)) * 3 * time.Hour)
+}
+
+func uniqueName(prefix string) string {
+	return prefix + "-" + storage.GenerateUID()
+}